@@ -0,0 +1,4147 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: crl.proto
+
+package crl
+
+import (
+	_ "github.com/grpc-ecosystem/grpc-gateway/v2/protoc-gen-openapiv2/options"
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// CRLFormat selects the encoding returned by GetCRL
+type CRLFormat int32
+
+const (
+	CRLFormat_CRL_FORMAT_UNSPECIFIED CRLFormat = 0 // DER in crl_data; both crl_der and crl_pem set
+	CRLFormat_CRL_FORMAT_DER         CRLFormat = 1
+	CRLFormat_CRL_FORMAT_PEM         CRLFormat = 2
+)
+
+// Enum value maps for CRLFormat.
+var (
+	CRLFormat_name = map[int32]string{
+		0: "CRL_FORMAT_UNSPECIFIED",
+		1: "CRL_FORMAT_DER",
+		2: "CRL_FORMAT_PEM",
+	}
+	CRLFormat_value = map[string]int32{
+		"CRL_FORMAT_UNSPECIFIED": 0,
+		"CRL_FORMAT_DER":         1,
+		"CRL_FORMAT_PEM":         2,
+	}
+)
+
+func (x CRLFormat) Enum() *CRLFormat {
+	p := new(CRLFormat)
+	*p = x
+	return p
+}
+
+func (x CRLFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CRLFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_crl_proto_enumTypes[0].Descriptor()
+}
+
+func (CRLFormat) Type() protoreflect.EnumType {
+	return &file_crl_proto_enumTypes[0]
+}
+
+func (x CRLFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CRLFormat.Descriptor instead.
+func (CRLFormat) EnumDescriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{0}
+}
+
+// ExportFormat selects the encoding of ExportRevocations
+type ExportFormat int32
+
+const (
+	ExportFormat_EXPORT_FORMAT_UNSPECIFIED ExportFormat = 0 // JSON
+	ExportFormat_EXPORT_FORMAT_JSON        ExportFormat = 1 // A JSON array of objects named like the Revocation fields
+	ExportFormat_EXPORT_FORMAT_CSV         ExportFormat = 2 // A header row, then one row per revocation
+)
+
+// Enum value maps for ExportFormat.
+var (
+	ExportFormat_name = map[int32]string{
+		0: "EXPORT_FORMAT_UNSPECIFIED",
+		1: "EXPORT_FORMAT_JSON",
+		2: "EXPORT_FORMAT_CSV",
+	}
+	ExportFormat_value = map[string]int32{
+		"EXPORT_FORMAT_UNSPECIFIED": 0,
+		"EXPORT_FORMAT_JSON":        1,
+		"EXPORT_FORMAT_CSV":         2,
+	}
+)
+
+func (x ExportFormat) Enum() *ExportFormat {
+	p := new(ExportFormat)
+	*p = x
+	return p
+}
+
+func (x ExportFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ExportFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_crl_proto_enumTypes[1].Descriptor()
+}
+
+func (ExportFormat) Type() protoreflect.EnumType {
+	return &file_crl_proto_enumTypes[1]
+}
+
+func (x ExportFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ExportFormat.Descriptor instead.
+func (ExportFormat) EnumDescriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{1}
+}
+
+type AddRevocationRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumber string                 `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	RevokedAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	// RFC 5280 CRLReason name: unspecified, keyCompromise, cACompromise,
+	// affiliationChanged, superseded, cessationOfOperation, certificateHold,
+	// privilegeWithdrawn or aACompromise. Unknown values are rejected.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// When the key is known or suspected to have been compromised (optional,
+	// must not be after revoked_at)
+	InvalidityDate *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=invalidity_date,json=invalidityDate,proto3" json:"invalidity_date,omitempty"`
+	// DER-encoded issuer Name of the revoked certificate when it was issued by
+	// a CA other than the CRL issuer (indirect CRLs only)
+	CertificateIssuer []byte `protobuf:"bytes,5,opt,name=certificate_issuer,json=certificateIssuer,proto3" json:"certificate_issuer,omitempty"`
+	IssuerId          string `protobuf:"bytes,6,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"` // Issuer whose CRL lists the certificate (defaults to the default issuer)
+	// Schedules the revocation: the entry only appears in CRLs and OCSP
+	// responses from this time on, which also defaults revoked_at. Past
+	// values revoke immediately.
+	EffectiveAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=effective_at,json=effectiveAt,proto3" json:"effective_at,omitempty"`
+	// Identity requesting the revocation; required when approval is enabled
+	RequestedBy string `protobuf:"bytes,8,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	// Retries with the same key within 24h return the first response instead
+	// of revoking again. Reusing a key for a different request fails. Ignored
+	// inside AddRevocations, which has its own key.
+	IdempotencyKey string `protobuf:"bytes,9,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// When the certificate expires (optional; looked up in the CA service
+	// when verification is enabled). CRLs drop the entry once it has been
+	// expired for longer than crl.expired_retention.
+	NotAfter      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRevocationRequest) Reset() {
+	*x = AddRevocationRequest{}
+	mi := &file_crl_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRevocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRevocationRequest) ProtoMessage() {}
+
+func (x *AddRevocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRevocationRequest.ProtoReflect.Descriptor instead.
+func (*AddRevocationRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AddRevocationRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *AddRevocationRequest) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *AddRevocationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AddRevocationRequest) GetInvalidityDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InvalidityDate
+	}
+	return nil
+}
+
+func (x *AddRevocationRequest) GetCertificateIssuer() []byte {
+	if x != nil {
+		return x.CertificateIssuer
+	}
+	return nil
+}
+
+func (x *AddRevocationRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *AddRevocationRequest) GetEffectiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveAt
+	}
+	return nil
+}
+
+func (x *AddRevocationRequest) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *AddRevocationRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *AddRevocationRequest) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+type AddRevocationResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Set when the revocation awaits approval instead of being added
+	PendingApproval bool  `protobuf:"varint,3,opt,name=pending_approval,json=pendingApproval,proto3" json:"pending_approval,omitempty"`
+	ApprovalId      int64 `protobuf:"varint,4,opt,name=approval_id,json=approvalId,proto3" json:"approval_id,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddRevocationResponse) Reset() {
+	*x = AddRevocationResponse{}
+	mi := &file_crl_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRevocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRevocationResponse) ProtoMessage() {}
+
+func (x *AddRevocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRevocationResponse.ProtoReflect.Descriptor instead.
+func (*AddRevocationResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AddRevocationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AddRevocationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AddRevocationResponse) GetPendingApproval() bool {
+	if x != nil {
+		return x.PendingApproval
+	}
+	return false
+}
+
+func (x *AddRevocationResponse) GetApprovalId() int64 {
+	if x != nil {
+		return x.ApprovalId
+	}
+	return 0
+}
+
+type AddRevocationsRequest struct {
+	state       protoimpl.MessageState  `protogen:"open.v1"`
+	Revocations []*AddRevocationRequest `protobuf:"bytes,1,rep,name=revocations,proto3" json:"revocations,omitempty"` // At most 1000
+	// Store nothing when any revocation is invalid; otherwise the valid ones
+	// are stored and the invalid ones reported
+	Atomic         bool   `protobuf:"varint,2,opt,name=atomic,proto3" json:"atomic,omitempty"`
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"` // As in AddRevocationRequest, for the whole batch
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AddRevocationsRequest) Reset() {
+	*x = AddRevocationsRequest{}
+	mi := &file_crl_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRevocationsRequest) ProtoMessage() {}
+
+func (x *AddRevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRevocationsRequest.ProtoReflect.Descriptor instead.
+func (*AddRevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AddRevocationsRequest) GetRevocations() []*AddRevocationRequest {
+	if x != nil {
+		return x.Revocations
+	}
+	return nil
+}
+
+func (x *AddRevocationsRequest) GetAtomic() bool {
+	if x != nil {
+		return x.Atomic
+	}
+	return false
+}
+
+func (x *AddRevocationsRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type AddRevocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*AddRevocationResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // In request order
+	Added         int32                  `protobuf:"varint,2,opt,name=added,proto3" json:"added,omitempty"`    // Added, or queued when approval is enabled
+	Failed        int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRevocationsResponse) Reset() {
+	*x = AddRevocationsResponse{}
+	mi := &file_crl_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRevocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRevocationsResponse) ProtoMessage() {}
+
+func (x *AddRevocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRevocationsResponse.ProtoReflect.Descriptor instead.
+func (*AddRevocationsResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AddRevocationsResponse) GetResults() []*AddRevocationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *AddRevocationsResponse) GetAdded() int32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *AddRevocationsResponse) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+type AddRevocationResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // Position in the request
+	SerialNumber  string                 `protobuf:"bytes,2,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Success       bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	Code          int32                  `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"` // gRPC status code of a failed revocation
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	ApprovalId    int64                  `protobuf:"varint,6,opt,name=approval_id,json=approvalId,proto3" json:"approval_id,omitempty"` // Set when the revocation awaits approval
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRevocationResult) Reset() {
+	*x = AddRevocationResult{}
+	mi := &file_crl_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRevocationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRevocationResult) ProtoMessage() {}
+
+func (x *AddRevocationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRevocationResult.ProtoReflect.Descriptor instead.
+func (*AddRevocationResult) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AddRevocationResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *AddRevocationResult) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *AddRevocationResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AddRevocationResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *AddRevocationResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AddRevocationResult) GetApprovalId() int64 {
+	if x != nil {
+		return x.ApprovalId
+	}
+	return 0
+}
+
+type ApproveRevocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApprovalId    int64                  `protobuf:"varint,1,opt,name=approval_id,json=approvalId,proto3" json:"approval_id,omitempty"`
+	ApprovedBy    string                 `protobuf:"bytes,2,opt,name=approved_by,json=approvedBy,proto3" json:"approved_by,omitempty"` // Must differ from the requester
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveRevocationRequest) Reset() {
+	*x = ApproveRevocationRequest{}
+	mi := &file_crl_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveRevocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveRevocationRequest) ProtoMessage() {}
+
+func (x *ApproveRevocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveRevocationRequest.ProtoReflect.Descriptor instead.
+func (*ApproveRevocationRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ApproveRevocationRequest) GetApprovalId() int64 {
+	if x != nil {
+		return x.ApprovalId
+	}
+	return 0
+}
+
+func (x *ApproveRevocationRequest) GetApprovedBy() string {
+	if x != nil {
+		return x.ApprovedBy
+	}
+	return ""
+}
+
+type ApproveRevocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveRevocationResponse) Reset() {
+	*x = ApproveRevocationResponse{}
+	mi := &file_crl_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveRevocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveRevocationResponse) ProtoMessage() {}
+
+func (x *ApproveRevocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveRevocationResponse.ProtoReflect.Descriptor instead.
+func (*ApproveRevocationResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ApproveRevocationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ApproveRevocationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type RejectRevocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApprovalId    int64                  `protobuf:"varint,1,opt,name=approval_id,json=approvalId,proto3" json:"approval_id,omitempty"`
+	RejectedBy    string                 `protobuf:"bytes,2,opt,name=rejected_by,json=rejectedBy,proto3" json:"rejected_by,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Why the revocation was rejected
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectRevocationRequest) Reset() {
+	*x = RejectRevocationRequest{}
+	mi := &file_crl_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectRevocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectRevocationRequest) ProtoMessage() {}
+
+func (x *RejectRevocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectRevocationRequest.ProtoReflect.Descriptor instead.
+func (*RejectRevocationRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RejectRevocationRequest) GetApprovalId() int64 {
+	if x != nil {
+		return x.ApprovalId
+	}
+	return 0
+}
+
+func (x *RejectRevocationRequest) GetRejectedBy() string {
+	if x != nil {
+		return x.RejectedBy
+	}
+	return ""
+}
+
+func (x *RejectRevocationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RejectRevocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectRevocationResponse) Reset() {
+	*x = RejectRevocationResponse{}
+	mi := &file_crl_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectRevocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectRevocationResponse) ProtoMessage() {}
+
+func (x *RejectRevocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectRevocationResponse.ProtoReflect.Descriptor instead.
+func (*RejectRevocationResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RejectRevocationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RejectRevocationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ListPendingRevocationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IssuerId      string                 `protobuf:"bytes,1,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"` // Defaults to the default issuer
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingRevocationsRequest) Reset() {
+	*x = ListPendingRevocationsRequest{}
+	mi := &file_crl_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingRevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingRevocationsRequest) ProtoMessage() {}
+
+func (x *ListPendingRevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingRevocationsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingRevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListPendingRevocationsRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+type ListPendingRevocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pending       []*PendingRevocation   `protobuf:"bytes,1,rep,name=pending,proto3" json:"pending,omitempty"` // Oldest first, at most 1000
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingRevocationsResponse) Reset() {
+	*x = ListPendingRevocationsResponse{}
+	mi := &file_crl_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingRevocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingRevocationsResponse) ProtoMessage() {}
+
+func (x *ListPendingRevocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingRevocationsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingRevocationsResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListPendingRevocationsResponse) GetPending() []*PendingRevocation {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+type PendingRevocation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApprovalId    int64                  `protobuf:"varint,1,opt,name=approval_id,json=approvalId,proto3" json:"approval_id,omitempty"`
+	Revocation    *Revocation            `protobuf:"bytes,2,opt,name=revocation,proto3" json:"revocation,omitempty"`
+	RequestedBy   string                 `protobuf:"bytes,3,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	RequestedAt   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // Not approvable after this
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PendingRevocation) Reset() {
+	*x = PendingRevocation{}
+	mi := &file_crl_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PendingRevocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PendingRevocation) ProtoMessage() {}
+
+func (x *PendingRevocation) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PendingRevocation.ProtoReflect.Descriptor instead.
+func (*PendingRevocation) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PendingRevocation) GetApprovalId() int64 {
+	if x != nil {
+		return x.ApprovalId
+	}
+	return 0
+}
+
+func (x *PendingRevocation) GetRevocation() *Revocation {
+	if x != nil {
+		return x.Revocation
+	}
+	return nil
+}
+
+func (x *PendingRevocation) GetRequestedBy() string {
+	if x != nil {
+		return x.RequestedBy
+	}
+	return ""
+}
+
+func (x *PendingRevocation) GetRequestedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RequestedAt
+	}
+	return nil
+}
+
+func (x *PendingRevocation) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type GetCRLRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Issuer        string                 `protobuf:"bytes,1,opt,name=issuer,proto3" json:"issuer,omitempty"`        // Issuer ID or CA common name (defaults to the default issuer)
+	Delta         bool                   `protobuf:"varint,2,opt,name=delta,proto3" json:"delta,omitempty"`         // Return a delta CRL relative to the last published base CRL
+	Partition     int32                  `protobuf:"varint,3,opt,name=partition,proto3" json:"partition,omitempty"` // Partition number for partitioned issuers
+	Format        CRLFormat              `protobuf:"varint,4,opt,name=format,proto3,enum=gigvault.crl.v1.CRLFormat" json:"format,omitempty"`
+	CrlNumber     int64                  `protobuf:"varint,5,opt,name=crl_number,json=crlNumber,proto3" json:"crl_number,omitempty"` // Return the recorded CRL with this number instead of a new one; delta and partition are ignored
+	Sha256        []byte                 `protobuf:"bytes,6,opt,name=sha256,proto3" json:"sha256,omitempty"`                         // Return the recorded CRL with this SHA-256 digest of its DER; takes precedence over crl_number
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCRLRequest) Reset() {
+	*x = GetCRLRequest{}
+	mi := &file_crl_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCRLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCRLRequest) ProtoMessage() {}
+
+func (x *GetCRLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCRLRequest.ProtoReflect.Descriptor instead.
+func (*GetCRLRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetCRLRequest) GetIssuer() string {
+	if x != nil {
+		return x.Issuer
+	}
+	return ""
+}
+
+func (x *GetCRLRequest) GetDelta() bool {
+	if x != nil {
+		return x.Delta
+	}
+	return false
+}
+
+func (x *GetCRLRequest) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *GetCRLRequest) GetFormat() CRLFormat {
+	if x != nil {
+		return x.Format
+	}
+	return CRLFormat_CRL_FORMAT_UNSPECIFIED
+}
+
+func (x *GetCRLRequest) GetCrlNumber() int64 {
+	if x != nil {
+		return x.CrlNumber
+	}
+	return 0
+}
+
+func (x *GetCRLRequest) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+type GetCRLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CrlDer        []byte                 `protobuf:"bytes,1,opt,name=crl_der,json=crlDer,proto3" json:"crl_der,omitempty"` // CRL in DER format (DER or unspecified format)
+	CrlPem        string                 `protobuf:"bytes,2,opt,name=crl_pem,json=crlPem,proto3" json:"crl_pem,omitempty"` // CRL in PEM format (PEM or unspecified format)
+	ThisUpdate    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=this_update,json=thisUpdate,proto3" json:"this_update,omitempty"`
+	NextUpdate    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=next_update,json=nextUpdate,proto3" json:"next_update,omitempty"`
+	RevokedCount  int32                  `protobuf:"varint,5,opt,name=revoked_count,json=revokedCount,proto3" json:"revoked_count,omitempty"`
+	CrlNumber     int64                  `protobuf:"varint,6,opt,name=crl_number,json=crlNumber,proto3" json:"crl_number,omitempty"`
+	BaseCrlNumber int64                  `protobuf:"varint,7,opt,name=base_crl_number,json=baseCrlNumber,proto3" json:"base_crl_number,omitempty"` // Set for delta CRLs only
+	Partition     int32                  `protobuf:"varint,8,opt,name=partition,proto3" json:"partition,omitempty"`
+	CrlData       []byte                 `protobuf:"bytes,9,opt,name=crl_data,json=crlData,proto3" json:"crl_data,omitempty"`                 // CRL in the requested format
+	Format        CRLFormat              `protobuf:"varint,10,opt,name=format,proto3,enum=gigvault.crl.v1.CRLFormat" json:"format,omitempty"` // Encoding of crl_data
+	Sha256        []byte                 `protobuf:"bytes,11,opt,name=sha256,proto3" json:"sha256,omitempty"`                                 // SHA-256 of the DER CRL, its key in the CRL history
+	Stale         bool                   `protobuf:"varint,12,opt,name=stale,proto3" json:"stale,omitempty"`                                  // Last known good CRL, served because a new one could not be signed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCRLResponse) Reset() {
+	*x = GetCRLResponse{}
+	mi := &file_crl_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCRLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCRLResponse) ProtoMessage() {}
+
+func (x *GetCRLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCRLResponse.ProtoReflect.Descriptor instead.
+func (*GetCRLResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetCRLResponse) GetCrlDer() []byte {
+	if x != nil {
+		return x.CrlDer
+	}
+	return nil
+}
+
+func (x *GetCRLResponse) GetCrlPem() string {
+	if x != nil {
+		return x.CrlPem
+	}
+	return ""
+}
+
+func (x *GetCRLResponse) GetThisUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ThisUpdate
+	}
+	return nil
+}
+
+func (x *GetCRLResponse) GetNextUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextUpdate
+	}
+	return nil
+}
+
+func (x *GetCRLResponse) GetRevokedCount() int32 {
+	if x != nil {
+		return x.RevokedCount
+	}
+	return 0
+}
+
+func (x *GetCRLResponse) GetCrlNumber() int64 {
+	if x != nil {
+		return x.CrlNumber
+	}
+	return 0
+}
+
+func (x *GetCRLResponse) GetBaseCrlNumber() int64 {
+	if x != nil {
+		return x.BaseCrlNumber
+	}
+	return 0
+}
+
+func (x *GetCRLResponse) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *GetCRLResponse) GetCrlData() []byte {
+	if x != nil {
+		return x.CrlData
+	}
+	return nil
+}
+
+func (x *GetCRLResponse) GetFormat() CRLFormat {
+	if x != nil {
+		return x.Format
+	}
+	return CRLFormat_CRL_FORMAT_UNSPECIFIED
+}
+
+func (x *GetCRLResponse) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+func (x *GetCRLResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+// GetCRLChunk is one piece of a streamed CRL. Concatenating data in order
+// yields the CRL; metadata fields are only set on the first chunk.
+type GetCRLChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	TotalSize     int64                  `protobuf:"varint,3,opt,name=total_size,json=totalSize,proto3" json:"total_size,omitempty"`
+	Format        CRLFormat              `protobuf:"varint,4,opt,name=format,proto3,enum=gigvault.crl.v1.CRLFormat" json:"format,omitempty"`
+	ThisUpdate    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=this_update,json=thisUpdate,proto3" json:"this_update,omitempty"`
+	NextUpdate    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=next_update,json=nextUpdate,proto3" json:"next_update,omitempty"`
+	RevokedCount  int32                  `protobuf:"varint,7,opt,name=revoked_count,json=revokedCount,proto3" json:"revoked_count,omitempty"`
+	CrlNumber     int64                  `protobuf:"varint,8,opt,name=crl_number,json=crlNumber,proto3" json:"crl_number,omitempty"`
+	BaseCrlNumber int64                  `protobuf:"varint,9,opt,name=base_crl_number,json=baseCrlNumber,proto3" json:"base_crl_number,omitempty"` // Set for delta CRLs only
+	Partition     int32                  `protobuf:"varint,10,opt,name=partition,proto3" json:"partition,omitempty"`
+	Sha256        []byte                 `protobuf:"bytes,11,opt,name=sha256,proto3" json:"sha256,omitempty"` // SHA-256 of the DER CRL
+	Stale         bool                   `protobuf:"varint,12,opt,name=stale,proto3" json:"stale,omitempty"`  // Last known good CRL, served because a new one could not be signed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCRLChunk) Reset() {
+	*x = GetCRLChunk{}
+	mi := &file_crl_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCRLChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCRLChunk) ProtoMessage() {}
+
+func (x *GetCRLChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCRLChunk.ProtoReflect.Descriptor instead.
+func (*GetCRLChunk) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetCRLChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *GetCRLChunk) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetCRLChunk) GetTotalSize() int64 {
+	if x != nil {
+		return x.TotalSize
+	}
+	return 0
+}
+
+func (x *GetCRLChunk) GetFormat() CRLFormat {
+	if x != nil {
+		return x.Format
+	}
+	return CRLFormat_CRL_FORMAT_UNSPECIFIED
+}
+
+func (x *GetCRLChunk) GetThisUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ThisUpdate
+	}
+	return nil
+}
+
+func (x *GetCRLChunk) GetNextUpdate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextUpdate
+	}
+	return nil
+}
+
+func (x *GetCRLChunk) GetRevokedCount() int32 {
+	if x != nil {
+		return x.RevokedCount
+	}
+	return 0
+}
+
+func (x *GetCRLChunk) GetCrlNumber() int64 {
+	if x != nil {
+		return x.CrlNumber
+	}
+	return 0
+}
+
+func (x *GetCRLChunk) GetBaseCrlNumber() int64 {
+	if x != nil {
+		return x.BaseCrlNumber
+	}
+	return 0
+}
+
+func (x *GetCRLChunk) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *GetCRLChunk) GetSha256() []byte {
+	if x != nil {
+		return x.Sha256
+	}
+	return nil
+}
+
+func (x *GetCRLChunk) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+type PublishCRLRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Force    bool                   `protobuf:"varint,1,opt,name=force,proto3" json:"force,omitempty"`                      // Force generation even if not needed
+	IssuerId string                 `protobuf:"bytes,2,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"` // Defaults to the default issuer
+	// Overrides of the configured validity window (optional)
+	Validity      *durationpb.Duration `protobuf:"bytes,3,opt,name=validity,proto3" json:"validity,omitempty"` // nextUpdate minus signing time
+	Overlap       *durationpb.Duration `protobuf:"bytes,4,opt,name=overlap,proto3" json:"overlap,omitempty"`   // thisUpdate backdating
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishCRLRequest) Reset() {
+	*x = PublishCRLRequest{}
+	mi := &file_crl_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishCRLRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishCRLRequest) ProtoMessage() {}
+
+func (x *PublishCRLRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishCRLRequest.ProtoReflect.Descriptor instead.
+func (*PublishCRLRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *PublishCRLRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *PublishCRLRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *PublishCRLRequest) GetValidity() *durationpb.Duration {
+	if x != nil {
+		return x.Validity
+	}
+	return nil
+}
+
+func (x *PublishCRLRequest) GetOverlap() *durationpb.Duration {
+	if x != nil {
+		return x.Overlap
+	}
+	return nil
+}
+
+type PublishCRLResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	PublishedAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	RevokedCount  int32                  `protobuf:"varint,4,opt,name=revoked_count,json=revokedCount,proto3" json:"revoked_count,omitempty"` // Summed over all partitions
+	Partitions    int32                  `protobuf:"varint,5,opt,name=partitions,proto3" json:"partitions,omitempty"`                         // Number of CRLs published
+	Targets       []*PublishTargetResult `protobuf:"bytes,6,rep,name=targets,proto3" json:"targets,omitempty"`                                // One per publisher and partition
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishCRLResponse) Reset() {
+	*x = PublishCRLResponse{}
+	mi := &file_crl_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishCRLResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishCRLResponse) ProtoMessage() {}
+
+func (x *PublishCRLResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishCRLResponse.ProtoReflect.Descriptor instead.
+func (*PublishCRLResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *PublishCRLResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PublishCRLResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PublishCRLResponse) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *PublishCRLResponse) GetRevokedCount() int32 {
+	if x != nil {
+		return x.RevokedCount
+	}
+	return 0
+}
+
+func (x *PublishCRLResponse) GetPartitions() int32 {
+	if x != nil {
+		return x.Partitions
+	}
+	return 0
+}
+
+func (x *PublishCRLResponse) GetTargets() []*PublishTargetResult {
+	if x != nil {
+		return x.Targets
+	}
+	return nil
+}
+
+type PublishTargetResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Publisher     string                 `protobuf:"bytes,1,opt,name=publisher,proto3" json:"publisher,omitempty"`
+	Partition     int32                  `protobuf:"varint,2,opt,name=partition,proto3" json:"partition,omitempty"`
+	Success       bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // Set when success is false
+	Duration      *durationpb.Duration   `protobuf:"bytes,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishTargetResult) Reset() {
+	*x = PublishTargetResult{}
+	mi := &file_crl_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishTargetResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishTargetResult) ProtoMessage() {}
+
+func (x *PublishTargetResult) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishTargetResult.ProtoReflect.Descriptor instead.
+func (*PublishTargetResult) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PublishTargetResult) GetPublisher() string {
+	if x != nil {
+		return x.Publisher
+	}
+	return ""
+}
+
+func (x *PublishTargetResult) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *PublishTargetResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PublishTargetResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PublishTargetResult) GetDuration() *durationpb.Duration {
+	if x != nil {
+		return x.Duration
+	}
+	return nil
+}
+
+type HoldCertificateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumber  string                 `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	HeldAt        *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=held_at,json=heldAt,proto3" json:"held_at,omitempty"`       // Defaults to now
+	IssuerId      string                 `protobuf:"bytes,3,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"` // Defaults to the default issuer
+	NotAfter      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"` // As in AddRevocationRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HoldCertificateRequest) Reset() {
+	*x = HoldCertificateRequest{}
+	mi := &file_crl_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoldCertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldCertificateRequest) ProtoMessage() {}
+
+func (x *HoldCertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldCertificateRequest.ProtoReflect.Descriptor instead.
+func (*HoldCertificateRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HoldCertificateRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *HoldCertificateRequest) GetHeldAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.HeldAt
+	}
+	return nil
+}
+
+func (x *HoldCertificateRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *HoldCertificateRequest) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+type HoldCertificateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HoldCertificateResponse) Reset() {
+	*x = HoldCertificateResponse{}
+	mi := &file_crl_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoldCertificateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldCertificateResponse) ProtoMessage() {}
+
+func (x *HoldCertificateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldCertificateResponse.ProtoReflect.Descriptor instead.
+func (*HoldCertificateResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *HoldCertificateResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *HoldCertificateResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type ReleaseHoldRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumber  string                 `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	IssuerId      string                 `protobuf:"bytes,2,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"` // Defaults to the default issuer
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseHoldRequest) Reset() {
+	*x = ReleaseHoldRequest{}
+	mi := &file_crl_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseHoldRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseHoldRequest) ProtoMessage() {}
+
+func (x *ReleaseHoldRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseHoldRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseHoldRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReleaseHoldRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *ReleaseHoldRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+type ReleaseHoldResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseHoldResponse) Reset() {
+	*x = ReleaseHoldResponse{}
+	mi := &file_crl_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseHoldResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseHoldResponse) ProtoMessage() {}
+
+func (x *ReleaseHoldResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseHoldResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseHoldResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReleaseHoldResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ReleaseHoldResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DeleteRevocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumber  string                 `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	IssuerId      string                 `protobuf:"bytes,2,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`    // Defaults to the default issuer
+	RemovedBy     string                 `protobuf:"bytes,3,opt,name=removed_by,json=removedBy,proto3" json:"removed_by,omitempty"` // Operator responsible for the removal
+	Justification string                 `protobuf:"bytes,4,opt,name=justification,proto3" json:"justification,omitempty"`          // Why the revocation was erroneous
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRevocationRequest) Reset() {
+	*x = DeleteRevocationRequest{}
+	mi := &file_crl_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRevocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRevocationRequest) ProtoMessage() {}
+
+func (x *DeleteRevocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRevocationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRevocationRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DeleteRevocationRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *DeleteRevocationRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *DeleteRevocationRequest) GetRemovedBy() string {
+	if x != nil {
+		return x.RemovedBy
+	}
+	return ""
+}
+
+func (x *DeleteRevocationRequest) GetJustification() string {
+	if x != nil {
+		return x.Justification
+	}
+	return ""
+}
+
+type DeleteRevocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRevocationResponse) Reset() {
+	*x = DeleteRevocationResponse{}
+	mi := &file_crl_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRevocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRevocationResponse) ProtoMessage() {}
+
+func (x *DeleteRevocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRevocationResponse.ProtoReflect.Descriptor instead.
+func (*DeleteRevocationResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteRevocationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteRevocationResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type CheckRevocationStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumber  string                 `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"` // Hex; case and leading zeros are ignored
+	IssuerId      string                 `protobuf:"bytes,2,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`             // Defaults to the default issuer
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRevocationStatusRequest) Reset() {
+	*x = CheckRevocationStatusRequest{}
+	mi := &file_crl_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRevocationStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRevocationStatusRequest) ProtoMessage() {}
+
+func (x *CheckRevocationStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRevocationStatusRequest.ProtoReflect.Descriptor instead.
+func (*CheckRevocationStatusRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CheckRevocationStatusRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *CheckRevocationStatusRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+type CheckRevocationStatusResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Revoked           bool                   `protobuf:"varint,1,opt,name=revoked,proto3" json:"revoked,omitempty"` // Also true while the certificate is on hold
+	Reason            string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`    // RFC 5280 reason name; certificateHold while on hold
+	RevokedAt         *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	OnHold            bool                   `protobuf:"varint,4,opt,name=on_hold,json=onHold,proto3" json:"on_hold,omitempty"`
+	InvalidityDate    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=invalidity_date,json=invalidityDate,proto3" json:"invalidity_date,omitempty"`
+	CertificateIssuer []byte                 `protobuf:"bytes,6,opt,name=certificate_issuer,json=certificateIssuer,proto3" json:"certificate_issuer,omitempty"` // Set for entries of another CA on an indirect CRL
+	// Set while a scheduled revocation is pending; revoked is false until then
+	EffectiveAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=effective_at,json=effectiveAt,proto3" json:"effective_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRevocationStatusResponse) Reset() {
+	*x = CheckRevocationStatusResponse{}
+	mi := &file_crl_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRevocationStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRevocationStatusResponse) ProtoMessage() {}
+
+func (x *CheckRevocationStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRevocationStatusResponse.ProtoReflect.Descriptor instead.
+func (*CheckRevocationStatusResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CheckRevocationStatusResponse) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+func (x *CheckRevocationStatusResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CheckRevocationStatusResponse) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *CheckRevocationStatusResponse) GetOnHold() bool {
+	if x != nil {
+		return x.OnHold
+	}
+	return false
+}
+
+func (x *CheckRevocationStatusResponse) GetInvalidityDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InvalidityDate
+	}
+	return nil
+}
+
+func (x *CheckRevocationStatusResponse) GetCertificateIssuer() []byte {
+	if x != nil {
+		return x.CertificateIssuer
+	}
+	return nil
+}
+
+func (x *CheckRevocationStatusResponse) GetEffectiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveAt
+	}
+	return nil
+}
+
+type CheckStatusBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumbers []string               `protobuf:"bytes,1,rep,name=serial_numbers,json=serialNumbers,proto3" json:"serial_numbers,omitempty"` // At most 1000
+	IssuerId      string                 `protobuf:"bytes,2,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`                // Defaults to the default issuer
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckStatusBatchRequest) Reset() {
+	*x = CheckStatusBatchRequest{}
+	mi := &file_crl_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckStatusBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckStatusBatchRequest) ProtoMessage() {}
+
+func (x *CheckStatusBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckStatusBatchRequest.ProtoReflect.Descriptor instead.
+func (*CheckStatusBatchRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CheckStatusBatchRequest) GetSerialNumbers() []string {
+	if x != nil {
+		return x.SerialNumbers
+	}
+	return nil
+}
+
+func (x *CheckStatusBatchRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+type CheckStatusBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []*SerialStatus        `protobuf:"bytes,1,rep,name=statuses,proto3" json:"statuses,omitempty"` // In request order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckStatusBatchResponse) Reset() {
+	*x = CheckStatusBatchResponse{}
+	mi := &file_crl_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckStatusBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckStatusBatchResponse) ProtoMessage() {}
+
+func (x *CheckStatusBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckStatusBatchResponse.ProtoReflect.Descriptor instead.
+func (*CheckStatusBatchResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CheckStatusBatchResponse) GetStatuses() []*SerialStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+type SerialStatus struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	SerialNumber  string                         `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Status        *CheckRevocationStatusResponse `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SerialStatus) Reset() {
+	*x = SerialStatus{}
+	mi := &file_crl_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SerialStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SerialStatus) ProtoMessage() {}
+
+func (x *SerialStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SerialStatus.ProtoReflect.Descriptor instead.
+func (*SerialStatus) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SerialStatus) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *SerialStatus) GetStatus() *CheckRevocationStatusResponse {
+	if x != nil {
+		return x.Status
+	}
+	return nil
+}
+
+type WatchRevocationsRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	IssuerId   string                 `protobuf:"bytes,1,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`        // Defaults to the default issuer
+	AllIssuers bool                   `protobuf:"varint,2,opt,name=all_issuers,json=allIssuers,proto3" json:"all_issuers,omitempty"` // Watch every issuer; issuer_id is ignored
+	// Resume after this sequence number; 0 starts with the next change
+	AfterSequence int64 `protobuf:"varint,3,opt,name=after_sequence,json=afterSequence,proto3" json:"after_sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRevocationsRequest) Reset() {
+	*x = WatchRevocationsRequest{}
+	mi := &file_crl_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRevocationsRequest) ProtoMessage() {}
+
+func (x *WatchRevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRevocationsRequest.ProtoReflect.Descriptor instead.
+func (*WatchRevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WatchRevocationsRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *WatchRevocationsRequest) GetAllIssuers() bool {
+	if x != nil {
+		return x.AllIssuers
+	}
+	return false
+}
+
+func (x *WatchRevocationsRequest) GetAfterSequence() int64 {
+	if x != nil {
+		return x.AfterSequence
+	}
+	return 0
+}
+
+type RevocationEvent struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Sequence     int64                  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"` // Increases with every change; pass as after_sequence to resume
+	IssuerId     string                 `protobuf:"bytes,2,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`
+	SerialNumber string                 `protobuf:"bytes,3,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	// Entry status after the change: revoked, on_hold, released, removed,
+	// deleted or archived
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Reason            string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	RevokedAt         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	InvalidityDate    *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=invalidity_date,json=invalidityDate,proto3" json:"invalidity_date,omitempty"`
+	CertificateIssuer []byte                 `protobuf:"bytes,8,opt,name=certificate_issuer,json=certificateIssuer,proto3" json:"certificate_issuer,omitempty"`
+	RecordedAt        *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
+	EffectiveAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=effective_at,json=effectiveAt,proto3" json:"effective_at,omitempty"` // Set for scheduled revocations
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RevocationEvent) Reset() {
+	*x = RevocationEvent{}
+	mi := &file_crl_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevocationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevocationEvent) ProtoMessage() {}
+
+func (x *RevocationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevocationEvent.ProtoReflect.Descriptor instead.
+func (*RevocationEvent) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RevocationEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *RevocationEvent) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *RevocationEvent) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *RevocationEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RevocationEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RevocationEvent) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *RevocationEvent) GetInvalidityDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InvalidityDate
+	}
+	return nil
+}
+
+func (x *RevocationEvent) GetCertificateIssuer() []byte {
+	if x != nil {
+		return x.CertificateIssuer
+	}
+	return nil
+}
+
+func (x *RevocationEvent) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+func (x *RevocationEvent) GetEffectiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveAt
+	}
+	return nil
+}
+
+type ListRevocationsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IssuerId        string                 `protobuf:"bytes,1,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`                       // Defaults to the default issuer
+	Reasons         []string               `protobuf:"bytes,2,rep,name=reasons,proto3" json:"reasons,omitempty"`                                         // RFC 5280 reason names; empty matches all
+	RevokedAfter    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=revoked_after,json=revokedAfter,proto3" json:"revoked_after,omitempty"`           // Inclusive
+	RevokedBefore   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=revoked_before,json=revokedBefore,proto3" json:"revoked_before,omitempty"`        // Exclusive
+	IncludeReleased bool                   `protobuf:"varint,5,opt,name=include_released,json=includeReleased,proto3" json:"include_released,omitempty"` // Also list released holds and removed revocations
+	PageSize        int32                  `protobuf:"varint,6,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`                      // Defaults to 100, at most 1000
+	PageToken       string                 `protobuf:"bytes,7,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`                    // next_page_token of the previous page
+	IncludeArchived bool                   `protobuf:"varint,8,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"` // Also list entries of expired certificates moved to the archive
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListRevocationsRequest) Reset() {
+	*x = ListRevocationsRequest{}
+	mi := &file_crl_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRevocationsRequest) ProtoMessage() {}
+
+func (x *ListRevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRevocationsRequest.ProtoReflect.Descriptor instead.
+func (*ListRevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListRevocationsRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *ListRevocationsRequest) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *ListRevocationsRequest) GetRevokedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAfter
+	}
+	return nil
+}
+
+func (x *ListRevocationsRequest) GetRevokedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedBefore
+	}
+	return nil
+}
+
+func (x *ListRevocationsRequest) GetIncludeReleased() bool {
+	if x != nil {
+		return x.IncludeReleased
+	}
+	return false
+}
+
+func (x *ListRevocationsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListRevocationsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListRevocationsRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type ListRevocationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revocations   []*Revocation          `protobuf:"bytes,1,rep,name=revocations,proto3" json:"revocations,omitempty"`                            // Newest revocation first
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRevocationsResponse) Reset() {
+	*x = ListRevocationsResponse{}
+	mi := &file_crl_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRevocationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRevocationsResponse) ProtoMessage() {}
+
+func (x *ListRevocationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRevocationsResponse.ProtoReflect.Descriptor instead.
+func (*ListRevocationsResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListRevocationsResponse) GetRevocations() []*Revocation {
+	if x != nil {
+		return x.Revocations
+	}
+	return nil
+}
+
+func (x *ListRevocationsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type Revocation struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	IssuerId          string                 `protobuf:"bytes,1,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`
+	SerialNumber      string                 `protobuf:"bytes,2,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Reason            string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"` // revoked, on_hold, released or removed
+	RevokedAt         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	InvalidityDate    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=invalidity_date,json=invalidityDate,proto3" json:"invalidity_date,omitempty"`
+	CertificateIssuer []byte                 `protobuf:"bytes,7,opt,name=certificate_issuer,json=certificateIssuer,proto3" json:"certificate_issuer,omitempty"`
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // Last change of the entry
+	RemovedBy         string                 `protobuf:"bytes,9,opt,name=removed_by,json=removedBy,proto3" json:"removed_by,omitempty"` // Set when status is removed
+	RemovalReason     string                 `protobuf:"bytes,10,opt,name=removal_reason,json=removalReason,proto3" json:"removal_reason,omitempty"`
+	RemovedAt         *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=removed_at,json=removedAt,proto3" json:"removed_at,omitempty"`
+	EffectiveAt       *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=effective_at,json=effectiveAt,proto3" json:"effective_at,omitempty"` // Set for scheduled revocations
+	NotAfter          *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`          // Certificate expiry, if known
+	ArchivedAt        *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=archived_at,json=archivedAt,proto3" json:"archived_at,omitempty"`    // Set for archived entries
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Revocation) Reset() {
+	*x = Revocation{}
+	mi := &file_crl_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Revocation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Revocation) ProtoMessage() {}
+
+func (x *Revocation) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Revocation.ProtoReflect.Descriptor instead.
+func (*Revocation) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *Revocation) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *Revocation) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *Revocation) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Revocation) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Revocation) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *Revocation) GetInvalidityDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.InvalidityDate
+	}
+	return nil
+}
+
+func (x *Revocation) GetCertificateIssuer() []byte {
+	if x != nil {
+		return x.CertificateIssuer
+	}
+	return nil
+}
+
+func (x *Revocation) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Revocation) GetRemovedBy() string {
+	if x != nil {
+		return x.RemovedBy
+	}
+	return ""
+}
+
+func (x *Revocation) GetRemovalReason() string {
+	if x != nil {
+		return x.RemovalReason
+	}
+	return ""
+}
+
+func (x *Revocation) GetRemovedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RemovedAt
+	}
+	return nil
+}
+
+func (x *Revocation) GetEffectiveAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EffectiveAt
+	}
+	return nil
+}
+
+func (x *Revocation) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+func (x *Revocation) GetArchivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ArchivedAt
+	}
+	return nil
+}
+
+type ExportRevocationsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IssuerId        string                 `protobuf:"bytes,1,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"` // Defaults to the default issuer
+	Format          ExportFormat           `protobuf:"varint,2,opt,name=format,proto3,enum=gigvault.crl.v1.ExportFormat" json:"format,omitempty"`
+	Reasons         []string               `protobuf:"bytes,3,rep,name=reasons,proto3" json:"reasons,omitempty"`                                         // RFC 5280 reason names; empty matches all
+	RevokedAfter    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=revoked_after,json=revokedAfter,proto3" json:"revoked_after,omitempty"`           // Inclusive
+	RevokedBefore   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=revoked_before,json=revokedBefore,proto3" json:"revoked_before,omitempty"`        // Exclusive
+	IncludeReleased bool                   `protobuf:"varint,6,opt,name=include_released,json=includeReleased,proto3" json:"include_released,omitempty"` // Also export released holds and removed revocations
+	IncludeArchived bool                   `protobuf:"varint,7,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"` // Also export entries moved to the archive
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ExportRevocationsRequest) Reset() {
+	*x = ExportRevocationsRequest{}
+	mi := &file_crl_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRevocationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRevocationsRequest) ProtoMessage() {}
+
+func (x *ExportRevocationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRevocationsRequest.ProtoReflect.Descriptor instead.
+func (*ExportRevocationsRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ExportRevocationsRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *ExportRevocationsRequest) GetFormat() ExportFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ExportFormat_EXPORT_FORMAT_UNSPECIFIED
+}
+
+func (x *ExportRevocationsRequest) GetReasons() []string {
+	if x != nil {
+		return x.Reasons
+	}
+	return nil
+}
+
+func (x *ExportRevocationsRequest) GetRevokedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAfter
+	}
+	return nil
+}
+
+func (x *ExportRevocationsRequest) GetRevokedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedBefore
+	}
+	return nil
+}
+
+func (x *ExportRevocationsRequest) GetIncludeReleased() bool {
+	if x != nil {
+		return x.IncludeReleased
+	}
+	return false
+}
+
+func (x *ExportRevocationsRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type ExportRevocationsChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`                                  // Concatenate the chunks for the whole export
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // Set on the first chunk
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRevocationsChunk) Reset() {
+	*x = ExportRevocationsChunk{}
+	mi := &file_crl_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRevocationsChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRevocationsChunk) ProtoMessage() {}
+
+func (x *ExportRevocationsChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRevocationsChunk.ProtoReflect.Descriptor instead.
+func (*ExportRevocationsChunk) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ExportRevocationsChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ExportRevocationsChunk) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type GetRevocationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SerialNumber  string                 `protobuf:"bytes,1,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	IssuerId      string                 `protobuf:"bytes,2,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"` // Defaults to the default issuer
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRevocationRequest) Reset() {
+	*x = GetRevocationRequest{}
+	mi := &file_crl_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRevocationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRevocationRequest) ProtoMessage() {}
+
+func (x *GetRevocationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRevocationRequest.ProtoReflect.Descriptor instead.
+func (*GetRevocationRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetRevocationRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *GetRevocationRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+type GetRevocationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Revocation    *Revocation            `protobuf:"bytes,1,opt,name=revocation,proto3" json:"revocation,omitempty"`
+	History       []*RevocationEvent     `protobuf:"bytes,2,rep,name=history,proto3" json:"history,omitempty"` // Every change to the entry, oldest first
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRevocationResponse) Reset() {
+	*x = GetRevocationResponse{}
+	mi := &file_crl_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRevocationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRevocationResponse) ProtoMessage() {}
+
+func (x *GetRevocationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRevocationResponse.ProtoReflect.Descriptor instead.
+func (*GetRevocationResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *GetRevocationResponse) GetRevocation() *Revocation {
+	if x != nil {
+		return x.Revocation
+	}
+	return nil
+}
+
+func (x *GetRevocationResponse) GetHistory() []*RevocationEvent {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type QueryAuditLogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters; empty fields match all records
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"` // RPC name, e.g. AddRevocation
+	Actor         string                 `protobuf:"bytes,2,opt,name=actor,proto3" json:"actor,omitempty"`
+	IssuerId      string                 `protobuf:"bytes,3,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`
+	SerialNumber  string                 `protobuf:"bytes,4,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Since         *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=since,proto3" json:"since,omitempty"`
+	Until         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=until,proto3" json:"until,omitempty"`                        // Exclusive
+	PageSize      int32                  `protobuf:"varint,7,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"` // Defaults to 100, at most 1000
+	PageToken     string                 `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditLogRequest) Reset() {
+	*x = QueryAuditLogRequest{}
+	mi := &file_crl_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditLogRequest) ProtoMessage() {}
+
+func (x *QueryAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *QueryAuditLogRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *QueryAuditLogRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *QueryAuditLogRequest) GetUntil() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Until
+	}
+	return nil
+}
+
+func (x *QueryAuditLogRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *QueryAuditLogRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type QueryAuditLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*AuditRecord         `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // Empty on the last page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QueryAuditLogResponse) Reset() {
+	*x = QueryAuditLogResponse{}
+	mi := &file_crl_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryAuditLogResponse) ProtoMessage() {}
+
+func (x *QueryAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*QueryAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *QueryAuditLogResponse) GetRecords() []*AuditRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *QueryAuditLogResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type AuditRecord struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Id           int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OccurredAt   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	Action       string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	IssuerId     string                 `protobuf:"bytes,4,opt,name=issuer_id,json=issuerId,proto3" json:"issuer_id,omitempty"`
+	SerialNumber string                 `protobuf:"bytes,5,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	// Authenticated identity, or the one declared in the request when
+	// auth_method is empty
+	Actor         string `protobuf:"bytes,6,opt,name=actor,proto3" json:"actor,omitempty"`
+	AuthMethod    string `protobuf:"bytes,7,opt,name=auth_method,json=authMethod,proto3" json:"auth_method,omitempty"` // mtls, jwt, api_key or empty
+	SourceIp      string `protobuf:"bytes,8,opt,name=source_ip,json=sourceIp,proto3" json:"source_ip,omitempty"`
+	Code          string `protobuf:"bytes,9,opt,name=code,proto3" json:"code,omitempty"` // gRPC status code of the outcome, e.g. OK
+	Detail        string `protobuf:"bytes,10,opt,name=detail,proto3" json:"detail,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditRecord) Reset() {
+	*x = AuditRecord{}
+	mi := &file_crl_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditRecord) ProtoMessage() {}
+
+func (x *AuditRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditRecord.ProtoReflect.Descriptor instead.
+func (*AuditRecord) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *AuditRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditRecord) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *AuditRecord) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetIssuerId() string {
+	if x != nil {
+		return x.IssuerId
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetSerialNumber() string {
+	if x != nil {
+		return x.SerialNumber
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetAuthMethod() string {
+	if x != nil {
+		return x.AuthMethod
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetSourceIp() string {
+	if x != nil {
+		return x.SourceIp
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *AuditRecord) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type CreateAPIKeyRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Client    string                 `protobuf:"bytes,1,opt,name=client,proto3" json:"client,omitempty"`   // Identity of callers using the key
+	Methods   []string               `protobuf:"bytes,2,rep,name=methods,proto3" json:"methods,omitempty"` // RPC names the key may call, or "*"
+	Ttl       *durationpb.Duration   `protobuf:"bytes,3,opt,name=ttl,proto3" json:"ttl,omitempty"`         // Never expires when unset
+	CreatedBy string                 `protobuf:"bytes,4,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	// Roles of the key: reader, revoker, publisher, admin or a custom role
+	Roles         []string `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyRequest) Reset() {
+	*x = CreateAPIKeyRequest{}
+	mi := &file_crl_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyRequest) ProtoMessage() {}
+
+func (x *CreateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateAPIKeyRequest) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetMethods() []string {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *CreateAPIKeyRequest) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type CreateAPIKeyResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// The key to send as x-api-key metadata. It is returned only here and
+	// cannot be recovered later.
+	Key           string `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAPIKeyResponse) Reset() {
+	*x = CreateAPIKeyResponse{}
+	mi := &file_crl_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAPIKeyResponse) ProtoMessage() {}
+
+func (x *CreateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateAPIKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type RotateAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Ttl           *durationpb.Duration   `protobuf:"bytes,2,opt,name=ttl,proto3" json:"ttl,omitempty"` // Of the new key; never expires when unset
+	RotatedBy     string                 `protobuf:"bytes,3,opt,name=rotated_by,json=rotatedBy,proto3" json:"rotated_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyRequest) Reset() {
+	*x = RotateAPIKeyRequest{}
+	mi := &file_crl_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyRequest) ProtoMessage() {}
+
+func (x *RotateAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RotateAPIKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *RotateAPIKeyRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *RotateAPIKeyRequest) GetRotatedBy() string {
+	if x != nil {
+		return x.RotatedBy
+	}
+	return ""
+}
+
+type RotateAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"` // The new key
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Previous      *APIKey                `protobuf:"bytes,3,opt,name=previous,proto3" json:"previous,omitempty"` // The old key, expiring after the grace period
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateAPIKeyResponse) Reset() {
+	*x = RotateAPIKeyResponse{}
+	mi := &file_crl_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateAPIKeyResponse) ProtoMessage() {}
+
+func (x *RotateAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RotateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *RotateAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *RotateAPIKeyResponse) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RotateAPIKeyResponse) GetPrevious() *APIKey {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	RevokedBy     string                 `protobuf:"bytes,2,opt,name=revoked_by,json=revokedBy,proto3" json:"revoked_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyRequest) Reset() {
+	*x = RevokeAPIKeyRequest{}
+	mi := &file_crl_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyRequest) ProtoMessage() {}
+
+func (x *RevokeAPIKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyRequest.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *RevokeAPIKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *RevokeAPIKeyRequest) GetRevokedBy() string {
+	if x != nil {
+		return x.RevokedBy
+	}
+	return ""
+}
+
+type RevokeAPIKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey        *APIKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeAPIKeyResponse) Reset() {
+	*x = RevokeAPIKeyResponse{}
+	mi := &file_crl_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeAPIKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeAPIKeyResponse) ProtoMessage() {}
+
+func (x *RevokeAPIKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeAPIKeyResponse.ProtoReflect.Descriptor instead.
+func (*RevokeAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *RevokeAPIKeyResponse) GetApiKey() *APIKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+type APIKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeyId         string                 `protobuf:"bytes,1,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	Client        string                 `protobuf:"bytes,2,opt,name=client,proto3" json:"client,omitempty"`
+	Methods       []string               `protobuf:"bytes,3,rep,name=methods,proto3" json:"methods,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,4,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	RevokedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=revoked_at,json=revokedAt,proto3" json:"revoked_at,omitempty"`
+	RotatedFrom   string                 `protobuf:"bytes,8,opt,name=rotated_from,json=rotatedFrom,proto3" json:"rotated_from,omitempty"` // ID of the key this one replaced
+	Roles         []string               `protobuf:"bytes,9,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *APIKey) Reset() {
+	*x = APIKey{}
+	mi := &file_crl_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *APIKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*APIKey) ProtoMessage() {}
+
+func (x *APIKey) ProtoReflect() protoreflect.Message {
+	mi := &file_crl_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use APIKey.ProtoReflect.Descriptor instead.
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return file_crl_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *APIKey) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+func (x *APIKey) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *APIKey) GetMethods() []string {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+func (x *APIKey) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *APIKey) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetRevokedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RevokedAt
+	}
+	return nil
+}
+
+func (x *APIKey) GetRotatedFrom() string {
+	if x != nil {
+		return x.RotatedFrom
+	}
+	return ""
+}
+
+func (x *APIKey) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+var File_crl_proto protoreflect.FileDescriptor
+
+const file_crl_proto_rawDesc = "" +
+	"\n" +
+	"\tcrl.proto\x12\x0fgigvault.crl.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a.protoc-gen-openapiv2/options/annotations.proto\"\xe3\x03\n" +
+	"\x14AddRevocationRequest\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x129\n" +
+	"\n" +
+	"revoked_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12C\n" +
+	"\x0finvalidity_date\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x0einvalidityDate\x12-\n" +
+	"\x12certificate_issuer\x18\x05 \x01(\fR\x11certificateIssuer\x12\x1b\n" +
+	"\tissuer_id\x18\x06 \x01(\tR\bissuerId\x12=\n" +
+	"\feffective_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\veffectiveAt\x12!\n" +
+	"\frequested_by\x18\b \x01(\tR\vrequestedBy\x12'\n" +
+	"\x0fidempotency_key\x18\t \x01(\tR\x0eidempotencyKey\x127\n" +
+	"\tnot_after\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\bnotAfter\"\x97\x01\n" +
+	"\x15AddRevocationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12)\n" +
+	"\x10pending_approval\x18\x03 \x01(\bR\x0fpendingApproval\x12\x1f\n" +
+	"\vapproval_id\x18\x04 \x01(\x03R\n" +
+	"approvalId\"\xa1\x01\n" +
+	"\x15AddRevocationsRequest\x12G\n" +
+	"\vrevocations\x18\x01 \x03(\v2%.gigvault.crl.v1.AddRevocationRequestR\vrevocations\x12\x16\n" +
+	"\x06atomic\x18\x02 \x01(\bR\x06atomic\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"\x86\x01\n" +
+	"\x16AddRevocationsResponse\x12>\n" +
+	"\aresults\x18\x01 \x03(\v2$.gigvault.crl.v1.AddRevocationResultR\aresults\x12\x14\n" +
+	"\x05added\x18\x02 \x01(\x05R\x05added\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\"\xb5\x01\n" +
+	"\x13AddRevocationResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12#\n" +
+	"\rserial_number\x18\x02 \x01(\tR\fserialNumber\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\x05R\x04code\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x12\x1f\n" +
+	"\vapproval_id\x18\x06 \x01(\x03R\n" +
+	"approvalId\"\\\n" +
+	"\x18ApproveRevocationRequest\x12\x1f\n" +
+	"\vapproval_id\x18\x01 \x01(\x03R\n" +
+	"approvalId\x12\x1f\n" +
+	"\vapproved_by\x18\x02 \x01(\tR\n" +
+	"approvedBy\"O\n" +
+	"\x19ApproveRevocationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"s\n" +
+	"\x17RejectRevocationRequest\x12\x1f\n" +
+	"\vapproval_id\x18\x01 \x01(\x03R\n" +
+	"approvalId\x12\x1f\n" +
+	"\vrejected_by\x18\x02 \x01(\tR\n" +
+	"rejectedBy\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"N\n" +
+	"\x18RejectRevocationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"<\n" +
+	"\x1dListPendingRevocationsRequest\x12\x1b\n" +
+	"\tissuer_id\x18\x01 \x01(\tR\bissuerId\"^\n" +
+	"\x1eListPendingRevocationsResponse\x12<\n" +
+	"\apending\x18\x01 \x03(\v2\".gigvault.crl.v1.PendingRevocationR\apending\"\x8e\x02\n" +
+	"\x11PendingRevocation\x12\x1f\n" +
+	"\vapproval_id\x18\x01 \x01(\x03R\n" +
+	"approvalId\x12;\n" +
+	"\n" +
+	"revocation\x18\x02 \x01(\v2\x1b.gigvault.crl.v1.RevocationR\n" +
+	"revocation\x12!\n" +
+	"\frequested_by\x18\x03 \x01(\tR\vrequestedBy\x12=\n" +
+	"\frequested_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\vrequestedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xc6\x01\n" +
+	"\rGetCRLRequest\x12\x16\n" +
+	"\x06issuer\x18\x01 \x01(\tR\x06issuer\x12\x14\n" +
+	"\x05delta\x18\x02 \x01(\bR\x05delta\x12\x1c\n" +
+	"\tpartition\x18\x03 \x01(\x05R\tpartition\x122\n" +
+	"\x06format\x18\x04 \x01(\x0e2\x1a.gigvault.crl.v1.CRLFormatR\x06format\x12\x1d\n" +
+	"\n" +
+	"crl_number\x18\x05 \x01(\x03R\tcrlNumber\x12\x16\n" +
+	"\x06sha256\x18\x06 \x01(\fR\x06sha256\"\xc3\x03\n" +
+	"\x0eGetCRLResponse\x12\x17\n" +
+	"\acrl_der\x18\x01 \x01(\fR\x06crlDer\x12\x17\n" +
+	"\acrl_pem\x18\x02 \x01(\tR\x06crlPem\x12;\n" +
+	"\vthis_update\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"thisUpdate\x12;\n" +
+	"\vnext_update\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"nextUpdate\x12#\n" +
+	"\rrevoked_count\x18\x05 \x01(\x05R\frevokedCount\x12\x1d\n" +
+	"\n" +
+	"crl_number\x18\x06 \x01(\x03R\tcrlNumber\x12&\n" +
+	"\x0fbase_crl_number\x18\a \x01(\x03R\rbaseCrlNumber\x12\x1c\n" +
+	"\tpartition\x18\b \x01(\x05R\tpartition\x12\x19\n" +
+	"\bcrl_data\x18\t \x01(\fR\acrlData\x122\n" +
+	"\x06format\x18\n" +
+	" \x01(\x0e2\x1a.gigvault.crl.v1.CRLFormatR\x06format\x12\x16\n" +
+	"\x06sha256\x18\v \x01(\fR\x06sha256\x12\x14\n" +
+	"\x05stale\x18\f \x01(\bR\x05stale\"\xbe\x03\n" +
+	"\vGetCRLChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x1d\n" +
+	"\n" +
+	"total_size\x18\x03 \x01(\x03R\ttotalSize\x122\n" +
+	"\x06format\x18\x04 \x01(\x0e2\x1a.gigvault.crl.v1.CRLFormatR\x06format\x12;\n" +
+	"\vthis_update\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"thisUpdate\x12;\n" +
+	"\vnext_update\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"nextUpdate\x12#\n" +
+	"\rrevoked_count\x18\a \x01(\x05R\frevokedCount\x12\x1d\n" +
+	"\n" +
+	"crl_number\x18\b \x01(\x03R\tcrlNumber\x12&\n" +
+	"\x0fbase_crl_number\x18\t \x01(\x03R\rbaseCrlNumber\x12\x1c\n" +
+	"\tpartition\x18\n" +
+	" \x01(\x05R\tpartition\x12\x16\n" +
+	"\x06sha256\x18\v \x01(\fR\x06sha256\x12\x14\n" +
+	"\x05stale\x18\f \x01(\bR\x05stale\"\xb2\x01\n" +
+	"\x11PublishCRLRequest\x12\x14\n" +
+	"\x05force\x18\x01 \x01(\bR\x05force\x12\x1b\n" +
+	"\tissuer_id\x18\x02 \x01(\tR\bissuerId\x125\n" +
+	"\bvalidity\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\bvalidity\x123\n" +
+	"\aoverlap\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\aoverlap\"\x8c\x02\n" +
+	"\x12PublishCRLResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12=\n" +
+	"\fpublished_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt\x12#\n" +
+	"\rrevoked_count\x18\x04 \x01(\x05R\frevokedCount\x12\x1e\n" +
+	"\n" +
+	"partitions\x18\x05 \x01(\x05R\n" +
+	"partitions\x12>\n" +
+	"\atargets\x18\x06 \x03(\v2$.gigvault.crl.v1.PublishTargetResultR\atargets\"\xb8\x01\n" +
+	"\x13PublishTargetResult\x12\x1c\n" +
+	"\tpublisher\x18\x01 \x01(\tR\tpublisher\x12\x1c\n" +
+	"\tpartition\x18\x02 \x01(\x05R\tpartition\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x125\n" +
+	"\bduration\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\bduration\"\xc8\x01\n" +
+	"\x16HoldCertificateRequest\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x123\n" +
+	"\aheld_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x06heldAt\x12\x1b\n" +
+	"\tissuer_id\x18\x03 \x01(\tR\bissuerId\x127\n" +
+	"\tnot_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bnotAfter\"M\n" +
+	"\x17HoldCertificateResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"V\n" +
+	"\x12ReleaseHoldRequest\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x12\x1b\n" +
+	"\tissuer_id\x18\x02 \x01(\tR\bissuerId\"I\n" +
+	"\x13ReleaseHoldResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xa0\x01\n" +
+	"\x17DeleteRevocationRequest\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x12\x1b\n" +
+	"\tissuer_id\x18\x02 \x01(\tR\bissuerId\x12\x1d\n" +
+	"\n" +
+	"removed_by\x18\x03 \x01(\tR\tremovedBy\x12$\n" +
+	"\rjustification\x18\x04 \x01(\tR\rjustification\"N\n" +
+	"\x18DeleteRevocationResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"`\n" +
+	"\x1cCheckRevocationStatusRequest\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x12\x1b\n" +
+	"\tissuer_id\x18\x02 \x01(\tR\bissuerId\"\xd8\x02\n" +
+	"\x1dCheckRevocationStatusResponse\x12\x18\n" +
+	"\arevoked\x18\x01 \x01(\bR\arevoked\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"revoked_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x12\x17\n" +
+	"\aon_hold\x18\x04 \x01(\bR\x06onHold\x12C\n" +
+	"\x0finvalidity_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x0einvalidityDate\x12-\n" +
+	"\x12certificate_issuer\x18\x06 \x01(\fR\x11certificateIssuer\x12=\n" +
+	"\feffective_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\veffectiveAt\"]\n" +
+	"\x17CheckStatusBatchRequest\x12%\n" +
+	"\x0eserial_numbers\x18\x01 \x03(\tR\rserialNumbers\x12\x1b\n" +
+	"\tissuer_id\x18\x02 \x01(\tR\bissuerId\"U\n" +
+	"\x18CheckStatusBatchResponse\x129\n" +
+	"\bstatuses\x18\x01 \x03(\v2\x1d.gigvault.crl.v1.SerialStatusR\bstatuses\"{\n" +
+	"\fSerialStatus\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x12F\n" +
+	"\x06status\x18\x02 \x01(\v2..gigvault.crl.v1.CheckRevocationStatusResponseR\x06status\"~\n" +
+	"\x17WatchRevocationsRequest\x12\x1b\n" +
+	"\tissuer_id\x18\x01 \x01(\tR\bissuerId\x12\x1f\n" +
+	"\vall_issuers\x18\x02 \x01(\bR\n" +
+	"allIssuers\x12%\n" +
+	"\x0eafter_sequence\x18\x03 \x01(\x03R\rafterSequence\"\xca\x03\n" +
+	"\x0fRevocationEvent\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\x1b\n" +
+	"\tissuer_id\x18\x02 \x01(\tR\bissuerId\x12#\n" +
+	"\rserial_number\x18\x03 \x01(\tR\fserialNumber\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"revoked_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x12C\n" +
+	"\x0finvalidity_date\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x0einvalidityDate\x12-\n" +
+	"\x12certificate_issuer\x18\b \x01(\fR\x11certificateIssuer\x12;\n" +
+	"\vrecorded_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordedAt\x12=\n" +
+	"\feffective_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\veffectiveAt\"\xe5\x02\n" +
+	"\x16ListRevocationsRequest\x12\x1b\n" +
+	"\tissuer_id\x18\x01 \x01(\tR\bissuerId\x12\x18\n" +
+	"\areasons\x18\x02 \x03(\tR\areasons\x12?\n" +
+	"\rrevoked_after\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\frevokedAfter\x12A\n" +
+	"\x0erevoked_before\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rrevokedBefore\x12)\n" +
+	"\x10include_released\x18\x05 \x01(\bR\x0fincludeReleased\x12\x1b\n" +
+	"\tpage_size\x18\x06 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\a \x01(\tR\tpageToken\x12)\n" +
+	"\x10include_archived\x18\b \x01(\bR\x0fincludeArchived\"\x80\x01\n" +
+	"\x17ListRevocationsResponse\x12=\n" +
+	"\vrevocations\x18\x01 \x03(\v2\x1b.gigvault.crl.v1.RevocationR\vrevocations\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x9e\x05\n" +
+	"\n" +
+	"Revocation\x12\x1b\n" +
+	"\tissuer_id\x18\x01 \x01(\tR\bissuerId\x12#\n" +
+	"\rserial_number\x18\x02 \x01(\tR\fserialNumber\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"revoked_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x12C\n" +
+	"\x0finvalidity_date\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x0einvalidityDate\x12-\n" +
+	"\x12certificate_issuer\x18\a \x01(\fR\x11certificateIssuer\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"removed_by\x18\t \x01(\tR\tremovedBy\x12%\n" +
+	"\x0eremoval_reason\x18\n" +
+	" \x01(\tR\rremovalReason\x129\n" +
+	"\n" +
+	"removed_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tremovedAt\x12=\n" +
+	"\feffective_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\veffectiveAt\x127\n" +
+	"\tnot_after\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\bnotAfter\x12;\n" +
+	"\varchived_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"archivedAt\"\xe2\x02\n" +
+	"\x18ExportRevocationsRequest\x12\x1b\n" +
+	"\tissuer_id\x18\x01 \x01(\tR\bissuerId\x125\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x1d.gigvault.crl.v1.ExportFormatR\x06format\x12\x18\n" +
+	"\areasons\x18\x03 \x03(\tR\areasons\x12?\n" +
+	"\rrevoked_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\frevokedAfter\x12A\n" +
+	"\x0erevoked_before\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rrevokedBefore\x12)\n" +
+	"\x10include_released\x18\x06 \x01(\bR\x0fincludeReleased\x12)\n" +
+	"\x10include_archived\x18\a \x01(\bR\x0fincludeArchived\"O\n" +
+	"\x16ExportRevocationsChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\"X\n" +
+	"\x14GetRevocationRequest\x12#\n" +
+	"\rserial_number\x18\x01 \x01(\tR\fserialNumber\x12\x1b\n" +
+	"\tissuer_id\x18\x02 \x01(\tR\bissuerId\"\x90\x01\n" +
+	"\x15GetRevocationResponse\x12;\n" +
+	"\n" +
+	"revocation\x18\x01 \x01(\v2\x1b.gigvault.crl.v1.RevocationR\n" +
+	"revocation\x12:\n" +
+	"\ahistory\x18\x02 \x03(\v2 .gigvault.crl.v1.RevocationEventR\ahistory\"\xa6\x02\n" +
+	"\x14QueryAuditLogRequest\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x14\n" +
+	"\x05actor\x18\x02 \x01(\tR\x05actor\x12\x1b\n" +
+	"\tissuer_id\x18\x03 \x01(\tR\bissuerId\x12#\n" +
+	"\rserial_number\x18\x04 \x01(\tR\fserialNumber\x120\n" +
+	"\x05since\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x120\n" +
+	"\x05until\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x05until\x12\x1b\n" +
+	"\tpage_size\x18\a \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tR\tpageToken\"w\n" +
+	"\x15QueryAuditLogResponse\x126\n" +
+	"\arecords\x18\x01 \x03(\v2\x1c.gigvault.crl.v1.AuditRecordR\arecords\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xb4\x02\n" +
+	"\vAuditRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12;\n" +
+	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1b\n" +
+	"\tissuer_id\x18\x04 \x01(\tR\bissuerId\x12#\n" +
+	"\rserial_number\x18\x05 \x01(\tR\fserialNumber\x12\x14\n" +
+	"\x05actor\x18\x06 \x01(\tR\x05actor\x12\x1f\n" +
+	"\vauth_method\x18\a \x01(\tR\n" +
+	"authMethod\x12\x1b\n" +
+	"\tsource_ip\x18\b \x01(\tR\bsourceIp\x12\x12\n" +
+	"\x04code\x18\t \x01(\tR\x04code\x12\x16\n" +
+	"\x06detail\x18\n" +
+	" \x01(\tR\x06detail\"\xa9\x01\n" +
+	"\x13CreateAPIKeyRequest\x12\x16\n" +
+	"\x06client\x18\x01 \x01(\tR\x06client\x12\x18\n" +
+	"\amethods\x18\x02 \x03(\tR\amethods\x12+\n" +
+	"\x03ttl\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x04 \x01(\tR\tcreatedBy\x12\x14\n" +
+	"\x05roles\x18\x05 \x03(\tR\x05roles\"Z\n" +
+	"\x14CreateAPIKeyResponse\x120\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x17.gigvault.crl.v1.APIKeyR\x06apiKey\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"x\n" +
+	"\x13RotateAPIKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12+\n" +
+	"\x03ttl\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x12\x1d\n" +
+	"\n" +
+	"rotated_by\x18\x03 \x01(\tR\trotatedBy\"\x8f\x01\n" +
+	"\x14RotateAPIKeyResponse\x120\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x17.gigvault.crl.v1.APIKeyR\x06apiKey\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x123\n" +
+	"\bprevious\x18\x03 \x01(\v2\x17.gigvault.crl.v1.APIKeyR\bprevious\"K\n" +
+	"\x13RevokeAPIKeyRequest\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x1d\n" +
+	"\n" +
+	"revoked_by\x18\x02 \x01(\tR\trevokedBy\"H\n" +
+	"\x14RevokeAPIKeyResponse\x120\n" +
+	"\aapi_key\x18\x01 \x01(\v2\x17.gigvault.crl.v1.APIKeyR\x06apiKey\"\xda\x02\n" +
+	"\x06APIKey\x12\x15\n" +
+	"\x06key_id\x18\x01 \x01(\tR\x05keyId\x12\x16\n" +
+	"\x06client\x18\x02 \x01(\tR\x06client\x12\x18\n" +
+	"\amethods\x18\x03 \x03(\tR\amethods\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x04 \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"revoked_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\trevokedAt\x12!\n" +
+	"\frotated_from\x18\b \x01(\tR\vrotatedFrom\x12\x14\n" +
+	"\x05roles\x18\t \x03(\tR\x05roles*O\n" +
+	"\tCRLFormat\x12\x1a\n" +
+	"\x16CRL_FORMAT_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eCRL_FORMAT_DER\x10\x01\x12\x12\n" +
+	"\x0eCRL_FORMAT_PEM\x10\x02*\\\n" +
+	"\fExportFormat\x12\x1d\n" +
+	"\x19EXPORT_FORMAT_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12EXPORT_FORMAT_JSON\x10\x01\x12\x15\n" +
+	"\x11EXPORT_FORMAT_CSV\x10\x022\xb0\x13\n" +
+	"\n" +
+	"CRLService\x12z\n" +
+	"\rAddRevocation\x12%.gigvault.crl.v1.AddRevocationRequest\x1a&.gigvault.crl.v1.AddRevocationResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/revocations\x12\x86\x01\n" +
+	"\x0eAddRevocations\x12&.gigvault.crl.v1.AddRevocationsRequest\x1a'.gigvault.crl.v1.AddRevocationsResponse\"#\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/revocations:batchAdd\x12j\n" +
+	"\x11ApproveRevocation\x12).gigvault.crl.v1.ApproveRevocationRequest\x1a*.gigvault.crl.v1.ApproveRevocationResponse\x12g\n" +
+	"\x10RejectRevocation\x12(.gigvault.crl.v1.RejectRevocationRequest\x1a).gigvault.crl.v1.RejectRevocationResponse\x12y\n" +
+	"\x16ListPendingRevocations\x12..gigvault.crl.v1.ListPendingRevocationsRequest\x1a/.gigvault.crl.v1.ListPendingRevocationsResponse\x12^\n" +
+	"\rQueryAuditLog\x12%.gigvault.crl.v1.QueryAuditLogRequest\x1a&.gigvault.crl.v1.QueryAuditLogResponse\x12[\n" +
+	"\fCreateAPIKey\x12$.gigvault.crl.v1.CreateAPIKeyRequest\x1a%.gigvault.crl.v1.CreateAPIKeyResponse\x12[\n" +
+	"\fRotateAPIKey\x12$.gigvault.crl.v1.RotateAPIKeyRequest\x1a%.gigvault.crl.v1.RotateAPIKeyResponse\x12[\n" +
+	"\fRevokeAPIKey\x12$.gigvault.crl.v1.RevokeAPIKeyRequest\x1a%.gigvault.crl.v1.RevokeAPIKeyResponse\x12Z\n" +
+	"\x06GetCRL\x12\x1e.gigvault.crl.v1.GetCRLRequest\x1a\x1f.gigvault.crl.v1.GetCRLResponse\"\x0f\x82\xd3\xe4\x93\x02\t\x12\a/v1/crl\x12N\n" +
+	"\fGetCRLStream\x12\x1e.gigvault.crl.v1.GetCRLRequest\x1a\x1c.gigvault.crl.v1.GetCRLChunk0\x01\x12q\n" +
+	"\n" +
+	"PublishCRL\x12\".gigvault.crl.v1.PublishCRLRequest\x1a#.gigvault.crl.v1.PublishCRLResponse\"\x1a\x82\xd3\xe4\x93\x02\x14:\x01*\"\x0f/v1/crl:publish\x12\x95\x01\n" +
+	"\x0fHoldCertificate\x12'.gigvault.crl.v1.HoldCertificateRequest\x1a(.gigvault.crl.v1.HoldCertificateResponse\"/\x82\xd3\xe4\x93\x02):\x01*\"$/v1/revocations/{serial_number}:hold\x12\x8c\x01\n" +
+	"\vReleaseHold\x12#.gigvault.crl.v1.ReleaseHoldRequest\x1a$.gigvault.crl.v1.ReleaseHoldResponse\"2\x82\xd3\xe4\x93\x02,:\x01*\"'/v1/revocations/{serial_number}:release\x12\x90\x01\n" +
+	"\x10DeleteRevocation\x12(.gigvault.crl.v1.DeleteRevocationRequest\x1a).gigvault.crl.v1.DeleteRevocationResponse\"'\x82\xd3\xe4\x93\x02!*\x1f/v1/revocations/{serial_number}\x12\x9a\x01\n" +
+	"\x15CheckRevocationStatus\x12-.gigvault.crl.v1.CheckRevocationStatusRequest\x1a..gigvault.crl.v1.CheckRevocationStatusResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/status/{serial_number}\x12\x89\x01\n" +
+	"\x10CheckStatusBatch\x12(.gigvault.crl.v1.CheckStatusBatchRequest\x1a).gigvault.crl.v1.CheckStatusBatchResponse\" \x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/status:batchCheck\x12`\n" +
+	"\x10WatchRevocations\x12(.gigvault.crl.v1.WatchRevocationsRequest\x1a .gigvault.crl.v1.RevocationEvent0\x01\x12}\n" +
+	"\x0fListRevocations\x12'.gigvault.crl.v1.ListRevocationsRequest\x1a(.gigvault.crl.v1.ListRevocationsResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/v1/revocations\x12\x87\x01\n" +
+	"\rGetRevocation\x12%.gigvault.crl.v1.GetRevocationRequest\x1a&.gigvault.crl.v1.GetRevocationResponse\"'\x82\xd3\xe4\x93\x02!\x12\x1f/v1/revocations/{serial_number}\x12i\n" +
+	"\x11ExportRevocations\x12).gigvault.crl.v1.ExportRevocationsRequest\x1a'.gigvault.crl.v1.ExportRevocationsChunk0\x01B\xe8\x01\x92A\xbd\x01\x12\x1a\n" +
+	"\x14GigVault CRL service2\x02v12\x10application/json:\x10application/jsonZ_\n" +
+	"\x19\n" +
+	"\x06ApiKey\x12\x0f\b\x02\x1a\tx-api-key \x02\n" +
+	"B\n" +
+	"\x06Bearer\x128\b\x02\x12#OIDC access token as Bearer <token>\x1a\rAuthorization \x02b\f\n" +
+	"\n" +
+	"\n" +
+	"\x06ApiKey\x12\x00b\f\n" +
+	"\n" +
+	"\n" +
+	"\x06Bearer\x12\x00Z%github.com/gigvault/crl/api/proto/crlb\x06proto3"
+
+var (
+	file_crl_proto_rawDescOnce sync.Once
+	file_crl_proto_rawDescData []byte
+)
+
+func file_crl_proto_rawDescGZIP() []byte {
+	file_crl_proto_rawDescOnce.Do(func() {
+		file_crl_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_crl_proto_rawDesc), len(file_crl_proto_rawDesc)))
+	})
+	return file_crl_proto_rawDescData
+}
+
+var file_crl_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_crl_proto_msgTypes = make([]protoimpl.MessageInfo, 48)
+var file_crl_proto_goTypes = []any{
+	(CRLFormat)(0),                         // 0: gigvault.crl.v1.CRLFormat
+	(ExportFormat)(0),                      // 1: gigvault.crl.v1.ExportFormat
+	(*AddRevocationRequest)(nil),           // 2: gigvault.crl.v1.AddRevocationRequest
+	(*AddRevocationResponse)(nil),          // 3: gigvault.crl.v1.AddRevocationResponse
+	(*AddRevocationsRequest)(nil),          // 4: gigvault.crl.v1.AddRevocationsRequest
+	(*AddRevocationsResponse)(nil),         // 5: gigvault.crl.v1.AddRevocationsResponse
+	(*AddRevocationResult)(nil),            // 6: gigvault.crl.v1.AddRevocationResult
+	(*ApproveRevocationRequest)(nil),       // 7: gigvault.crl.v1.ApproveRevocationRequest
+	(*ApproveRevocationResponse)(nil),      // 8: gigvault.crl.v1.ApproveRevocationResponse
+	(*RejectRevocationRequest)(nil),        // 9: gigvault.crl.v1.RejectRevocationRequest
+	(*RejectRevocationResponse)(nil),       // 10: gigvault.crl.v1.RejectRevocationResponse
+	(*ListPendingRevocationsRequest)(nil),  // 11: gigvault.crl.v1.ListPendingRevocationsRequest
+	(*ListPendingRevocationsResponse)(nil), // 12: gigvault.crl.v1.ListPendingRevocationsResponse
+	(*PendingRevocation)(nil),              // 13: gigvault.crl.v1.PendingRevocation
+	(*GetCRLRequest)(nil),                  // 14: gigvault.crl.v1.GetCRLRequest
+	(*GetCRLResponse)(nil),                 // 15: gigvault.crl.v1.GetCRLResponse
+	(*GetCRLChunk)(nil),                    // 16: gigvault.crl.v1.GetCRLChunk
+	(*PublishCRLRequest)(nil),              // 17: gigvault.crl.v1.PublishCRLRequest
+	(*PublishCRLResponse)(nil),             // 18: gigvault.crl.v1.PublishCRLResponse
+	(*PublishTargetResult)(nil),            // 19: gigvault.crl.v1.PublishTargetResult
+	(*HoldCertificateRequest)(nil),         // 20: gigvault.crl.v1.HoldCertificateRequest
+	(*HoldCertificateResponse)(nil),        // 21: gigvault.crl.v1.HoldCertificateResponse
+	(*ReleaseHoldRequest)(nil),             // 22: gigvault.crl.v1.ReleaseHoldRequest
+	(*ReleaseHoldResponse)(nil),            // 23: gigvault.crl.v1.ReleaseHoldResponse
+	(*DeleteRevocationRequest)(nil),        // 24: gigvault.crl.v1.DeleteRevocationRequest
+	(*DeleteRevocationResponse)(nil),       // 25: gigvault.crl.v1.DeleteRevocationResponse
+	(*CheckRevocationStatusRequest)(nil),   // 26: gigvault.crl.v1.CheckRevocationStatusRequest
+	(*CheckRevocationStatusResponse)(nil),  // 27: gigvault.crl.v1.CheckRevocationStatusResponse
+	(*CheckStatusBatchRequest)(nil),        // 28: gigvault.crl.v1.CheckStatusBatchRequest
+	(*CheckStatusBatchResponse)(nil),       // 29: gigvault.crl.v1.CheckStatusBatchResponse
+	(*SerialStatus)(nil),                   // 30: gigvault.crl.v1.SerialStatus
+	(*WatchRevocationsRequest)(nil),        // 31: gigvault.crl.v1.WatchRevocationsRequest
+	(*RevocationEvent)(nil),                // 32: gigvault.crl.v1.RevocationEvent
+	(*ListRevocationsRequest)(nil),         // 33: gigvault.crl.v1.ListRevocationsRequest
+	(*ListRevocationsResponse)(nil),        // 34: gigvault.crl.v1.ListRevocationsResponse
+	(*Revocation)(nil),                     // 35: gigvault.crl.v1.Revocation
+	(*ExportRevocationsRequest)(nil),       // 36: gigvault.crl.v1.ExportRevocationsRequest
+	(*ExportRevocationsChunk)(nil),         // 37: gigvault.crl.v1.ExportRevocationsChunk
+	(*GetRevocationRequest)(nil),           // 38: gigvault.crl.v1.GetRevocationRequest
+	(*GetRevocationResponse)(nil),          // 39: gigvault.crl.v1.GetRevocationResponse
+	(*QueryAuditLogRequest)(nil),           // 40: gigvault.crl.v1.QueryAuditLogRequest
+	(*QueryAuditLogResponse)(nil),          // 41: gigvault.crl.v1.QueryAuditLogResponse
+	(*AuditRecord)(nil),                    // 42: gigvault.crl.v1.AuditRecord
+	(*CreateAPIKeyRequest)(nil),            // 43: gigvault.crl.v1.CreateAPIKeyRequest
+	(*CreateAPIKeyResponse)(nil),           // 44: gigvault.crl.v1.CreateAPIKeyResponse
+	(*RotateAPIKeyRequest)(nil),            // 45: gigvault.crl.v1.RotateAPIKeyRequest
+	(*RotateAPIKeyResponse)(nil),           // 46: gigvault.crl.v1.RotateAPIKeyResponse
+	(*RevokeAPIKeyRequest)(nil),            // 47: gigvault.crl.v1.RevokeAPIKeyRequest
+	(*RevokeAPIKeyResponse)(nil),           // 48: gigvault.crl.v1.RevokeAPIKeyResponse
+	(*APIKey)(nil),                         // 49: gigvault.crl.v1.APIKey
+	(*timestamppb.Timestamp)(nil),          // 50: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),            // 51: google.protobuf.Duration
+}
+var file_crl_proto_depIdxs = []int32{
+	50, // 0: gigvault.crl.v1.AddRevocationRequest.revoked_at:type_name -> google.protobuf.Timestamp
+	50, // 1: gigvault.crl.v1.AddRevocationRequest.invalidity_date:type_name -> google.protobuf.Timestamp
+	50, // 2: gigvault.crl.v1.AddRevocationRequest.effective_at:type_name -> google.protobuf.Timestamp
+	50, // 3: gigvault.crl.v1.AddRevocationRequest.not_after:type_name -> google.protobuf.Timestamp
+	2,  // 4: gigvault.crl.v1.AddRevocationsRequest.revocations:type_name -> gigvault.crl.v1.AddRevocationRequest
+	6,  // 5: gigvault.crl.v1.AddRevocationsResponse.results:type_name -> gigvault.crl.v1.AddRevocationResult
+	13, // 6: gigvault.crl.v1.ListPendingRevocationsResponse.pending:type_name -> gigvault.crl.v1.PendingRevocation
+	35, // 7: gigvault.crl.v1.PendingRevocation.revocation:type_name -> gigvault.crl.v1.Revocation
+	50, // 8: gigvault.crl.v1.PendingRevocation.requested_at:type_name -> google.protobuf.Timestamp
+	50, // 9: gigvault.crl.v1.PendingRevocation.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 10: gigvault.crl.v1.GetCRLRequest.format:type_name -> gigvault.crl.v1.CRLFormat
+	50, // 11: gigvault.crl.v1.GetCRLResponse.this_update:type_name -> google.protobuf.Timestamp
+	50, // 12: gigvault.crl.v1.GetCRLResponse.next_update:type_name -> google.protobuf.Timestamp
+	0,  // 13: gigvault.crl.v1.GetCRLResponse.format:type_name -> gigvault.crl.v1.CRLFormat
+	0,  // 14: gigvault.crl.v1.GetCRLChunk.format:type_name -> gigvault.crl.v1.CRLFormat
+	50, // 15: gigvault.crl.v1.GetCRLChunk.this_update:type_name -> google.protobuf.Timestamp
+	50, // 16: gigvault.crl.v1.GetCRLChunk.next_update:type_name -> google.protobuf.Timestamp
+	51, // 17: gigvault.crl.v1.PublishCRLRequest.validity:type_name -> google.protobuf.Duration
+	51, // 18: gigvault.crl.v1.PublishCRLRequest.overlap:type_name -> google.protobuf.Duration
+	50, // 19: gigvault.crl.v1.PublishCRLResponse.published_at:type_name -> google.protobuf.Timestamp
+	19, // 20: gigvault.crl.v1.PublishCRLResponse.targets:type_name -> gigvault.crl.v1.PublishTargetResult
+	51, // 21: gigvault.crl.v1.PublishTargetResult.duration:type_name -> google.protobuf.Duration
+	50, // 22: gigvault.crl.v1.HoldCertificateRequest.held_at:type_name -> google.protobuf.Timestamp
+	50, // 23: gigvault.crl.v1.HoldCertificateRequest.not_after:type_name -> google.protobuf.Timestamp
+	50, // 24: gigvault.crl.v1.CheckRevocationStatusResponse.revoked_at:type_name -> google.protobuf.Timestamp
+	50, // 25: gigvault.crl.v1.CheckRevocationStatusResponse.invalidity_date:type_name -> google.protobuf.Timestamp
+	50, // 26: gigvault.crl.v1.CheckRevocationStatusResponse.effective_at:type_name -> google.protobuf.Timestamp
+	30, // 27: gigvault.crl.v1.CheckStatusBatchResponse.statuses:type_name -> gigvault.crl.v1.SerialStatus
+	27, // 28: gigvault.crl.v1.SerialStatus.status:type_name -> gigvault.crl.v1.CheckRevocationStatusResponse
+	50, // 29: gigvault.crl.v1.RevocationEvent.revoked_at:type_name -> google.protobuf.Timestamp
+	50, // 30: gigvault.crl.v1.RevocationEvent.invalidity_date:type_name -> google.protobuf.Timestamp
+	50, // 31: gigvault.crl.v1.RevocationEvent.recorded_at:type_name -> google.protobuf.Timestamp
+	50, // 32: gigvault.crl.v1.RevocationEvent.effective_at:type_name -> google.protobuf.Timestamp
+	50, // 33: gigvault.crl.v1.ListRevocationsRequest.revoked_after:type_name -> google.protobuf.Timestamp
+	50, // 34: gigvault.crl.v1.ListRevocationsRequest.revoked_before:type_name -> google.protobuf.Timestamp
+	35, // 35: gigvault.crl.v1.ListRevocationsResponse.revocations:type_name -> gigvault.crl.v1.Revocation
+	50, // 36: gigvault.crl.v1.Revocation.revoked_at:type_name -> google.protobuf.Timestamp
+	50, // 37: gigvault.crl.v1.Revocation.invalidity_date:type_name -> google.protobuf.Timestamp
+	50, // 38: gigvault.crl.v1.Revocation.updated_at:type_name -> google.protobuf.Timestamp
+	50, // 39: gigvault.crl.v1.Revocation.removed_at:type_name -> google.protobuf.Timestamp
+	50, // 40: gigvault.crl.v1.Revocation.effective_at:type_name -> google.protobuf.Timestamp
+	50, // 41: gigvault.crl.v1.Revocation.not_after:type_name -> google.protobuf.Timestamp
+	50, // 42: gigvault.crl.v1.Revocation.archived_at:type_name -> google.protobuf.Timestamp
+	1,  // 43: gigvault.crl.v1.ExportRevocationsRequest.format:type_name -> gigvault.crl.v1.ExportFormat
+	50, // 44: gigvault.crl.v1.ExportRevocationsRequest.revoked_after:type_name -> google.protobuf.Timestamp
+	50, // 45: gigvault.crl.v1.ExportRevocationsRequest.revoked_before:type_name -> google.protobuf.Timestamp
+	35, // 46: gigvault.crl.v1.GetRevocationResponse.revocation:type_name -> gigvault.crl.v1.Revocation
+	32, // 47: gigvault.crl.v1.GetRevocationResponse.history:type_name -> gigvault.crl.v1.RevocationEvent
+	50, // 48: gigvault.crl.v1.QueryAuditLogRequest.since:type_name -> google.protobuf.Timestamp
+	50, // 49: gigvault.crl.v1.QueryAuditLogRequest.until:type_name -> google.protobuf.Timestamp
+	42, // 50: gigvault.crl.v1.QueryAuditLogResponse.records:type_name -> gigvault.crl.v1.AuditRecord
+	50, // 51: gigvault.crl.v1.AuditRecord.occurred_at:type_name -> google.protobuf.Timestamp
+	51, // 52: gigvault.crl.v1.CreateAPIKeyRequest.ttl:type_name -> google.protobuf.Duration
+	49, // 53: gigvault.crl.v1.CreateAPIKeyResponse.api_key:type_name -> gigvault.crl.v1.APIKey
+	51, // 54: gigvault.crl.v1.RotateAPIKeyRequest.ttl:type_name -> google.protobuf.Duration
+	49, // 55: gigvault.crl.v1.RotateAPIKeyResponse.api_key:type_name -> gigvault.crl.v1.APIKey
+	49, // 56: gigvault.crl.v1.RotateAPIKeyResponse.previous:type_name -> gigvault.crl.v1.APIKey
+	49, // 57: gigvault.crl.v1.RevokeAPIKeyResponse.api_key:type_name -> gigvault.crl.v1.APIKey
+	50, // 58: gigvault.crl.v1.APIKey.created_at:type_name -> google.protobuf.Timestamp
+	50, // 59: gigvault.crl.v1.APIKey.expires_at:type_name -> google.protobuf.Timestamp
+	50, // 60: gigvault.crl.v1.APIKey.revoked_at:type_name -> google.protobuf.Timestamp
+	2,  // 61: gigvault.crl.v1.CRLService.AddRevocation:input_type -> gigvault.crl.v1.AddRevocationRequest
+	4,  // 62: gigvault.crl.v1.CRLService.AddRevocations:input_type -> gigvault.crl.v1.AddRevocationsRequest
+	7,  // 63: gigvault.crl.v1.CRLService.ApproveRevocation:input_type -> gigvault.crl.v1.ApproveRevocationRequest
+	9,  // 64: gigvault.crl.v1.CRLService.RejectRevocation:input_type -> gigvault.crl.v1.RejectRevocationRequest
+	11, // 65: gigvault.crl.v1.CRLService.ListPendingRevocations:input_type -> gigvault.crl.v1.ListPendingRevocationsRequest
+	40, // 66: gigvault.crl.v1.CRLService.QueryAuditLog:input_type -> gigvault.crl.v1.QueryAuditLogRequest
+	43, // 67: gigvault.crl.v1.CRLService.CreateAPIKey:input_type -> gigvault.crl.v1.CreateAPIKeyRequest
+	45, // 68: gigvault.crl.v1.CRLService.RotateAPIKey:input_type -> gigvault.crl.v1.RotateAPIKeyRequest
+	47, // 69: gigvault.crl.v1.CRLService.RevokeAPIKey:input_type -> gigvault.crl.v1.RevokeAPIKeyRequest
+	14, // 70: gigvault.crl.v1.CRLService.GetCRL:input_type -> gigvault.crl.v1.GetCRLRequest
+	14, // 71: gigvault.crl.v1.CRLService.GetCRLStream:input_type -> gigvault.crl.v1.GetCRLRequest
+	17, // 72: gigvault.crl.v1.CRLService.PublishCRL:input_type -> gigvault.crl.v1.PublishCRLRequest
+	20, // 73: gigvault.crl.v1.CRLService.HoldCertificate:input_type -> gigvault.crl.v1.HoldCertificateRequest
+	22, // 74: gigvault.crl.v1.CRLService.ReleaseHold:input_type -> gigvault.crl.v1.ReleaseHoldRequest
+	24, // 75: gigvault.crl.v1.CRLService.DeleteRevocation:input_type -> gigvault.crl.v1.DeleteRevocationRequest
+	26, // 76: gigvault.crl.v1.CRLService.CheckRevocationStatus:input_type -> gigvault.crl.v1.CheckRevocationStatusRequest
+	28, // 77: gigvault.crl.v1.CRLService.CheckStatusBatch:input_type -> gigvault.crl.v1.CheckStatusBatchRequest
+	31, // 78: gigvault.crl.v1.CRLService.WatchRevocations:input_type -> gigvault.crl.v1.WatchRevocationsRequest
+	33, // 79: gigvault.crl.v1.CRLService.ListRevocations:input_type -> gigvault.crl.v1.ListRevocationsRequest
+	38, // 80: gigvault.crl.v1.CRLService.GetRevocation:input_type -> gigvault.crl.v1.GetRevocationRequest
+	36, // 81: gigvault.crl.v1.CRLService.ExportRevocations:input_type -> gigvault.crl.v1.ExportRevocationsRequest
+	3,  // 82: gigvault.crl.v1.CRLService.AddRevocation:output_type -> gigvault.crl.v1.AddRevocationResponse
+	5,  // 83: gigvault.crl.v1.CRLService.AddRevocations:output_type -> gigvault.crl.v1.AddRevocationsResponse
+	8,  // 84: gigvault.crl.v1.CRLService.ApproveRevocation:output_type -> gigvault.crl.v1.ApproveRevocationResponse
+	10, // 85: gigvault.crl.v1.CRLService.RejectRevocation:output_type -> gigvault.crl.v1.RejectRevocationResponse
+	12, // 86: gigvault.crl.v1.CRLService.ListPendingRevocations:output_type -> gigvault.crl.v1.ListPendingRevocationsResponse
+	41, // 87: gigvault.crl.v1.CRLService.QueryAuditLog:output_type -> gigvault.crl.v1.QueryAuditLogResponse
+	44, // 88: gigvault.crl.v1.CRLService.CreateAPIKey:output_type -> gigvault.crl.v1.CreateAPIKeyResponse
+	46, // 89: gigvault.crl.v1.CRLService.RotateAPIKey:output_type -> gigvault.crl.v1.RotateAPIKeyResponse
+	48, // 90: gigvault.crl.v1.CRLService.RevokeAPIKey:output_type -> gigvault.crl.v1.RevokeAPIKeyResponse
+	15, // 91: gigvault.crl.v1.CRLService.GetCRL:output_type -> gigvault.crl.v1.GetCRLResponse
+	16, // 92: gigvault.crl.v1.CRLService.GetCRLStream:output_type -> gigvault.crl.v1.GetCRLChunk
+	18, // 93: gigvault.crl.v1.CRLService.PublishCRL:output_type -> gigvault.crl.v1.PublishCRLResponse
+	21, // 94: gigvault.crl.v1.CRLService.HoldCertificate:output_type -> gigvault.crl.v1.HoldCertificateResponse
+	23, // 95: gigvault.crl.v1.CRLService.ReleaseHold:output_type -> gigvault.crl.v1.ReleaseHoldResponse
+	25, // 96: gigvault.crl.v1.CRLService.DeleteRevocation:output_type -> gigvault.crl.v1.DeleteRevocationResponse
+	27, // 97: gigvault.crl.v1.CRLService.CheckRevocationStatus:output_type -> gigvault.crl.v1.CheckRevocationStatusResponse
+	29, // 98: gigvault.crl.v1.CRLService.CheckStatusBatch:output_type -> gigvault.crl.v1.CheckStatusBatchResponse
+	32, // 99: gigvault.crl.v1.CRLService.WatchRevocations:output_type -> gigvault.crl.v1.RevocationEvent
+	34, // 100: gigvault.crl.v1.CRLService.ListRevocations:output_type -> gigvault.crl.v1.ListRevocationsResponse
+	39, // 101: gigvault.crl.v1.CRLService.GetRevocation:output_type -> gigvault.crl.v1.GetRevocationResponse
+	37, // 102: gigvault.crl.v1.CRLService.ExportRevocations:output_type -> gigvault.crl.v1.ExportRevocationsChunk
+	82, // [82:103] is the sub-list for method output_type
+	61, // [61:82] is the sub-list for method input_type
+	61, // [61:61] is the sub-list for extension type_name
+	61, // [61:61] is the sub-list for extension extendee
+	0,  // [0:61] is the sub-list for field type_name
+}
+
+func init() { file_crl_proto_init() }
+func file_crl_proto_init() {
+	if File_crl_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_crl_proto_rawDesc), len(file_crl_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   48,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_crl_proto_goTypes,
+		DependencyIndexes: file_crl_proto_depIdxs,
+		EnumInfos:         file_crl_proto_enumTypes,
+		MessageInfos:      file_crl_proto_msgTypes,
+	}.Build()
+	File_crl_proto = out.File
+	file_crl_proto_goTypes = nil
+	file_crl_proto_depIdxs = nil
+}
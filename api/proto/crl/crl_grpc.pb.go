@@ -0,0 +1,957 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: crl.proto
+
+package crl
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CRLService_AddRevocation_FullMethodName          = "/gigvault.crl.v1.CRLService/AddRevocation"
+	CRLService_AddRevocations_FullMethodName         = "/gigvault.crl.v1.CRLService/AddRevocations"
+	CRLService_ApproveRevocation_FullMethodName      = "/gigvault.crl.v1.CRLService/ApproveRevocation"
+	CRLService_RejectRevocation_FullMethodName       = "/gigvault.crl.v1.CRLService/RejectRevocation"
+	CRLService_ListPendingRevocations_FullMethodName = "/gigvault.crl.v1.CRLService/ListPendingRevocations"
+	CRLService_QueryAuditLog_FullMethodName          = "/gigvault.crl.v1.CRLService/QueryAuditLog"
+	CRLService_CreateAPIKey_FullMethodName           = "/gigvault.crl.v1.CRLService/CreateAPIKey"
+	CRLService_RotateAPIKey_FullMethodName           = "/gigvault.crl.v1.CRLService/RotateAPIKey"
+	CRLService_RevokeAPIKey_FullMethodName           = "/gigvault.crl.v1.CRLService/RevokeAPIKey"
+	CRLService_GetCRL_FullMethodName                 = "/gigvault.crl.v1.CRLService/GetCRL"
+	CRLService_GetCRLStream_FullMethodName           = "/gigvault.crl.v1.CRLService/GetCRLStream"
+	CRLService_PublishCRL_FullMethodName             = "/gigvault.crl.v1.CRLService/PublishCRL"
+	CRLService_HoldCertificate_FullMethodName        = "/gigvault.crl.v1.CRLService/HoldCertificate"
+	CRLService_ReleaseHold_FullMethodName            = "/gigvault.crl.v1.CRLService/ReleaseHold"
+	CRLService_DeleteRevocation_FullMethodName       = "/gigvault.crl.v1.CRLService/DeleteRevocation"
+	CRLService_CheckRevocationStatus_FullMethodName  = "/gigvault.crl.v1.CRLService/CheckRevocationStatus"
+	CRLService_CheckStatusBatch_FullMethodName       = "/gigvault.crl.v1.CRLService/CheckStatusBatch"
+	CRLService_WatchRevocations_FullMethodName       = "/gigvault.crl.v1.CRLService/WatchRevocations"
+	CRLService_ListRevocations_FullMethodName        = "/gigvault.crl.v1.CRLService/ListRevocations"
+	CRLService_GetRevocation_FullMethodName          = "/gigvault.crl.v1.CRLService/GetRevocation"
+	CRLService_ExportRevocations_FullMethodName      = "/gigvault.crl.v1.CRLService/ExportRevocations"
+)
+
+// CRLServiceClient is the client API for CRLService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CRLService handles Certificate Revocation List operations
+type CRLServiceClient interface {
+	// AddRevocation adds a revoked certificate to the CRL
+	AddRevocation(ctx context.Context, in *AddRevocationRequest, opts ...grpc.CallOption) (*AddRevocationResponse, error)
+	// AddRevocations adds up to 1000 revocations in one transaction and
+	// reports the outcome of each
+	AddRevocations(ctx context.Context, in *AddRevocationsRequest, opts ...grpc.CallOption) (*AddRevocationsResponse, error)
+	// ApproveRevocation adds a revocation pending two-person approval; the
+	// approver must differ from the requester
+	ApproveRevocation(ctx context.Context, in *ApproveRevocationRequest, opts ...grpc.CallOption) (*ApproveRevocationResponse, error)
+	// RejectRevocation discards a revocation pending approval
+	RejectRevocation(ctx context.Context, in *RejectRevocationRequest, opts ...grpc.CallOption) (*RejectRevocationResponse, error)
+	// ListPendingRevocations returns the revocations awaiting approval
+	ListPendingRevocations(ctx context.Context, in *ListPendingRevocationsRequest, opts ...grpc.CallOption) (*ListPendingRevocationsResponse, error)
+	// QueryAuditLog returns the recorded state-changing calls, newest first
+	QueryAuditLog(ctx context.Context, in *QueryAuditLogRequest, opts ...grpc.CallOption) (*QueryAuditLogResponse, error)
+	// CreateAPIKey issues an API key for a client. It is an admin operation
+	// and requires the admin token.
+	CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	// RotateAPIKey issues a successor to an API key; the old key keeps
+	// working for the configured grace period. Requires the admin token.
+	RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*RotateAPIKeyResponse, error)
+	// RevokeAPIKey disables an API key at once. Requires the admin token.
+	RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error)
+	// GetCRL retrieves the current CRL, or a historical one by CRL number
+	GetCRL(ctx context.Context, in *GetCRLRequest, opts ...grpc.CallOption) (*GetCRLResponse, error)
+	// GetCRLStream retrieves the current CRL in chunks, for CRLs larger than
+	// the gRPC message size limit
+	GetCRLStream(ctx context.Context, in *GetCRLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetCRLChunk], error)
+	// PublishCRL generates and publishes a new CRL
+	PublishCRL(ctx context.Context, in *PublishCRLRequest, opts ...grpc.CallOption) (*PublishCRLResponse, error)
+	// HoldCertificate places a certificate on hold (certificateHold)
+	HoldCertificate(ctx context.Context, in *HoldCertificateRequest, opts ...grpc.CallOption) (*HoldCertificateResponse, error)
+	// ReleaseHold lifts a hold; the next delta CRL reports removeFromCRL
+	ReleaseHold(ctx context.Context, in *ReleaseHoldRequest, opts ...grpc.CallOption) (*ReleaseHoldResponse, error)
+	// DeleteRevocation withdraws an erroneous revocation. It is an admin
+	// operation, disabled unless configured, and requires the admin token.
+	DeleteRevocation(ctx context.Context, in *DeleteRevocationRequest, opts ...grpc.CallOption) (*DeleteRevocationResponse, error)
+	// CheckRevocationStatus reports whether one certificate is revoked,
+	// without downloading the CRL
+	CheckRevocationStatus(ctx context.Context, in *CheckRevocationStatusRequest, opts ...grpc.CallOption) (*CheckRevocationStatusResponse, error)
+	// CheckStatusBatch reports the status of up to 1000 certificates of one
+	// issuer in a single round trip
+	CheckStatusBatch(ctx context.Context, in *CheckStatusBatchRequest, opts ...grpc.CallOption) (*CheckStatusBatchResponse, error)
+	// WatchRevocations streams changes to the revocation set as they happen,
+	// resuming after a previously received sequence number
+	WatchRevocations(ctx context.Context, in *WatchRevocationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RevocationEvent], error)
+	// ListRevocations pages through the revocation set of an issuer
+	ListRevocations(ctx context.Context, in *ListRevocationsRequest, opts ...grpc.CallOption) (*ListRevocationsResponse, error)
+	// GetRevocation returns the stored record of one serial and its history
+	GetRevocation(ctx context.Context, in *GetRevocationRequest, opts ...grpc.CallOption) (*GetRevocationResponse, error)
+	// ExportRevocations streams every revocation of an issuer matching the
+	// filters as JSON or CSV, for reporting
+	ExportRevocations(ctx context.Context, in *ExportRevocationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportRevocationsChunk], error)
+}
+
+type cRLServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCRLServiceClient(cc grpc.ClientConnInterface) CRLServiceClient {
+	return &cRLServiceClient{cc}
+}
+
+func (c *cRLServiceClient) AddRevocation(ctx context.Context, in *AddRevocationRequest, opts ...grpc.CallOption) (*AddRevocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddRevocationResponse)
+	err := c.cc.Invoke(ctx, CRLService_AddRevocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) AddRevocations(ctx context.Context, in *AddRevocationsRequest, opts ...grpc.CallOption) (*AddRevocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddRevocationsResponse)
+	err := c.cc.Invoke(ctx, CRLService_AddRevocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) ApproveRevocation(ctx context.Context, in *ApproveRevocationRequest, opts ...grpc.CallOption) (*ApproveRevocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApproveRevocationResponse)
+	err := c.cc.Invoke(ctx, CRLService_ApproveRevocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) RejectRevocation(ctx context.Context, in *RejectRevocationRequest, opts ...grpc.CallOption) (*RejectRevocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RejectRevocationResponse)
+	err := c.cc.Invoke(ctx, CRLService_RejectRevocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) ListPendingRevocations(ctx context.Context, in *ListPendingRevocationsRequest, opts ...grpc.CallOption) (*ListPendingRevocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPendingRevocationsResponse)
+	err := c.cc.Invoke(ctx, CRLService_ListPendingRevocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) QueryAuditLog(ctx context.Context, in *QueryAuditLogRequest, opts ...grpc.CallOption) (*QueryAuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryAuditLogResponse)
+	err := c.cc.Invoke(ctx, CRLService_QueryAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) CreateAPIKey(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, CRLService_CreateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) RotateAPIKey(ctx context.Context, in *RotateAPIKeyRequest, opts ...grpc.CallOption) (*RotateAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RotateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, CRLService_RotateAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) RevokeAPIKey(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeAPIKeyResponse)
+	err := c.cc.Invoke(ctx, CRLService_RevokeAPIKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) GetCRL(ctx context.Context, in *GetCRLRequest, opts ...grpc.CallOption) (*GetCRLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCRLResponse)
+	err := c.cc.Invoke(ctx, CRLService_GetCRL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) GetCRLStream(ctx context.Context, in *GetCRLRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetCRLChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CRLService_ServiceDesc.Streams[0], CRLService_GetCRLStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetCRLRequest, GetCRLChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CRLService_GetCRLStreamClient = grpc.ServerStreamingClient[GetCRLChunk]
+
+func (c *cRLServiceClient) PublishCRL(ctx context.Context, in *PublishCRLRequest, opts ...grpc.CallOption) (*PublishCRLResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublishCRLResponse)
+	err := c.cc.Invoke(ctx, CRLService_PublishCRL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) HoldCertificate(ctx context.Context, in *HoldCertificateRequest, opts ...grpc.CallOption) (*HoldCertificateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HoldCertificateResponse)
+	err := c.cc.Invoke(ctx, CRLService_HoldCertificate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) ReleaseHold(ctx context.Context, in *ReleaseHoldRequest, opts ...grpc.CallOption) (*ReleaseHoldResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseHoldResponse)
+	err := c.cc.Invoke(ctx, CRLService_ReleaseHold_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) DeleteRevocation(ctx context.Context, in *DeleteRevocationRequest, opts ...grpc.CallOption) (*DeleteRevocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteRevocationResponse)
+	err := c.cc.Invoke(ctx, CRLService_DeleteRevocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) CheckRevocationStatus(ctx context.Context, in *CheckRevocationStatusRequest, opts ...grpc.CallOption) (*CheckRevocationStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckRevocationStatusResponse)
+	err := c.cc.Invoke(ctx, CRLService_CheckRevocationStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) CheckStatusBatch(ctx context.Context, in *CheckStatusBatchRequest, opts ...grpc.CallOption) (*CheckStatusBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckStatusBatchResponse)
+	err := c.cc.Invoke(ctx, CRLService_CheckStatusBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) WatchRevocations(ctx context.Context, in *WatchRevocationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RevocationEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CRLService_ServiceDesc.Streams[1], CRLService_WatchRevocations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRevocationsRequest, RevocationEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CRLService_WatchRevocationsClient = grpc.ServerStreamingClient[RevocationEvent]
+
+func (c *cRLServiceClient) ListRevocations(ctx context.Context, in *ListRevocationsRequest, opts ...grpc.CallOption) (*ListRevocationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRevocationsResponse)
+	err := c.cc.Invoke(ctx, CRLService_ListRevocations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) GetRevocation(ctx context.Context, in *GetRevocationRequest, opts ...grpc.CallOption) (*GetRevocationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRevocationResponse)
+	err := c.cc.Invoke(ctx, CRLService_GetRevocation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cRLServiceClient) ExportRevocations(ctx context.Context, in *ExportRevocationsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportRevocationsChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CRLService_ServiceDesc.Streams[2], CRLService_ExportRevocations_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportRevocationsRequest, ExportRevocationsChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CRLService_ExportRevocationsClient = grpc.ServerStreamingClient[ExportRevocationsChunk]
+
+// CRLServiceServer is the server API for CRLService service.
+// All implementations must embed UnimplementedCRLServiceServer
+// for forward compatibility.
+//
+// CRLService handles Certificate Revocation List operations
+type CRLServiceServer interface {
+	// AddRevocation adds a revoked certificate to the CRL
+	AddRevocation(context.Context, *AddRevocationRequest) (*AddRevocationResponse, error)
+	// AddRevocations adds up to 1000 revocations in one transaction and
+	// reports the outcome of each
+	AddRevocations(context.Context, *AddRevocationsRequest) (*AddRevocationsResponse, error)
+	// ApproveRevocation adds a revocation pending two-person approval; the
+	// approver must differ from the requester
+	ApproveRevocation(context.Context, *ApproveRevocationRequest) (*ApproveRevocationResponse, error)
+	// RejectRevocation discards a revocation pending approval
+	RejectRevocation(context.Context, *RejectRevocationRequest) (*RejectRevocationResponse, error)
+	// ListPendingRevocations returns the revocations awaiting approval
+	ListPendingRevocations(context.Context, *ListPendingRevocationsRequest) (*ListPendingRevocationsResponse, error)
+	// QueryAuditLog returns the recorded state-changing calls, newest first
+	QueryAuditLog(context.Context, *QueryAuditLogRequest) (*QueryAuditLogResponse, error)
+	// CreateAPIKey issues an API key for a client. It is an admin operation
+	// and requires the admin token.
+	CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	// RotateAPIKey issues a successor to an API key; the old key keeps
+	// working for the configured grace period. Requires the admin token.
+	RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*RotateAPIKeyResponse, error)
+	// RevokeAPIKey disables an API key at once. Requires the admin token.
+	RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error)
+	// GetCRL retrieves the current CRL, or a historical one by CRL number
+	GetCRL(context.Context, *GetCRLRequest) (*GetCRLResponse, error)
+	// GetCRLStream retrieves the current CRL in chunks, for CRLs larger than
+	// the gRPC message size limit
+	GetCRLStream(*GetCRLRequest, grpc.ServerStreamingServer[GetCRLChunk]) error
+	// PublishCRL generates and publishes a new CRL
+	PublishCRL(context.Context, *PublishCRLRequest) (*PublishCRLResponse, error)
+	// HoldCertificate places a certificate on hold (certificateHold)
+	HoldCertificate(context.Context, *HoldCertificateRequest) (*HoldCertificateResponse, error)
+	// ReleaseHold lifts a hold; the next delta CRL reports removeFromCRL
+	ReleaseHold(context.Context, *ReleaseHoldRequest) (*ReleaseHoldResponse, error)
+	// DeleteRevocation withdraws an erroneous revocation. It is an admin
+	// operation, disabled unless configured, and requires the admin token.
+	DeleteRevocation(context.Context, *DeleteRevocationRequest) (*DeleteRevocationResponse, error)
+	// CheckRevocationStatus reports whether one certificate is revoked,
+	// without downloading the CRL
+	CheckRevocationStatus(context.Context, *CheckRevocationStatusRequest) (*CheckRevocationStatusResponse, error)
+	// CheckStatusBatch reports the status of up to 1000 certificates of one
+	// issuer in a single round trip
+	CheckStatusBatch(context.Context, *CheckStatusBatchRequest) (*CheckStatusBatchResponse, error)
+	// WatchRevocations streams changes to the revocation set as they happen,
+	// resuming after a previously received sequence number
+	WatchRevocations(*WatchRevocationsRequest, grpc.ServerStreamingServer[RevocationEvent]) error
+	// ListRevocations pages through the revocation set of an issuer
+	ListRevocations(context.Context, *ListRevocationsRequest) (*ListRevocationsResponse, error)
+	// GetRevocation returns the stored record of one serial and its history
+	GetRevocation(context.Context, *GetRevocationRequest) (*GetRevocationResponse, error)
+	// ExportRevocations streams every revocation of an issuer matching the
+	// filters as JSON or CSV, for reporting
+	ExportRevocations(*ExportRevocationsRequest, grpc.ServerStreamingServer[ExportRevocationsChunk]) error
+	mustEmbedUnimplementedCRLServiceServer()
+}
+
+// UnimplementedCRLServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCRLServiceServer struct{}
+
+func (UnimplementedCRLServiceServer) AddRevocation(context.Context, *AddRevocationRequest) (*AddRevocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRevocation not implemented")
+}
+func (UnimplementedCRLServiceServer) AddRevocations(context.Context, *AddRevocationsRequest) (*AddRevocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddRevocations not implemented")
+}
+func (UnimplementedCRLServiceServer) ApproveRevocation(context.Context, *ApproveRevocationRequest) (*ApproveRevocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveRevocation not implemented")
+}
+func (UnimplementedCRLServiceServer) RejectRevocation(context.Context, *RejectRevocationRequest) (*RejectRevocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RejectRevocation not implemented")
+}
+func (UnimplementedCRLServiceServer) ListPendingRevocations(context.Context, *ListPendingRevocationsRequest) (*ListPendingRevocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingRevocations not implemented")
+}
+func (UnimplementedCRLServiceServer) QueryAuditLog(context.Context, *QueryAuditLogRequest) (*QueryAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QueryAuditLog not implemented")
+}
+func (UnimplementedCRLServiceServer) CreateAPIKey(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAPIKey not implemented")
+}
+func (UnimplementedCRLServiceServer) RotateAPIKey(context.Context, *RotateAPIKeyRequest) (*RotateAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RotateAPIKey not implemented")
+}
+func (UnimplementedCRLServiceServer) RevokeAPIKey(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAPIKey not implemented")
+}
+func (UnimplementedCRLServiceServer) GetCRL(context.Context, *GetCRLRequest) (*GetCRLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCRL not implemented")
+}
+func (UnimplementedCRLServiceServer) GetCRLStream(*GetCRLRequest, grpc.ServerStreamingServer[GetCRLChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method GetCRLStream not implemented")
+}
+func (UnimplementedCRLServiceServer) PublishCRL(context.Context, *PublishCRLRequest) (*PublishCRLResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishCRL not implemented")
+}
+func (UnimplementedCRLServiceServer) HoldCertificate(context.Context, *HoldCertificateRequest) (*HoldCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HoldCertificate not implemented")
+}
+func (UnimplementedCRLServiceServer) ReleaseHold(context.Context, *ReleaseHoldRequest) (*ReleaseHoldResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseHold not implemented")
+}
+func (UnimplementedCRLServiceServer) DeleteRevocation(context.Context, *DeleteRevocationRequest) (*DeleteRevocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRevocation not implemented")
+}
+func (UnimplementedCRLServiceServer) CheckRevocationStatus(context.Context, *CheckRevocationStatusRequest) (*CheckRevocationStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckRevocationStatus not implemented")
+}
+func (UnimplementedCRLServiceServer) CheckStatusBatch(context.Context, *CheckStatusBatchRequest) (*CheckStatusBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckStatusBatch not implemented")
+}
+func (UnimplementedCRLServiceServer) WatchRevocations(*WatchRevocationsRequest, grpc.ServerStreamingServer[RevocationEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchRevocations not implemented")
+}
+func (UnimplementedCRLServiceServer) ListRevocations(context.Context, *ListRevocationsRequest) (*ListRevocationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRevocations not implemented")
+}
+func (UnimplementedCRLServiceServer) GetRevocation(context.Context, *GetRevocationRequest) (*GetRevocationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRevocation not implemented")
+}
+func (UnimplementedCRLServiceServer) ExportRevocations(*ExportRevocationsRequest, grpc.ServerStreamingServer[ExportRevocationsChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportRevocations not implemented")
+}
+func (UnimplementedCRLServiceServer) mustEmbedUnimplementedCRLServiceServer() {}
+func (UnimplementedCRLServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeCRLServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CRLServiceServer will
+// result in compilation errors.
+type UnsafeCRLServiceServer interface {
+	mustEmbedUnimplementedCRLServiceServer()
+}
+
+func RegisterCRLServiceServer(s grpc.ServiceRegistrar, srv CRLServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCRLServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CRLService_ServiceDesc, srv)
+}
+
+func _CRLService_AddRevocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRevocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).AddRevocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_AddRevocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).AddRevocation(ctx, req.(*AddRevocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_AddRevocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddRevocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).AddRevocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_AddRevocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).AddRevocations(ctx, req.(*AddRevocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_ApproveRevocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveRevocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).ApproveRevocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_ApproveRevocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).ApproveRevocation(ctx, req.(*ApproveRevocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_RejectRevocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RejectRevocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).RejectRevocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_RejectRevocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).RejectRevocation(ctx, req.(*RejectRevocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_ListPendingRevocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingRevocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).ListPendingRevocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_ListPendingRevocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).ListPendingRevocations(ctx, req.(*ListPendingRevocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_QueryAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).QueryAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_QueryAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).QueryAuditLog(ctx, req.(*QueryAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_CreateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).CreateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_CreateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).CreateAPIKey(ctx, req.(*CreateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_RotateAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).RotateAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_RotateAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).RotateAPIKey(ctx, req.(*RotateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_RevokeAPIKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).RevokeAPIKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_RevokeAPIKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).RevokeAPIKey(ctx, req.(*RevokeAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_GetCRL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCRLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).GetCRL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_GetCRL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).GetCRL(ctx, req.(*GetCRLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_GetCRLStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetCRLRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CRLServiceServer).GetCRLStream(m, &grpc.GenericServerStream[GetCRLRequest, GetCRLChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CRLService_GetCRLStreamServer = grpc.ServerStreamingServer[GetCRLChunk]
+
+func _CRLService_PublishCRL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishCRLRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).PublishCRL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_PublishCRL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).PublishCRL(ctx, req.(*PublishCRLRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_HoldCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HoldCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).HoldCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_HoldCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).HoldCertificate(ctx, req.(*HoldCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_ReleaseHold_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseHoldRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).ReleaseHold(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_ReleaseHold_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).ReleaseHold(ctx, req.(*ReleaseHoldRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_DeleteRevocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRevocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).DeleteRevocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_DeleteRevocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).DeleteRevocation(ctx, req.(*DeleteRevocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_CheckRevocationStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckRevocationStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).CheckRevocationStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_CheckRevocationStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).CheckRevocationStatus(ctx, req.(*CheckRevocationStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_CheckStatusBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckStatusBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).CheckStatusBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_CheckStatusBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).CheckStatusBatch(ctx, req.(*CheckStatusBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_WatchRevocations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRevocationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CRLServiceServer).WatchRevocations(m, &grpc.GenericServerStream[WatchRevocationsRequest, RevocationEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CRLService_WatchRevocationsServer = grpc.ServerStreamingServer[RevocationEvent]
+
+func _CRLService_ListRevocations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRevocationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).ListRevocations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_ListRevocations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).ListRevocations(ctx, req.(*ListRevocationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_GetRevocation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRevocationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CRLServiceServer).GetRevocation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CRLService_GetRevocation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CRLServiceServer).GetRevocation(ctx, req.(*GetRevocationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CRLService_ExportRevocations_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRevocationsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CRLServiceServer).ExportRevocations(m, &grpc.GenericServerStream[ExportRevocationsRequest, ExportRevocationsChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CRLService_ExportRevocationsServer = grpc.ServerStreamingServer[ExportRevocationsChunk]
+
+// CRLService_ServiceDesc is the grpc.ServiceDesc for CRLService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CRLService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gigvault.crl.v1.CRLService",
+	HandlerType: (*CRLServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddRevocation",
+			Handler:    _CRLService_AddRevocation_Handler,
+		},
+		{
+			MethodName: "AddRevocations",
+			Handler:    _CRLService_AddRevocations_Handler,
+		},
+		{
+			MethodName: "ApproveRevocation",
+			Handler:    _CRLService_ApproveRevocation_Handler,
+		},
+		{
+			MethodName: "RejectRevocation",
+			Handler:    _CRLService_RejectRevocation_Handler,
+		},
+		{
+			MethodName: "ListPendingRevocations",
+			Handler:    _CRLService_ListPendingRevocations_Handler,
+		},
+		{
+			MethodName: "QueryAuditLog",
+			Handler:    _CRLService_QueryAuditLog_Handler,
+		},
+		{
+			MethodName: "CreateAPIKey",
+			Handler:    _CRLService_CreateAPIKey_Handler,
+		},
+		{
+			MethodName: "RotateAPIKey",
+			Handler:    _CRLService_RotateAPIKey_Handler,
+		},
+		{
+			MethodName: "RevokeAPIKey",
+			Handler:    _CRLService_RevokeAPIKey_Handler,
+		},
+		{
+			MethodName: "GetCRL",
+			Handler:    _CRLService_GetCRL_Handler,
+		},
+		{
+			MethodName: "PublishCRL",
+			Handler:    _CRLService_PublishCRL_Handler,
+		},
+		{
+			MethodName: "HoldCertificate",
+			Handler:    _CRLService_HoldCertificate_Handler,
+		},
+		{
+			MethodName: "ReleaseHold",
+			Handler:    _CRLService_ReleaseHold_Handler,
+		},
+		{
+			MethodName: "DeleteRevocation",
+			Handler:    _CRLService_DeleteRevocation_Handler,
+		},
+		{
+			MethodName: "CheckRevocationStatus",
+			Handler:    _CRLService_CheckRevocationStatus_Handler,
+		},
+		{
+			MethodName: "CheckStatusBatch",
+			Handler:    _CRLService_CheckStatusBatch_Handler,
+		},
+		{
+			MethodName: "ListRevocations",
+			Handler:    _CRLService_ListRevocations_Handler,
+		},
+		{
+			MethodName: "GetRevocation",
+			Handler:    _CRLService_GetRevocation_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "GetCRLStream",
+			Handler:       _CRLService_GetCRLStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchRevocations",
+			Handler:       _CRLService_WatchRevocations_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportRevocations",
+			Handler:       _CRLService_ExportRevocations_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "crl.proto",
+}
@@ -0,0 +1,9 @@
+package crl
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 2.0 document of the REST gateway, generated from
+// crl.proto by protoc-gen-openapiv2
+//
+//go:embed crl.swagger.json
+var OpenAPI []byte
@@ -2,18 +2,51 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
+	crlpb "github.com/gigvault/crl/api/proto/crl"
 	"github.com/gigvault/crl/internal/api"
-	"github.com/gigvault/shared/pkg/config"
+	"github.com/gigvault/crl/internal/archive"
+	"github.com/gigvault/crl/internal/auth"
+	"github.com/gigvault/crl/internal/bloom"
+	"github.com/gigvault/crl/internal/ca"
+	"github.com/gigvault/crl/internal/cdn"
+	"github.com/gigvault/crl/internal/config"
+	"github.com/gigvault/crl/internal/events"
+	"github.com/gigvault/crl/internal/export"
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/notify"
+	"github.com/gigvault/crl/internal/ocsp"
+	"github.com/gigvault/crl/internal/publisher"
+	"github.com/gigvault/crl/internal/ratelimit"
+	"github.com/gigvault/crl/internal/scheduler"
+	"github.com/gigvault/crl/internal/signer"
+	"github.com/gigvault/crl/internal/statuscache"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/crl/internal/tracing"
+	"github.com/gigvault/shared/pkg/db"
 	"github.com/gigvault/shared/pkg/logger"
+	"github.com/gigvault/shared/pkg/security"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
@@ -27,18 +60,186 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	logger, err := logger.New(cfg.Logging.Level, cfg.Logging.Format)
+	appLogger, logLevel, err := newLogger(cfg.Logging.Level, cfg.Logging.Format)
 	if err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
-	defer logger.Sync()
+	defer appLogger.Sync()
+	logger.SetGlobal(appLogger)
 
-	logger.Info("Starting crl service",
+	appLogger.Info("Starting crl service",
 		zap.String("service", cfg.Service.Name),
 		zap.String("version", cfg.Service.Version),
 	)
 
-	handler := api.NewHTTPHandler(logger)
+	ctx := context.Background()
+
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, cfg, os.Args[2:]); err != nil {
+			appLogger.Fatal("Migration failed", zap.Error(err))
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(ctx, cfg, os.Args[2:]); err != nil {
+			appLogger.Fatal("Export failed", zap.Error(err))
+		}
+		return
+	}
+
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing, cfg.Service.Name, cfg.Service.Version)
+	if err != nil {
+		appLogger.Fatal("Failed to initialize tracing", zap.Error(err))
+	}
+
+	pool, err := connectWithRetry(ctx, cfg.DB.ConnectTimeout, "database", func(ctx context.Context) (*pgxpool.Pool, error) {
+		return connectDatabase(ctx, cfg)
+	})
+	if err != nil {
+		appLogger.Fatal("Failed to connect to database", zap.Error(err))
+	}
+	if cfg.Tracing.Enabled {
+		pool, err = tracing.InstrumentPool(ctx, pool)
+		if err != nil {
+			appLogger.Fatal("Failed to connect to database", zap.Error(err))
+		}
+	}
+	defer db.Close(pool)
+
+	st := store.New(pool)
+	st.UseBreaker(cfg.DB.CircuitBreaker)
+	if cfg.DB.ReplicaDSN != "" {
+		replica, err := connectWithRetry(ctx, cfg.DB.ConnectTimeout, "database replica", func(ctx context.Context) (*pgxpool.Pool, error) {
+			return openPool(ctx, cfg.DB.ReplicaDSN, "database.replica_dsn")
+		})
+		if err != nil {
+			appLogger.Fatal("Failed to connect to database replica", zap.Error(err))
+		}
+		if cfg.Tracing.Enabled {
+			replica, err = tracing.InstrumentPool(ctx, replica)
+			if err != nil {
+				appLogger.Fatal("Failed to connect to database replica", zap.Error(err))
+			}
+		}
+		defer db.Close(replica)
+		st.SetReplica(replica)
+	}
+	if cfg.DB.Migrate {
+		if err := migrate(ctx, st); err != nil {
+			appLogger.Fatal("Failed to migrate database", zap.Error(err))
+		}
+	}
+	issuers := generator.NewRegistry(cfg.DefaultIssuer())
+	for _, ic := range cfg.IssuerConfigs() {
+		crlSigner, err := signer.New(ctx, ic.Signer, pool)
+		if err != nil {
+			appLogger.Fatal("Failed to initialize CRL signer", zap.String("issuer_id", ic.ID), zap.Error(err))
+		}
+		if c, ok := crlSigner.(io.Closer); ok {
+			defer c.Close()
+		}
+		appLogger.Info("CRL signer loaded",
+			zap.String("issuer_id", ic.ID),
+			zap.String("backend", ic.Signer.Backend),
+			zap.String("issuer", crlSigner.Certificate().Subject.String()),
+		)
+
+		publishers, purgers, err := newTargets(ctx, ic)
+		if err != nil {
+			appLogger.Fatal("Failed to initialize distribution targets", zap.String("issuer_id", ic.ID), zap.Error(err))
+		}
+
+		gen, err := generator.New(ic.ID, st, crlSigner, ic.CRL, publishers, purgers)
+		if err != nil {
+			appLogger.Fatal("Failed to initialize CRL generator", zap.String("issuer_id", ic.ID), zap.Error(err))
+		}
+		defer gen.Close()
+		if err := issuers.Add(gen); err != nil {
+			appLogger.Fatal("Failed to register issuer", zap.Error(err))
+		}
+
+		issuerCert := gen.Issuer()
+		err = st.UpsertIssuer(ctx, store.Issuer{
+			ID:           ic.ID,
+			Subject:      issuerCert.Subject.String(),
+			RawSubject:   issuerCert.RawSubject,
+			SubjectKeyID: issuerCert.SubjectKeyId,
+		})
+		if err != nil {
+			appLogger.Fatal("Failed to register issuer", zap.String("issuer_id", ic.ID), zap.Error(err))
+		}
+	}
+
+	// Hooks are added before the scheduler publishes
+	var relay *events.Relay
+	if cfg.Events.Enabled() {
+		relay, err = events.NewRelay(ctx, st, cfg.Events)
+		if err != nil {
+			appLogger.Fatal("Failed to create event sinks", zap.Error(err))
+		}
+		defer relay.Close()
+		for _, gen := range issuers.All() {
+			gen.OnPublish(relay.CRLPublished)
+		}
+	}
+
+	sched := scheduler.New(st, issuers, cfg.Scheduler)
+	schedCtx, stopScheduler := context.WithCancel(ctx)
+	schedDone := make(chan struct{})
+	go func() {
+		defer close(schedDone)
+		sched.Run(schedCtx)
+	}()
+
+	reloader := config.NewReloader(configPath, cfg.Reload, func(next *config.Config) error {
+		return applyReload(ctx, next, logLevel, issuers, sched)
+	})
+	go reloader.Run(schedCtx)
+
+	if relay != nil {
+		go relay.Run(schedCtx)
+		appLogger.Info("Sending revocation events", zap.Int("sinks", len(cfg.Events.Sinks)))
+	}
+
+	healthChecker := api.NewHealthChecker(st, issuers, cfg.Health)
+	go healthChecker.Run(schedCtx)
+	go st.MonitorReplica(schedCtx, cfg.DB.ReplicaMaxLag)
+
+	if cfg.Export.Parquet.Enabled {
+		parquetExporter, err := export.NewParquetExporter(ctx, st, cfg.Export.Parquet)
+		if err != nil {
+			appLogger.Fatal("Failed to create Parquet exporter", zap.Error(err))
+		}
+		go parquetExporter.Run(schedCtx)
+		appLogger.Info("Exporting revocation events to Parquet",
+			zap.String("bucket", cfg.Export.Parquet.S3.Bucket),
+			zap.Duration("interval", cfg.Export.Parquet.Interval),
+		)
+	}
+
+	if cfg.Archive.Enabled {
+		go archive.NewArchiver(st, issuers, cfg.Archive).Run(schedCtx)
+		appLogger.Info("Archiving entries of expired certificates", zap.Duration("retention", cfg.Archive.Retention))
+	}
+
+	var exports *export.Service
+	if cfg.Export.Enabled() {
+		// A client of its own: CRLite lists valid certificates whether or
+		// not revocations are verified with the CA
+		var exportCA *ca.Client
+		if cfg.Export.CRLite.Enabled {
+			exportCA, err = ca.NewClient(cfg.CA)
+			if err != nil {
+				appLogger.Fatal("Failed to create CA client", zap.Error(err))
+			}
+			defer exportCA.Close()
+		}
+		exports = export.NewService(st, issuers, exportCA, cfg.Export)
+		go exports.Run(schedCtx)
+		appLogger.Info("Exporting revocation sets", zap.Duration("interval", cfg.Export.Interval))
+	}
+
+	handler := api.NewHTTPHandler(appLogger, healthChecker)
 	router := handler.Routes()
 
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.HTTPPort)
@@ -51,23 +252,438 @@ func main() {
 	}
 
 	go func() {
-		logger.Info("Starting HTTP server", zap.String("address", addr))
+		appLogger.Info("Starting HTTP server", zap.String("address", addr))
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("HTTP server error", zap.Error(err))
+			appLogger.Fatal("HTTP server error", zap.Error(err))
 		}
 	}()
 
+	var cdp *http.Server
+	if cfg.Distribution.Enabled {
+		cdpAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Distribution.Port)
+		cdp = &http.Server{
+			Addr:         cdpAddr,
+			Handler:      api.NewDistributionHandler(issuers, exports).Routes(),
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 60 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		}
+
+		go func() {
+			appLogger.Info("Starting CRL distribution point", zap.String("address", cdpAddr))
+			if err := cdp.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				appLogger.Fatal("CRL distribution point error", zap.Error(err))
+			}
+		}()
+	}
+
+	var diag *http.Server
+	if cfg.Diagnostics.Enabled {
+		diagAddr := fmt.Sprintf("%s:%d", api.DiagnosticsAddress, cfg.Diagnostics.Port)
+		diag = &http.Server{
+			Addr:        diagAddr,
+			Handler:     api.DiagnosticsRoutes(),
+			ReadTimeout: 15 * time.Second,
+			// CPU profiles and execution traces stream for their duration
+			WriteTimeout: 10 * time.Minute,
+			IdleTimeout:  60 * time.Second,
+		}
+
+		go func() {
+			appLogger.Info("Starting diagnostics listener", zap.String("address", diagAddr))
+			if err := diag.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				appLogger.Fatal("Diagnostics listener error", zap.Error(err))
+			}
+		}()
+	}
+
+	var responder *ocsp.Responder
+	var ocspSrv *http.Server
+	if cfg.OCSP.Enabled {
+		responder = ocsp.NewResponder(st, issuers, cfg.OCSP)
+		for _, ic := range cfg.IssuerConfigs() {
+			for _, rc := range ic.OCSPResponders {
+				delegate, err := signer.New(ctx, rc, pool)
+				if err != nil {
+					appLogger.Fatal("Failed to initialize OCSP responder key", zap.String("issuer_id", ic.ID), zap.Error(err))
+				}
+				if c, ok := delegate.(io.Closer); ok {
+					defer c.Close()
+				}
+				if err := responder.AddDelegate(ic.ID, delegate); err != nil {
+					appLogger.Fatal("Invalid OCSP responder certificate", zap.String("issuer_id", ic.ID), zap.Error(err))
+				}
+				appLogger.Info("OCSP responder certificate loaded",
+					zap.String("issuer_id", ic.ID),
+					zap.String("responder", delegate.Certificate().Subject.String()),
+					zap.Time("not_after", delegate.Certificate().NotAfter),
+				)
+			}
+		}
+		go responder.Run(schedCtx)
+
+		ocspAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.OCSP.Port)
+		ocspSrv = &http.Server{
+			Addr:         ocspAddr,
+			Handler:      responder.Routes(),
+			ReadTimeout:  5 * time.Second,
+			WriteTimeout: 10 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		}
+
+		go func() {
+			appLogger.Info("Starting OCSP responder", zap.String("address", ocspAddr))
+			if err := ocspSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				appLogger.Fatal("OCSP responder error", zap.Error(err))
+			}
+		}()
+	}
+
+	var statusCache *statuscache.Cache
+	if cfg.StatusCache.Enabled {
+		statusCache, err = statuscache.New(cfg.StatusCache)
+		if err != nil {
+			appLogger.Fatal("Failed to create status cache", zap.Error(err))
+		}
+		defer statusCache.Close()
+		appLogger.Info("Caching revocation status in Redis",
+			zap.String("address", cfg.StatusCache.Address),
+			zap.Duration("ttl", cfg.StatusCache.TTL),
+		)
+	}
+
+	var statusFilter *bloom.Index
+	if cfg.StatusFilter.Enabled {
+		statusFilter = bloom.NewIndex(st, issuers, cfg.StatusFilter)
+		go statusFilter.Run(schedCtx)
+	}
+
+	if cfg.Notify.Enabled {
+		go notify.NewListener(st, issuers, responder, statusCache, cfg.Notify).Run(schedCtx)
+		appLogger.Info("Listening for revocation notifications", zap.Duration("debounce", cfg.Notify.Debounce))
+	}
+
+	grpcAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.GRPCPort)
+	lis, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		appLogger.Fatal("Failed to listen for gRPC", zap.Error(err))
+	}
+	var caClient *ca.Client
+	if cfg.CA.Enabled() {
+		caClient, err = ca.NewClient(cfg.CA)
+		if err != nil {
+			appLogger.Fatal("Failed to create CA client", zap.Error(err))
+		}
+		defer caClient.Close()
+		appLogger.Info("Verifying revoked serials with the CA",
+			zap.String("address", cfg.CA.Address),
+			zap.String("mode", cfg.CA.Verify),
+		)
+	}
+	crlServer := api.NewCRLGRPCServer(st, issuers, sched, responder, caClient, statusCache, statusFilter, cfg.Admin, cfg.Approval, cfg.Auth)
+	authenticator := auth.NewAuthenticator(cfg.Auth, cfg.Security.MTLSEnabled, st)
+	authenticator.Exempt(api.APIKeyMethods...)
+	authenticator.Exempt(api.HealthMethods...)
+	go authenticator.Run(schedCtx)
+	authorizer := auth.NewAuthorizer(cfg.Auth)
+	limiter := ratelimit.New(cfg.RateLimit)
+	serverOpts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		// Authorization runs inside the audit interceptor so denied calls
+		// are recorded; rate-limited calls are not, so a flood of them
+		// cannot flood the audit log
+		grpc.ChainUnaryInterceptor(
+			authenticator.UnaryInterceptor(),
+			limiter.UnaryInterceptor(),
+			crlServer.AuditInterceptor(),
+			authorizer.UnaryInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			authenticator.StreamInterceptor(),
+			limiter.StreamInterceptor(),
+			authorizer.StreamInterceptor(),
+		),
+	}
+	grpcOpts := slices.Clone(serverOpts)
+	var tlsConfig *tls.Config
+	if cfg.Security.TLSEnabled {
+		tlsConfig, err = security.LoadTLSConfig(security.TLSConfig{
+			Enabled:     true,
+			CertFile:    cfg.Security.TLSCertPath,
+			KeyFile:     cfg.Security.TLSKeyPath,
+			CAFile:      cfg.Security.CACertPath,
+			MTLSEnabled: cfg.Security.MTLSEnabled,
+		})
+		if err != nil {
+			appLogger.Fatal("Failed to load gRPC TLS config", zap.Error(err))
+		}
+		grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	} else {
+		appLogger.Warn("gRPC server is running without TLS")
+	}
+	grpcServer := grpc.NewServer(grpcOpts...)
+	crlpb.RegisterCRLServiceServer(grpcServer, crlServer)
+	healthpb.RegisterHealthServer(grpcServer, healthChecker.Server())
+	if cfg.Diagnostics.GRPCReflection {
+		reflection.Register(grpcServer)
+		appLogger.Warn("gRPC server reflection is enabled")
+	}
+
+	go func() {
+		appLogger.Info("Starting gRPC server",
+			zap.String("address", grpcAddr),
+			zap.Bool("tls", cfg.Security.TLSEnabled),
+			zap.Bool("mtls", cfg.Security.MTLSEnabled),
+		)
+		if err := grpcServer.Serve(lis); err != nil {
+			appLogger.Fatal("gRPC server error", zap.Error(err))
+		}
+	}()
+
+	var gateway *api.Gateway
+	var gatewaySrv *http.Server
+	if cfg.Gateway.Enabled {
+		gateway, err = api.NewGateway(schedCtx, cfg.Gateway, crlServer, serverOpts...)
+		if err != nil {
+			appLogger.Fatal("Failed to create REST gateway", zap.Error(err))
+		}
+		gatewayAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Gateway.Port)
+		gatewaySrv = &http.Server{
+			Addr:         gatewayAddr,
+			Handler:      gateway.Handler(),
+			TLSConfig:    tlsConfig,
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 60 * time.Second,
+			IdleTimeout:  60 * time.Second,
+		}
+
+		go func() {
+			appLogger.Info("Starting REST gateway",
+				zap.String("address", gatewayAddr),
+				zap.Bool("tls", tlsConfig != nil),
+			)
+			var err error
+			if tlsConfig != nil {
+				err = gatewaySrv.ListenAndServeTLS("", "")
+			} else {
+				err = gatewaySrv.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				appLogger.Fatal("REST gateway error", zap.Error(err))
+			}
+		}()
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	logger.Info("Shutting down server...")
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	appLogger.Info("Shutting down server...", zap.Duration("timeout", cfg.Shutdown.Timeout))
+	healthChecker.Shutdown()
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Shutdown.Timeout)
 	defer cancel()
 
+	// Stop accepting RPCs and let in-flight ones finish while publications
+	// in progress complete and the due publish retries are flushed
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		if gatewaySrv != nil {
+			if err := gatewaySrv.Shutdown(ctx); err != nil {
+				appLogger.Error("REST gateway forced to shutdown", zap.Error(err))
+			}
+			gateway.Close()
+		}
+		grpcServer.GracefulStop()
+	}()
+	crlServer.StopWatches()
+	if err := sched.Shutdown(ctx); err != nil {
+		appLogger.Warn("CRL publications did not finish before the shutdown timeout", zap.Error(err))
+	}
+	select {
+	case <-grpcStopped:
+	case <-ctx.Done():
+		appLogger.Warn("gRPC calls did not finish before the shutdown timeout")
+		grpcServer.Stop()
+		<-grpcStopped
+	}
+	stopScheduler()
+	<-schedDone
+
 	if err := srv.Shutdown(ctx); err != nil {
-		logger.Error("Server forced to shutdown", zap.Error(err))
+		appLogger.Error("Server forced to shutdown", zap.Error(err))
+	}
+	if cdp != nil {
+		if err := cdp.Shutdown(ctx); err != nil {
+			appLogger.Error("CRL distribution point forced to shutdown", zap.Error(err))
+		}
+	}
+	if ocspSrv != nil {
+		if err := ocspSrv.Shutdown(ctx); err != nil {
+			appLogger.Error("OCSP responder forced to shutdown", zap.Error(err))
+		}
+	}
+	if diag != nil {
+		// Profiles in progress are of no use once the process exits
+		diag.Close()
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		appLogger.Error("Failed to flush traces", zap.Error(err))
+	}
+
+	appLogger.Info("Server exited")
+}
+
+// connectDatabase opens the connection pool from database.dsn, or from the
+// individual database settings without one
+func connectDatabase(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	if cfg.DB.DSN == "" {
+		// Built here rather than with db.New, which leaks the pool when the
+		// ping fails and startup retries
+		dsn := fmt.Sprintf("host=%s port=%d dbname=%s user=%s password=%s sslmode=%s",
+			dsnValue(cfg.Database.Host),
+			cfg.Database.Port,
+			dsnValue(cfg.Database.Database),
+			dsnValue(cfg.Database.User),
+			dsnValue(cfg.Database.Password),
+			dsnValue(cfg.Database.SSLMode),
+		)
+		return openPool(ctx, dsn, "database")
+	}
+
+	return openPool(ctx, cfg.DB.DSN, "database.dsn")
+}
+
+// dsnValue quotes a value for a libpq key/value connection string
+func dsnValue(v string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// connectWithRetry calls connect with exponential backoff until it succeeds
+// or timeout has passed, so that the service waits out a database that is
+// starting or failing over instead of exiting
+func connectWithRetry(ctx context.Context, timeout time.Duration, what string, connect func(context.Context) (*pgxpool.Pool, error)) (*pgxpool.Pool, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	delay := 500 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		pool, err := connect(ctx)
+		if err == nil {
+			return pool, nil
+		}
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		}
+		logger.Global().Warn("Failed to connect to "+what+"; retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("retry_in", delay),
+			zap.Error(err),
+		)
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, 10*time.Second)
+	}
+}
+
+// openPool opens a connection pool from the DSN of a setting
+func openPool(ctx context.Context, dsn, setting string) (*pgxpool.Pool, error) {
+	// The DSN may hold the password, so errors do not repeat it
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s", setting)
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection pool to %s:%d", poolCfg.ConnConfig.Host, poolCfg.ConnConfig.Port)
+	}
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to ping database at %s:%d", poolCfg.ConnConfig.Host, poolCfg.ConnConfig.Port)
+	}
+	return pool, nil
+}
+
+// newLogger builds the logger like logger.New, and returns its level so that
+// configuration reloads can change it
+func newLogger(level, format string) (*logger.Logger, zap.AtomicLevel, error) {
+	zcfg := zap.NewDevelopmentConfig()
+	zcfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+	if format == "json" {
+		zcfg = zap.NewProductionConfig()
+	}
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return nil, zap.AtomicLevel{}, err
 	}
+	zcfg.Level = zap.NewAtomicLevelAt(lvl)
 
-	logger.Info("Server exited")
+	z, err := zcfg.Build()
+	if err != nil {
+		return nil, zap.AtomicLevel{}, err
+	}
+	return &logger.Logger{Logger: z}, zcfg.Level, nil
+}
+
+// newTargets builds the publishers and CDN purgers of an issuer. On error
+// the publishers built so far are closed.
+func newTargets(ctx context.Context, ic config.IssuerConfig) ([]publisher.Publisher, []cdn.Purger, error) {
+	var publishers []publisher.Publisher
+	for _, pc := range ic.Publishers {
+		p, err := publisher.New(ctx, pc)
+		if err != nil {
+			publisher.CloseAll(publishers)
+			return nil, nil, fmt.Errorf("failed to initialize %s publisher: %w", pc.Type, err)
+		}
+		publishers = append(publishers, p)
+	}
+
+	var purgers []cdn.Purger
+	for _, cc := range ic.CDN {
+		p, err := cdn.New(ctx, cc)
+		if err != nil {
+			publisher.CloseAll(publishers)
+			return nil, nil, fmt.Errorf("failed to initialize %s CDN purger: %w", cc.Type, err)
+		}
+		purgers = append(purgers, p)
+	}
+	return publishers, purgers, nil
+}
+
+// applyReload applies the runtime settings of a reloaded configuration:
+// the log level and each issuer's validity window, schedule, distribution
+// points, verification, publishers and CDNs. An issuer whose settings or
+// targets fail to apply keeps its previous ones. Other changes, such as
+// new issuers, signers or listeners, take effect on restart.
+func applyReload(ctx context.Context, cfg *config.Config, logLevel zap.AtomicLevel, issuers *generator.Registry, sched *scheduler.Scheduler) error {
+	lvl, err := zapcore.ParseLevel(cfg.Logging.Level)
+	if err != nil {
+		return fmt.Errorf("invalid logging.level: %w", err)
+	}
+	logLevel.SetLevel(lvl)
+
+	var errs []error
+	for _, ic := range cfg.IssuerConfigs() {
+		gen, err := issuers.Get(ic.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("issuer %q is new and needs a restart", ic.ID))
+			continue
+		}
+		publishers, purgers, err := newTargets(ctx, ic)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("issuer %q: %w", ic.ID, err))
+			continue
+		}
+		if err := gen.Reload(ic.CRL); err != nil {
+			publisher.CloseAll(publishers)
+			errs = append(errs, fmt.Errorf("issuer %q: %w", ic.ID, err))
+			continue
+		}
+		gen.SetTargets(publishers, purgers)
+		sched.Reschedule(ic.ID)
+	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/gigvault/crl/internal/config"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/db"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// runMigrate implements the migrate subcommand, which applies the embedded
+// migrations the database lacks and exits
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	baseline := flags.Int("baseline", 0, "record migrations up to this version as applied without running them, for a schema applied by hand")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *baseline < 0 || *baseline > store.SchemaVersion() {
+		return fmt.Errorf("baseline must be between 1 and %d", store.SchemaVersion())
+	}
+
+	pool, err := connectDatabase(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close(pool)
+	st := store.New(pool)
+
+	if *baseline > 0 {
+		if err := st.BaselineSchema(ctx, *baseline); err != nil {
+			return err
+		}
+		logger.Global().Info("Recorded migrations as applied", zap.Int("baseline", *baseline))
+	}
+	return migrate(ctx, st)
+}
+
+// migrate applies the pending migrations, logging each one
+func migrate(ctx context.Context, st *store.Store) error {
+	applied, err := st.Migrate(ctx)
+	for _, m := range applied {
+		logger.Global().Info("Applied migration", zap.Int("version", m.Version), zap.String("name", m.Name))
+	}
+	if err != nil {
+		return err
+	}
+	logger.Global().Info("Database schema is current", zap.Int("version", store.SchemaVersion()))
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	crlpb "github.com/gigvault/crl/api/proto/crl"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// runPublish signs and distributes an issuer's CRLs with PublishCRL
+func runPublish(ctx context.Context, c *cli, args []string) error {
+	flags := newFlags("publish", "[flags]")
+	issuer := flags.String("issuer", "", "issuer ID (default: the service's default issuer)")
+	force := flags.Bool("force", false, "publish even if nothing changed")
+	validity := flags.Duration("validity", 0, "override the validity of the CRLs (nextUpdate minus signing time)")
+	overlap := flags.Duration("overlap", 0, "override the thisUpdate backdating")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	req := &crlpb.PublishCRLRequest{Force: *force, IssuerId: *issuer}
+	if *validity != 0 {
+		req.Validity = durationpb.New(*validity)
+	}
+	if *overlap != 0 {
+		req.Overlap = durationpb.New(*overlap)
+	}
+	resp, err := c.rpc.PublishCRL(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := c.print(resp, func(w io.Writer) {
+		fmt.Fprintf(w, "%s: %d partitions, %d entries, published %s\n", resp.GetMessage(),
+			resp.GetPartitions(), resp.GetRevokedCount(), formatTime(resp.GetPublishedAt()))
+		for _, t := range resp.GetTargets() {
+			result := "ok"
+			if !t.GetSuccess() {
+				result = "failed: " + t.GetError()
+			}
+			fmt.Fprintf(w, "  %s partition %d: %s (%s)\n", t.GetPublisher(), t.GetPartition(), result,
+				t.GetDuration().AsDuration().Round(time.Millisecond))
+		}
+	}); err != nil {
+		return err
+	}
+	if !resp.GetSuccess() {
+		return errors.New("publication did not fully succeed")
+	}
+	return nil
+}
+
+// runFetch downloads a CRL with GetCRLStream, so CRLs too large for one
+// message work too
+func runFetch(ctx context.Context, c *cli, args []string) error {
+	flags := newFlags("crl-fetch", "[flags]")
+	issuer := flags.String("issuer", "", "issuer ID or CA common name (default: the default issuer)")
+	format := flags.String("format", "der", "der or pem")
+	delta := flags.Bool("delta", false, "fetch the delta CRL")
+	partition := flags.Int("partition", 0, "partition of a partitioned issuer")
+	number := flags.Int64("number", 0, "fetch the recorded CRL with this number")
+	output := flags.String("output", "-", "file to write, - for standard output")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	req := &crlpb.GetCRLRequest{
+		Issuer:    *issuer,
+		Delta:     *delta,
+		Partition: int32(*partition),
+		CrlNumber: *number,
+	}
+	switch *format {
+	case "der":
+		req.Format = crlpb.CRLFormat_CRL_FORMAT_DER
+	case "pem":
+		req.Format = crlpb.CRLFormat_CRL_FORMAT_PEM
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	stream, err := c.rpc.GetCRLStream(ctx, req)
+	if err != nil {
+		return err
+	}
+	var (
+		first *crlpb.GetCRLChunk
+		data  []byte
+	)
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if first == nil {
+			first = chunk
+		}
+		data = append(data, chunk.GetData()...)
+	}
+	if first == nil {
+		return errors.New("empty CRL stream")
+	}
+	if total := first.GetTotalSize(); total != 0 && int64(len(data)) != total {
+		return fmt.Errorf("received %d of %d bytes", len(data), total)
+	}
+
+	if *output == "-" {
+		if _, err := os.Stdout.Write(data); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(*output, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	// The summary goes to stderr so the CRL can be piped
+	kind := "CRL"
+	if first.GetBaseCrlNumber() != 0 {
+		kind = fmt.Sprintf("delta CRL (base %d)", first.GetBaseCrlNumber())
+	}
+	fmt.Fprintf(os.Stderr, "%s %d, partition %d: %d entries, thisUpdate %s, nextUpdate %s, %d bytes, DER sha256 %x\n",
+		kind, first.GetCrlNumber(), first.GetPartition(), first.GetRevokedCount(),
+		formatTime(first.GetThisUpdate()), formatTime(first.GetNextUpdate()), len(data), first.GetSha256())
+	if first.GetStale() {
+		fmt.Fprintln(os.Stderr, "warning: stale CRL, served because a new one could not be signed")
+	}
+	return nil
+}
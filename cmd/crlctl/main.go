@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	crlpb "github.com/gigvault/crl/api/proto/crl"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Metadata keys of the service's credentials
+const (
+	apiKeyHeader     = "x-api-key"
+	adminTokenHeader = "x-admin-token"
+)
+
+// command is a crlctl subcommand
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(ctx context.Context, c *cli, args []string) error
+}
+
+var commands = []command{
+	{"revoke", "[flags] SERIAL", "revoke a certificate, or put it on hold", runRevoke},
+	{"unrevoke", "[flags] SERIAL", "release a hold, or remove an erroneous revocation", runUnrevoke},
+	{"status", "[flags] SERIAL...", "check the revocation status of serials", runStatus},
+	{"list", "[flags]", "list an issuer's revocations, newest first", runList},
+	{"publish", "[flags]", "sign and distribute an issuer's CRLs", runPublish},
+	{"crl-fetch", "[flags]", "download a CRL", runFetch},
+}
+
+// cli is the state shared by the subcommands
+type cli struct {
+	rpc        crlpb.CRLServiceClient
+	adminToken string
+	json       bool
+	out        io.Writer
+}
+
+func main() {
+	flags := flag.NewFlagSet("crlctl", flag.ContinueOnError)
+	flags.Usage = func() { usage(flags) }
+	addr := flags.String("addr", envOr("CRLCTL_ADDR", "localhost:9085"), "gRPC address of the CRL service (CRLCTL_ADDR)")
+	useTLS := flags.Bool("tls", false, "connect with TLS")
+	caFile := flags.String("ca-file", "", "CA certificates verifying the server, instead of the system roots")
+	certFile := flags.String("cert", "", "client certificate for mTLS")
+	keyFile := flags.String("key", "", "client key for mTLS")
+	serverName := flags.String("server-name", "", "server name to verify, if not the host of -addr")
+	apiKey := flags.String("api-key", os.Getenv("CRLCTL_API_KEY"), "API key (CRLCTL_API_KEY)")
+	token := flags.String("token", os.Getenv("CRLCTL_TOKEN"), "OIDC bearer token (CRLCTL_TOKEN)")
+	adminToken := flags.String("admin-token", os.Getenv("CRLCTL_ADMIN_TOKEN"), "admin token for unrevoke -remove (CRLCTL_ADMIN_TOKEN)")
+	timeout := flags.Duration("timeout", 30*time.Second, "deadline of the whole command")
+	jsonOut := flags.Bool("json", false, "print responses as JSON")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	if flags.NArg() == 0 {
+		usage(flags)
+		os.Exit(2)
+	}
+	name := flags.Arg(0)
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == name {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "crlctl: unknown command %q\n", name)
+		usage(flags)
+		os.Exit(2)
+	}
+
+	creds := insecure.NewCredentials()
+	if *useTLS || *caFile != "" || *certFile != "" {
+		tlsConfig, err := clientTLS(*caFile, *certFile, *keyFile, *serverName)
+		if err != nil {
+			fail(name, err)
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(*addr, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		fail(name, err)
+	}
+	defer conn.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+	if *apiKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, apiKeyHeader, *apiKey)
+	}
+	if *token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+*token)
+	}
+
+	c := &cli{
+		rpc:        crlpb.NewCRLServiceClient(conn),
+		adminToken: *adminToken,
+		json:       *jsonOut,
+		out:        os.Stdout,
+	}
+	if err := cmd.run(ctx, c, flags.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fail(name, err)
+	}
+}
+
+func usage(flags *flag.FlagSet) {
+	fmt.Fprintln(os.Stderr, "Usage: crlctl [flags] COMMAND [command flags] [args]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun crlctl COMMAND -h for the flags of a command.\n\nFlags:")
+	flags.PrintDefaults()
+}
+
+// newFlags returns the flag set of a subcommand
+func newFlags(name, args string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: crlctl %s %s\n", name, args)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// fail prints err, with the details of a gRPC status, and exits
+func fail(name string, err error) {
+	fmt.Fprintf(os.Stderr, "crlctl %s: %s\n", name, describe(err))
+	os.Exit(1)
+}
+
+// describe formats an error, spelling out the code and field violations of
+// a gRPC status
+func describe(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err.Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", st.Code(), st.Message())
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				fmt.Fprintf(&b, "\n  %s: %s", v.GetField(), v.GetDescription())
+			}
+		case *errdetails.PreconditionFailure:
+			for _, v := range d.GetViolations() {
+				fmt.Fprintf(&b, "\n  %s %s: %s", v.GetType(), v.GetSubject(), v.GetDescription())
+			}
+		case *errdetails.RetryInfo:
+			fmt.Fprintf(&b, "\n  retry after %s", d.GetRetryDelay().AsDuration())
+		}
+	}
+	return b.String()
+}
+
+// clientTLS builds the TLS config of the connection
+func clientTLS(caFile, certFile, keyFile, serverName string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: serverName}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates in CA file")
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// print writes a response as JSON with -json, or with text otherwise
+func (c *cli) print(m proto.Message, text func(w io.Writer)) error {
+	if !c.json {
+		text(c.out)
+		return nil
+	}
+	data, err := protojson.MarshalOptions{Multiline: true, UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(c.out, string(data))
+	return err
+}
+
+// parseTime parses an optional RFC 3339 flag value
+func parseTime(name, value string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -%s: %w", name, err)
+	}
+	return timestamppb.New(t), nil
+}
+
+// formatTime formats an optional timestamp for text output
+func formatTime(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return "-"
+	}
+	return ts.AsTime().UTC().Format(time.RFC3339)
+}
+
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	crlpb "github.com/gigvault/crl/api/proto/crl"
+	"google.golang.org/grpc/metadata"
+)
+
+// runRevoke adds a revocation with AddRevocation, or with -hold puts the
+// certificate on hold
+func runRevoke(ctx context.Context, c *cli, args []string) error {
+	flags := newFlags("revoke", "[flags] SERIAL")
+	issuer := flags.String("issuer", "", "issuer ID (default: the service's default issuer)")
+	reason := flags.String("reason", "unspecified", "RFC 5280 reason name, e.g. keyCompromise or superseded")
+	hold := flags.Bool("hold", false, "put the certificate on hold instead (certificateHold)")
+	revokedAt := flags.String("revoked-at", "", "RFC 3339 revocation time (default: now)")
+	invalidity := flags.String("invalidity-date", "", "RFC 3339 time the key is believed compromised")
+	effectiveAt := flags.String("effective-at", "", "RFC 3339 time the revocation takes effect")
+	requestedBy := flags.String("requested-by", "", "identity requesting the revocation, when approval is required")
+	idempotencyKey := flags.String("idempotency-key", "", "key making a retried revoke return the first response")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected one serial")
+	}
+	serial := flags.Arg(0)
+	at, err := parseTime("revoked-at", *revokedAt)
+	if err != nil {
+		return err
+	}
+
+	if *hold {
+		resp, err := c.rpc.HoldCertificate(ctx, &crlpb.HoldCertificateRequest{
+			SerialNumber: serial,
+			HeldAt:       at,
+			IssuerId:     *issuer,
+		})
+		if err != nil {
+			return err
+		}
+		return c.print(resp, func(w io.Writer) {
+			fmt.Fprintf(w, "%s on hold: %s\n", serial, resp.GetMessage())
+		})
+	}
+
+	req := &crlpb.AddRevocationRequest{
+		SerialNumber:   serial,
+		RevokedAt:      at,
+		Reason:         *reason,
+		IssuerId:       *issuer,
+		RequestedBy:    *requestedBy,
+		IdempotencyKey: *idempotencyKey,
+	}
+	if req.InvalidityDate, err = parseTime("invalidity-date", *invalidity); err != nil {
+		return err
+	}
+	if req.EffectiveAt, err = parseTime("effective-at", *effectiveAt); err != nil {
+		return err
+	}
+	resp, err := c.rpc.AddRevocation(ctx, req)
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		if resp.GetPendingApproval() {
+			fmt.Fprintf(w, "%s awaits approval %d\n", serial, resp.GetApprovalId())
+			return
+		}
+		fmt.Fprintf(w, "%s revoked: %s\n", serial, resp.GetMessage())
+	})
+}
+
+// runUnrevoke releases a hold with ReleaseHold, or with -remove withdraws
+// an erroneous revocation with DeleteRevocation
+func runUnrevoke(ctx context.Context, c *cli, args []string) error {
+	flags := newFlags("unrevoke", "[flags] SERIAL")
+	issuer := flags.String("issuer", "", "issuer ID (default: the service's default issuer)")
+	remove := flags.Bool("remove", false, "remove an erroneous revocation (needs -admin-token)")
+	removedBy := flags.String("removed-by", "", "operator responsible for the removal")
+	justification := flags.String("justification", "", "why the revocation was erroneous")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("expected one serial")
+	}
+	serial := flags.Arg(0)
+
+	if !*remove {
+		resp, err := c.rpc.ReleaseHold(ctx, &crlpb.ReleaseHoldRequest{SerialNumber: serial, IssuerId: *issuer})
+		if err != nil {
+			return err
+		}
+		return c.print(resp, func(w io.Writer) {
+			fmt.Fprintf(w, "%s released: %s\n", serial, resp.GetMessage())
+		})
+	}
+
+	if *removedBy == "" || *justification == "" {
+		return errors.New("-remove needs -removed-by and -justification")
+	}
+	if c.adminToken == "" {
+		return errors.New("-remove needs -admin-token or CRLCTL_ADMIN_TOKEN")
+	}
+	ctx = metadata.AppendToOutgoingContext(ctx, adminTokenHeader, c.adminToken)
+	resp, err := c.rpc.DeleteRevocation(ctx, &crlpb.DeleteRevocationRequest{
+		SerialNumber:  serial,
+		IssuerId:      *issuer,
+		RemovedBy:     *removedBy,
+		Justification: *justification,
+	})
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintf(w, "%s removed: %s\n", serial, resp.GetMessage())
+	})
+}
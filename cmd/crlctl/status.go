@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	crlpb "github.com/gigvault/crl/api/proto/crl"
+)
+
+// maxPageSize is the largest page ListRevocations returns
+const maxPageSize = 1000
+
+// runStatus checks one serial with CheckRevocationStatus, or several in one
+// CheckStatusBatch
+func runStatus(ctx context.Context, c *cli, args []string) error {
+	flags := newFlags("status", "[flags] SERIAL...")
+	issuer := flags.String("issuer", "", "issuer ID (default: the service's default issuer)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("expected at least one serial")
+	}
+
+	if flags.NArg() == 1 {
+		serial := flags.Arg(0)
+		resp, err := c.rpc.CheckRevocationStatus(ctx, &crlpb.CheckRevocationStatusRequest{SerialNumber: serial, IssuerId: *issuer})
+		if err != nil {
+			return err
+		}
+		return c.print(resp, func(w io.Writer) {
+			printStatuses(w, []*crlpb.SerialStatus{{SerialNumber: serial, Status: resp}})
+		})
+	}
+	resp, err := c.rpc.CheckStatusBatch(ctx, &crlpb.CheckStatusBatchRequest{SerialNumbers: flags.Args(), IssuerId: *issuer})
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		printStatuses(w, resp.GetStatuses())
+	})
+}
+
+func printStatuses(out io.Writer, statuses []*crlpb.SerialStatus) {
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SERIAL\tSTATUS\tREASON\tREVOKED AT\tEFFECTIVE AT")
+	for _, s := range statuses {
+		st := s.GetStatus()
+		state := "good"
+		switch {
+		case st.GetOnHold():
+			state = "on hold"
+		case st.GetRevoked():
+			state = "revoked"
+		case st.GetEffectiveAt() != nil:
+			state = "scheduled"
+		}
+		reason := st.GetReason()
+		if reason == "" {
+			reason = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.GetSerialNumber(), state, reason,
+			formatTime(st.GetRevokedAt()), formatTime(st.GetEffectiveAt()))
+	}
+	w.Flush()
+}
+
+// runList pages through ListRevocations
+func runList(ctx context.Context, c *cli, args []string) error {
+	flags := newFlags("list", "[flags]")
+	issuer := flags.String("issuer", "", "issuer ID (default: the service's default issuer)")
+	reasons := flags.String("reasons", "", "comma-separated RFC 5280 reason names; empty lists all")
+	after := flags.String("revoked-after", "", "list revocations at or after this RFC 3339 time")
+	before := flags.String("revoked-before", "", "list revocations before this RFC 3339 time")
+	released := flags.Bool("include-released", false, "also list released holds and removed revocations")
+	archived := flags.Bool("include-archived", false, "also list entries moved to the archive")
+	limit := flags.Int("limit", 100, "list at most this many revocations; 0 lists all")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *limit < 0 {
+		return errors.New("-limit must not be negative")
+	}
+
+	req := &crlpb.ListRevocationsRequest{
+		IssuerId:        *issuer,
+		IncludeReleased: *released,
+		IncludeArchived: *archived,
+	}
+	if *reasons != "" {
+		for _, r := range strings.Split(*reasons, ",") {
+			req.Reasons = append(req.Reasons, strings.TrimSpace(r))
+		}
+	}
+	var err error
+	if req.RevokedAfter, err = parseTime("revoked-after", *after); err != nil {
+		return err
+	}
+	if req.RevokedBefore, err = parseTime("revoked-before", *before); err != nil {
+		return err
+	}
+
+	all := &crlpb.ListRevocationsResponse{}
+	for {
+		req.PageSize = maxPageSize
+		if *limit > 0 {
+			req.PageSize = int32(min(*limit-len(all.Revocations), maxPageSize))
+		}
+		resp, err := c.rpc.ListRevocations(ctx, req)
+		if err != nil {
+			return err
+		}
+		all.Revocations = append(all.Revocations, resp.GetRevocations()...)
+		all.NextPageToken = resp.GetNextPageToken()
+		if all.NextPageToken == "" || (*limit > 0 && len(all.Revocations) >= *limit) {
+			break
+		}
+		req.PageToken = all.NextPageToken
+	}
+
+	return c.print(all, func(out io.Writer) {
+		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "SERIAL\tSTATUS\tREASON\tREVOKED AT\tUPDATED AT")
+		for _, r := range all.Revocations {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.GetSerialNumber(), r.GetStatus(), r.GetReason(),
+				formatTime(r.GetRevokedAt()), formatTime(r.GetUpdatedAt()))
+		}
+		w.Flush()
+		if all.NextPageToken != "" {
+			fmt.Fprintln(os.Stderr, "more revocations follow; raise -limit to list them")
+		}
+	})
+}
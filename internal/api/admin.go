@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// adminTokenHeader is the gRPC metadata key carrying the admin token
+const adminTokenHeader = "x-admin-token"
+
+// AdminConfig enables the destructive admin RPCs
+type AdminConfig struct {
+	// DeleteRevocation enables the DeleteRevocation RPC
+	DeleteRevocation bool `yaml:"delete_revocation"`
+
+	// Token must be sent as x-admin-token metadata; falls back to
+	// CRL_ADMIN_TOKEN
+	Token string `yaml:"token"`
+}
+
+// Validate checks that enabled admin RPCs have a token
+func (c *AdminConfig) Validate() error {
+	if c.Token == "" {
+		c.Token = os.Getenv("CRL_ADMIN_TOKEN")
+	}
+	if c.DeleteRevocation && c.Token == "" {
+		return errors.New("token is required when delete_revocation is enabled")
+	}
+	return nil
+}
+
+// authorizeAdmin checks the admin token of a request
+func (s *CRLGRPCServer) authorizeAdmin(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	tokens := md.Get(adminTokenHeader)
+	if len(tokens) != 1 || subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(s.admin.Token)) != 1 {
+		return status.Error(codes.PermissionDenied, "admin token missing or invalid")
+	}
+	return nil
+}
+
+// DeleteRevocation withdraws an erroneous revocation or hold. The entry is
+// kept with status removed and the operator and justification, so delta
+// CRLs report removeFromCRL and the next full CRL omits it.
+func (s *CRLGRPCServer) DeleteRevocation(ctx context.Context, req *crl.DeleteRevocationRequest) (*crl.DeleteRevocationResponse, error) {
+	s.logger.Info("Received DeleteRevocation request",
+		zap.String("issuer_id", req.IssuerId),
+		zap.String("serial", req.SerialNumber),
+		zap.String("removed_by", req.RemovedBy),
+	)
+
+	if !s.admin.DeleteRevocation {
+		return nil, status.Error(codes.PermissionDenied, "DeleteRevocation is disabled")
+	}
+	if err := s.authorizeAdmin(ctx); err != nil {
+		s.logger.Warn("Unauthorized DeleteRevocation request", zap.String("serial", req.SerialNumber))
+		return nil, err
+	}
+
+	serialHex, err := serialField("serial_number", req.SerialNumber)
+	if err != nil {
+		return nil, err
+	}
+	removedBy := actor(ctx, req.RemovedBy)
+	if removedBy == "" {
+		return nil, fieldError("removed_by", "removed_by is required")
+	}
+	justification := strings.TrimSpace(req.Justification)
+	if justification == "" {
+		return nil, fieldError("justification", "justification is required")
+	}
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	serial, err := s.store.RemoveEntry(ctx, gen.ID(), serialHex, removedBy, justification)
+	switch {
+	case errors.Is(err, store.ErrEntryNotFound):
+		return nil, notFoundError(resourceRevocation, serialHex, "no revocation recorded for serial")
+	case err != nil:
+		s.logger.Error("Failed to delete revocation", zap.Error(err))
+		return nil, storeError("failed to delete revocation")
+	}
+
+	s.logger.Warn("Revocation removed",
+		zap.String("issuer_id", gen.ID()),
+		zap.String("serial", serial),
+		zap.String("removed_by", removedBy),
+		zap.String("justification", justification),
+	)
+	s.entryChanged(ctx, gen.ID(), serial)
+
+	return &crl.DeleteRevocationResponse{
+		Success: true,
+		Message: "revocation removed; the next CRL omits it",
+	}, nil
+}
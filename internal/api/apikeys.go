@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/auth"
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// APIKeyMethods are the key management RPCs. They are authorized by the
+// admin token, so they can issue the first key.
+var APIKeyMethods = []string{
+	crl.CRLService_CreateAPIKey_FullMethodName,
+	crl.CRLService_RotateAPIKey_FullMethodName,
+	crl.CRLService_RevokeAPIKey_FullMethodName,
+}
+
+// authorizeKeyManagement checks that API keys are enabled and the request
+// carries the admin token
+func (s *CRLGRPCServer) authorizeKeyManagement(ctx context.Context) error {
+	if !s.apiKeys.Enabled {
+		return status.Error(codes.PermissionDenied, "api keys are disabled")
+	}
+	return s.authorizeAdmin(ctx)
+}
+
+// validateGrants checks that roles are built-in or custom roles and methods
+// are RPCs of the service, so a key never carries grants that match nothing
+func (s *CRLGRPCServer) validateGrants(roles, methods []string) error {
+	if err := auth.ValidateRoles(roles, s.roles); err != nil {
+		return fieldError("roles", err.Error())
+	}
+	if err := auth.ValidateMethods(methods); err != nil {
+		return fieldError("methods", err.Error())
+	}
+	return nil
+}
+
+// keyExpiry converts an optional key lifetime to its expiry time
+func keyExpiry(field string, ttl *durationpb.Duration) (*time.Time, error) {
+	if ttl == nil {
+		return nil, nil
+	}
+	if err := ttl.CheckValid(); err != nil || ttl.AsDuration() <= 0 {
+		return nil, fieldError(field, "ttl must be positive")
+	}
+	t := time.Now().Add(ttl.AsDuration())
+	return &t, nil
+}
+
+// CreateAPIKey issues an API key for a client. The secret is returned once
+// and only its hash is stored.
+func (s *CRLGRPCServer) CreateAPIKey(ctx context.Context, req *crl.CreateAPIKeyRequest) (*crl.CreateAPIKeyResponse, error) {
+	if err := s.authorizeKeyManagement(ctx); err != nil {
+		s.logger.Warn("Unauthorized CreateAPIKey request", zap.String("client", req.Client))
+		return nil, err
+	}
+
+	if req.Client == "" {
+		return nil, fieldError("client", "client is required")
+	}
+	if len(req.Roles) == 0 && len(req.Methods) == 0 {
+		return nil, fieldError("roles", "at least one role or method is required")
+	}
+	if err := s.validateGrants(req.Roles, req.Methods); err != nil {
+		return nil, err
+	}
+	expiresAt, err := keyExpiry("ttl", req.Ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	id, key, hash, err := auth.NewAPIKey()
+	if err != nil {
+		s.logger.Error("Failed to generate api key", zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to generate api key")
+	}
+	created, err := s.store.CreateAPIKey(ctx, store.APIKey{
+		ID:         id,
+		Client:     req.Client,
+		SecretHash: hash,
+		Roles:      req.Roles,
+		Methods:    req.Methods,
+		CreatedBy:  actor(ctx, req.CreatedBy),
+		ExpiresAt:  expiresAt,
+	})
+	if err != nil {
+		s.logger.Error("Failed to create api key", zap.Error(err))
+		return nil, storeError("failed to create api key")
+	}
+
+	s.logger.Info("API key created",
+		zap.String("key_id", created.ID),
+		zap.String("client", created.Client),
+		zap.Strings("roles", created.Roles),
+		zap.Strings("methods", created.Methods),
+	)
+	return &crl.CreateAPIKeyResponse{ApiKey: toProtoAPIKey(created), Key: key}, nil
+}
+
+// RotateAPIKey issues a successor to an API key, with the same client,
+// roles and methods, and lets the old key expire after the rotation grace period
+func (s *CRLGRPCServer) RotateAPIKey(ctx context.Context, req *crl.RotateAPIKeyRequest) (*crl.RotateAPIKeyResponse, error) {
+	if err := s.authorizeKeyManagement(ctx); err != nil {
+		s.logger.Warn("Unauthorized RotateAPIKey request", zap.String("key_id", req.KeyId))
+		return nil, err
+	}
+
+	if req.KeyId == "" {
+		return nil, fieldError("key_id", "key_id is required")
+	}
+	expiresAt, err := keyExpiry("ttl", req.Ttl)
+	if err != nil {
+		return nil, err
+	}
+	// The successor inherits the grants, which must still exist
+	current, err := s.store.GetAPIKey(ctx, req.KeyId)
+	if err != nil {
+		return nil, s.apiKeyError(err, "rotate", req.KeyId)
+	}
+	if err := s.validateGrants(current.Roles, current.Methods); err != nil {
+		return nil, err
+	}
+
+	id, key, hash, err := auth.NewAPIKey()
+	if err != nil {
+		s.logger.Error("Failed to generate api key", zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to generate api key")
+	}
+	old, created, err := s.store.RotateAPIKey(ctx, req.KeyId, store.APIKey{
+		ID:         id,
+		SecretHash: hash,
+		CreatedBy:  actor(ctx, req.RotatedBy),
+		ExpiresAt:  expiresAt,
+	}, s.apiKeys.RotationGrace)
+	if err != nil {
+		return nil, s.apiKeyError(err, "rotate", req.KeyId)
+	}
+
+	s.logger.Info("API key rotated",
+		zap.String("key_id", created.ID),
+		zap.String("rotated_from", old.ID),
+		zap.String("client", created.Client),
+		zap.Timep("previous_expires_at", old.ExpiresAt),
+	)
+	return &crl.RotateAPIKeyResponse{
+		ApiKey:   toProtoAPIKey(created),
+		Key:      key,
+		Previous: toProtoAPIKey(old),
+	}, nil
+}
+
+// RevokeAPIKey disables an API key immediately
+func (s *CRLGRPCServer) RevokeAPIKey(ctx context.Context, req *crl.RevokeAPIKeyRequest) (*crl.RevokeAPIKeyResponse, error) {
+	if err := s.authorizeKeyManagement(ctx); err != nil {
+		s.logger.Warn("Unauthorized RevokeAPIKey request", zap.String("key_id", req.KeyId))
+		return nil, err
+	}
+
+	if req.KeyId == "" {
+		return nil, fieldError("key_id", "key_id is required")
+	}
+
+	revoked, err := s.store.RevokeAPIKey(ctx, req.KeyId)
+	if err != nil {
+		return nil, s.apiKeyError(err, "revoke", req.KeyId)
+	}
+
+	s.logger.Warn("API key revoked",
+		zap.String("key_id", revoked.ID),
+		zap.String("client", revoked.Client),
+		zap.String("revoked_by", actor(ctx, req.RevokedBy)),
+	)
+	return &crl.RevokeAPIKeyResponse{ApiKey: toProtoAPIKey(revoked)}, nil
+}
+
+// apiKeyError maps a store error of operation on a key to a status error
+func (s *CRLGRPCServer) apiKeyError(err error, operation, keyID string) error {
+	switch {
+	case errors.Is(err, store.ErrAPIKeyNotFound):
+		return notFoundError(resourceAPIKey, keyID, "api key not found")
+	case errors.Is(err, store.ErrAPIKeyInactive):
+		return preconditionError(preconditionAPIKeyState, keyID, "api key is revoked or expired")
+	}
+	s.logger.Error("Failed to "+operation+" api key", zap.String("key_id", keyID), zap.Error(err))
+	return storeError("failed to " + operation + " api key")
+}
+
+// toProtoAPIKey converts a stored key, without its secret hash
+func toProtoAPIKey(k *store.APIKey) *crl.APIKey {
+	msg := &crl.APIKey{
+		KeyId:       k.ID,
+		Client:      k.Client,
+		Roles:       k.Roles,
+		Methods:     k.Methods,
+		CreatedBy:   k.CreatedBy,
+		CreatedAt:   timestamppb.New(k.CreatedAt),
+		RotatedFrom: k.RotatedFrom,
+	}
+	if k.ExpiresAt != nil {
+		msg.ExpiresAt = timestamppb.New(*k.ExpiresAt)
+	}
+	if k.RevokedAt != nil {
+		msg.RevokedAt = timestamppb.New(*k.RevokedAt)
+	}
+	return msg
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateAPIKeyRejectsUnknownGrants(t *testing.T) {
+	s := NewCRLGRPCServer(nil, nil, nil, nil, nil, nil, nil, AdminConfig{Token: "admin"}, ApprovalConfig{}, auth.Config{
+		APIKeys: auth.APIKeyConfig{Enabled: true},
+		Roles:   map[string][]string{"auditor": {"QueryAuditLog"}},
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(adminTokenHeader, "admin"))
+
+	tests := []struct {
+		name    string
+		roles   []string
+		methods []string
+		field   string
+	}{
+		{"unknown role", []string{"reader", "superuser"}, nil, "roles"},
+		{"role of another deployment", []string{"Auditor"}, nil, "roles"},
+		{"unknown method", []string{"auditor"}, []string{"AddRevocation", "RevokeEverything"}, "methods"},
+		{"method of another service", nil, []string{"/grpc.health.v1.Health/Check"}, "methods"},
+		{"method without its service", nil, []string{"Check"}, "methods"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreateAPIKey(ctx, &crl.CreateAPIKeyRequest{Client: "ra", Roles: tt.roles, Methods: tt.methods})
+			st := status.Convert(err)
+			if st.Code() != codes.InvalidArgument {
+				t.Fatalf("got %v (%v), want InvalidArgument", st.Code(), err)
+			}
+			for _, d := range st.Details() {
+				if br, ok := d.(*errdetails.BadRequest); ok && br.FieldViolations[0].Field == tt.field {
+					return
+				}
+			}
+			t.Fatalf("no violation of field %s in %v", tt.field, st.Details())
+		})
+	}
+}
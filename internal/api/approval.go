@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultApprovalExpiry is how long a revocation may await approval
+const defaultApprovalExpiry = 24 * time.Hour
+
+// maxPendingListed bounds the results of ListPendingRevocations
+const maxPendingListed = 1000
+
+// ApprovalConfig configures two-person approval of revocations
+type ApprovalConfig struct {
+	// Enabled queues AddRevocation and AddRevocations for approval by a
+	// second identity instead of adding them
+	Enabled bool `yaml:"enabled"`
+
+	// Expiry is how long a request may await approval (default 24h)
+	Expiry time.Duration `yaml:"expiry"`
+}
+
+// Validate applies defaults and checks the approval config
+func (c *ApprovalConfig) Validate() error {
+	if c.Expiry < 0 {
+		return errors.New("expiry must not be negative")
+	}
+	if c.Expiry == 0 {
+		c.Expiry = defaultApprovalExpiry
+	}
+	return nil
+}
+
+// queueApproval stores a validated revocation pending approval
+func (s *CRLGRPCServer) queueApproval(ctx context.Context, req *crl.AddRevocationRequest, entry store.Entry) (*crl.AddRevocationResponse, error) {
+	requestedBy := actor(ctx, req.RequestedBy)
+	if requestedBy == "" {
+		return nil, fieldError("requested_by", "requested_by is required when approval is enabled")
+	}
+
+	ids, err := s.store.AddApprovals(ctx, []store.Approval{{Entry: entry, RequestedBy: requestedBy}})
+	if err != nil {
+		s.logger.Error("Failed to queue revocation for approval", zap.Error(err))
+		return nil, storeError("failed to add revocation")
+	}
+
+	s.logger.Info("Revocation awaiting approval",
+		zap.String("issuer_id", entry.IssuerID),
+		zap.String("serial", entry.Serial),
+		zap.String("requested_by", requestedBy),
+		zap.Int64("approval_id", ids[0]),
+	)
+
+	return &crl.AddRevocationResponse{
+		Success:         true,
+		Message:         "revocation awaiting approval",
+		PendingApproval: true,
+		ApprovalId:      ids[0],
+	}, nil
+}
+
+// queueApprovals stores the validated revocations of an AddRevocations
+// request pending approval; valid holds their request indexes
+func (s *CRLGRPCServer) queueApprovals(ctx context.Context, req *crl.AddRevocationsRequest, entries []store.Entry, valid []int, results []*crl.AddRevocationResult, failed int) (*crl.AddRevocationsResponse, error) {
+	approvals := make([]store.Approval, len(entries))
+	for n, i := range valid {
+		approvals[n] = store.Approval{Entry: entries[n], RequestedBy: actor(ctx, req.Revocations[i].RequestedBy)}
+	}
+
+	if len(approvals) > 0 {
+		ids, err := s.store.AddApprovals(ctx, approvals)
+		if err != nil {
+			s.logger.Error("Failed to queue revocations for approval", zap.Int("revocations", len(approvals)), zap.Error(err))
+			return nil, storeError("failed to add revocations")
+		}
+		for n, i := range valid {
+			results[i].Success = true
+			results[i].ApprovalId = ids[n]
+		}
+	}
+
+	s.logger.Info("Revocations awaiting approval", zap.Int("pending", len(approvals)), zap.Int("failed", failed))
+
+	return &crl.AddRevocationsResponse{
+		Results: results,
+		Added:   int32(len(approvals)),
+		Failed:  int32(failed),
+	}, nil
+}
+
+// approvalName is the ResourceInfo and PreconditionFailure subject of an
+// approval request
+func approvalName(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// ApproveRevocation adds a revocation pending approval
+func (s *CRLGRPCServer) ApproveRevocation(ctx context.Context, req *crl.ApproveRevocationRequest) (*crl.ApproveRevocationResponse, error) {
+	s.logger.Info("Received ApproveRevocation request",
+		zap.Int64("approval_id", req.ApprovalId),
+		zap.String("approved_by", req.ApprovedBy),
+	)
+
+	approvedBy := actor(ctx, req.ApprovedBy)
+	if approvedBy == "" {
+		return nil, fieldError("approved_by", "approved_by is required")
+	}
+	approval, err := s.store.GetApproval(ctx, req.ApprovalId)
+	switch {
+	case errors.Is(err, store.ErrApprovalNotFound):
+		return nil, notFoundError(resourceApproval, approvalName(req.ApprovalId), err.Error())
+	case err != nil:
+		s.logger.Error("Failed to get revocation approval", zap.Error(err))
+		return nil, storeError("failed to approve revocation")
+	}
+	switch {
+	case approval.Status != store.ApprovalPending:
+		return nil, preconditionError(preconditionApprovalState, approvalName(req.ApprovalId), store.ErrApprovalDecided.Error())
+	case approval.RequestedBy == approvedBy:
+		return nil, status.Error(codes.PermissionDenied, store.ErrSelfApproval.Error())
+	case time.Since(approval.RequestedAt) > s.approval.Expiry:
+		return nil, preconditionError(preconditionApprovalState, approvalName(req.ApprovalId), "revocation approval has expired")
+	}
+
+	approvedAt := time.Now()
+	entry, err := s.store.ApproveRevocation(ctx, req.ApprovalId, approvedBy, approvedAt.Add(-s.approval.Expiry))
+	switch {
+	case errors.Is(err, store.ErrApprovalDecided):
+		// Decided or expired since it was read
+		return nil, preconditionError(preconditionApprovalState, approvalName(req.ApprovalId), err.Error())
+	case err != nil:
+		s.logger.Error("Failed to approve revocation", zap.Error(err))
+		return nil, storeError("failed to approve revocation")
+	}
+
+	s.logger.Info("Revocation approved",
+		zap.String("issuer_id", entry.IssuerID),
+		zap.String("serial", entry.Serial),
+		zap.String("requested_by", approval.RequestedBy),
+		zap.String("approved_by", approvedBy),
+	)
+	s.entryChanged(ctx, entry.IssuerID, entry.Serial)
+	s.triggerEmergency(*entry, approvedAt)
+	if entry.EffectiveAt != nil {
+		s.scheduler.Reschedule(entry.IssuerID)
+	}
+
+	return &crl.ApproveRevocationResponse{
+		Success: true,
+		Message: "revocation approved and added",
+	}, nil
+}
+
+// RejectRevocation discards a revocation pending approval
+func (s *CRLGRPCServer) RejectRevocation(ctx context.Context, req *crl.RejectRevocationRequest) (*crl.RejectRevocationResponse, error) {
+	s.logger.Info("Received RejectRevocation request",
+		zap.Int64("approval_id", req.ApprovalId),
+		zap.String("rejected_by", req.RejectedBy),
+	)
+
+	rejectedBy := actor(ctx, req.RejectedBy)
+	if rejectedBy == "" {
+		return nil, fieldError("rejected_by", "rejected_by is required")
+	}
+
+	err := s.store.RejectApproval(ctx, req.ApprovalId, rejectedBy, strings.TrimSpace(req.Reason))
+	switch {
+	case errors.Is(err, store.ErrApprovalDecided):
+		if _, getErr := s.store.GetApproval(ctx, req.ApprovalId); errors.Is(getErr, store.ErrApprovalNotFound) {
+			return nil, notFoundError(resourceApproval, approvalName(req.ApprovalId), getErr.Error())
+		}
+		return nil, preconditionError(preconditionApprovalState, approvalName(req.ApprovalId), err.Error())
+	case err != nil:
+		s.logger.Error("Failed to reject revocation", zap.Error(err))
+		return nil, storeError("failed to reject revocation")
+	}
+
+	s.logger.Info("Revocation rejected", zap.Int64("approval_id", req.ApprovalId), zap.String("rejected_by", rejectedBy))
+
+	return &crl.RejectRevocationResponse{
+		Success: true,
+		Message: "revocation rejected",
+	}, nil
+}
+
+// ListPendingRevocations returns the unexpired revocations of an issuer
+// awaiting approval
+func (s *CRLGRPCServer) ListPendingRevocations(ctx context.Context, req *crl.ListPendingRevocationsRequest) (*crl.ListPendingRevocationsResponse, error) {
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals, err := s.store.ListPendingApprovals(ctx, gen.ID(), time.Now().Add(-s.approval.Expiry), maxPendingListed)
+	if err != nil {
+		s.logger.Error("Failed to list pending revocations", zap.Error(err))
+		return nil, storeError("failed to list pending revocations")
+	}
+
+	resp := &crl.ListPendingRevocationsResponse{}
+	for i := range approvals {
+		a := &approvals[i]
+		resp.Pending = append(resp.Pending, &crl.PendingRevocation{
+			ApprovalId:  a.ID,
+			Revocation:  revocationRecord(&a.Entry),
+			RequestedBy: a.RequestedBy,
+			RequestedAt: timestamppb.New(a.RequestedAt),
+			ExpiresAt:   timestamppb.New(a.RequestedAt.Add(s.approval.Expiry)),
+		})
+	}
+	return resp, nil
+}
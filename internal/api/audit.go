@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/auth"
+	"github.com/gigvault/crl/internal/serial"
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// auditedMethods are the state-changing RPCs recorded in the audit log
+var auditedMethods = map[string]bool{
+	"AddRevocation":     true,
+	"AddRevocations":    true,
+	"HoldCertificate":   true,
+	"ReleaseHold":       true,
+	"DeleteRevocation":  true,
+	"ApproveRevocation": true,
+	"RejectRevocation":  true,
+	"PublishCRL":        true,
+	"CreateAPIKey":      true,
+	"RotateAPIKey":      true,
+	"RevokeAPIKey":      true,
+}
+
+// AuditInterceptor records every state-changing RPC, with the caller's
+// identity, source IP, target and outcome, in the audit log. A failed
+// audit write is logged and does not fail the request.
+func (s *CRLGRPCServer) AuditInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		action := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+		if !auditedMethods[action] {
+			return handler(ctx, req)
+		}
+
+		resp, err := handler(ctx, req)
+
+		record := auditRecord(ctx, action, req, err)
+		if m, ok := req.(interface{ GetIssuerId() string }); ok {
+			// Requests without an issuer ID target the default issuer
+			if gen, genErr := s.issuers.Get(m.GetIssuerId()); genErr == nil {
+				record.IssuerID = gen.ID()
+			}
+		}
+		records := []store.AuditRecord{record}
+		if batch, ok := req.(*crl.AddRevocationsRequest); ok && err == nil {
+			records = s.batchAuditRecords(record, batch, resp.(*crl.AddRevocationsResponse))
+		}
+		if auditErr := s.store.InsertAuditRecords(ctx, records); auditErr != nil {
+			s.logger.Error("Failed to write audit record",
+				zap.String("action", action),
+				zap.String("actor", record.Actor),
+				zap.Error(auditErr),
+			)
+		}
+		return resp, err
+	}
+}
+
+// auditRecord describes a finished request
+func auditRecord(ctx context.Context, action string, req interface{}, err error) store.AuditRecord {
+	r := store.AuditRecord{
+		Action:   action,
+		SourceIP: auth.SourceIP(ctx),
+		Code:     status.Code(err).String(),
+	}
+	if id, ok := auth.FromContext(ctx); ok {
+		r.Actor, r.AuthMethod = id.Subject, id.Method
+	}
+
+	var detail []string
+	if m, ok := req.(interface{ GetIssuerId() string }); ok {
+		r.IssuerID = m.GetIssuerId()
+	}
+	if m, ok := req.(interface{ GetSerialNumber() string }); ok {
+		r.Serial = auditSerial(m.GetSerialNumber())
+	}
+	if m, ok := req.(interface{ GetReason() string }); ok && m.GetReason() != "" {
+		detail = append(detail, "reason="+m.GetReason())
+	}
+	if m, ok := req.(interface {
+		GetRevocations() []*crl.AddRevocationRequest
+	}); ok {
+		detail = append(detail, fmt.Sprintf("revocations=%d", len(m.GetRevocations())))
+	}
+	if m, ok := req.(interface{ GetApprovalId() int64 }); ok {
+		detail = append(detail, fmt.Sprintf("approval_id=%d", m.GetApprovalId()))
+	}
+	if m, ok := req.(interface{ GetKeyId() string }); ok {
+		detail = append(detail, "key_id="+m.GetKeyId())
+	}
+	if m, ok := req.(interface{ GetClient() string }); ok {
+		detail = append(detail, "client="+strconv.Quote(m.GetClient()))
+	}
+	if m, ok := req.(interface{ GetJustification() string }); ok {
+		detail = append(detail, "justification="+strconv.Quote(m.GetJustification()))
+	}
+	if r.Actor == "" {
+		// Unauthenticated callers are recorded under the identity they declare
+		r.Actor = strings.TrimSpace(declaredActor(req))
+	}
+	if err != nil {
+		detail = append(detail, "error="+strconv.Quote(status.Convert(err).Message()))
+	}
+	r.Detail = strings.Join(detail, " ")
+	return r
+}
+
+// batchAuditRecords records each revocation of a completed AddRevocations
+// request with its own target and outcome
+func (s *CRLGRPCServer) batchAuditRecords(base store.AuditRecord, req *crl.AddRevocationsRequest, resp *crl.AddRevocationsResponse) []store.AuditRecord {
+	records := make([]store.AuditRecord, 0, len(req.Revocations))
+	for i, item := range req.Revocations {
+		r := base
+		r.IssuerID = item.IssuerId
+		if gen, err := s.issuers.Get(item.IssuerId); err == nil {
+			r.IssuerID = gen.ID()
+		}
+		r.Serial = auditSerial(item.SerialNumber)
+		if base.AuthMethod == "" {
+			r.Actor = strings.TrimSpace(item.RequestedBy)
+		}
+		detail := []string{fmt.Sprintf("batch_index=%d", i)}
+		if item.Reason != "" {
+			detail = append(detail, "reason="+item.Reason)
+		}
+		if i < len(resp.Results) && !resp.Results[i].Success {
+			r.Code = codes.Code(resp.Results[i].Code).String()
+			detail = append(detail, "error="+strconv.Quote(resp.Results[i].Error))
+		}
+		r.Detail = strings.Join(detail, " ")
+		records = append(records, r)
+	}
+	return records
+}
+
+// declaredActor returns the operator a request names for itself
+func declaredActor(req interface{}) string {
+	switch r := req.(type) {
+	case *crl.AddRevocationRequest:
+		return r.RequestedBy
+	case *crl.DeleteRevocationRequest:
+		return r.RemovedBy
+	case *crl.ApproveRevocationRequest:
+		return r.ApprovedBy
+	case *crl.RejectRevocationRequest:
+		return r.RejectedBy
+	case *crl.CreateAPIKeyRequest:
+		return r.CreatedBy
+	case *crl.RotateAPIKeyRequest:
+		return r.RotatedBy
+	case *crl.RevokeAPIKeyRequest:
+		return r.RevokedBy
+	}
+	return ""
+}
+
+// actor returns the authenticated identity of a request, or the declared
+// one for unauthenticated callers
+func actor(ctx context.Context, declared string) string {
+	if id, ok := auth.FromContext(ctx); ok {
+		return id.Subject
+	}
+	return strings.TrimSpace(declared)
+}
+
+// auditSerial returns the canonical form of a serial to record, or the
+// serial as sent if it is malformed, so rejected requests are recorded too
+func auditSerial(value string) string {
+	if canonical, err := serial.Normalize(value); err == nil {
+		return canonical
+	}
+	return value
+}
+
+// QueryAuditLog returns a page of audit records, newest first
+func (s *CRLGRPCServer) QueryAuditLog(ctx context.Context, req *crl.QueryAuditLogRequest) (*crl.QueryAuditLogResponse, error) {
+	if req.PageSize < 0 {
+		return nil, fieldError("page_size", "page size must not be negative")
+	}
+	pageSize := int(req.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	var beforeID int64
+	if req.PageToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(req.PageToken)
+		if err == nil {
+			beforeID, err = strconv.ParseInt(string(raw), 10, 64)
+		}
+		if err != nil || beforeID <= 0 {
+			return nil, fieldError("page_token", "invalid page token")
+		}
+	}
+
+	filter := store.AuditFilter{
+		Action:   req.Action,
+		Actor:    req.Actor,
+		IssuerID: req.IssuerId,
+	}
+	if req.SerialNumber != "" {
+		serialHex, err := serialField("serial_number", req.SerialNumber)
+		if err != nil {
+			return nil, err
+		}
+		filter.Serial = serialHex
+	}
+	if req.Since != nil {
+		t := req.Since.AsTime()
+		filter.Since = &t
+	}
+	if req.Until != nil {
+		t := req.Until.AsTime()
+		filter.Until = &t
+	}
+
+	records, err := s.store.ListAuditRecords(ctx, filter, beforeID, pageSize+1)
+	if err != nil {
+		s.logger.Error("Failed to query audit log", zap.Error(err))
+		return nil, storeError("failed to query audit log")
+	}
+
+	resp := &crl.QueryAuditLogResponse{}
+	if len(records) > pageSize {
+		records = records[:pageSize]
+		last := records[len(records)-1].ID
+		resp.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(last, 10)))
+	}
+	for _, r := range records {
+		resp.Records = append(resp.Records, &crl.AuditRecord{
+			Id:           r.ID,
+			OccurredAt:   timestamppb.New(r.OccurredAt),
+			Action:       r.Action,
+			IssuerId:     r.IssuerID,
+			SerialNumber: r.Serial,
+			Actor:        r.Actor,
+			AuthMethod:   r.AuthMethod,
+			SourceIp:     r.SourceIP,
+			Code:         r.Code,
+			Detail:       r.Detail,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Content codings offered by the distribution point, most preferred first
+const (
+	encodingZstd     = "zstd"
+	encodingGzip     = "gzip"
+	encodingIdentity = "identity"
+)
+
+var offeredEncodings = []string{encodingZstd, encodingGzip}
+
+// artifact is one CRL representation with its pre-compressed variants
+type artifact struct {
+	src    *generator.CRL
+	bodies map[string][]byte // by content coding, identity included
+}
+
+// artifactCache compresses each CRL once when it becomes current instead of
+// on every download
+type artifactCache struct {
+	mu        sync.Mutex
+	artifacts map[string]*artifact
+}
+
+func newArtifactCache() *artifactCache {
+	return &artifactCache{artifacts: make(map[string]*artifact)}
+}
+
+// get returns the encoded bodies of list in format, building them when list
+// is not the CRL cached under key
+func (c *artifactCache) get(key string, list *generator.CRL, format string) (*artifact, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Stale copies of a last known good CRL share its digest
+	if a, ok := c.artifacts[key]; ok && (a.src == list || bytes.Equal(a.src.SHA256, list.SHA256)) {
+		return a, nil
+	}
+
+	body := list.DER
+	if format == "pem" {
+		body = []byte(list.PEM())
+	}
+	a := &artifact{
+		src:    list,
+		bodies: map[string][]byte{encodingIdentity: body},
+	}
+	for _, enc := range offeredEncodings {
+		compressed, err := compress(enc, body)
+		if err != nil {
+			return nil, err
+		}
+		// Tiny CRLs may not shrink; serve those uncompressed
+		if len(compressed) < len(body) {
+			a.bodies[enc] = compressed
+		}
+	}
+	c.artifacts[key] = a
+
+	return a, nil
+}
+
+func compress(encoding string, body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	switch encoding {
+	case encodingGzip:
+		zw, err := gzip.NewWriterLevel(&buf, gzip.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(body); err != nil {
+			return nil, fmt.Errorf("failed to gzip CRL: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to gzip CRL: %w", err)
+		}
+	case encodingZstd:
+		zw, err := zstd.NewWriter(&buf, zstd.WithEncoderLevel(zstd.SpeedBestCompression))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := zw.Write(body); err != nil {
+			return nil, fmt.Errorf("failed to zstd-compress CRL: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to zstd-compress CRL: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content coding %q", encoding)
+	}
+	return buf.Bytes(), nil
+}
+
+// negotiateEncoding picks the preferred available coding the client
+// accepts (RFC 9110 section 12.5.3), falling back to identity
+func negotiateEncoding(acceptEncoding string, available map[string][]byte) string {
+	if acceptEncoding == "" {
+		return encodingIdentity
+	}
+
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	best, bestQ := encodingIdentity, 0.0
+	for _, enc := range offeredEncodings {
+		if _, ok := available[enc]; !ok {
+			continue
+		}
+		q, ok := accepted[enc]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = enc, q
+		}
+	}
+	return best
+}
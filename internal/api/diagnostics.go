@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+)
+
+// DiagnosticsAddress is the only interface the diagnostics listener binds
+// to, as profiles expose memory contents and command-line flags
+const DiagnosticsAddress = "127.0.0.1"
+
+// DiagnosticsConfig configures the localhost-only listener serving pprof
+// profiles and expvar runtime statistics, and gRPC server reflection
+type DiagnosticsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+
+	// GRPCReflection registers the reflection service on the gRPC server,
+	// for grpcurl and other tools without the proto files. It is meant for
+	// non-production environments.
+	GRPCReflection bool `yaml:"grpc_reflection"`
+}
+
+// Validate checks the diagnostics config
+func (c *DiagnosticsConfig) Validate() error {
+	if c.Enabled && c.Port == 0 {
+		return errors.New("port is required")
+	}
+	return nil
+}
+
+// DiagnosticsRoutes serves the pprof profiles under /debug/pprof/ and the
+// expvar variables, including runtime.MemStats, at /debug/vars
+func DiagnosticsRoutes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gigvault/crl/internal/export"
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/shared/pkg/logger"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// Content types for CRL downloads (RFC 2585 section 4.2)
+const (
+	contentTypeDER = "application/pkix-crl"
+	contentTypePEM = "application/x-pem-file"
+)
+
+// DistributionConfig configures the CRL distribution point listener
+type DistributionConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+}
+
+// DistributionHandler serves published CRLs over plain HTTP so the CRL
+// distribution point URL in certificates can point at this service
+type DistributionHandler struct {
+	issuers   *generator.Registry
+	exports   *export.Service // nil without exports
+	artifacts *artifactCache
+	logger    *logger.Logger
+}
+
+// NewDistributionHandler creates a new distribution point handler. exports
+// may be nil.
+func NewDistributionHandler(issuers *generator.Registry, exports *export.Service) *DistributionHandler {
+	return &DistributionHandler{
+		issuers:   issuers,
+		exports:   exports,
+		artifacts: newArtifactCache(),
+		logger:    logger.Global(),
+	}
+}
+
+// Routes serves /{issuer}/crl.der and /{issuer}/crl.pem, plus
+// /{issuer}/crl-{partition}.der and .pem for partitioned issuers and the
+// exports of the revocation set, /{issuer}/crl.crlite, crl.crlset and
+// onecrl.json
+func (h *DistributionHandler) Routes() http.Handler {
+	r := mux.NewRouter()
+	r.HandleFunc("/{issuer}/crl.{format:der|pem}", h.ServeCRL).Methods("GET", "HEAD")
+	r.HandleFunc("/{issuer}/crl-{partition:[0-9]+}.{format:der|pem}", h.ServeCRL).Methods("GET", "HEAD")
+	r.HandleFunc("/{issuer}/crl.{export:crlite|crlset}", h.ServeExport).Methods("GET", "HEAD")
+	r.HandleFunc("/{issuer}/{export:onecrl}.json", h.ServeExport).Methods("GET", "HEAD")
+	return r
+}
+
+// ServeExport writes the latest export of the issuer's revocation set
+func (h *DistributionHandler) ServeExport(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	gen, err := h.issuers.Get(vars["issuer"])
+	if err != nil {
+		http.Error(w, "unknown issuer", http.StatusNotFound)
+		return
+	}
+	a, ok := h.exports.Get(gen.ID(), vars["export"])
+	if !ok {
+		http.Error(w, "export unavailable", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("ETag", fmt.Sprintf(`"%x"`, a.SHA256))
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.exports.Interval().Seconds())))
+	w.Header().Set("Content-Type", a.ContentType)
+	http.ServeContent(w, r, "", a.BuiltAt, bytes.NewReader(a.Data))
+}
+
+// ServeCRL writes the issuer's current CRL
+func (h *DistributionHandler) ServeCRL(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	gen, err := h.issuers.Get(vars["issuer"])
+	if err != nil {
+		http.Error(w, "unknown issuer", http.StatusNotFound)
+		return
+	}
+
+	partition := 0
+	if p, ok := vars["partition"]; ok {
+		partition, err = strconv.Atoi(p)
+		if err != nil {
+			http.Error(w, "invalid partition", http.StatusBadRequest)
+			return
+		}
+	}
+
+	list, err := gen.Current(r.Context(), partition)
+	switch {
+	case errors.Is(err, generator.ErrUnknownPartition):
+		http.Error(w, "unknown partition", http.StatusNotFound)
+		return
+	case err != nil:
+		h.logger.Error("Failed to load CRL for distribution",
+			zap.String("issuer_id", gen.ID()),
+			zap.Int("partition", partition),
+			zap.Error(err),
+		)
+		http.Error(w, "CRL unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	format := vars["format"]
+	contentType := contentTypeDER
+	if format == "pem" {
+		contentType = contentTypePEM
+	}
+
+	a, err := h.artifacts.get(fmt.Sprintf("%s/%d/%s", gen.ID(), partition, format), list, format)
+	if err != nil {
+		h.logger.Error("Failed to encode CRL for distribution", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		http.Error(w, "CRL unavailable", http.StatusInternalServerError)
+		return
+	}
+	encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"), a.bodies)
+	body := a.bodies[encoding]
+
+	// Relying parties and caches may keep the CRL until nextUpdate
+	maxAge := int(time.Until(list.NextUpdate).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+
+	// The CRL number identifies the content; partitions share the sequence.
+	// Each content coding is a distinct representation with its own tag.
+	etag := fmt.Sprintf(`"%s-%s"`, list.Number.Text(16), format)
+	if encoding != encodingIdentity {
+		etag = fmt.Sprintf(`"%s-%s-%s"`, list.Number.Text(16), format, encoding)
+	}
+	lastModified := list.ThisUpdate
+
+	w.Header().Set("Vary", "Accept-Encoding")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", maxAge))
+	w.Header().Set("Content-Type", contentType)
+	if encoding != encodingIdentity {
+		w.Header().Set("Content-Encoding", encoding)
+	}
+	// A last known good CRL served while a new one cannot be signed
+	if list.Stale {
+		w.Header().Set("Warning", `110 - "Response is Stale"`)
+	}
+
+	// ServeContent answers conditional requests with 304 and Range requests
+	// with 206, so interrupted downloads of large CRLs can resume; If-Range
+	// restarts the download when the CRL changed in between
+	http.ServeContent(w, r, "", lastModified, bytes.NewReader(body))
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// RetryInfo delays suggested after transient failures
+const (
+	storeRetryDelay  = time.Second
+	signerRetryDelay = 5 * time.Second
+	caRetryDelay     = time.Second
+)
+
+// Precondition violation types of PreconditionFailure details
+const (
+	preconditionEntryState    = "ENTRY_STATE"
+	preconditionApprovalState = "APPROVAL_STATE"
+	preconditionCRLState      = "CRL_STATE"
+	preconditionIdempotency   = "IDEMPOTENCY_KEY"
+	preconditionAPIKeyState   = "API_KEY_STATE"
+	preconditionIssued        = "CERTIFICATE_ISSUED"
+)
+
+// Resource types of ResourceInfo details
+const (
+	resourceIssuer     = "issuer"
+	resourceRevocation = "revocation"
+	resourceApproval   = "revocation_approval"
+	resourceAPIKey     = "api_key"
+	resourceCRL        = "crl"
+)
+
+// withDetails returns a status error carrying details; the bare status is
+// returned if they cannot be encoded
+func withDetails(code codes.Code, msg string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, msg)
+	if detailed, err := st.WithDetails(details...); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// fieldError reports an invalid request field as InvalidArgument with a
+// BadRequest field violation
+func fieldError(field, description string) error {
+	return withDetails(codes.InvalidArgument, description, &errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{
+			Field:       field,
+			Description: description,
+		}},
+	})
+}
+
+// preconditionError reports state that prevents the request as
+// FailedPrecondition with a PreconditionFailure violation
+func preconditionError(typ, subject, description string) error {
+	return withDetails(codes.FailedPrecondition, description, &errdetails.PreconditionFailure{
+		Violations: []*errdetails.PreconditionFailure_Violation{{
+			Type:        typ,
+			Subject:     subject,
+			Description: description,
+		}},
+	})
+}
+
+// notFoundError reports a missing resource as NotFound with ResourceInfo
+func notFoundError(resourceType, name, description string) error {
+	return withDetails(codes.NotFound, description, &errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: name,
+		Description:  description,
+	})
+}
+
+// retryableError reports a transient failure with the suggested RetryInfo
+// delay
+func retryableError(code codes.Code, description string, delay time.Duration) error {
+	return withDetails(code, description, &errdetails.RetryInfo{
+		RetryDelay: durationpb.New(delay),
+	})
+}
+
+// storeError reports a failed database operation as Unavailable: the
+// request was well-formed and may succeed when retried
+func storeError(description string) error {
+	return retryableError(codes.Unavailable, description, storeRetryDelay)
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/export"
+	"go.uber.org/zap"
+)
+
+// exportFormats maps the ExportRevocations formats to those of
+// export.WriteEntries
+var exportFormats = map[crl.ExportFormat]string{
+	crl.ExportFormat_EXPORT_FORMAT_UNSPECIFIED: export.FormatJSON,
+	crl.ExportFormat_EXPORT_FORMAT_JSON:        export.FormatJSON,
+	crl.ExportFormat_EXPORT_FORMAT_CSV:         export.FormatCSV,
+}
+
+// ExportRevocations streams the revocations of an issuer matching the
+// request's filters as JSON or CSV, in chunks of at most crlChunkSize bytes
+func (s *CRLGRPCServer) ExportRevocations(req *crl.ExportRevocationsRequest, stream crl.CRLService_ExportRevocationsServer) error {
+	format, ok := exportFormats[req.Format]
+	if !ok {
+		return fieldError("format", "unknown export format")
+	}
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return err
+	}
+	filter, err := entryFilter(gen, req.Reasons, req.RevokedAfter, req.RevokedBefore, req.IncludeReleased, req.IncludeArchived)
+	if err != nil {
+		return err
+	}
+
+	w := &chunkWriter{stream: stream, contentType: export.ContentType(format)}
+	n, err := export.WriteEntries(stream.Context(), s.store, filter, format, w)
+	if err == nil {
+		err = w.flush()
+	}
+	if err != nil {
+		if stream.Context().Err() != nil || w.sendErr != nil {
+			return err
+		}
+		s.logger.Error("Failed to export revocations", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		return storeError("failed to export revocations")
+	}
+
+	s.logger.Info("Revocations exported",
+		zap.String("issuer_id", gen.ID()),
+		zap.String("format", format),
+		zap.Int("entries", n),
+	)
+	return nil
+}
+
+// chunkWriter buffers an export and sends it in ExportRevocationsChunk
+// messages of crlChunkSize bytes
+type chunkWriter struct {
+	stream      crl.CRLService_ExportRevocationsServer
+	contentType string
+	buf         []byte
+	sent        bool
+	sendErr     error
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for len(w.buf) >= crlChunkSize {
+		if err := w.send(w.buf[:crlChunkSize]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[crlChunkSize:]
+	}
+	return len(p), nil
+}
+
+// flush sends what is buffered, and the first chunk even if empty
+func (w *chunkWriter) flush() error {
+	if len(w.buf) == 0 && w.sent {
+		return nil
+	}
+	err := w.send(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *chunkWriter) send(data []byte) error {
+	chunk := &crl.ExportRevocationsChunk{Data: data}
+	if !w.sent {
+		chunk.ContentType = w.contentType
+		w.sent = true
+	}
+	if err := w.stream.Send(chunk); err != nil {
+		w.sendErr = err
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	crlpb "github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/auth"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/rs/cors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// gatewayBufferSize is the buffer of the in-memory connection between the
+// gateway and its gRPC server
+const gatewayBufferSize = 1 << 20
+
+// OpenAPIPath is where the gateway serves its OpenAPI document
+const OpenAPIPath = "/openapi.json"
+
+// defaultCORSMaxAge is how long browsers may cache preflight results
+const defaultCORSMaxAge = 10 * time.Minute
+
+// GatewayConfig configures the REST/JSON gateway to the CRL service
+type GatewayConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+
+	// GRPCWeb also serves the gRPC API, streams included, to browsers
+	// with the grpc-web protocol
+	GRPCWeb bool `yaml:"grpc_web"`
+
+	CORS CORSConfig `yaml:"cors"`
+}
+
+// CORSConfig lets browser apps on other origins call the gateway
+type CORSConfig struct {
+	// AllowedOrigins are origins such as https://console.example.com, or
+	// patterns with one * such as https://*.example.com; "*" allows any.
+	// Empty disables CORS.
+	AllowedOrigins []string `yaml:"allowed_origins"`
+
+	// MaxAge is how long browsers may cache a preflight (default 10m)
+	MaxAge time.Duration `yaml:"max_age"`
+}
+
+// Validate applies defaults and checks the gateway config
+func (c *GatewayConfig) Validate() error {
+	if c.Enabled && c.Port == 0 {
+		return errors.New("port is required")
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(strings.Replace(origin, "*", "x", 1))
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.Path != "" || strings.Count(origin, "*") > 1 {
+			return fmt.Errorf("invalid cors origin %q: want scheme://host[:port]", origin)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		return errors.New("cors max_age must not be negative")
+	}
+	if c.CORS.MaxAge == 0 {
+		c.CORS.MaxAge = defaultCORSMaxAge
+	}
+	return nil
+}
+
+// Gateway translates the HTTP/JSON bindings of the CRL service to gRPC
+// calls. The calls go through an in-memory gRPC server built with the
+// options of the public one, so authentication, rate limits, audit and
+// authorization apply as they do to gRPC clients.
+type Gateway struct {
+	server *grpc.Server
+	lis    *bufconn.Listener
+	conn   *grpc.ClientConn
+	mux    *runtime.ServeMux
+	web    *grpcweb.WrappedGrpcServer // nil without grpc_web
+	cors   *cors.Cors                 // nil without allowed origins
+}
+
+// NewGateway creates a gateway to srv. opts are the server options of the
+// public gRPC server, minus its transport credentials.
+func NewGateway(ctx context.Context, cfg GatewayConfig, srv crlpb.CRLServiceServer, opts ...grpc.ServerOption) (*Gateway, error) {
+	// Calls come from the gateway, so the caller is whoever it forwarded
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(forwardedPeerUnary),
+		grpc.ChainStreamInterceptor(forwardedPeerStream),
+	}, opts...)
+	g := &Gateway{
+		server: grpc.NewServer(opts...),
+		lis:    bufconn.Listen(gatewayBufferSize),
+		mux: runtime.NewServeMux(
+			runtime.WithIncomingHeaderMatcher(gatewayHeader),
+			runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+				MarshalOptions: protojson.MarshalOptions{UseProtoNames: true},
+			}),
+		),
+	}
+	crlpb.RegisterCRLServiceServer(g.server, srv)
+	go g.server.Serve(g.lis)
+	if cfg.GRPCWeb {
+		g.web = grpcweb.WrapServer(g.server)
+	}
+	if len(cfg.CORS.AllowedOrigins) > 0 {
+		g.cors = cors.New(cors.Options{
+			AllowedOrigins: cfg.CORS.AllowedOrigins,
+			AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+			AllowedHeaders: []string{
+				"Content-Type", auth.APIKeyHeader, "Authorization", adminTokenHeader,
+				"X-Grpc-Web", "X-User-Agent", "Grpc-Timeout",
+			},
+			// grpc-web sends the status of calls failing before any
+			// message as headers
+			ExposedHeaders: []string{"Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin"},
+			MaxAge:         int(cfg.CORS.MaxAge / time.Second),
+		})
+	}
+
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return g.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		g.server.Stop()
+		return nil, err
+	}
+	g.conn = conn
+	if err := crlpb.RegisterCRLServiceHandler(ctx, g.mux, conn); err != nil {
+		g.Close()
+		return nil, err
+	}
+	if err := g.mux.HandlePath(http.MethodGet, OpenAPIPath, serveOpenAPI); err != nil {
+		g.Close()
+		return nil, err
+	}
+	return g, nil
+}
+
+// Handler serves the HTTP bindings under /v1/, their OpenAPI document at
+// OpenAPIPath and, with grpc_web, grpc-web calls
+func (g *Gateway) Handler() http.Handler {
+	var h http.Handler = g.mux
+	if g.web != nil {
+		h = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !g.web.IsGrpcWebRequest(r) {
+				g.mux.ServeHTTP(w, r)
+				return
+			}
+			// grpc-web calls reach the server directly, so the peer is
+			// already the client; a forwarded address would be the
+			// client's claim
+			r.Header.Del("X-Forwarded-For")
+			g.web.HandleGrpcWebRequest(w, r)
+		})
+	}
+	if g.cors != nil {
+		h = g.cors.Handler(h)
+	}
+	return h
+}
+
+// Close lets in-flight calls finish and closes the in-memory server
+func (g *Gateway) Close() {
+	g.conn.Close()
+	g.server.GracefulStop()
+}
+
+// serveOpenAPI serves the OpenAPI document, which needs no credentials
+func serveOpenAPI(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600")
+	w.Write(crlpb.OpenAPI)
+}
+
+// gatewayHeader forwards the credential headers of HTTP requests as gRPC
+// metadata, along with the headers grpc-gateway forwards by default
+func gatewayHeader(key string) (string, bool) {
+	switch k := strings.ToLower(key); k {
+	case auth.APIKeyHeader, adminTokenHeader:
+		return k, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// forwardedPeer replaces the in-memory peer of a gateway call with the
+// HTTP client. grpc-gateway appends the client address to any
+// X-Forwarded-For the client sent, so only the last entry is trusted.
+func forwardedPeer(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("x-forwarded-for")
+	if len(values) == 0 {
+		return ctx
+	}
+	forwarded := values[len(values)-1]
+	ip := net.ParseIP(strings.TrimSpace(forwarded[strings.LastIndex(forwarded, ",")+1:]))
+	if ip == nil {
+		return ctx
+	}
+	p, _ := peer.FromContext(ctx)
+	if p == nil {
+		p = &peer.Peer{}
+	}
+	fwd := *p
+	fwd.Addr = &net.TCPAddr{IP: ip}
+	return peer.NewContext(ctx, &fwd)
+}
+
+func forwardedPeerUnary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	return handler(forwardedPeer(ctx), req)
+}
+
+func forwardedPeerStream(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &peerStream{ServerStream: ss, ctx: forwardedPeer(ss.Context())})
+}
+
+// peerStream is a server stream with a replaced context
+type peerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *peerStream) Context() context.Context {
+	return s.ctx
+}
@@ -2,65 +2,169 @@ package api
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
-	"github.com/gigvault/shared/api/proto/crl"
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/auth"
+	"github.com/gigvault/crl/internal/bloom"
+	"github.com/gigvault/crl/internal/ca"
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/ocsp"
+	"github.com/gigvault/crl/internal/revocation"
+	"github.com/gigvault/crl/internal/scheduler"
+	"github.com/gigvault/crl/internal/serial"
+	"github.com/gigvault/crl/internal/statuscache"
+	"github.com/gigvault/crl/internal/store"
 	"github.com/gigvault/shared/pkg/logger"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/gigvault/shared/pkg/models"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// crlChunkSize is the payload size of GetCRLStream messages, well below
+// the default 4 MiB gRPC message limit
+const crlChunkSize = 64 << 10
+
+// maxBatchSize bounds the serials of one CheckStatusBatch or
+// AddRevocations request
+const maxBatchSize = 1000
+
+// Page sizes of ListRevocations
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
 )
 
 // CRLGRPCServer implements the CRL gRPC service
 type CRLGRPCServer struct {
 	crl.UnimplementedCRLServiceServer
-	db     *pgxpool.Pool
-	logger *logger.Logger
+	store     *store.Store
+	issuers   *generator.Registry
+	scheduler *scheduler.Scheduler
+	ocsp      *ocsp.Responder    // nil without an OCSP responder
+	ca        *ca.Client         // nil unless serials are verified with the CA
+	status    *statuscache.Cache // nil without the Redis status cache
+	filter    *bloom.Index       // nil without the revoked serial filter
+	admin     AdminConfig
+	approval  ApprovalConfig
+	apiKeys   auth.APIKeyConfig
+	roles     map[string][]string // custom roles API keys may be granted
+	changes   *changeNotifier
+	logger    *logger.Logger
+
+	// watchesStopped is closed by StopWatches
+	watchesStopped chan struct{}
+	stopOnce       sync.Once
 }
 
 // NewCRLGRPCServer creates a new CRL gRPC server
-func NewCRLGRPCServer(db *pgxpool.Pool) *CRLGRPCServer {
+func NewCRLGRPCServer(st *store.Store, issuers *generator.Registry, sched *scheduler.Scheduler, responder *ocsp.Responder, caClient *ca.Client, statusCache *statuscache.Cache, filter *bloom.Index, admin AdminConfig, approval ApprovalConfig, authCfg auth.Config) *CRLGRPCServer {
 	return &CRLGRPCServer{
-		db:     db,
-		logger: logger.Global(),
+		store:     st,
+		issuers:   issuers,
+		scheduler: sched,
+		ocsp:      responder,
+		ca:        caClient,
+		status:    statusCache,
+		filter:    filter,
+		admin:     admin,
+		approval:  approval,
+		apiKeys:   authCfg.APIKeys,
+		roles:     authCfg.Roles,
+		changes:   newChangeNotifier(),
+		logger:    logger.Global(),
+
+		watchesStopped: make(chan struct{}),
+	}
+}
+
+// entryChanged refreshes cached status and CRLs and wakes revocation
+// watchers after an entry was written
+func (s *CRLGRPCServer) entryChanged(ctx context.Context, issuerID, serial string) {
+	s.filter.Add(issuerID, serial)
+	if gen, err := s.issuers.Get(issuerID); err == nil {
+		gen.Invalidate()
+	}
+	s.status.Delete(ctx, issuerID, serial)
+	s.changes.notify()
+	s.ocsp.StatusChanged(ctx, issuerID, serial)
+}
+
+// issuer resolves the generator of the issuer named in a request
+func (s *CRLGRPCServer) issuer(id string) (*generator.Generator, error) {
+	gen, err := s.issuers.Get(id)
+	if err != nil {
+		return nil, notFoundError(resourceIssuer, id, err.Error())
+	}
+	return gen, nil
+}
+
+// serialField normalizes the serial number in a request field, reporting
+// a missing or malformed serial as InvalidArgument
+func serialField(field, value string) (string, error) {
+	if value == "" {
+		return "", fieldError(field, "serial number is required")
+	}
+	canonical, err := serial.Normalize(value)
+	if err != nil {
+		return "", fieldError(field, err.Error())
 	}
+	return canonical, nil
 }
 
 // AddRevocation adds a certificate revocation to the CRL
 func (s *CRLGRPCServer) AddRevocation(ctx context.Context, req *crl.AddRevocationRequest) (*crl.AddRevocationResponse, error) {
+	receivedAt := time.Now()
 	s.logger.Info("Received AddRevocation request",
+		zap.String("issuer_id", req.IssuerId),
 		zap.String("serial", req.SerialNumber),
 		zap.String("reason", req.Reason),
 	)
 
-	// Validate input
-	if req.SerialNumber == "" {
-		return nil, status.Error(codes.InvalidArgument, "serial number is required")
-	}
-
-	// Insert revocation into database
-	query := `
-		INSERT INTO crl_entries (serial, revoked_at, reason)
-		VALUES ($1, $2, $3)
-		ON CONFLICT (serial) DO UPDATE SET
-			revoked_at = EXCLUDED.revoked_at,
-			reason = EXCLUDED.reason
-	`
+	unkeyed := proto.Clone(req).(*crl.AddRevocationRequest)
+	unkeyed.IdempotencyKey = ""
+	return idempotent(ctx, s, "AddRevocation", req.IdempotencyKey, unkeyed,
+		func() *crl.AddRevocationResponse { return &crl.AddRevocationResponse{} },
+		func() (*crl.AddRevocationResponse, error) { return s.addRevocation(ctx, req, receivedAt) },
+	)
+}
 
-	revokedAt := time.Unix(req.RevokedAt.Seconds, 0)
-	if req.RevokedAt == nil || req.RevokedAt.Seconds == 0 {
-		revokedAt = time.Now()
+// addRevocation adds or, with approval enabled, queues one revocation
+func (s *CRLGRPCServer) addRevocation(ctx context.Context, req *crl.AddRevocationRequest, receivedAt time.Time) (*crl.AddRevocationResponse, error) {
+	entry, err := s.revocationEntry(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkIssued(ctx, &entry); err != nil {
+		return nil, err
+	}
+	if s.approval.Enabled {
+		return s.queueApproval(ctx, req, entry)
 	}
 
-	_, err := s.db.Exec(ctx, query, req.SerialNumber, revokedAt, req.Reason)
-	if err != nil {
+	if err := s.store.AddEntry(ctx, entry); err != nil {
 		s.logger.Error("Failed to add revocation", zap.Error(err))
-		return nil, status.Error(codes.Internal, "failed to add revocation")
+		return nil, storeError("failed to add revocation")
 	}
 
-	s.logger.Info("Revocation added successfully", zap.String("serial", req.SerialNumber))
+	s.logger.Info("Revocation added successfully", zap.String("serial", entry.Serial))
+	s.entryChanged(ctx, entry.IssuerID, entry.Serial)
+	s.triggerEmergency(entry, receivedAt)
+	if entry.EffectiveAt != nil {
+		s.scheduler.Reschedule(entry.IssuerID)
+	}
 
 	return &crl.AddRevocationResponse{
 		Success: true,
@@ -68,82 +172,822 @@ func (s *CRLGRPCServer) AddRevocation(ctx context.Context, req *crl.AddRevocatio
 	}, nil
 }
 
+// AddRevocations adds several certificate revocations in one transaction
+func (s *CRLGRPCServer) AddRevocations(ctx context.Context, req *crl.AddRevocationsRequest) (*crl.AddRevocationsResponse, error) {
+	receivedAt := time.Now()
+	s.logger.Info("Received AddRevocations request",
+		zap.Int("revocations", len(req.Revocations)),
+		zap.Bool("atomic", req.Atomic),
+	)
+
+	if len(req.Revocations) == 0 {
+		return nil, fieldError("revocations", "at least one revocation is required")
+	}
+	if len(req.Revocations) > maxBatchSize {
+		return nil, fieldError("revocations", fmt.Sprintf("at most %d revocations per request", maxBatchSize))
+	}
+
+	unkeyed := proto.Clone(req).(*crl.AddRevocationsRequest)
+	unkeyed.IdempotencyKey = ""
+	return idempotent(ctx, s, "AddRevocations", req.IdempotencyKey, unkeyed,
+		func() *crl.AddRevocationsResponse { return &crl.AddRevocationsResponse{} },
+		func() (*crl.AddRevocationsResponse, error) { return s.addRevocations(ctx, req, receivedAt) },
+	)
+}
+
+// addRevocations validates and adds or queues the revocations of a batch
+func (s *CRLGRPCServer) addRevocations(ctx context.Context, req *crl.AddRevocationsRequest, receivedAt time.Time) (*crl.AddRevocationsResponse, error) {
+
+	results := make([]*crl.AddRevocationResult, len(req.Revocations))
+	entries := make([]store.Entry, 0, len(req.Revocations))
+	valid := make([]int, 0, len(req.Revocations))
+	failed := 0
+	for i, r := range req.Revocations {
+		results[i] = &crl.AddRevocationResult{Index: int32(i), SerialNumber: r.SerialNumber}
+		entry, err := s.revocationEntry(r)
+		if err == nil {
+			err = s.checkIssued(ctx, &entry)
+		}
+		if err == nil && s.approval.Enabled && actor(ctx, r.RequestedBy) == "" {
+			err = fieldError("requested_by", "requested_by is required when approval is enabled")
+		}
+		if err != nil {
+			st := status.Convert(err)
+			results[i].Code = int32(st.Code())
+			results[i].Error = st.Message()
+			failed++
+			continue
+		}
+		entries = append(entries, entry)
+		valid = append(valid, i)
+	}
+
+	if failed > 0 && req.Atomic {
+		for _, i := range valid {
+			results[i].Code = int32(codes.Aborted)
+			results[i].Error = "not added: another revocation in the request is invalid"
+		}
+		return &crl.AddRevocationsResponse{Results: results, Failed: int32(len(results))}, nil
+	}
+
+	if s.approval.Enabled {
+		return s.queueApprovals(ctx, req, entries, valid, results, failed)
+	}
+
+	if len(entries) > 0 {
+		if err := s.store.AddEntries(ctx, entries); err != nil {
+			s.logger.Error("Failed to add revocations", zap.Int("revocations", len(entries)), zap.Error(err))
+			return nil, storeError("failed to add revocations")
+		}
+	}
+
+	emergency := make(map[string]bool)
+	for n, i := range valid {
+		results[i].Success = true
+		s.entryChanged(ctx, entries[n].IssuerID, entries[n].Serial)
+		if !emergency[entries[n].IssuerID] {
+			emergency[entries[n].IssuerID] = s.triggerEmergency(entries[n], receivedAt)
+		}
+		if entries[n].EffectiveAt != nil {
+			s.scheduler.Reschedule(entries[n].IssuerID)
+		}
+	}
+
+	s.logger.Info("Revocations added", zap.Int("added", len(entries)), zap.Int("failed", failed))
+
+	return &crl.AddRevocationsResponse{
+		Results: results,
+		Added:   int32(len(entries)),
+		Failed:  int32(failed),
+	}, nil
+}
+
+// revocationEntry validates an AddRevocation request and converts it to
+// the entry to store
+func (s *CRLGRPCServer) revocationEntry(req *crl.AddRevocationRequest) (store.Entry, error) {
+	serialHex, err := serialField("serial_number", req.SerialNumber)
+	if err != nil {
+		return store.Entry{}, err
+	}
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return store.Entry{}, err
+	}
+	reason, err := revocation.ParseReason(req.Reason)
+	if err != nil {
+		return store.Entry{}, fieldError("reason", err.Error())
+	}
+	// removeFromCRL only appears in delta CRLs when a hold is released
+	switch reason {
+	case models.ReasonRemoveFromCRL:
+		return store.Entry{}, fieldError("reason", "removeFromCRL cannot be used as a revocation reason")
+	case models.ReasonCertificateHold:
+		return store.Entry{}, fieldError("reason", "use HoldCertificate to place a certificate on hold")
+	}
+
+	// A future effective_at schedules the revocation; it defaults the
+	// revocation time to the cutover
+	revokedAt := time.Now()
+	var effectiveAt *time.Time
+	if req.EffectiveAt != nil {
+		if t := req.EffectiveAt.AsTime(); t.After(revokedAt) {
+			effectiveAt = &t
+			revokedAt = t
+		}
+	}
+	if req.RevokedAt != nil && req.RevokedAt.Seconds != 0 {
+		revokedAt = req.RevokedAt.AsTime()
+	}
+
+	var invalidityDate *time.Time
+	if req.InvalidityDate != nil {
+		t := req.InvalidityDate.AsTime()
+		if t.After(revokedAt) {
+			return store.Entry{}, fieldError("invalidity_date", "invalidity date must not be after the revocation time")
+		}
+		invalidityDate = &t
+	}
+
+	var notAfter *time.Time
+	if req.NotAfter != nil {
+		t := req.NotAfter.AsTime()
+		notAfter = &t
+	}
+
+	var certIssuer []byte
+	if len(req.CertificateIssuer) > 0 && !gen.IsCRLIssuer(req.CertificateIssuer) {
+		if !gen.Indirect() {
+			return store.Entry{}, fieldError("certificate_issuer", "certificate issuer differs from the CRL issuer and indirect CRLs are disabled")
+		}
+		if _, err := generator.ParseIssuerName(req.CertificateIssuer); err != nil {
+			return store.Entry{}, fieldError("certificate_issuer", err.Error())
+		}
+		certIssuer = req.CertificateIssuer
+	}
+
+	return store.Entry{
+		IssuerID:          gen.ID(),
+		Serial:            serialHex,
+		RevokedAt:         revokedAt,
+		Reason:            revocation.ReasonName(reason),
+		InvalidityDate:    invalidityDate,
+		CertificateIssuer: certIssuer,
+		EffectiveAt:       effectiveAt,
+		NotAfter:          notAfter,
+	}, nil
+}
+
+// checkIssued verifies a revocation with the CA service, filling in the
+// certificate's expiry if the request did not give it
+func (s *CRLGRPCServer) checkIssued(ctx context.Context, entry *store.Entry) error {
+	notAfter, err := s.verifyIssued(ctx, entry.IssuerID, entry.Serial, entry.CertificateIssuer)
+	if err != nil {
+		return err
+	}
+	if entry.NotAfter == nil {
+		entry.NotAfter = notAfter
+	}
+	return nil
+}
+
+// triggerEmergency publishes out of band for key and CA compromise
+// revocations: compromised keys must not wait for the next scheduled CRL.
+// It reports whether a publication was triggered.
+func (s *CRLGRPCServer) triggerEmergency(entry store.Entry, receivedAt time.Time) bool {
+	reason, _ := revocation.ParseReason(entry.Reason)
+	if entry.EffectiveAt != nil || (reason != models.ReasonKeyCompromise && reason != models.ReasonCACompromise) {
+		return false
+	}
+	if !s.scheduler.TriggerEmergency(entry.IssuerID, receivedAt) {
+		return false
+	}
+	s.logger.Warn("Emergency CRL publication triggered",
+		zap.String("issuer_id", entry.IssuerID),
+		zap.String("serial", entry.Serial),
+		zap.String("reason", entry.Reason),
+	)
+	return true
+}
+
 // GetCRL returns the current Certificate Revocation List
 func (s *CRLGRPCServer) GetCRL(ctx context.Context, req *crl.GetCRLRequest) (*crl.GetCRLResponse, error) {
-	s.logger.Info("Received GetCRL request")
+	s.logger.Info("Received GetCRL request",
+		zap.String("issuer", req.Issuer),
+		zap.Bool("delta", req.Delta),
+		zap.Int32("partition", req.Partition),
+		zap.Int64("crl_number", req.CrlNumber),
+		zap.String("format", req.Format.String()),
+	)
+
+	gen, list, err := s.generateCRL(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("CRL retrieved",
+		zap.String("issuer_id", gen.ID()),
+		zap.Int("entries", list.RevokedCount),
+		zap.Bool("delta", list.IsDelta()),
+	)
+
+	resp := &crl.GetCRLResponse{
+		ThisUpdate:   timestamppb.New(list.ThisUpdate),
+		NextUpdate:   timestamppb.New(list.NextUpdate),
+		RevokedCount: int32(list.RevokedCount),
+		CrlNumber:    list.Number.Int64(),
+		Partition:    int32(list.Partition),
+		Sha256:       list.SHA256,
+		Stale:        list.Stale,
+	}
+	if list.IsDelta() {
+		resp.BaseCrlNumber = list.BaseNumber.Int64()
+	}
+
+	// Binary consumers ask for DER only and skip the PEM copy
+	switch req.Format {
+	case crl.CRLFormat_CRL_FORMAT_DER:
+		resp.CrlDer = list.DER
+		resp.CrlData = list.DER
+		resp.Format = crl.CRLFormat_CRL_FORMAT_DER
+	case crl.CRLFormat_CRL_FORMAT_PEM:
+		resp.CrlPem = list.PEM()
+		resp.CrlData = []byte(resp.CrlPem)
+		resp.Format = crl.CRLFormat_CRL_FORMAT_PEM
+	default:
+		resp.CrlDer = list.DER
+		resp.CrlPem = list.PEM()
+		resp.CrlData = list.DER
+		resp.Format = crl.CRLFormat_CRL_FORMAT_DER
+	}
 
-	// Query all revoked certificates
-	query := `
-		SELECT serial, revoked_at, reason
-		FROM crl_entries
-		ORDER BY revoked_at DESC
-	`
+	return resp, nil
+}
+
+// GetCRLStream returns the current CRL in chunks of at most crlChunkSize
+// bytes, for CRLs exceeding the gRPC message size limit. The first chunk
+// carries the CRL metadata.
+func (s *CRLGRPCServer) GetCRLStream(req *crl.GetCRLRequest, stream crl.CRLService_GetCRLStreamServer) error {
+	s.logger.Info("Received GetCRLStream request",
+		zap.String("issuer", req.Issuer),
+		zap.Bool("delta", req.Delta),
+		zap.Int32("partition", req.Partition),
+		zap.Int64("crl_number", req.CrlNumber),
+		zap.String("format", req.Format.String()),
+	)
 
-	rows, err := s.db.Query(ctx, query)
+	gen, list, err := s.generateCRL(stream.Context(), req)
 	if err != nil {
-		s.logger.Error("Failed to query CRL entries", zap.Error(err))
-		return nil, status.Error(codes.Internal, "failed to query CRL")
+		return err
 	}
-	defer rows.Close()
 
-	// Build CRL PEM (simplified - in production use x509.CreateRevocationList)
-	var crlPEM string
-	var entries []string
+	data, format := list.DER, crl.CRLFormat_CRL_FORMAT_DER
+	if req.Format == crl.CRLFormat_CRL_FORMAT_PEM {
+		data, format = []byte(list.PEM()), crl.CRLFormat_CRL_FORMAT_PEM
+	}
 
-	for rows.Next() {
-		var serial, reason string
-		var revokedAt time.Time
-		if err := rows.Scan(&serial, &revokedAt, &reason); err != nil {
-			s.logger.Error("Failed to scan CRL entry", zap.Error(err))
-			continue
+	for offset := 0; offset == 0 || offset < len(data); offset += crlChunkSize {
+		end := min(offset+crlChunkSize, len(data))
+		chunk := &crl.GetCRLChunk{
+			Data:   data[offset:end],
+			Offset: int64(offset),
+		}
+		if offset == 0 {
+			chunk.TotalSize = int64(len(data))
+			chunk.Format = format
+			chunk.ThisUpdate = timestamppb.New(list.ThisUpdate)
+			chunk.NextUpdate = timestamppb.New(list.NextUpdate)
+			chunk.RevokedCount = int32(list.RevokedCount)
+			chunk.CrlNumber = list.Number.Int64()
+			chunk.Partition = int32(list.Partition)
+			chunk.Sha256 = list.SHA256
+			chunk.Stale = list.Stale
+			if list.IsDelta() {
+				chunk.BaseCrlNumber = list.BaseNumber.Int64()
+			}
+		}
+		if err := stream.Send(chunk); err != nil {
+			return err
 		}
-		entries = append(entries, fmt.Sprintf("%s,%s,%s", serial, revokedAt.Format(time.RFC3339), reason))
 	}
 
-	// In production, this should be a proper X.509 CRL
-	// For now, return a simplified format
-	crlPEM = "-----BEGIN X509 CRL-----\n"
-	for _, entry := range entries {
-		crlPEM += entry + "\n"
+	s.logger.Info("CRL streamed",
+		zap.String("issuer_id", gen.ID()),
+		zap.Int("entries", list.RevokedCount),
+		zap.Int("bytes", len(data)),
+	)
+
+	return nil
+}
+
+// generateCRL returns the full or delta CRL selected by a GetCRL request,
+// signed on demand and cached, or the recorded CRL with its sha256 or
+// crl_number
+func (s *CRLGRPCServer) generateCRL(ctx context.Context, req *crl.GetCRLRequest) (*generator.Generator, *generator.CRL, error) {
+	gen, err := s.issuers.Lookup(req.Issuer)
+	if err != nil {
+		return nil, nil, notFoundError(resourceIssuer, req.Issuer, err.Error())
 	}
-	crlPEM += "-----END X509 CRL-----\n"
 
-	s.logger.Info("CRL retrieved", zap.Int("entries", len(entries)))
+	var list *generator.CRL
+	switch {
+	case len(req.Sha256) > 0:
+		if len(req.Sha256) != sha256.Size {
+			return nil, nil, fieldError("sha256", fmt.Sprintf("must be %d bytes", sha256.Size))
+		}
+		list, err = gen.HistoricalByDigest(ctx, req.Sha256)
+		if errors.Is(err, store.ErrCRLNotFound) {
+			return nil, nil, notFoundError(resourceCRL, fmt.Sprintf("%s/%x", gen.ID(), req.Sha256), err.Error())
+		}
+	case req.CrlNumber < 0:
+		return nil, nil, fieldError("crl_number", "must not be negative")
+	case req.CrlNumber > 0:
+		list, err = gen.Historical(ctx, req.CrlNumber)
+		if errors.Is(err, store.ErrCRLNotFound) {
+			return nil, nil, notFoundError(resourceCRL, fmt.Sprintf("%s/%d", gen.ID(), req.CrlNumber), err.Error())
+		}
+	case req.Delta:
+		list, err = gen.LatestDelta(ctx)
+	default:
+		list, err = gen.Latest(ctx, int(req.Partition))
+		if err != nil && !errors.Is(err, generator.ErrUnknownPartition) {
+			if stale := gen.LastKnownGood(int(req.Partition), err); stale != nil {
+				list, err = stale, nil
+			}
+		}
+	}
+	switch {
+	case errors.Is(err, generator.ErrUnknownPartition):
+		return nil, nil, fieldError("partition", err.Error())
+	case errors.Is(err, generator.ErrDeltaDisabled), errors.Is(err, store.ErrNoBaseCRL):
+		return nil, nil, preconditionError(preconditionCRLState, gen.ID(), err.Error())
+	case errors.Is(err, store.ErrCRLCorrupt):
+		s.logger.Error("Recorded CRL failed integrity check", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		return nil, nil, status.Error(codes.DataLoss, "recorded CRL does not match its digest")
+	case errors.Is(err, store.ErrUnavailable):
+		return nil, nil, storeError("database unavailable")
+	case err != nil:
+		s.logger.Error("Failed to generate CRL", zap.Error(err))
+		return nil, nil, status.Error(codes.Internal, "failed to generate CRL")
+	}
 
-	return &crl.GetCRLResponse{
-		CrlPem: crlPEM,
-	}, nil
+	return gen, list, nil
 }
 
 // PublishCRL publishes the CRL to distribution points
 func (s *CRLGRPCServer) PublishCRL(ctx context.Context, req *crl.PublishCRLRequest) (*crl.PublishCRLResponse, error) {
-	s.logger.Info("Received PublishCRL request")
+	s.logger.Info("Received PublishCRL request", zap.String("issuer_id", req.IssuerId))
 
-	// Get current CRL
-	crlResp, err := s.GetCRL(ctx, &crl.GetCRLRequest{})
+	gen, err := s.issuer(req.IssuerId)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update publication timestamp
-	query := `
-		INSERT INTO crl_metadata (id, last_published, next_update)
-		VALUES (1, NOW(), NOW() + INTERVAL '24 hours')
-		ON CONFLICT (id) DO UPDATE SET
-			last_published = NOW(),
-			next_update = NOW() + INTERVAL '24 hours'
-	`
+	window := gen.Window()
+	if req.Validity != nil {
+		window.Validity = req.Validity.AsDuration()
+	}
+	if req.Overlap != nil {
+		window.Overlap = req.Overlap.AsDuration()
+	}
 
-	_, err = s.db.Exec(ctx, query)
-	if err != nil {
-		s.logger.Error("Failed to update CRL metadata", zap.Error(err))
+	pub, err := gen.Publish(ctx, window)
+	switch {
+	case errors.Is(err, generator.ErrInvalidWindow):
+		return nil, fieldError("validity", err.Error())
+	case errors.Is(err, generator.ErrSignerUnavailable):
+		s.logger.Error("CRL signer unavailable", zap.Error(err))
+		return nil, retryableError(codes.Unavailable, "CRL signer unavailable", signerRetryDelay)
+	case errors.Is(err, generator.ErrVerificationFailed):
+		s.logger.Error("Published CRL failed verification", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		return nil, status.Error(codes.Internal, err.Error())
+	case errors.Is(err, generator.ErrDistributionFailed):
+		// The CRL is signed and current; report which targets missed it
+		s.logger.Warn("CRL published with failed uploads", zap.String("issuer_id", gen.ID()), zap.Error(err))
+	case errors.Is(err, store.ErrUnavailable):
+		return nil, storeError("database unavailable")
+	case err != nil:
+		s.logger.Error("Failed to publish CRL", zap.Error(err))
 		return nil, status.Error(codes.Internal, "failed to publish CRL")
 	}
 
-	s.logger.Info("CRL published successfully")
+	failed := pub.Failed()
+	targets := make([]*crl.PublishTargetResult, 0, len(pub.Targets))
+	for _, t := range pub.Targets {
+		result := &crl.PublishTargetResult{
+			Publisher: t.Publisher,
+			Partition: int32(t.Partition),
+			Success:   t.Err == nil,
+			Duration:  durationpb.New(t.Duration),
+		}
+		if t.Err != nil {
+			result.Error = t.Err.Error()
+		}
+		targets = append(targets, result)
+	}
+
+	message := fmt.Sprintf("CRL published successfully (%d bytes)", pub.Size())
+	if failed > 0 {
+		message = fmt.Sprintf("CRL published with %d of %d uploads failed", failed, len(pub.Targets))
+	} else {
+		s.logger.Info("CRL published successfully",
+			zap.String("issuer_id", gen.ID()),
+			zap.Int("partitions", len(pub.CRLs)),
+		)
+	}
 
 	return &crl.PublishCRLResponse{
+		Success:      failed == 0,
+		Message:      message,
+		PublishedAt:  timestamppb.New(pub.PublishedAt),
+		RevokedCount: int32(pub.RevokedCount()),
+		Partitions:   int32(len(pub.CRLs)),
+		Targets:      targets,
+	}, nil
+}
+
+// HoldCertificate places a certificate on hold
+func (s *CRLGRPCServer) HoldCertificate(ctx context.Context, req *crl.HoldCertificateRequest) (*crl.HoldCertificateResponse, error) {
+	s.logger.Info("Received HoldCertificate request",
+		zap.String("issuer_id", req.IssuerId),
+		zap.String("serial", req.SerialNumber),
+	)
+
+	serialHex, err := serialField("serial_number", req.SerialNumber)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	heldAt := time.Now()
+	if req.HeldAt != nil && req.HeldAt.Seconds != 0 {
+		heldAt = req.HeldAt.AsTime()
+	}
+	var notAfter *time.Time
+	if req.NotAfter != nil {
+		t := req.NotAfter.AsTime()
+		notAfter = &t
+	}
+	caNotAfter, err := s.verifyIssued(ctx, gen.ID(), serialHex, nil)
+	if err != nil {
+		return nil, err
+	}
+	if notAfter == nil {
+		notAfter = caNotAfter
+	}
+
+	err = s.store.HoldEntry(ctx, gen.ID(), serialHex, heldAt, notAfter)
+	switch {
+	case errors.Is(err, store.ErrAlreadyRevoked):
+		return nil, preconditionError(preconditionEntryState, serialHex, err.Error())
+	case err != nil:
+		s.logger.Error("Failed to hold certificate", zap.Error(err))
+		return nil, storeError("failed to hold certificate")
+	}
+
+	s.logger.Info("Certificate placed on hold", zap.String("serial", serialHex))
+	s.entryChanged(ctx, gen.ID(), serialHex)
+
+	return &crl.HoldCertificateResponse{
 		Success: true,
-		Message: fmt.Sprintf("CRL published successfully (%d bytes)", len(crlResp.CrlPem)),
+		Message: "certificate placed on hold",
 	}, nil
 }
+
+// ReleaseHold lifts a hold placed with HoldCertificate
+func (s *CRLGRPCServer) ReleaseHold(ctx context.Context, req *crl.ReleaseHoldRequest) (*crl.ReleaseHoldResponse, error) {
+	s.logger.Info("Received ReleaseHold request",
+		zap.String("issuer_id", req.IssuerId),
+		zap.String("serial", req.SerialNumber),
+	)
+
+	serialHex, err := serialField("serial_number", req.SerialNumber)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.store.ReleaseHold(ctx, gen.ID(), serialHex)
+	switch {
+	case errors.Is(err, store.ErrNotOnHold):
+		return nil, preconditionError(preconditionEntryState, serialHex, err.Error())
+	case err != nil:
+		s.logger.Error("Failed to release hold", zap.Error(err))
+		return nil, storeError("failed to release hold")
+	}
+
+	s.logger.Info("Certificate hold released", zap.String("serial", serialHex))
+	s.entryChanged(ctx, gen.ID(), serialHex)
+
+	return &crl.ReleaseHoldResponse{
+		Success: true,
+		Message: "hold released",
+	}, nil
+}
+
+// CheckRevocationStatus reports the revocation status of one certificate
+func (s *CRLGRPCServer) CheckRevocationStatus(ctx context.Context, req *crl.CheckRevocationStatusRequest) (*crl.CheckRevocationStatusResponse, error) {
+	serialHex, err := serialField("serial_number", req.SerialNumber)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Most serials were never revoked
+	if !s.filter.MayContain(gen.ID(), serialHex) {
+		return &crl.CheckRevocationStatusResponse{}, nil
+	}
+	if resp, ok := s.cachedStatus(ctx, gen.ID(), serialHex); ok {
+		return resp, nil
+	}
+
+	reader := s.store.Reader()
+	entry, err := reader.GetEntry(ctx, gen.ID(), serialHex)
+	switch {
+	case errors.Is(err, store.ErrEntryNotFound):
+		entry = nil
+	case err != nil:
+		s.logger.Error("Failed to check revocation status", zap.String("serial", serialHex), zap.Error(err))
+		return nil, storeError("failed to check revocation status")
+	}
+
+	resp := revocationStatus(entry)
+	// A replica may not have the latest change yet, which the cache would
+	// keep past the invalidation
+	if reader == s.store {
+		s.cacheStatuses(ctx, gen.ID(), map[string]*crl.CheckRevocationStatusResponse{serialHex: resp})
+	}
+	return resp, nil
+}
+
+// CheckStatusBatch reports the revocation status of several certificates
+func (s *CRLGRPCServer) CheckStatusBatch(ctx context.Context, req *crl.CheckStatusBatchRequest) (*crl.CheckStatusBatchResponse, error) {
+	if len(req.SerialNumbers) == 0 {
+		return nil, fieldError("serial_numbers", "serial numbers are required")
+	}
+	if len(req.SerialNumbers) > maxBatchSize {
+		return nil, fieldError("serial_numbers", fmt.Sprintf("at most %d serial numbers per request", maxBatchSize))
+	}
+	serials := make([]string, len(req.SerialNumbers))
+	for i, value := range req.SerialNumbers {
+		canonical, err := serialField(fmt.Sprintf("serial_numbers[%d]", i), value)
+		if err != nil {
+			return nil, err
+		}
+		serials[i] = canonical
+	}
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []string
+	unrevoked := make(map[string]*crl.CheckRevocationStatusResponse)
+	for _, serial := range serials {
+		if s.filter.MayContain(gen.ID(), serial) {
+			candidates = append(candidates, serial)
+		} else {
+			unrevoked[serial] = &crl.CheckRevocationStatusResponse{}
+		}
+	}
+	found := s.cachedStatuses(ctx, gen.ID(), candidates)
+	var missing []string
+	for _, serial := range candidates {
+		if _, ok := found[serial]; !ok {
+			missing = append(missing, serial)
+		}
+	}
+	for serial, resp := range unrevoked {
+		found[serial] = resp
+	}
+	if len(missing) > 0 {
+		reader := s.store.Reader()
+		entries, err := reader.GetEntries(ctx, gen.ID(), missing)
+		if err != nil {
+			s.logger.Error("Failed to check revocation status", zap.Int("serials", len(req.SerialNumbers)), zap.Error(err))
+			return nil, storeError("failed to check revocation status")
+		}
+		looked := make(map[string]*crl.CheckRevocationStatusResponse, len(missing))
+		for _, serial := range missing {
+			looked[serial] = revocationStatus(entries[serial])
+			found[serial] = looked[serial]
+		}
+		if reader == s.store {
+			s.cacheStatuses(ctx, gen.ID(), looked)
+		}
+	}
+
+	statuses := make([]*crl.SerialStatus, 0, len(req.SerialNumbers))
+	for i, value := range req.SerialNumbers {
+		statuses = append(statuses, &crl.SerialStatus{SerialNumber: value, Status: found[serials[i]]})
+	}
+
+	return &crl.CheckStatusBatchResponse{Statuses: statuses}, nil
+}
+
+// revocationStatus converts a stored entry, nil if there is none, to its
+// status response
+func revocationStatus(entry *store.Entry) *crl.CheckRevocationStatusResponse {
+	if entry == nil {
+		return &crl.CheckRevocationStatusResponse{}
+	}
+	if entry.Status == store.StatusReleased || entry.Status == store.StatusRemoved {
+		return &crl.CheckRevocationStatusResponse{}
+	}
+	if entry.EffectiveAt != nil && entry.EffectiveAt.After(time.Now()) {
+		return &crl.CheckRevocationStatusResponse{EffectiveAt: timestamppb.New(*entry.EffectiveAt)}
+	}
+	resp := &crl.CheckRevocationStatusResponse{
+		Revoked:           true,
+		Reason:            entry.Reason,
+		RevokedAt:         timestamppb.New(entry.RevokedAt),
+		CertificateIssuer: entry.CertificateIssuer,
+	}
+	if entry.Status == store.StatusOnHold {
+		resp.OnHold = true
+		resp.Reason = revocation.ReasonName(models.ReasonCertificateHold)
+	}
+	if entry.InvalidityDate != nil {
+		resp.InvalidityDate = timestamppb.New(*entry.InvalidityDate)
+	}
+	return resp
+}
+
+// ListRevocations returns a page of an issuer's revocations
+func (s *CRLGRPCServer) ListRevocations(ctx context.Context, req *crl.ListRevocationsRequest) (*crl.ListRevocationsResponse, error) {
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := entryFilter(gen, req.Reasons, req.RevokedAfter, req.RevokedBefore, req.IncludeReleased, req.IncludeArchived)
+	if err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize < 0:
+		return nil, fieldError("page_size", "page size must not be negative")
+	case pageSize == 0:
+		pageSize = defaultPageSize
+	case pageSize > maxPageSize:
+		pageSize = maxPageSize
+	}
+
+	var cursor *store.EntryCursor
+	if req.PageToken != "" {
+		if cursor, err = decodePageToken(req.PageToken); err != nil {
+			return nil, fieldError("page_token", "invalid page token")
+		}
+	}
+
+	// One extra entry tells whether another page follows
+	entries, err := s.store.Reader().ListEntriesPage(ctx, filter, cursor, pageSize+1)
+	if err != nil {
+		s.logger.Error("Failed to list revocations", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		return nil, storeError("failed to list revocations")
+	}
+
+	resp := &crl.ListRevocationsResponse{}
+	if len(entries) > pageSize {
+		entries = entries[:pageSize]
+		last := entries[len(entries)-1]
+		resp.NextPageToken = encodePageToken(store.EntryCursor{RevokedAt: last.RevokedAt, Serial: last.Serial})
+	}
+	for i := range entries {
+		resp.Revocations = append(resp.Revocations, revocationRecord(&entries[i]))
+	}
+	return resp, nil
+}
+
+// entryFilter makes the store filter of the entries of gen a listing
+// request selects, reporting unknown reasons as InvalidArgument
+func entryFilter(gen *generator.Generator, reasons []string, after, before *timestamppb.Timestamp, released, archived bool) (store.EntryFilter, error) {
+	filter := store.EntryFilter{
+		IssuerID:        gen.ID(),
+		IncludeReleased: released,
+		IncludeArchived: archived,
+	}
+	for _, name := range reasons {
+		code, err := revocation.ParseReason(name)
+		if err != nil {
+			return filter, fieldError("reasons", err.Error())
+		}
+		filter.Reasons = append(filter.Reasons, revocation.ReasonName(code))
+	}
+	if after != nil {
+		t := after.AsTime()
+		filter.RevokedAfter = &t
+	}
+	if before != nil {
+		t := before.AsTime()
+		filter.RevokedBefore = &t
+	}
+	return filter, nil
+}
+
+// GetRevocation returns the full record of one revoked serial
+func (s *CRLGRPCServer) GetRevocation(ctx context.Context, req *crl.GetRevocationRequest) (*crl.GetRevocationResponse, error) {
+	serialHex, err := serialField("serial_number", req.SerialNumber)
+	if err != nil {
+		return nil, err
+	}
+	gen, err := s.issuer(req.IssuerId)
+	if err != nil {
+		return nil, err
+	}
+
+	reader := s.store.Reader()
+	entry, err := reader.GetEntry(ctx, gen.ID(), serialHex)
+	// A revocation added moments ago may not have reached the replica yet
+	if errors.Is(err, store.ErrEntryNotFound) && reader != s.store {
+		reader = s.store
+		entry, err = reader.GetEntry(ctx, gen.ID(), serialHex)
+	}
+	switch {
+	case errors.Is(err, store.ErrEntryNotFound):
+		return nil, notFoundError(resourceRevocation, serialHex, "no revocation recorded for serial")
+	case err != nil:
+		s.logger.Error("Failed to get revocation", zap.String("serial", serialHex), zap.Error(err))
+		return nil, storeError("failed to get revocation")
+	}
+
+	events, err := reader.ListSerialEvents(ctx, gen.ID(), serialHex)
+	if err != nil {
+		s.logger.Error("Failed to get revocation history", zap.String("serial", serialHex), zap.Error(err))
+		return nil, storeError("failed to get revocation")
+	}
+
+	resp := &crl.GetRevocationResponse{Revocation: revocationRecord(entry)}
+	for _, e := range events {
+		resp.History = append(resp.History, revocationEvent(e))
+	}
+	return resp, nil
+}
+
+// revocationRecord converts a stored entry to its message
+func revocationRecord(e *store.Entry) *crl.Revocation {
+	r := &crl.Revocation{
+		IssuerId:          e.IssuerID,
+		SerialNumber:      e.Serial,
+		Reason:            e.Reason,
+		Status:            e.Status,
+		RevokedAt:         timestamppb.New(e.RevokedAt),
+		CertificateIssuer: e.CertificateIssuer,
+		UpdatedAt:         timestamppb.New(e.UpdatedAt),
+		RemovedBy:         e.RemovedBy,
+		RemovalReason:     e.RemovalReason,
+	}
+	if e.InvalidityDate != nil {
+		r.InvalidityDate = timestamppb.New(*e.InvalidityDate)
+	}
+	if e.RemovedAt != nil {
+		r.RemovedAt = timestamppb.New(*e.RemovedAt)
+	}
+	if e.EffectiveAt != nil {
+		r.EffectiveAt = timestamppb.New(*e.EffectiveAt)
+	}
+	if e.NotAfter != nil {
+		r.NotAfter = timestamppb.New(*e.NotAfter)
+	}
+	if e.ArchivedAt != nil {
+		r.ArchivedAt = timestamppb.New(*e.ArchivedAt)
+	}
+	return r
+}
+
+// encodePageToken makes an opaque token from a page cursor
+func encodePageToken(c store.EntryCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.RevokedAt.UnixMicro(), 10) + "/" + c.Serial))
+}
+
+// decodePageToken parses a token made by encodePageToken. Tokens the store
+// could not take as a cursor, such as ones with a serial that is not UTF-8,
+// are rejected here rather than failing the query.
+func decodePageToken(token string) (*store.EntryCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	micros, serial, ok := strings.Cut(string(raw), "/")
+	if !ok || serial == "" || !utf8.ValidString(serial) || strings.ContainsRune(serial, 0) {
+		return nil, errors.New("malformed page token")
+	}
+	us, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if us < 0 {
+		return nil, errors.New("malformed page token")
+	}
+	return &store.EntryCursor{RevokedAt: time.UnixMicro(us), Serial: serial}, nil
+}
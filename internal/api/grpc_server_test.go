@@ -0,0 +1,170 @@
+package api
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/auth"
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/crl/internal/testutil"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestServer serves one issuer, id, from st, which may be nil for
+// requests rejected before the store is read
+func newTestServer(t *testing.T, id string, st *store.Store) *CRLGRPCServer {
+	t.Helper()
+	gen, err := generator.New(id, st, testutil.NewSigner(t, "Test CA "+id), generator.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuers := generator.NewRegistry(id)
+	if err := issuers.Add(gen); err != nil {
+		t.Fatal(err)
+	}
+	return NewCRLGRPCServer(st, issuers, nil, nil, nil, nil, nil, AdminConfig{}, ApprovalConfig{}, auth.Config{})
+}
+
+func TestPageTokenRoundTrip(t *testing.T) {
+	for _, c := range []store.EntryCursor{
+		{RevokedAt: time.Date(2025, 3, 1, 12, 30, 45, 123456000, time.UTC), Serial: "1a2b3c"},
+		{RevokedAt: time.Unix(0, 0), Serial: "1"},
+		{RevokedAt: time.Date(2099, 12, 31, 23, 59, 59, 999999000, time.UTC), Serial: strings.Repeat("ff", 20)},
+		// Serials stored before they were canonical
+		{RevokedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Serial: "legacy/serial 01"},
+	} {
+		token := encodePageToken(c)
+		got, err := decodePageToken(token)
+		if err != nil {
+			t.Fatalf("decodePageToken(%q): %v", token, err)
+		}
+		if !got.RevokedAt.Equal(c.RevokedAt) || got.Serial != c.Serial {
+			t.Fatalf("round trip of %+v gave %+v", c, *got)
+		}
+	}
+
+	// Cursors keep microseconds, the precision of timestamptz
+	c := store.EntryCursor{RevokedAt: time.Date(2025, 1, 1, 0, 0, 0, 1500, time.UTC), Serial: "1"}
+	got, err := decodePageToken(encodePageToken(c))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := c.RevokedAt.Truncate(time.Microsecond); !got.RevokedAt.Equal(want) {
+		t.Fatalf("got %s, want %s", got.RevokedAt, want)
+	}
+}
+
+func TestListRevocationsRejectsInvalidPageToken(t *testing.T) {
+	s := newTestServer(t, "root", nil)
+	valid := encodePageToken(store.EntryCursor{RevokedAt: time.Now(), Serial: "1a"})
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := map[string]string{
+		"not base64":           "!!!",
+		"padded base64":        base64.URLEncoding.EncodeToString([]byte("1/1a")),
+		"standard alphabet":    "MTc0MDAwMDAwMDAwMDAwMC8xYQ+/",
+		"truncated":            valid[:len(valid)-3] + "*",
+		"no separator":         raw("17400000000000001a"),
+		"no serial":            raw("1740000000000000/"),
+		"no time":              raw("/1a"),
+		"time not a number":    raw("yesterday/1a"),
+		"time overflows":       raw("99999999999999999999/1a"),
+		"negative time":        raw("-1/1a"),
+		"serial not UTF-8":     raw("1740000000000000/\xff\xfe"),
+		"serial with NUL":      raw("1740000000000000/1a\x00"),
+		"tampered base64 tail": valid + "A",
+	}
+	for name, token := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.ListRevocations(context.Background(), &crl.ListRevocationsRequest{
+				IssuerId:  "root",
+				PageToken: token,
+			})
+			if code := status.Code(err); code != codes.InvalidArgument {
+				t.Fatalf("got %v (%v), want InvalidArgument", code, err)
+			}
+		})
+	}
+}
+
+func TestListRevocationsPagesStayStable(t *testing.T) {
+	st := testutil.Store(t)
+	ctx := context.Background()
+	id := fmt.Sprintf("test-%d", time.Now().UnixNano())
+	s := newTestServer(t, id, st)
+	cert := s.issuers.All()[0].Issuer()
+	if err := st.UpsertIssuer(ctx, store.Issuer{ID: id, Subject: cert.Subject.String(), RawSubject: cert.RawSubject, SubjectKeyID: cert.SubjectKeyId}); err != nil {
+		t.Fatal(err)
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	entry := func(serial string, at time.Time) store.Entry {
+		return store.Entry{IssuerID: id, Serial: serial, RevokedAt: at, Reason: "keyCompromise"}
+	}
+	// Pairs of entries revoked at the same time, so pages also split ties
+	var entries []store.Entry
+	for i := range 12 {
+		at := base.Add(time.Duration(i/2) * time.Minute)
+		entries = append(entries, entry(fmt.Sprintf("%x", 0x100+i), at))
+	}
+	if err := st.AddEntries(ctx, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	list := func(token string) *crl.ListRevocationsResponse {
+		t.Helper()
+		resp, err := s.ListRevocations(ctx, &crl.ListRevocationsRequest{IssuerId: id, PageSize: 5, PageToken: token})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	var listed []*crl.Revocation
+	resp := list("")
+	listed = append(listed, resp.Revocations...)
+	last := resp.Revocations[len(resp.Revocations)-1]
+
+	// Revocations added between pages: before the cursor they belong to
+	// pages already read, after it to the following ones
+	newer := entry("aaa1", base.Add(time.Hour))
+	tie := entry("1", last.RevokedAt.AsTime())
+	older := entry("aaa2", base.Add(-time.Hour))
+	if err := st.AddEntries(ctx, []store.Entry{newer, tie, older}); err != nil {
+		t.Fatal(err)
+	}
+
+	for token := resp.NextPageToken; token != ""; token = resp.NextPageToken {
+		resp = list(token)
+		listed = append(listed, resp.Revocations...)
+	}
+
+	seen := make(map[string]int)
+	for i, r := range listed {
+		seen[r.SerialNumber]++
+		if i > 0 && r.RevokedAt.AsTime().After(listed[i-1].RevokedAt.AsTime()) {
+			t.Errorf("%s revoked after %s is listed after it", r.SerialNumber, listed[i-1].SerialNumber)
+		}
+	}
+	for _, e := range entries {
+		if seen[e.Serial] != 1 {
+			t.Errorf("serial %s listed %d times, want once", e.Serial, seen[e.Serial])
+		}
+	}
+	if seen[newer.Serial] != 0 {
+		t.Errorf("revocation before the cursor listed on a later page")
+	}
+	if seen[tie.Serial] != 1 || seen[older.Serial] != 1 {
+		t.Errorf("revocations after the cursor listed %d and %d times, want once", seen[tie.Serial], seen[older.Serial])
+	}
+	if len(listed) != len(entries)+2 {
+		t.Errorf("listed %d revocations, want %d", len(listed), len(entries)+2)
+	}
+}
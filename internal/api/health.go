@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	defaultHealthInterval = 10 * time.Second
+	defaultHealthTimeout  = 5 * time.Second
+)
+
+// Health services reported besides the overall status ("") and that of
+// the CRL service
+const (
+	healthServiceDatabase   = "database"
+	healthServiceMigrations = "migrations"
+	healthServiceSigner     = "signer/"
+	healthServiceCRL        = "crl/"
+)
+
+// HealthMethods are the grpc.health.v1 RPCs, called by load balancers and
+// probes without credentials
+var HealthMethods = []string{
+	healthpb.Health_Check_FullMethodName,
+	healthpb.Health_List_FullMethodName,
+	healthpb.Health_Watch_FullMethodName,
+}
+
+// HealthConfig configures the checks behind the gRPC health service and
+// the readiness endpoint
+type HealthConfig struct {
+	// Interval is how often the checks run (default 10s)
+	Interval time.Duration `yaml:"interval"`
+
+	// Timeout bounds each check (default 5s)
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Validate applies defaults and checks the health config
+func (c *HealthConfig) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if c.Interval == 0 {
+		c.Interval = defaultHealthInterval
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultHealthTimeout
+	}
+	return nil
+}
+
+// CheckResult is the outcome of the last run of one check
+type CheckResult struct {
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// HealthChecker periodically checks database connectivity, the schema
+// version, the signer of every issuer and the freshness of its published
+// CRL, and reports them through grpc.health.v1. The overall status and
+// that of the CRL service are SERVING only while every check passes.
+type HealthChecker struct {
+	store   *store.Store
+	issuers *generator.Registry
+	cfg     HealthConfig
+	server  *health.Server
+	logger  *logger.Logger
+
+	mu       sync.RWMutex
+	results  map[string]CheckResult
+	shutdown bool
+}
+
+// NewHealthChecker creates a health checker. Every service is NOT_SERVING
+// until the first check completes.
+func NewHealthChecker(st *store.Store, issuers *generator.Registry, cfg HealthConfig) *HealthChecker {
+	h := &HealthChecker{
+		store:   st,
+		issuers: issuers,
+		cfg:     cfg,
+		server:  health.NewServer(),
+		logger:  logger.Global(),
+		results: make(map[string]CheckResult),
+	}
+	for _, service := range h.services() {
+		h.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return h
+}
+
+// Server returns the grpc.health.v1 service to register
+func (h *HealthChecker) Server() healthpb.HealthServer {
+	return h.server
+}
+
+// services lists every reported service
+func (h *HealthChecker) services() []string {
+	services := []string{"", crl.CRLService_ServiceDesc.ServiceName}
+	for service := range h.checks() {
+		services = append(services, service)
+	}
+	return services
+}
+
+// checks returns the check of every reported component, by service
+func (h *HealthChecker) checks() map[string]func(context.Context) error {
+	checks := map[string]func(context.Context) error{
+		healthServiceDatabase:   h.store.Ping,
+		healthServiceMigrations: h.store.CheckSchema,
+	}
+	for _, g := range h.issuers.All() {
+		checks[healthServiceSigner+g.ID()] = g.CheckHealth
+		checks[healthServiceCRL+g.ID()] = h.crlCheck(g.ID())
+	}
+	return checks
+}
+
+// crlCheck returns a check that the last CRL published by an issuer has
+// not passed its nextUpdate
+func (h *HealthChecker) crlCheck(issuerID string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, nextUpdate, err := h.store.PublicationState(ctx, issuerID)
+		if err != nil {
+			return err
+		}
+		if nextUpdate.IsZero() {
+			return errors.New("no CRL has been published")
+		}
+		if time.Now().After(nextUpdate) {
+			return fmt.Errorf("published CRL expired at %s", nextUpdate.UTC().Format(time.RFC3339))
+		}
+		return nil
+	}
+}
+
+// Results returns the last result of every check and whether the instance
+// is ready: all checks passed and it is not shutting down. Checks that have
+// not run yet count as failed.
+func (h *HealthChecker) Results() (map[string]CheckResult, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	results := make(map[string]CheckResult, len(h.results))
+	ready := len(h.results) > 0 && !h.shutdown
+	for service, r := range h.results {
+		results[service] = r
+		ready = ready && r.Healthy
+	}
+	return results, ready
+}
+
+// Run checks the components every interval until ctx is done, then
+// reports every service as NOT_SERVING
+func (h *HealthChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.check(ctx)
+		select {
+		case <-ctx.Done():
+			h.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Shutdown reports every service as NOT_SERVING, so that load balancers
+// drain the instance, and stops further updates
+func (h *HealthChecker) Shutdown() {
+	h.mu.Lock()
+	h.shutdown = true
+	h.mu.Unlock()
+	h.server.Shutdown()
+}
+
+// check runs every component check concurrently and updates the statuses
+func (h *HealthChecker) check(parent context.Context) {
+	ctx, cancel := context.WithTimeout(parent, h.cfg.Timeout)
+	defer cancel()
+
+	checks := h.checks()
+	results := make(map[string]error, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for service, fn := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := fn(ctx)
+			mu.Lock()
+			results[service] = err
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if parent.Err() != nil {
+		// Shutting down; the results of interrupted checks mean nothing
+		return
+	}
+
+	healthy := true
+	for service, err := range results {
+		h.report(service, err)
+		if err != nil {
+			healthy = false
+		}
+	}
+	overall := healthpb.HealthCheckResponse_SERVING
+	if !healthy {
+		overall = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+	h.server.SetServingStatus("", overall)
+	h.server.SetServingStatus(crl.CRLService_ServiceDesc.ServiceName, overall)
+}
+
+// report records the result of the check of service and sets its status,
+// logging failures and recoveries
+func (h *HealthChecker) report(service string, err error) {
+	result := CheckResult{Healthy: err == nil, CheckedAt: time.Now()}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	h.mu.Lock()
+	prev, seen := h.results[service]
+	h.results[service] = result
+	h.mu.Unlock()
+	wasFailed := seen && !prev.Healthy
+
+	if err != nil {
+		if !wasFailed {
+			h.logger.Warn("Health check failed", zap.String("service", service), zap.Error(err))
+		}
+		h.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+		return
+	}
+	if wasFailed {
+		h.logger.Info("Health check passed", zap.String("service", service))
+	}
+	h.server.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+}
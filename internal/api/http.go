@@ -6,21 +6,24 @@ import (
 
 	"github.com/gigvault/shared/pkg/logger"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 )
 
 type HTTPHandler struct {
 	logger *logger.Logger
+	health *HealthChecker
 }
 
-func NewHTTPHandler(logger *logger.Logger) *HTTPHandler {
-	return &HTTPHandler{logger: logger}
+func NewHTTPHandler(logger *logger.Logger, health *HealthChecker) *HTTPHandler {
+	return &HTTPHandler{logger: logger, health: health}
 }
 
 func (h *HTTPHandler) Routes() http.Handler {
 	r := mux.NewRouter()
 	r.HandleFunc("/health", h.Health).Methods("GET")
 	r.HandleFunc("/ready", h.Ready).Methods("GET")
+	r.Handle("/metrics", promhttp.Handler()).Methods("GET")
 	
 	api := r.PathPrefix("/api/v1").Subrouter()
 	api.HandleFunc("/status", h.Status).Methods("GET")
@@ -34,8 +37,17 @@ func (h *HTTPHandler) Health(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *HTTPHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	checks, ready := h.health.Results()
+	status := "ready"
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
+	if !ready {
+		status = "not_ready"
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": status,
+		"checks": checks,
+	})
 }
 
 func (h *HTTPHandler) Status(w http.ResponseWriter, r *http.Request) {
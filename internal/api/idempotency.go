@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Idempotency key limits: replays within idempotencyTTL return the first
+// response
+const (
+	maxIdempotencyKeyLen = 255
+	idempotencyTTL       = 24 * time.Hour
+)
+
+// idempotent runs do once per idempotency key. Retries of the same request
+// with the key get the first response without running do again; newResp
+// returns the empty response message to decode it into. Without a key do
+// always runs.
+func idempotent[T proto.Message](ctx context.Context, s *CRLGRPCServer, method, key string, req proto.Message, newResp func() T, do func() (T, error)) (T, error) {
+	var zero T
+	if key == "" {
+		return do()
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		return zero, fieldError("idempotency_key", fmt.Sprintf("idempotency key must not exceed %d bytes", maxIdempotencyKeyLen))
+	}
+
+	hash, err := requestHash(method, req)
+	if err != nil {
+		return zero, status.Error(codes.Internal, "failed to hash request")
+	}
+
+	stored, err := s.store.ClaimIdempotencyKey(ctx, key, hash, idempotencyTTL)
+	switch {
+	case errors.Is(err, store.ErrIdempotencyConflict):
+		return zero, preconditionError(preconditionIdempotency, key, err.Error())
+	case errors.Is(err, store.ErrIdempotencyInProgress):
+		return zero, retryableError(codes.Aborted, err.Error(), storeRetryDelay)
+	case err != nil:
+		s.logger.Error("Failed to claim idempotency key", zap.Error(err))
+		return zero, storeError("failed to check idempotency key")
+	case stored != nil:
+		resp := newResp()
+		if err := proto.Unmarshal(stored, resp); err != nil {
+			return zero, status.Error(codes.Internal, "failed to decode stored response")
+		}
+		s.logger.Info("Replayed idempotent request", zap.String("method", method), zap.String("idempotency_key", key))
+		return resp, nil
+	}
+
+	resp, err := do()
+	if err != nil {
+		if relErr := s.store.ReleaseIdempotencyKey(ctx, key); relErr != nil {
+			s.logger.Warn("Failed to release idempotency key", zap.String("idempotency_key", key), zap.Error(relErr))
+		}
+		return zero, err
+	}
+
+	encoded, err := proto.Marshal(resp)
+	if err == nil {
+		err = s.store.CompleteIdempotencyKey(ctx, key, encoded)
+	}
+	if err != nil {
+		// The request succeeded; a retry will see it as in progress until
+		// the key expires
+		s.logger.Warn("Failed to store idempotent response", zap.String("idempotency_key", key), zap.Error(err))
+	}
+	return resp, nil
+}
+
+// requestHash identifies a request by its method and deterministic
+// encoding, which the caller passes without the idempotency key
+func requestHash(method string, req proto.Message) ([]byte, error) {
+	encoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write(encoded)
+	return h.Sum(nil), nil
+}
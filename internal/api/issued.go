@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"time"
+
+	"github.com/gigvault/crl/internal/ca"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/serial"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+)
+
+// errNotIssuedByIssuer is returned when the CA certificate with a serial
+// names a different issuer than the revocation
+var errNotIssuedByIssuer = errors.New("certificate with this serial was not issued by the issuer")
+
+// verifyIssued confirms with the CA service that issuerID issued the
+// certificate with serialHex before it is revoked or held, and returns
+// when the certificate expires. certIssuer is the certificate issuer of an
+// indirect CRL entry, nil for the CRL issuer. In warn mode, and in reject
+// mode with fail_open when the CA cannot be reached, failures are logged
+// and the revocation proceeds with an unknown expiry.
+func (s *CRLGRPCServer) verifyIssued(ctx context.Context, issuerID, serialHex string, certIssuer []byte) (*time.Time, error) {
+	if s.ca == nil {
+		return nil, nil
+	}
+	cfg := s.ca.Config()
+
+	cert, err := s.lookupIssued(ctx, issuerID, serialHex, certIssuer)
+	result := "issued"
+	switch {
+	case errors.Is(err, ca.ErrUnknownSerial):
+		result = "unknown"
+	case errors.Is(err, errNotIssuedByIssuer):
+		result = "mismatch"
+	case err != nil:
+		result = "error"
+	}
+	metrics.CAVerifications.WithLabelValues(result).Inc()
+	if err == nil {
+		return &cert.NotAfter, nil
+	}
+
+	fields := []zap.Field{zap.String("issuer_id", issuerID), zap.String("serial", serialHex), zap.Error(err)}
+	if result == "error" {
+		if cfg.Verify == ca.VerifyReject && !cfg.FailOpen {
+			s.logger.Error("Failed to verify serial with the CA; refusing revocation", fields...)
+			return nil, retryableError(codes.Unavailable, "certificate authority unavailable", caRetryDelay)
+		}
+		s.logger.Warn("Failed to verify serial with the CA; accepting revocation", fields...)
+		return nil, nil
+	}
+	if cfg.Verify == ca.VerifyReject {
+		s.logger.Warn("Refusing revocation of a serial the CA did not issue", fields...)
+		return nil, preconditionError(preconditionIssued, serialHex, err.Error())
+	}
+	s.logger.Warn("Revoking a serial the CA did not issue", fields...)
+	return nil, nil
+}
+
+// lookupIssued fetches the certificate with serialHex from the CA and
+// checks that it was issued by issuerID, or certIssuer when set
+func (s *CRLGRPCServer) lookupIssued(ctx context.Context, issuerID, serialHex string, certIssuer []byte) (*x509.Certificate, error) {
+	cert, err := s.ca.Certificate(ctx, serialHex)
+	if err != nil {
+		return nil, err
+	}
+	if serial.Hex(cert.SerialNumber) != serialHex {
+		return nil, ca.ErrUnknownSerial
+	}
+	issued := bytes.Equal(cert.RawIssuer, certIssuer)
+	if certIssuer == nil {
+		gen, err := s.issuers.Get(issuerID)
+		if err != nil {
+			return nil, err
+		}
+		issued = gen.IsCRLIssuer(cert.RawIssuer)
+	}
+	if !issued {
+		return nil, errNotIssuedByIssuer
+	}
+	return cert, nil
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"google.golang.org/protobuf/proto"
+)
+
+// cachedStatus returns the status of a serial from the Redis status cache
+func (s *CRLGRPCServer) cachedStatus(ctx context.Context, issuerID, serial string) (*crl.CheckRevocationStatusResponse, bool) {
+	value, ok := s.status.Get(ctx, issuerID, serial)
+	if !ok {
+		return nil, false
+	}
+	resp := &crl.CheckRevocationStatusResponse{}
+	if err := proto.Unmarshal(value, resp); err != nil {
+		return nil, false
+	}
+	return resp, true
+}
+
+// cachedStatuses returns the statuses of the serials found in the Redis
+// status cache
+func (s *CRLGRPCServer) cachedStatuses(ctx context.Context, issuerID string, serials []string) map[string]*crl.CheckRevocationStatusResponse {
+	found := make(map[string]*crl.CheckRevocationStatusResponse, len(serials))
+	for i, value := range s.status.GetMany(ctx, issuerID, serials) {
+		if value == nil {
+			continue
+		}
+		resp := &crl.CheckRevocationStatusResponse{}
+		if err := proto.Unmarshal(value, resp); err != nil {
+			continue
+		}
+		found[serials[i]] = resp
+	}
+	return found
+}
+
+// cacheStatuses stores statuses read from Postgres in the Redis status
+// cache. A scheduled revocation is cached only until it takes effect.
+func (s *CRLGRPCServer) cacheStatuses(ctx context.Context, issuerID string, statuses map[string]*crl.CheckRevocationStatusResponse) {
+	if s.status == nil {
+		return
+	}
+	now := time.Now()
+	byTTL := make(map[time.Duration]map[string][]byte)
+	for serial, resp := range statuses {
+		var ttl time.Duration // the configured TTL
+		if !resp.Revoked && resp.EffectiveAt != nil {
+			ttl = resp.EffectiveAt.AsTime().Sub(now)
+			if ttl <= 0 {
+				continue
+			}
+		}
+		value, err := proto.Marshal(resp)
+		if err != nil {
+			continue
+		}
+		if byTTL[ttl] == nil {
+			byTTL[ttl] = make(map[string][]byte)
+		}
+		byTTL[ttl][serial] = value
+	}
+	for ttl, values := range byTTL {
+		s.status.SetMany(ctx, issuerID, values, ttl)
+	}
+}
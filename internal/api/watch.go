@@ -0,0 +1,149 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	// watchBatch bounds the events read per query
+	watchBatch = 500
+
+	// watchPollInterval picks up changes made by other replicas
+	watchPollInterval = 2 * time.Second
+
+	// watchGapTimeout is how long events after a sequence gap are held
+	// back: the missing sequence may belong to a transaction that has not
+	// committed yet. Older gaps are rolled back transactions.
+	watchGapTimeout = 5 * time.Second
+)
+
+// changeNotifier wakes watchers when this replica changes an entry
+type changeNotifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func newChangeNotifier() *changeNotifier {
+	return &changeNotifier{ch: make(chan struct{})}
+}
+
+// changed returns a channel closed at the next change
+func (n *changeNotifier) changed() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.ch
+}
+
+func (n *changeNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.ch)
+	n.ch = make(chan struct{})
+}
+
+// WatchRevocations streams revocation events of one or all issuers
+func (s *CRLGRPCServer) WatchRevocations(req *crl.WatchRevocationsRequest, stream crl.CRLService_WatchRevocationsServer) error {
+	ctx := stream.Context()
+
+	issuerID := ""
+	if !req.AllIssuers {
+		gen, err := s.issuer(req.IssuerId)
+		if err != nil {
+			return err
+		}
+		issuerID = gen.ID()
+	}
+	if req.AfterSequence < 0 {
+		return fieldError("after_sequence", "after_sequence must not be negative")
+	}
+
+	last := req.AfterSequence
+	if last == 0 {
+		seq, err := s.store.LatestEventSequence(ctx)
+		if err != nil {
+			s.logger.Error("Failed to start revocation watch", zap.Error(err))
+			return storeError("failed to read revocation events")
+		}
+		last = seq
+	}
+	s.logger.Info("Revocation watch started", zap.String("issuer_id", issuerID), zap.Int64("after_sequence", last))
+
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Subscribe before reading so no local change is missed in between
+		changed := s.changes.changed()
+
+		events, err := s.store.ListEvents(ctx, last, watchBatch)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			s.logger.Error("Failed to read revocation events", zap.Error(err))
+			return storeError("failed to read revocation events")
+		}
+
+		read := 0
+		for _, e := range events {
+			if e.Sequence != last+1 && time.Since(e.CreatedAt) < watchGapTimeout {
+				break
+			}
+			if issuerID == "" || e.IssuerID == issuerID {
+				if err := stream.Send(revocationEvent(e)); err != nil {
+					return err
+				}
+			}
+			last = e.Sequence
+			read++
+		}
+		if read == watchBatch {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.watchesStopped:
+			return status.Error(codes.Unavailable, "server is shutting down")
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// StopWatches ends every revocation watch with Unavailable, so that
+// clients resume after their last sequence on another replica instead of
+// holding up a graceful stop
+func (s *CRLGRPCServer) StopWatches() {
+	s.stopOnce.Do(func() { close(s.watchesStopped) })
+}
+
+// revocationEvent converts a stored event to its message
+func revocationEvent(e store.Event) *crl.RevocationEvent {
+	msg := &crl.RevocationEvent{
+		Sequence:          e.Sequence,
+		IssuerId:          e.IssuerID,
+		SerialNumber:      e.Serial,
+		Status:            e.Status,
+		Reason:            e.Reason,
+		RevokedAt:         timestamppb.New(e.RevokedAt),
+		CertificateIssuer: e.CertificateIssuer,
+		RecordedAt:        timestamppb.New(e.CreatedAt),
+	}
+	if e.InvalidityDate != nil {
+		msg.InvalidityDate = timestamppb.New(*e.InvalidityDate)
+	}
+	if e.EffectiveAt != nil {
+		msg.EffectiveAt = timestamppb.New(*e.EffectiveAt)
+	}
+	return msg
+}
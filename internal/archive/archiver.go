@@ -0,0 +1,129 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval  = time.Hour
+	defaultBatchSize = 1000
+)
+
+// Config is the retention policy of entries that left CRL generation
+type Config struct {
+	// Enabled moves the entries of each issuer whose certificate expired
+	// longer ago than its crl.expired_retention to crl_entries_archive.
+	// Issuers that keep expired entries on their CRLs are not archived.
+	Enabled bool `yaml:"enabled"`
+
+	// Retention deletes archived entries after this long; zero keeps them
+	// for good
+	Retention time.Duration `yaml:"retention"`
+
+	// Interval is how often entries are archived (default 1h)
+	Interval time.Duration `yaml:"interval"`
+
+	// BatchSize bounds the entries moved per transaction (default 1000)
+	BatchSize int `yaml:"batch_size"`
+}
+
+// Validate applies defaults and checks the archive config
+func (c *Config) Validate() error {
+	if c.Retention < 0 || c.Interval < 0 {
+		return errors.New("retention and interval must not be negative")
+	}
+	if c.BatchSize < 0 {
+		return errors.New("batch_size must not be negative")
+	}
+	if c.Interval == 0 {
+		c.Interval = defaultInterval
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	return nil
+}
+
+// Archiver periodically moves expired entries to the archive and purges
+// archived entries past the retention
+type Archiver struct {
+	store   *store.Store
+	issuers *generator.Registry
+	cfg     Config
+	logger  *logger.Logger
+}
+
+// NewArchiver creates an archiver of the entries of issuers
+func NewArchiver(st *store.Store, issuers *generator.Registry, cfg Config) *Archiver {
+	return &Archiver{
+		store:   st,
+		issuers: issuers,
+		cfg:     cfg,
+		logger:  logger.Global(),
+	}
+}
+
+// Run archives and purges every interval until ctx is done
+func (a *Archiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		a.archive(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// archive makes one pass over every issuer and the archive
+func (a *Archiver) archive(ctx context.Context) {
+	now := time.Now()
+	for _, gen := range a.issuers.All() {
+		retention := gen.ExpiredRetention()
+		if retention == 0 {
+			continue
+		}
+		var total int64
+		for ctx.Err() == nil {
+			n, err := a.store.ArchiveExpired(ctx, gen.ID(), now.Add(-retention), a.cfg.BatchSize)
+			if err != nil {
+				a.logger.Error("Failed to archive expired entries", zap.String("issuer_id", gen.ID()), zap.Error(err))
+				break
+			}
+			total += n
+			metrics.EntriesArchived.WithLabelValues(gen.ID()).Add(float64(n))
+			if n < int64(a.cfg.BatchSize) {
+				break
+			}
+		}
+		if total > 0 {
+			a.logger.Info("Archived entries of expired certificates",
+				zap.String("issuer_id", gen.ID()),
+				zap.Int64("entries", total),
+			)
+		}
+	}
+
+	if a.cfg.Retention == 0 || ctx.Err() != nil {
+		return
+	}
+	n, err := a.store.PurgeArchive(ctx, now.Add(-a.cfg.Retention))
+	if err != nil {
+		a.logger.Error("Failed to purge archived entries", zap.Error(err))
+		return
+	}
+	if n > 0 {
+		a.logger.Info("Purged archived entries", zap.Int64("entries", n))
+	}
+}
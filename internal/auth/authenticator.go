@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// bearerPrefix starts the authorization metadata of bearer tokens
+const bearerPrefix = "bearer "
+
+// Config configures authentication of gRPC callers
+type Config struct {
+	MTLS    MTLSConfig   `yaml:"mtls"`
+	APIKeys APIKeyConfig `yaml:"api_keys"`
+	OIDC    OIDCConfig   `yaml:"oidc"`
+
+	// Roles defines custom roles as the RPCs they grant, alongside the
+	// built-in reader, revoker, publisher and admin
+	Roles map[string][]string `yaml:"roles"`
+}
+
+// Validate applies defaults and checks the settings
+func (c *Config) Validate() error {
+	for name, methods := range c.Roles {
+		if _, ok := builtinRoles[name]; ok {
+			return fmt.Errorf("roles: built-in role %q cannot be redefined", name)
+		}
+		if len(methods) == 0 {
+			return fmt.Errorf("roles: %q grants no methods", name)
+		}
+	}
+	if err := c.MTLS.validate(c.Roles); err != nil {
+		return err
+	}
+	if err := c.APIKeys.Validate(); err != nil {
+		return fmt.Errorf("api_keys: %w", err)
+	}
+	if err := c.OIDC.Validate(); err != nil {
+		return fmt.Errorf("oidc: %w", err)
+	}
+	for value, role := range c.OIDC.RoleMapping {
+		if err := ValidateRoles([]string{role}, c.Roles); err != nil {
+			return fmt.Errorf("oidc.role_mapping[%q]: %w", value, err)
+		}
+	}
+	return nil
+}
+
+// Authenticator establishes the identity of gRPC calls; the Authorizer
+// checks what it may call
+type Authenticator struct {
+	cfg         Config
+	mtlsEnabled bool
+	store       *store.Store
+	tokens      *tokenVerifier // nil without OIDC
+	exempt      map[string]bool
+	logger      *logger.Logger
+}
+
+// NewAuthenticator creates an authenticator. Calls present a verified
+// client certificate when mtlsEnabled, an API key from st when API keys
+// are enabled, or an OIDC bearer token when OIDC is enabled; with none
+// enabled they pass through unauthenticated.
+func NewAuthenticator(cfg Config, mtlsEnabled bool, st *store.Store) *Authenticator {
+	a := &Authenticator{
+		cfg:         cfg,
+		mtlsEnabled: mtlsEnabled,
+		store:       st,
+		exempt:      make(map[string]bool),
+		logger:      logger.Global(),
+	}
+	if cfg.OIDC.Enabled {
+		a.tokens = newTokenVerifier(cfg.OIDC)
+	}
+	return a
+}
+
+// Run keeps the OIDC signing keys fresh until ctx is done
+func (a *Authenticator) Run(ctx context.Context) {
+	if a.tokens != nil {
+		a.tokens.run(ctx)
+	}
+}
+
+// Exempt lets methods, given by their full /package.Service/Method names,
+// that are authorized by other means, such as the admin token, be called
+// without credentials
+func (a *Authenticator) Exempt(methods ...string) {
+	for _, m := range methods {
+		a.exempt[m] = true
+	}
+}
+
+// authenticate returns the context of a call to fullMethod carrying the
+// caller's identity
+func (a *Authenticator) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if a.exempt[fullMethod] {
+		return ctx, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if keys := md.Get(APIKeyHeader); a.cfg.APIKeys.Enabled && len(keys) > 0 {
+		return a.authenticateKey(ctx, keys)
+	}
+	if values := md.Get("authorization"); a.tokens != nil && len(values) > 0 {
+		return a.authenticateToken(ctx, fullMethod, values)
+	}
+
+	if a.mtlsEnabled {
+		id, ok := PeerIdentity(ctx)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "client certificate required")
+		}
+		return NewContext(ctx, a.cfg.MTLS.identity(id)), nil
+	}
+	if a.cfg.APIKeys.Enabled || a.tokens != nil {
+		return nil, status.Error(codes.Unauthenticated, "credentials required")
+	}
+	return ctx, nil
+}
+
+// authenticateToken checks the bearer token sent with a call to fullMethod
+func (a *Authenticator) authenticateToken(ctx context.Context, fullMethod string, values []string) (context.Context, error) {
+	if len(values) != 1 || len(values[0]) <= len(bearerPrefix) || !strings.EqualFold(values[0][:len(bearerPrefix)], bearerPrefix) {
+		return nil, status.Error(codes.Unauthenticated, "authorization must be a single bearer token")
+	}
+	id, err := a.tokens.verify(ctx, values[0][len(bearerPrefix):])
+	if err != nil {
+		a.logger.Warn("Rejected bearer token", zap.String("method", fullMethod), zap.Error(err))
+		return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+	}
+	return NewContext(ctx, id), nil
+}
+
+// authenticateKey checks the API key sent with a call
+func (a *Authenticator) authenticateKey(ctx context.Context, keys []string) (context.Context, error) {
+	if len(keys) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "exactly one api key is required")
+	}
+	id, secret, ok := ParseAPIKey(keys[0])
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	}
+
+	key, err := a.store.GetAPIKey(ctx, id)
+	switch {
+	case errors.Is(err, store.ErrAPIKeyNotFound):
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	case err != nil:
+		a.logger.Error("Failed to look up api key", zap.String("key_id", id), zap.Error(err))
+		return nil, status.Error(codes.Unavailable, "failed to verify api key")
+	}
+	if subtle.ConstantTimeCompare(HashAPIKeySecret(secret), key.SecretHash) != 1 {
+		return nil, status.Error(codes.Unauthenticated, "invalid api key")
+	}
+	if !key.Active(time.Now()) {
+		return nil, status.Error(codes.Unauthenticated, "api key is revoked or expired")
+	}
+	return NewContext(ctx, Identity{
+		Subject: key.Client,
+		Method:  MethodAPIKey,
+		Roles:   key.Roles,
+		RPCs:    key.Methods,
+	}), nil
+}
+
+// UnaryInterceptor authenticates unary calls
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authenticates streaming calls
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &identityStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// identityStream is a server stream carrying an authenticated context
+type identityStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identityStream) Context() context.Context {
+	return s.ctx
+}
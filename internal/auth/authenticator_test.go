@@ -0,0 +1,197 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/crl/internal/testutil"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// withAPIKey returns a context of a call sending keys
+func withAPIKey(keys ...string) context.Context {
+	md := metadata.MD{}
+	for _, k := range keys {
+		md.Append(APIKeyHeader, k)
+	}
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestAuthenticateExempt(t *testing.T) {
+	a := NewAuthenticator(Config{APIKeys: APIKeyConfig{Enabled: true}}, false, nil)
+	a.Exempt(healthpb.Health_Check_FullMethodName, healthpb.Health_List_FullMethodName)
+
+	tests := []struct {
+		method string
+		exempt bool
+	}{
+		{healthpb.Health_Check_FullMethodName, true},
+		{healthpb.Health_List_FullMethodName, true},
+		{healthpb.Health_Watch_FullMethodName, false},
+		{"/evil.v1.Shadow/Check", false},
+		{"Check", false},
+		{crl.CRLService_GetCRL_FullMethodName, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			ctx, err := a.authenticate(context.Background(), tt.method)
+			if tt.exempt {
+				if err != nil {
+					t.Fatalf("exempt method: %v", err)
+				}
+				if _, ok := FromContext(ctx); ok {
+					t.Fatal("exempt call carries an identity")
+				}
+				return
+			}
+			if status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("got %v, want Unauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestAuthenticateMalformedKey(t *testing.T) {
+	// Rejected before the store is consulted
+	a := NewAuthenticator(Config{APIKeys: APIKeyConfig{Enabled: true}}, false, nil)
+	_, key, _, err := NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, keys := range map[string][]string{
+		"two keys":    {key, key},
+		"no prefix":   {strings.TrimPrefix(key, apiKeyPrefix)},
+		"no secret":   {strings.SplitAfter(key, ".")[0]},
+		"no id":       {apiKeyPrefix + "." + "secret"},
+		"not a key":   {"secret"},
+		"empty value": {""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := a.authenticate(withAPIKey(keys...), crl.CRLService_GetCRL_FullMethodName)
+			if status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("got %v, want Unauthenticated", err)
+			}
+		})
+	}
+}
+
+func TestAPIKeyHash(t *testing.T) {
+	id, key, secretHash, err := NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotID, secret, ok := ParseAPIKey(key)
+	if !ok || gotID != id {
+		t.Fatalf("ParseAPIKey(%q) = %q, %v; want ID %q", key, gotID, ok, id)
+	}
+	if !bytes.Equal(HashAPIKeySecret(secret), secretHash) {
+		t.Fatal("secret does not match its stored hash")
+	}
+	if bytes.Equal(HashAPIKeySecret(secret+"x"), secretHash) {
+		t.Fatal("different secret matches the stored hash")
+	}
+
+	// A known vector: the hash is the SHA-256 of the encoded secret
+	const want = "\x2c\xf2\x4d\xba\x5f\xb0\xa3\x0e\x26\xe8\x3b\x2a\xc5\xb9\xe2\x9e\x1b\x16\x1e\x5c\x1f\xa7\x42\x5e\x73\x04\x33\x62\x93\x8b\x98\x24"
+	if got := HashAPIKeySecret("hello"); string(got) != want {
+		t.Fatalf("HashAPIKeySecret(hello) = %x", got)
+	}
+
+	_, other, _, err := NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == key {
+		t.Fatal("NewAPIKey returned the same key twice")
+	}
+}
+
+func TestAPIKeyActive(t *testing.T) {
+	now := time.Now()
+	past, future := now.Add(-time.Minute), now.Add(time.Minute)
+	tests := []struct {
+		name   string
+		key    store.APIKey
+		active bool
+	}{
+		{"no expiry", store.APIKey{}, true},
+		{"expires later", store.APIKey{ExpiresAt: &future}, true},
+		{"expired", store.APIKey{ExpiresAt: &past}, false},
+		{"expires now", store.APIKey{ExpiresAt: &now}, false},
+		{"revoked", store.APIKey{RevokedAt: &past}, false},
+		{"revoked before expiry", store.APIKey{ExpiresAt: &future, RevokedAt: &past}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.key.Active(now); got != tt.active {
+			t.Errorf("%s: Active = %v, want %v", tt.name, got, tt.active)
+		}
+	}
+}
+
+func TestAuthenticateKey(t *testing.T) {
+	st := testutil.Store(t)
+	ctx := context.Background()
+	a := NewAuthenticator(Config{APIKeys: APIKeyConfig{Enabled: true}}, false, st)
+
+	create := func(expiresAt *time.Time) (string, string) {
+		t.Helper()
+		id, key, secretHash, err := NewAPIKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := st.CreateAPIKey(ctx, store.APIKey{
+			ID: id, Client: "ra-" + id, SecretHash: secretHash,
+			Roles: []string{RoleRevoker}, Methods: []string{"PublishCRL"}, CreatedBy: "test", ExpiresAt: expiresAt,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		return id, key
+	}
+
+	id, key := create(nil)
+	ctx2, err := a.authenticate(withAPIKey(key), crl.CRLService_AddRevocation_FullMethodName)
+	if err != nil {
+		t.Fatalf("valid key: %v", err)
+	}
+	got, ok := FromContext(ctx2)
+	if !ok || got.Subject != "ra-"+id || got.Method != MethodAPIKey ||
+		len(got.Roles) != 1 || got.Roles[0] != RoleRevoker || len(got.RPCs) != 1 || got.RPCs[0] != "PublishCRL" {
+		t.Fatalf("identity %+v", got)
+	}
+
+	_, revoked := create(nil)
+	revokedID, _, _ := ParseAPIKey(revoked)
+	if _, err := st.RevokeAPIKey(ctx, revokedID); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Minute)
+	_, expired := create(&past)
+
+	unknownID, unknown, _, err := NewAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherSecret, _ := ParseAPIKey(unknown)
+
+	for name, k := range map[string]string{
+		"revoked":      revoked,
+		"expired":      expired,
+		"unknown":      apiKeyPrefix + unknownID + "." + otherSecret,
+		"wrong secret": apiKeyPrefix + id + "." + otherSecret,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := a.authenticate(withAPIKey(k), crl.CRLService_GetCRL_FullMethodName)
+			if status.Code(err) != codes.Unauthenticated {
+				t.Fatalf("got %v, want Unauthenticated", err)
+			}
+		})
+	}
+}
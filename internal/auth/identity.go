@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+)
+
+// Authentication methods of an Identity
+const (
+	MethodMTLS   = "mtls"
+	MethodJWT    = "jwt"
+	MethodAPIKey = "api_key"
+)
+
+// Identity is an authenticated caller
+type Identity struct {
+	// Subject names the caller: the client certificate subject, token
+	// subject or API key client
+	Subject string
+
+	// Method is how the identity was established
+	Method string
+
+	// Roles are the built-in or custom roles of the caller
+	Roles []string
+
+	// RPCs are granted to the caller directly, in addition to its roles
+	RPCs []string
+}
+
+type identityKey struct{}
+
+// NewContext returns a context carrying id
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext returns the identity authenticated for a request. Without
+// one set by an authenticator it falls back to a verified client
+// certificate of the connection.
+func FromContext(ctx context.Context) (Identity, bool) {
+	if id, ok := ctx.Value(identityKey{}).(Identity); ok {
+		return id, true
+	}
+	return PeerIdentity(ctx)
+}
+
+// PeerIdentity returns the subject of the verified client certificate of a
+// gRPC connection
+func PeerIdentity(ctx context.Context) (Identity, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return Identity{}, false
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return Identity{}, false
+	}
+	return Identity{
+		Subject: info.State.VerifiedChains[0][0].Subject.String(),
+		Method:  MethodMTLS,
+	}, true
+}
+
+// SourceIP returns the IP address of the caller of a gRPC request
+func SourceIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
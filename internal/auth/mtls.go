@@ -0,0 +1,71 @@
+package auth
+
+import "fmt"
+
+// allMethods grants a client every RPC
+const allMethods = "*"
+
+// MTLSConfig maps verified client certificates to roles and RPCs. The
+// certificates are verified against security.ca_cert_path when
+// security.mtls_enabled is set.
+type MTLSConfig struct {
+	// Clients are the allowed client subjects; when empty every verified
+	// client is an admin
+	Clients []ClientRule `yaml:"clients"`
+}
+
+// ClientRule grants a client certificate subject roles and RPCs
+type ClientRule struct {
+	// Subject is the certificate subject DN in RFC 2253 form, e.g.
+	// "CN=ra,O=GigVault"
+	Subject string `yaml:"subject"`
+
+	// Roles are built-in or custom role names
+	Roles []string `yaml:"roles"`
+
+	// Methods are RPC names such as AddRevocation, full names such as
+	// /grpc.health.v1.Health/Check for other services, or "*" for all,
+	// granted in addition to the roles
+	Methods []string `yaml:"methods"`
+}
+
+// validate checks the client rules against the custom roles
+func (c *MTLSConfig) validate(roles map[string][]string) error {
+	for i, rule := range c.Clients {
+		if rule.Subject == "" {
+			return fmt.Errorf("mtls.clients[%d]: subject is required", i)
+		}
+		if len(rule.Roles) == 0 && len(rule.Methods) == 0 {
+			return fmt.Errorf("mtls.clients[%d]: roles or methods are required", i)
+		}
+		if err := ValidateRoles(rule.Roles, roles); err != nil {
+			return fmt.Errorf("mtls.clients[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// identity returns the certificate identity with the grants of its rule
+func (c *MTLSConfig) identity(id Identity) Identity {
+	if len(c.Clients) == 0 {
+		id.Roles = []string{RoleAdmin}
+		return id
+	}
+	for _, rule := range c.Clients {
+		if rule.Subject == id.Subject {
+			id.Roles = append(id.Roles, rule.Roles...)
+			id.RPCs = append(id.RPCs, rule.Methods...)
+		}
+	}
+	return id
+}
+
+// methodAllowed reports whether methods grants fullMethod
+func methodAllowed(methods []string, fullMethod string) bool {
+	for _, m := range methods {
+		if m == allMethods || FullMethod(m) == fullMethod {
+			return true
+		}
+	}
+	return false
+}
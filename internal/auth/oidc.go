@@ -0,0 +1,282 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gigvault/shared/pkg/logger"
+	"github.com/go-jose/go-jose/v4"
+	"github.com/go-jose/go-jose/v4/jwt"
+	"go.uber.org/zap"
+)
+
+// OIDC defaults
+const (
+	defaultJWKSRefresh  = time.Hour
+	defaultSubjectClaim = "sub"
+	defaultRolesClaim   = "roles"
+)
+
+// minJWKSRefetch bounds how often a token with an unknown key ID triggers a
+// JWKS fetch
+const minJWKSRefetch = time.Minute
+
+// jwksTimeout bounds discovery and JWKS requests
+const jwksTimeout = 10 * time.Second
+
+// signatureAlgorithms are the accepted token signature algorithms; HMAC and
+// none are never accepted
+var signatureAlgorithms = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.EdDSA,
+}
+
+// OIDCConfig configures validation of bearer tokens issued by an OpenID
+// Connect provider
+type OIDCConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Issuer is the expected iss claim and the base of discovery
+	Issuer string `yaml:"issuer"`
+
+	// Audience must be among the aud claim
+	Audience string `yaml:"audience"`
+
+	// JWKSURL overrides the jwks_uri of the discovery document
+	JWKSURL string `yaml:"jwks_url"`
+
+	// RefreshInterval is how often the JWKS is refetched (default 1h)
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+
+	// SubjectClaim names the caller's identity (default sub)
+	SubjectClaim string `yaml:"subject_claim"`
+
+	// RolesClaim holds the caller's roles as a string or list of strings;
+	// nested claims use dots, e.g. realm_access.roles (default roles)
+	RolesClaim string `yaml:"roles_claim"`
+
+	// RoleMapping maps values of RolesClaim, such as SSO group names, to
+	// roles; without it the values are role names
+	RoleMapping map[string]string `yaml:"role_mapping"`
+}
+
+// Validate applies defaults and checks the settings
+func (c *OIDCConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Issuer == "" {
+		return errors.New("issuer is required")
+	}
+	if c.Audience == "" {
+		return errors.New("audience is required")
+	}
+	if c.RefreshInterval < 0 {
+		return errors.New("refresh_interval must not be negative")
+	}
+	if c.RefreshInterval == 0 {
+		c.RefreshInterval = defaultJWKSRefresh
+	}
+	if c.SubjectClaim == "" {
+		c.SubjectClaim = defaultSubjectClaim
+	}
+	if c.RolesClaim == "" {
+		c.RolesClaim = defaultRolesClaim
+	}
+	return nil
+}
+
+// tokenVerifier validates bearer tokens against the provider's JWKS
+type tokenVerifier struct {
+	cfg    OIDCConfig
+	client *http.Client
+	logger *logger.Logger
+
+	mu        sync.RWMutex
+	jwksURL   string
+	keys      jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+func newTokenVerifier(cfg OIDCConfig) *tokenVerifier {
+	return &tokenVerifier{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: jwksTimeout},
+		logger:  logger.Global(),
+		jwksURL: cfg.JWKSURL,
+	}
+}
+
+// run refetches the JWKS every refresh interval until ctx is done
+func (v *tokenVerifier) run(ctx context.Context) {
+	ticker := time.NewTicker(v.cfg.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := v.refresh(ctx); err != nil {
+			v.logger.Error("Failed to refresh OIDC JWKS", zap.String("issuer", v.cfg.Issuer), zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh fetches the JWKS, discovering its URL first if needed
+func (v *tokenVerifier) refresh(ctx context.Context) error {
+	v.mu.RLock()
+	url := v.jwksURL
+	v.mu.RUnlock()
+
+	if url == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, strings.TrimSuffix(v.cfg.Issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return fmt.Errorf("failed to discover OIDC provider: %w", err)
+		}
+		if discovery.Issuer != v.cfg.Issuer {
+			return fmt.Errorf("discovery document names issuer %q", discovery.Issuer)
+		}
+		if discovery.JWKSURI == "" {
+			return errors.New("discovery document has no jwks_uri")
+		}
+		url = discovery.JWKSURI
+	}
+
+	var keys jose.JSONWebKeySet
+	if err := v.getJSON(ctx, url, &keys); err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+
+	v.mu.Lock()
+	v.jwksURL = url
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	v.mu.Unlock()
+
+	v.logger.Debug("OIDC JWKS refreshed", zap.String("url", url), zap.Int("keys", len(keys.Keys)))
+	return nil
+}
+
+func (v *tokenVerifier) getJSON(ctx context.Context, url string, dst interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// key returns the signing key kid, refetching the JWKS for unknown IDs so
+// keys rotated in at the provider are picked up
+func (v *tokenVerifier) key(ctx context.Context, kid string) (*jose.JSONWebKey, error) {
+	v.mu.RLock()
+	keys, fetchedAt := v.keys.Key(kid), v.fetchedAt
+	v.mu.RUnlock()
+
+	if len(keys) == 0 && time.Since(fetchedAt) >= minJWKSRefetch {
+		if err := v.refresh(ctx); err != nil {
+			return nil, err
+		}
+		v.mu.RLock()
+		keys = v.keys.Key(kid)
+		v.mu.RUnlock()
+	}
+	for i := range keys {
+		if keys[i].Use == "" || keys[i].Use == "sig" {
+			return &keys[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// verify validates a bearer token and returns the caller's identity
+func (v *tokenVerifier) verify(ctx context.Context, token string) (Identity, error) {
+	parsed, err := jwt.ParseSigned(token, signatureAlgorithms)
+	if err != nil {
+		return Identity{}, fmt.Errorf("malformed token: %w", err)
+	}
+	if len(parsed.Headers) != 1 {
+		return Identity{}, errors.New("token must have one signature")
+	}
+	key, err := v.key(ctx, parsed.Headers[0].KeyID)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	var claims jwt.Claims
+	var raw map[string]interface{}
+	if err := parsed.Claims(key.Key, &claims, &raw); err != nil {
+		return Identity{}, fmt.Errorf("invalid token signature: %w", err)
+	}
+	if claims.Expiry == nil {
+		return Identity{}, errors.New("token has no expiry")
+	}
+	err = claims.Validate(jwt.Expected{
+		Issuer:      v.cfg.Issuer,
+		AnyAudience: jwt.Audience{v.cfg.Audience},
+		Time:        time.Now(),
+	})
+	if err != nil {
+		return Identity{}, err
+	}
+
+	subjects := claimStrings(raw, v.cfg.SubjectClaim)
+	if len(subjects) != 1 || subjects[0] == "" {
+		return Identity{}, fmt.Errorf("token has no %s claim", v.cfg.SubjectClaim)
+	}
+
+	var roles []string
+	for _, value := range claimStrings(raw, v.cfg.RolesClaim) {
+		if len(v.cfg.RoleMapping) == 0 {
+			roles = append(roles, value)
+		} else if role, ok := v.cfg.RoleMapping[value]; ok {
+			roles = append(roles, role)
+		}
+	}
+	return Identity{Subject: subjects[0], Method: MethodJWT, Roles: roles}, nil
+}
+
+// claimStrings returns the string or strings of a claim at a dotted path
+func claimStrings(claims map[string]interface{}, path string) []string {
+	var value interface{} = claims
+	for _, name := range strings.Split(path, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[name]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var out []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
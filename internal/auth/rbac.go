@@ -0,0 +1,178 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	reflectionv1alphapb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+)
+
+// Built-in roles
+const (
+	// RoleReader reads CRLs and revocation status
+	RoleReader = "reader"
+
+	// RoleRevoker adds, holds, releases and approves revocations
+	RoleRevoker = "revoker"
+
+	// RolePublisher publishes CRLs on demand
+	RolePublisher = "publisher"
+
+	// RoleAdmin may call every RPC, including deletion and the audit log
+	RoleAdmin = "admin"
+)
+
+// readMethods are the RPCs that change nothing
+var readMethods = []string{
+	crl.CRLService_GetCRL_FullMethodName,
+	crl.CRLService_GetCRLStream_FullMethodName,
+	crl.CRLService_CheckRevocationStatus_FullMethodName,
+	crl.CRLService_CheckStatusBatch_FullMethodName,
+	crl.CRLService_WatchRevocations_FullMethodName,
+	crl.CRLService_ListRevocations_FullMethodName,
+	crl.CRLService_GetRevocation_FullMethodName,
+	crl.CRLService_ExportRevocations_FullMethodName,
+	crl.CRLService_ListPendingRevocations_FullMethodName,
+	// gRPC reflection, with diagnostics.grpc_reflection
+	reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName,
+	reflectionv1alphapb.ServerReflection_ServerReflectionInfo_FullMethodName,
+}
+
+// builtinRoles are the RPCs granted by each built-in role. Any RPC not
+// listed, including ones added later, needs admin.
+var builtinRoles = map[string][]string{
+	RoleReader: readMethods,
+	RoleRevoker: append([]string{
+		crl.CRLService_AddRevocation_FullMethodName,
+		crl.CRLService_AddRevocations_FullMethodName,
+		crl.CRLService_HoldCertificate_FullMethodName,
+		crl.CRLService_ReleaseHold_FullMethodName,
+		crl.CRLService_ApproveRevocation_FullMethodName,
+		crl.CRLService_RejectRevocation_FullMethodName,
+	}, readMethods...),
+	RolePublisher: append([]string{crl.CRLService_PublishCRL_FullMethodName}, readMethods...),
+	RoleAdmin:     {allMethods},
+}
+
+// FullMethod returns the full /package.Service/Method name of a granted
+// RPC. Bare names such as AddRevocation are CRL service methods, so a grant
+// never matches a same-named RPC of another service.
+func FullMethod(name string) string {
+	if name == allMethods || strings.HasPrefix(name, "/") {
+		return name
+	}
+	return "/" + crl.CRLService_ServiceDesc.ServiceName + "/" + name
+}
+
+// ValidateRoles checks that names are built-in or custom roles
+func ValidateRoles(names []string, custom map[string][]string) error {
+	for _, name := range names {
+		if _, ok := builtinRoles[name]; ok {
+			continue
+		}
+		if _, ok := custom[name]; !ok {
+			return fmt.Errorf("unknown role %q", name)
+		}
+	}
+	return nil
+}
+
+// grantableMethods are the full names of the RPCs that can be granted
+var grantableMethods = func() map[string]bool {
+	methods := make(map[string]bool)
+	for _, desc := range []grpc.ServiceDesc{
+		crl.CRLService_ServiceDesc,
+		reflectionpb.ServerReflection_ServiceDesc,
+		reflectionv1alphapb.ServerReflection_ServiceDesc,
+	} {
+		for _, m := range desc.Methods {
+			methods["/"+desc.ServiceName+"/"+m.MethodName] = true
+		}
+		for _, st := range desc.Streams {
+			methods["/"+desc.ServiceName+"/"+st.StreamName] = true
+		}
+	}
+	return methods
+}()
+
+// ValidateMethods checks that methods are "*" or RPCs of the CRL or
+// reflection services
+func ValidateMethods(methods []string) error {
+	for _, m := range methods {
+		if m != allMethods && !grantableMethods[FullMethod(m)] {
+			return fmt.Errorf("unknown method %q", m)
+		}
+	}
+	return nil
+}
+
+// Authorizer enforces the roles and RPC grants of authenticated identities
+type Authorizer struct {
+	roles map[string][]string
+}
+
+// NewAuthorizer creates an authorizer for the built-in roles and the
+// custom roles of cfg
+func NewAuthorizer(cfg Config) *Authorizer {
+	roles := make(map[string][]string, len(builtinRoles)+len(cfg.Roles))
+	for name, methods := range cfg.Roles {
+		roles[name] = methods
+	}
+	for name, methods := range builtinRoles {
+		roles[name] = methods
+	}
+	return &Authorizer{roles: roles}
+}
+
+// allowed reports whether id may call fullMethod
+func (a *Authorizer) allowed(id Identity, fullMethod string) bool {
+	if methodAllowed(id.RPCs, fullMethod) {
+		return true
+	}
+	for _, role := range id.Roles {
+		if methodAllowed(a.roles[role], fullMethod) {
+			return true
+		}
+	}
+	return false
+}
+
+// authorize checks the identity set by the Authenticator. Calls without
+// one, to exempt methods or with authentication disabled, are not
+// restricted.
+func (a *Authorizer) authorize(ctx context.Context, fullMethod string) error {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	if !ok {
+		return nil
+	}
+	if !a.allowed(id, fullMethod) {
+		return status.Errorf(codes.PermissionDenied, "%q is not allowed to call %s", id.Subject, fullMethod)
+	}
+	return nil
+}
+
+// UnaryInterceptor authorizes unary calls
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor authorizes streaming calls
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
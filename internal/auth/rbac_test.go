@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorize(t *testing.T) {
+	a := NewAuthorizer(Config{Roles: map[string][]string{
+		"auditor": {"QueryAuditLog", "GetRevocation"},
+		"prober":  {healthpb.Health_Check_FullMethodName},
+	}})
+
+	tests := []struct {
+		name   string
+		id     Identity
+		method string
+		allow  bool
+	}{
+		{"reader reads", Identity{Roles: []string{RoleReader}}, crl.CRLService_GetCRL_FullMethodName, true},
+		{"reader reflects", Identity{Roles: []string{RoleReader}}, reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName, true},
+		{"reader revokes", Identity{Roles: []string{RoleReader}}, crl.CRLService_AddRevocation_FullMethodName, false},
+		{"revoker revokes", Identity{Roles: []string{RoleRevoker}}, crl.CRLService_AddRevocation_FullMethodName, true},
+		{"revoker approves", Identity{Roles: []string{RoleRevoker}}, crl.CRLService_ApproveRevocation_FullMethodName, true},
+		{"revoker reads", Identity{Roles: []string{RoleRevoker}}, crl.CRLService_CheckRevocationStatus_FullMethodName, true},
+		{"revoker publishes", Identity{Roles: []string{RoleRevoker}}, crl.CRLService_PublishCRL_FullMethodName, false},
+		{"revoker deletes", Identity{Roles: []string{RoleRevoker}}, crl.CRLService_DeleteRevocation_FullMethodName, false},
+		{"publisher publishes", Identity{Roles: []string{RolePublisher}}, crl.CRLService_PublishCRL_FullMethodName, true},
+		{"publisher revokes", Identity{Roles: []string{RolePublisher}}, crl.CRLService_AddRevocation_FullMethodName, false},
+		{"admin", Identity{Roles: []string{RoleAdmin}}, crl.CRLService_QueryAuditLog_FullMethodName, true},
+		{"admin of another service", Identity{Roles: []string{RoleAdmin}}, healthpb.Health_Watch_FullMethodName, true},
+		{"custom role", Identity{Roles: []string{"auditor"}}, crl.CRLService_QueryAuditLog_FullMethodName, true},
+		{"custom role outside its grants", Identity{Roles: []string{"auditor"}}, crl.CRLService_GetCRL_FullMethodName, false},
+		{"custom role of another service", Identity{Roles: []string{"prober"}}, healthpb.Health_Check_FullMethodName, true},
+		{"unknown role", Identity{Roles: []string{"superuser"}}, crl.CRLService_GetCRL_FullMethodName, false},
+		{"no grants", Identity{}, crl.CRLService_GetCRL_FullMethodName, false},
+		{"bare grant", Identity{RPCs: []string{"AddRevocation"}}, crl.CRLService_AddRevocation_FullMethodName, true},
+		{"full grant", Identity{RPCs: []string{crl.CRLService_AddRevocation_FullMethodName}}, crl.CRLService_AddRevocation_FullMethodName, true},
+		{"bare grant of another service", Identity{RPCs: []string{"Check"}}, healthpb.Health_Check_FullMethodName, false},
+		{"bare grant on another service", Identity{RPCs: []string{"GetCRL"}}, "/evil.v1.Shadow/GetCRL", false},
+		{"grant of every RPC", Identity{RPCs: []string{"*"}}, crl.CRLService_DeleteRevocation_FullMethodName, true},
+		{"grants added to roles", Identity{Roles: []string{RoleReader}, RPCs: []string{"PublishCRL"}}, crl.CRLService_PublishCRL_FullMethodName, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.id.Subject = "CN=client"
+			err := a.authorize(NewContext(context.Background(), tt.id), tt.method)
+			if tt.allow && err != nil {
+				t.Fatalf("denied: %v", err)
+			}
+			if !tt.allow && status.Code(err) != codes.PermissionDenied {
+				t.Fatalf("got %v, want PermissionDenied", err)
+			}
+		})
+	}
+
+	// Calls without an identity were exempted by the authenticator
+	if err := a.authorize(context.Background(), crl.CRLService_DeleteRevocation_FullMethodName); err != nil {
+		t.Fatalf("call without identity: %v", err)
+	}
+}
+
+func TestFullMethod(t *testing.T) {
+	tests := map[string]string{
+		"GetCRL":                             crl.CRLService_GetCRL_FullMethodName,
+		crl.CRLService_GetCRL_FullMethodName: crl.CRLService_GetCRL_FullMethodName,
+		healthpb.Health_Check_FullMethodName: healthpb.Health_Check_FullMethodName,
+		"*":                                  "*",
+	}
+	for name, want := range tests {
+		if got := FullMethod(name); got != want {
+			t.Errorf("FullMethod(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestValidateGrants(t *testing.T) {
+	custom := map[string][]string{"auditor": {"QueryAuditLog"}}
+	for _, roles := range [][]string{nil, {RoleReader, RoleAdmin}, {"auditor"}} {
+		if err := ValidateRoles(roles, custom); err != nil {
+			t.Errorf("ValidateRoles(%q): %v", roles, err)
+		}
+	}
+	for _, roles := range [][]string{{"superuser"}, {RoleReader, "Auditor"}, {""}} {
+		if err := ValidateRoles(roles, custom); err == nil {
+			t.Errorf("ValidateRoles(%q) accepted an unknown role", roles)
+		}
+	}
+
+	for _, methods := range [][]string{
+		nil,
+		{"*"},
+		{"AddRevocation", "WatchRevocations"},
+		{crl.CRLService_GetCRL_FullMethodName},
+		{reflectionpb.ServerReflection_ServerReflectionInfo_FullMethodName},
+	} {
+		if err := ValidateMethods(methods); err != nil {
+			t.Errorf("ValidateMethods(%q): %v", methods, err)
+		}
+	}
+	for _, methods := range [][]string{
+		{"RevokeEverything"},
+		{"addRevocation"},
+		{"Check"},
+		{healthpb.Health_Check_FullMethodName},
+		{"/gigvault.crl.v1.CRLService/"},
+	} {
+		if err := ValidateMethods(methods); err == nil {
+			t.Errorf("ValidateMethods(%q) accepted an unknown method", methods)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package bloom
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+// Filter is a Bloom filter: Test never misses an added key and reports a
+// key that was not added with the false positive rate it was sized for.
+// It is not safe for concurrent use.
+type Filter struct {
+	bits     []uint64
+	m        uint64 // number of bits
+	k        uint64 // number of hash functions
+	capacity int
+	count    int
+}
+
+// NewFilter sizes a filter for capacity keys at a false positive rate
+func NewFilter(capacity int, falsePositiveRate float64) *Filter {
+	if capacity < 1 {
+		capacity = 1
+	}
+	n := float64(capacity)
+	m := math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2))
+	k := math.Max(1, math.Round(m/n*math.Ln2))
+	words := (uint64(m) + 63) / 64
+	return &Filter{
+		bits:     make([]uint64, words),
+		m:        words * 64,
+		k:        uint64(k),
+		capacity: capacity,
+	}
+}
+
+// hashes derives the two hashes combined into the k bit positions of key
+// from its SHA-256 digest. FNV mixes the last octets of short keys too
+// weakly into its high bits, so keys differing only there collided.
+func hashes(key []byte) (uint64, uint64) {
+	sum := sha256.Sum256(key)
+	return binary.BigEndian.Uint64(sum[:8]), binary.BigEndian.Uint64(sum[8:16]) | 1
+}
+
+// Add adds key to the filter
+func (f *Filter) Add(key []byte) {
+	h1, h2 := hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+	f.count++
+}
+
+// Test reports whether key may have been added
+func (f *Filter) Test(key []byte) bool {
+	h1, h2 := hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary encodes the filter as the number of hash functions (one
+// octet), the number of bits (eight octets, big-endian) and the bits as
+// little-endian 64-bit words, bit i being bit i%64 of word i/64
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	out := make([]byte, 9, 9+8*len(f.bits))
+	out[0] = byte(f.k)
+	binary.BigEndian.PutUint64(out[1:], f.m)
+	for _, w := range f.bits {
+		out = binary.LittleEndian.AppendUint64(out, w)
+	}
+	return out, nil
+}
+
+// Full reports whether more keys were added than the filter was sized
+// for, so its false positive rate is above the target
+func (f *Filter) Full() bool {
+	return f.count > f.capacity
+}
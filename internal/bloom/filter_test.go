@@ -0,0 +1,107 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+func testKeys(prefix string, n int) [][]byte {
+	keys := make([][]byte, n)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("%s\x00%x", prefix, i))
+	}
+	return keys
+}
+
+func TestFilterHasNoFalseNegatives(t *testing.T) {
+	for _, tt := range []struct {
+		capacity int
+		rate     float64
+	}{
+		{0, 0.01},
+		{1, 0.001},
+		{100, 0.1},
+		{10000, 0.001},
+		{50000, 0.0001},
+	} {
+		t.Run(fmt.Sprintf("%d/%g", tt.capacity, tt.rate), func(t *testing.T) {
+			f := NewFilter(tt.capacity, tt.rate)
+			keys := testKeys("issuer", max(tt.capacity, 1))
+			for _, k := range keys {
+				f.Add(k)
+			}
+			for _, k := range keys {
+				if !f.Test(k) {
+					t.Fatalf("added key %q tests false", k)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterFalsePositiveRate(t *testing.T) {
+	const capacity, probes = 20000, 200000
+	for _, rate := range []float64{0.05, 0.01, 0.001} {
+		t.Run(fmt.Sprint(rate), func(t *testing.T) {
+			f := NewFilter(capacity, rate)
+			for _, k := range testKeys("added", capacity) {
+				f.Add(k)
+			}
+			positives := 0
+			for _, k := range testKeys("absent", probes) {
+				if f.Test(k) {
+					positives++
+				}
+			}
+			got := float64(positives) / probes
+			// The keys are fixed, so the rate is too; the bounds allow for
+			// the sampling error at the lowest rate
+			if got > 1.5*rate || got < rate/3 {
+				t.Fatalf("false positive rate %g, configured %g", got, rate)
+			}
+		})
+	}
+}
+
+func TestFilterFull(t *testing.T) {
+	f := NewFilter(10, 0.01)
+	keys := testKeys("k", 11)
+	for _, k := range keys[:10] {
+		f.Add(k)
+	}
+	if f.Full() {
+		t.Fatal("full at capacity")
+	}
+	f.Add(keys[10])
+	if !f.Full() {
+		t.Fatal("not full above capacity")
+	}
+}
+
+func TestFilterMarshalBinary(t *testing.T) {
+	f := NewFilter(1000, 0.01)
+	for _, k := range testKeys("added", 1000) {
+		f.Add(k)
+	}
+	data, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 9+len(f.bits)*8 {
+		t.Fatalf("encoding of %d octets, want %d", len(data), 9+len(f.bits)*8)
+	}
+	if uint64(data[0]) != f.k {
+		t.Fatalf("encoded %d hash functions, want %d", data[0], f.k)
+	}
+	if m := binary.BigEndian.Uint64(data[1:9]); m != f.m {
+		t.Fatalf("encoded %d bits, want %d", m, f.m)
+	}
+	// Bit i is bit i%64 of little-endian word i/64
+	for bit := uint64(0); bit < f.m; bit++ {
+		set := data[9+bit/8]&(1<<(bit%8)) != 0
+		if want := f.bits[bit/64]&(1<<(bit%64)) != 0; set != want {
+			t.Fatalf("bit %d encoded as %t, want %t", bit, set, want)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package bloom
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultFalsePositiveRate = 0.001
+	defaultPollInterval      = time.Second
+	defaultRebuildInterval   = time.Hour
+
+	// minCapacity sizes the filter of a small revocation set
+	minCapacity = 1024
+
+	// eventBatch bounds the events read per query
+	eventBatch = 1000
+
+	// eventGap is how long events are read again: a transaction may commit
+	// an event after others with higher sequences
+	eventGap = 10 * time.Second
+)
+
+// Config configures the Bloom filter of serials that have an entry
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+
+	// FalsePositiveRate is the share of unrevoked serials still looked up
+	// in the store (default 0.001)
+	FalsePositiveRate float64 `yaml:"false_positive_rate"`
+
+	// PollInterval is how often entries added by other replicas are read
+	// from the revocation events (default 1s)
+	PollInterval time.Duration `yaml:"poll_interval"`
+
+	// RebuildInterval rebuilds the filter from crl_entries, dropping
+	// serials whose entry was archived or deleted (default 1h)
+	RebuildInterval time.Duration `yaml:"rebuild_interval"`
+}
+
+// Validate applies defaults and checks the Bloom filter config
+func (c *Config) Validate() error {
+	if c.FalsePositiveRate < 0 || c.FalsePositiveRate >= 1 {
+		return errors.New("false_positive_rate must be between 0 and 1")
+	}
+	if c.PollInterval < 0 || c.RebuildInterval < 0 {
+		return errors.New("poll_interval and rebuild_interval must not be negative")
+	}
+	if c.FalsePositiveRate == 0 {
+		c.FalsePositiveRate = defaultFalsePositiveRate
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.RebuildInterval == 0 {
+		c.RebuildInterval = defaultRebuildInterval
+	}
+	return nil
+}
+
+// Index answers "does this serial have an entry" from memory. Serials
+// without one are certainly not revoked; the others, and every serial
+// while the index is not current, must be looked up in the store. A nil
+// Index answers that every serial may have an entry.
+type Index struct {
+	store   *store.Store
+	issuers *generator.Registry
+	cfg     Config
+	logger  *logger.Logger
+
+	mu       sync.RWMutex
+	filter   *Filter // nil until built
+	polledAt time.Time
+	floor    int64 // events up to this sequence are in the filter
+}
+
+// NewIndex creates an index of the entries of issuers. It is empty until
+// Run builds it.
+func NewIndex(st *store.Store, issuers *generator.Registry, cfg Config) *Index {
+	return &Index{
+		store:   st,
+		issuers: issuers,
+		cfg:     cfg,
+		logger:  logger.Global(),
+	}
+}
+
+func key(issuerID, serial string) []byte {
+	return []byte(issuerID + "\x00" + serial)
+}
+
+// MayContain reports whether a canonical serial may have an entry of the
+// issuer. It is true whenever the index has not caught up recently.
+func (x *Index) MayContain(issuerID, serial string) bool {
+	if x == nil {
+		return true
+	}
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	// Entries of other replicas may be missing while polling fails
+	if x.filter == nil || time.Since(x.polledAt) > 10*x.cfg.PollInterval {
+		return true
+	}
+	if x.filter.Test(key(issuerID, serial)) {
+		metrics.StatusFilterLookups.WithLabelValues("maybe").Inc()
+		return true
+	}
+	metrics.StatusFilterLookups.WithLabelValues("negative").Inc()
+	return false
+}
+
+// Add records a serial whose entry this replica just wrote
+func (x *Index) Add(issuerID, serial string) {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	if x.filter != nil {
+		x.filter.Add(key(issuerID, serial))
+	}
+}
+
+// Run builds the index, then keeps it current until ctx is done
+func (x *Index) Run(ctx context.Context) {
+	poll := time.NewTicker(x.cfg.PollInterval)
+	defer poll.Stop()
+
+	var builtAt time.Time
+	for {
+		switch {
+		case builtAt.IsZero() || time.Since(builtAt) >= x.cfg.RebuildInterval || x.full():
+			if err := x.rebuild(ctx); err != nil {
+				x.logger.Error("Failed to build revoked serial filter", zap.Error(err))
+			} else {
+				builtAt = time.Now()
+			}
+		default:
+			if err := x.poll(ctx); err != nil {
+				x.logger.Warn("Failed to read revocation events for serial filter", zap.Error(err))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+		}
+	}
+}
+
+func (x *Index) full() bool {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return x.filter != nil && x.filter.Full()
+}
+
+// rebuild fills a new filter from crl_entries, then applies the events
+// committed meanwhile
+func (x *Index) rebuild(ctx context.Context) error {
+	floor, err := x.store.EventSequenceBefore(ctx, time.Now().Add(-eventGap))
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+	for _, gen := range x.issuers.All() {
+		serials, err := x.store.ListSerials(ctx, gen.ID())
+		if err != nil {
+			return err
+		}
+		for _, serial := range serials {
+			keys = append(keys, key(gen.ID(), serial))
+		}
+	}
+	filter := NewFilter(max(2*len(keys), minCapacity), x.cfg.FalsePositiveRate)
+	for _, k := range keys {
+		filter.Add(k)
+	}
+
+	x.mu.Lock()
+	// Serials added meanwhile are in the events read below
+	x.filter = filter
+	x.floor = floor
+	x.polledAt = time.Time{}
+	x.mu.Unlock()
+
+	x.logger.Info("Built revoked serial filter", zap.Int("serials", len(keys)))
+	return x.poll(ctx)
+}
+
+// poll adds the serials of the events after the floor. Recent events are
+// read again on every poll, so that ones committed out of sequence order
+// are not skipped.
+func (x *Index) poll(ctx context.Context) error {
+	x.mu.RLock()
+	after := x.floor
+	x.mu.RUnlock()
+
+	floor := after
+	advancing := true
+	for {
+		events, err := x.store.ListEvents(ctx, after, eventBatch)
+		if err != nil {
+			return err
+		}
+		settled := time.Now().Add(-eventGap)
+
+		x.mu.Lock()
+		for _, e := range events {
+			x.filter.Add(key(e.IssuerID, e.Serial))
+			if advancing && e.CreatedAt.Before(settled) {
+				floor = e.Sequence
+			} else {
+				advancing = false
+			}
+		}
+		x.mu.Unlock()
+
+		if len(events) < eventBatch {
+			break
+		}
+		after = events[len(events)-1].Sequence
+	}
+
+	x.mu.Lock()
+	x.floor = floor
+	x.polledAt = time.Now()
+	x.mu.Unlock()
+	return nil
+}
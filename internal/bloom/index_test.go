@@ -0,0 +1,57 @@
+package bloom
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIndexMayContain(t *testing.T) {
+	built := func(polledAt time.Time) *Index {
+		f := NewFilter(minCapacity, 0.001)
+		f.Add(key("root", "1a"))
+		return &Index{cfg: Config{PollInterval: time.Second}, filter: f, polledAt: polledAt}
+	}
+
+	tests := []struct {
+		name   string
+		index  *Index
+		serial string
+		want   bool
+	}{
+		{"nil index", nil, "2b", true},
+		{"unbuilt", &Index{cfg: Config{PollInterval: time.Second}}, "2b", true},
+		{"never polled", built(time.Time{}), "2b", true},
+		{"stale", built(time.Now().Add(-11 * time.Second)), "2b", true},
+		{"current, added", built(time.Now()), "1a", true},
+		{"current, absent", built(time.Now()), "2b", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.index.MayContain("root", tt.serial); got != tt.want {
+				t.Fatalf("MayContain = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Keys are per issuer
+	if built(time.Now()).MayContain("other", "1a") {
+		t.Fatal("serial of another issuer may be contained")
+	}
+}
+
+func TestIndexAdd(t *testing.T) {
+	x := &Index{cfg: Config{PollInterval: time.Second}}
+	// Adding before the filter is built is a no-op, and nil is allowed
+	x.Add("root", "1a")
+	(*Index)(nil).Add("root", "1a")
+
+	x.filter = NewFilter(minCapacity, 0.001)
+	x.polledAt = time.Now()
+	if x.MayContain("root", "1a") {
+		t.Fatal("serial added before the build is in the filter")
+	}
+	x.Add("root", "1a")
+	if !x.MayContain("root", "1a") {
+		t.Fatal("added serial missing")
+	}
+}
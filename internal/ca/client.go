@@ -0,0 +1,206 @@
+package ca
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gigvault/crl/internal/serial"
+	capb "github.com/gigvault/shared/api/proto/ca"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// Verification modes of revoked serials
+const (
+	VerifyOff    = "off"
+	VerifyWarn   = "warn"
+	VerifyReject = "reject"
+)
+
+// ErrUnknownSerial is returned for serials the CA service has no
+// certificate for
+var ErrUnknownSerial = errors.New("certificate authority has no certificate with this serial")
+
+// Config configures the client of the gigvault CA service
+type Config struct {
+	// Verify is off (default), warn to log revocations of serials the CA
+	// did not issue and accept them, or reject to refuse them
+	Verify string `yaml:"verify"`
+
+	// Address is the host:port of the CA gRPC service
+	Address string `yaml:"address"`
+
+	// TLS connects with TLS, verifying the server against CAFile or the
+	// system roots; CertFile and KeyFile add a client certificate
+	TLS      bool   `yaml:"tls"`
+	CAFile   string `yaml:"ca_file"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+
+	// Timeout bounds each lookup (default 5s)
+	Timeout time.Duration `yaml:"timeout"`
+
+	// FailOpen accepts revocations when the CA service cannot be reached.
+	// By default reject mode refuses them as Unavailable.
+	FailOpen bool `yaml:"fail_open"`
+}
+
+// Validate applies defaults and checks the CA config
+func (c *Config) Validate() error {
+	switch c.Verify {
+	case "":
+		c.Verify = VerifyOff
+	case VerifyOff, VerifyWarn, VerifyReject:
+	default:
+		return fmt.Errorf("unknown verify mode %q", c.Verify)
+	}
+	if c.Verify != VerifyOff && c.Address == "" {
+		return errors.New("address is required to verify serials")
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return errors.New("cert_file and key_file must be set together")
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultTimeout
+	}
+	return nil
+}
+
+// Enabled reports whether revoked serials are verified with the CA
+func (c Config) Enabled() bool {
+	return c.Verify != "" && c.Verify != VerifyOff
+}
+
+// Client looks up issued certificates in the CA service
+type Client struct {
+	cfg    Config
+	conn   *grpc.ClientConn
+	client capb.CAServiceClient
+}
+
+// NewClient creates a client of the CA service. The connection is made
+// lazily, on the first lookup.
+func NewClient(cfg Config) (*Client, error) {
+	creds := insecure.NewCredentials()
+	if cfg.TLS {
+		tlsConfig, err := clientTLS(cfg)
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	conn, err := grpc.NewClient(cfg.Address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CA client: %w", err)
+	}
+	return &Client{cfg: cfg, conn: conn, client: capb.NewCAServiceClient(conn)}, nil
+}
+
+func clientTLS(cfg Config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pemData, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pemData) {
+			return nil, fmt.Errorf("no certificates in CA file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// Config returns the client's configuration
+func (c *Client) Config() Config {
+	return c.cfg
+}
+
+// Certificate returns the certificate the CA issued with a serial, given
+// in canonical hex, or ErrUnknownSerial
+func (c *Client) Certificate(ctx context.Context, serialHex string) (*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+
+	resp, err := c.client.GetCertificate(ctx, &capb.GetCertificateRequest{SerialNumber: serialHex})
+	if status.Code(err) == codes.NotFound {
+		return nil, ErrUnknownSerial
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up certificate: %w", err)
+	}
+
+	block, _ := pem.Decode([]byte(resp.CertificatePem))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("CA returned no PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate returned by CA: %w", err)
+	}
+	return cert, nil
+}
+
+// listPageSize is the page size of ValidSerials
+const listPageSize = 1000
+
+// ValidSerials returns the serials, in canonical hex, of every certificate
+// the CA service reports as valid: issued, unexpired and not revoked
+func (c *Client) ValidSerials(ctx context.Context) ([]string, error) {
+	var serials []string
+	token := ""
+	for {
+		pageCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+		resp, err := c.client.ListCertificates(pageCtx, &capb.ListCertificatesRequest{
+			Status:    "valid",
+			PageSize:  listPageSize,
+			PageToken: token,
+		})
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to list certificates: %w", err)
+		}
+		for _, cert := range resp.Certificates {
+			s, err := serial.Normalize(cert.SerialNumber)
+			if err != nil {
+				return nil, fmt.Errorf("CA listed an invalid serial %q: %w", cert.SerialNumber, err)
+			}
+			serials = append(serials, s)
+		}
+		if resp.NextPageToken == "" {
+			return serials, nil
+		}
+		token = resp.NextPageToken
+	}
+}
+
+// Close closes the connection to the CA service
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,76 @@
+package cdn
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported CDN types
+const (
+	TypeCloudFront = "cloudfront"
+	TypeFastly     = "fastly"
+	TypeCloudflare = "cloudflare"
+)
+
+// purgeTimeout bounds a single purge request
+const purgeTimeout = 30 * time.Second
+
+// Purger invalidates cached copies of an issuer's CRLs after a publication
+type Purger interface {
+	// Name identifies the CDN in logs
+	Name() string
+
+	Purge(ctx context.Context, issuerID string, partitions []int) error
+}
+
+// Config selects a CDN to purge. URLs may use the {issuer} and {partition}
+// placeholders.
+type Config struct {
+	Name string   `yaml:"name"`
+	Type string   `yaml:"type"` // cloudfront, fastly, cloudflare
+	URLs []string `yaml:"urls"`
+
+	CloudFront CloudFrontConfig `yaml:"cloudfront"`
+	Fastly     FastlyConfig     `yaml:"fastly"`
+	Cloudflare CloudflareConfig `yaml:"cloudflare"`
+}
+
+// New creates the purger selected by cfg.Type
+func New(ctx context.Context, cfg Config) (Purger, error) {
+	if cfg.Name == "" {
+		cfg.Name = cfg.Type
+	}
+	if len(cfg.URLs) == 0 {
+		return nil, fmt.Errorf("%s purger requires urls", cfg.Type)
+	}
+	switch cfg.Type {
+	case TypeCloudFront:
+		return newCloudFrontPurger(ctx, cfg)
+	case TypeFastly:
+		return newFastlyPurger(cfg)
+	case TypeCloudflare:
+		return newCloudflarePurger(cfg)
+	default:
+		return nil, fmt.Errorf("unknown cdn type: %q", cfg.Type)
+	}
+}
+
+// urls expands the configured URLs for the published partitions. URLs
+// without {partition} are returned once.
+func urls(cfg Config, issuerID string, partitions []int) []string {
+	var out []string
+	for _, tmpl := range cfg.URLs {
+		tmpl = strings.ReplaceAll(tmpl, "{issuer}", issuerID)
+		if !strings.Contains(tmpl, "{partition}") {
+			out = append(out, tmpl)
+			continue
+		}
+		for _, p := range partitions {
+			out = append(out, strings.ReplaceAll(tmpl, "{partition}", strconv.Itoa(p)))
+		}
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+const cloudflareAPI = "https://api.cloudflare.com/client/v4"
+
+// CloudflareConfig is a Cloudflare zone. The API token needs the Cache
+// Purge permission and may also be supplied through CLOUDFLARE_API_TOKEN.
+type CloudflareConfig struct {
+	ZoneID   string `yaml:"zone_id"`
+	APIToken string `yaml:"api_token"`
+}
+
+// cloudflarePurger purges files from a Cloudflare zone
+type cloudflarePurger struct {
+	cfg    Config
+	token  string
+	client *http.Client
+}
+
+func newCloudflarePurger(cfg Config) (Purger, error) {
+	if cfg.Cloudflare.ZoneID == "" {
+		return nil, errors.New("cloudflare purger requires zone_id")
+	}
+	token := cfg.Cloudflare.APIToken
+	if token == "" {
+		token = os.Getenv("CLOUDFLARE_API_TOKEN")
+	}
+	if token == "" {
+		return nil, errors.New("cloudflare purger requires api_token or CLOUDFLARE_API_TOKEN")
+	}
+	return &cloudflarePurger{cfg: cfg, token: token, client: &http.Client{Timeout: purgeTimeout}}, nil
+}
+
+func (p *cloudflarePurger) Name() string {
+	return p.cfg.Name
+}
+
+func (p *cloudflarePurger) Purge(ctx context.Context, issuerID string, partitions []int) error {
+	body, err := json.Marshal(map[string][]string{"files": urls(p.cfg, issuerID, partitions)})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/zones/%s/purge_cache", cloudflareAPI, p.cfg.Cloudflare.ZoneID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to purge Cloudflare zone %s: %w", p.cfg.Cloudflare.ZoneID, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Success bool `json:"success"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || !result.Success {
+		msg := resp.Status
+		if len(result.Errors) > 0 {
+			msg = result.Errors[0].Message
+		}
+		return fmt.Errorf("cloudflare purge of zone %s failed: %s", p.cfg.Cloudflare.ZoneID, msg)
+	}
+	return nil
+}
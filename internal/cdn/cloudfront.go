@@ -0,0 +1,71 @@
+package cdn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+)
+
+// CloudFrontConfig is a CloudFront distribution. Credentials come from the
+// default AWS chain.
+type CloudFrontConfig struct {
+	DistributionID string `yaml:"distribution_id"`
+}
+
+// cloudFrontPurger creates CloudFront invalidations
+type cloudFrontPurger struct {
+	cfg    Config
+	client *cloudfront.Client
+}
+
+func newCloudFrontPurger(ctx context.Context, cfg Config) (Purger, error) {
+	if cfg.CloudFront.DistributionID == "" {
+		return nil, errors.New("cloudfront purger requires distribution_id")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &cloudFrontPurger{cfg: cfg, client: cloudfront.NewFromConfig(awsCfg)}, nil
+}
+
+func (p *cloudFrontPurger) Name() string {
+	return p.cfg.Name
+}
+
+func (p *cloudFrontPurger) Purge(ctx context.Context, issuerID string, partitions []int) error {
+	ctx, cancel := context.WithTimeout(ctx, purgeTimeout)
+	defer cancel()
+
+	// Invalidations take paths; full URLs are reduced to their path
+	var paths []string
+	for _, u := range urls(p.cfg, issuerID, partitions) {
+		if parsed, err := url.Parse(u); err == nil && parsed.Path != "" {
+			u = parsed.Path
+		}
+		paths = append(paths, u)
+	}
+
+	_, err := p.client.CreateInvalidation(ctx, &cloudfront.CreateInvalidationInput{
+		DistributionId: aws.String(p.cfg.CloudFront.DistributionID),
+		InvalidationBatch: &types.InvalidationBatch{
+			CallerReference: aws.String(issuerID + "-" + strconv.FormatInt(time.Now().UnixNano(), 10)),
+			Paths: &types.Paths{
+				Items:    paths,
+				Quantity: aws.Int32(int32(len(paths))),
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to invalidate CloudFront distribution %s: %w", p.cfg.CloudFront.DistributionID, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package cdn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const fastlyAPI = "https://api.fastly.com"
+
+// FastlyConfig authorizes purges with a Fastly API token, which may also be
+// supplied through FASTLY_API_TOKEN
+type FastlyConfig struct {
+	APIToken string `yaml:"api_token"`
+}
+
+// fastlyPurger purges single URLs from Fastly
+type fastlyPurger struct {
+	cfg    Config
+	token  string
+	client *http.Client
+}
+
+func newFastlyPurger(cfg Config) (Purger, error) {
+	token := cfg.Fastly.APIToken
+	if token == "" {
+		token = os.Getenv("FASTLY_API_TOKEN")
+	}
+	if token == "" {
+		return nil, errors.New("fastly purger requires api_token or FASTLY_API_TOKEN")
+	}
+	return &fastlyPurger{cfg: cfg, token: token, client: &http.Client{Timeout: purgeTimeout}}, nil
+}
+
+func (p *fastlyPurger) Name() string {
+	return p.cfg.Name
+}
+
+func (p *fastlyPurger) Purge(ctx context.Context, issuerID string, partitions []int) error {
+	for _, u := range urls(p.cfg, issuerID, partitions) {
+		// The purge endpoint takes the URL without its scheme
+		target := strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://")
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, fastlyAPI+"/purge/"+target, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Fastly-Key", p.token)
+		req.Header.Set("Accept", "application/json")
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to purge %s from Fastly: %w", u, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("fastly purge of %s returned %s", u, resp.Status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,384 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gigvault/crl/internal/api"
+	"github.com/gigvault/crl/internal/archive"
+	"github.com/gigvault/crl/internal/auth"
+	"github.com/gigvault/crl/internal/bloom"
+	"github.com/gigvault/crl/internal/ca"
+	"github.com/gigvault/crl/internal/cdn"
+	"github.com/gigvault/crl/internal/events"
+	"github.com/gigvault/crl/internal/export"
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/notify"
+	"github.com/gigvault/crl/internal/ocsp"
+	"github.com/gigvault/crl/internal/publisher"
+	"github.com/gigvault/crl/internal/ratelimit"
+	"github.com/gigvault/crl/internal/scheduler"
+	"github.com/gigvault/crl/internal/signer"
+	"github.com/gigvault/crl/internal/statuscache"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/crl/internal/tracing"
+	shared "github.com/gigvault/shared/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultIssuerID names the issuer configured by the top-level signer and
+// crl sections
+const DefaultIssuerID = "default"
+
+// defaultShutdownTimeout bounds a graceful shutdown
+const defaultShutdownTimeout = 30 * time.Second
+
+// defaultReplicaMaxLag is the replication lag up to which reads use the
+// replica
+const defaultReplicaMaxLag = 5 * time.Second
+
+// defaultConnectTimeout is how long startup waits for the database
+const defaultConnectTimeout = time.Minute
+
+// Config is the crl service configuration: the shared GigVault settings
+// plus the sections specific to CRL generation
+type Config struct {
+	*shared.Config `yaml:"-"`
+
+	// DB holds the crl-specific settings of the database section
+	DB DatabaseConfig `yaml:"database"`
+
+	Signer signer.Config    `yaml:"signer"`
+	CRL    generator.Config `yaml:"crl"`
+
+	// Publishers are the distribution targets of published CRLs
+	Publishers []publisher.Config `yaml:"publishers"`
+
+	// CDN caches in front of the distribution targets, purged after each
+	// publication
+	CDN []cdn.Config `yaml:"cdn"`
+
+	// Distribution serves published CRLs on a separate HTTP listener
+	Distribution api.DistributionConfig `yaml:"distribution"`
+
+	// Gateway serves the HTTP/JSON bindings of the gRPC API on a separate
+	// listener
+	Gateway api.GatewayConfig `yaml:"gateway"`
+
+	// OCSP answers certificate status requests on a separate HTTP listener
+	OCSP ocsp.Config `yaml:"ocsp"`
+
+	// Auth maps authenticated gRPC callers to the RPCs they may call
+	Auth auth.Config `yaml:"auth"`
+
+	// Tracing exports OpenTelemetry spans of RPCs, queries, signing and
+	// uploads
+	Tracing tracing.Config `yaml:"tracing"`
+
+	// RateLimit limits gRPC calls per client and RPC
+	RateLimit ratelimit.Config `yaml:"rate_limit"`
+
+	// Admin enables destructive admin RPCs such as DeleteRevocation
+	Admin api.AdminConfig `yaml:"admin"`
+
+	// Approval requires a second identity to approve each revocation
+	Approval api.ApprovalConfig `yaml:"approval"`
+
+	// Health configures the checks reported by the gRPC health service
+	Health api.HealthConfig `yaml:"health"`
+
+	// Archive moves the entries of expired certificates out of crl_entries
+	Archive archive.Config `yaml:"archive"`
+
+	// Notify refreshes CRLs on revocations made through any replica
+	Notify notify.Config `yaml:"notify"`
+
+	// Events sends revocation and CRL events to Kafka and other sinks
+	Events events.Config `yaml:"events"`
+
+	// StatusCache caches per-serial revocation status in Redis
+	StatusCache statuscache.Config `yaml:"status_cache"`
+
+	// Export serves alternative encodings of the revocation set next to
+	// the CRLs
+	Export export.Config `yaml:"export"`
+
+	// StatusFilter answers status checks of never revoked serials from a
+	// Bloom filter in memory
+	StatusFilter bloom.Config `yaml:"status_filter"`
+
+	// CA verifies with the gigvault CA service that revoked serials were
+	// issued by the named issuer
+	CA ca.Config `yaml:"ca"`
+
+	// Diagnostics serves pprof and expvar on a localhost-only listener
+	Diagnostics api.DiagnosticsConfig `yaml:"diagnostics"`
+
+	// Reload applies changes of the runtime settings without a restart
+	Reload ReloadConfig `yaml:"reload"`
+
+	// Shutdown bounds the draining of RPCs and publications on SIGTERM
+	Shutdown ShutdownConfig `yaml:"shutdown"`
+
+	// Scheduler elects the replica running the scheduled publications
+	Scheduler scheduler.Config `yaml:"scheduler"`
+
+	// OCSPResponders are delegated OCSP signing certificates and keys of the
+	// default issuer
+	OCSPResponders []signer.Config `yaml:"ocsp_responders"`
+
+	// Issuers configures several CAs in one deployment. When empty, the
+	// top-level signer and crl sections define the single default issuer.
+	Issuers []IssuerConfig `yaml:"issuers"`
+}
+
+// IssuerConfig is the signer and CRL settings of one issuer
+type IssuerConfig struct {
+	ID         string             `yaml:"id"`
+	Signer     signer.Config      `yaml:"signer"`
+	CRL        generator.Config   `yaml:"crl"`
+	Publishers []publisher.Config `yaml:"publishers"`
+	CDN        []cdn.Config       `yaml:"cdn"`
+
+	OCSPResponders []signer.Config `yaml:"ocsp_responders"`
+}
+
+// DatabaseConfig extends the shared database settings
+type DatabaseConfig struct {
+	// DSN is a libpq connection string or postgres:// URL. When set it is
+	// used instead of host, port, database, user, password and sslmode.
+	DSN string `yaml:"dsn"`
+
+	// Migrate applies the embedded schema migrations the database lacks at
+	// startup
+	Migrate bool `yaml:"migrate"`
+
+	// ReplicaDSN connects to a read replica serving status checks, OCSP,
+	// ListRevocations, GetRevocation and recorded CRLs. Writes and CRL
+	// signing stay on the primary.
+	ReplicaDSN string `yaml:"replica_dsn"`
+
+	// ReplicaMaxLag sends reads to the primary while the replica trails it
+	// by more than this (default 5s)
+	ReplicaMaxLag time.Duration `yaml:"replica_max_lag"`
+
+	// ConnectTimeout is how long startup keeps retrying to connect to the
+	// database before it gives up (default 1m)
+	ConnectTimeout time.Duration `yaml:"connect_timeout"`
+
+	// CircuitBreaker fails database calls fast while the database is
+	// unreachable
+	CircuitBreaker store.BreakerConfig `yaml:"circuit_breaker"`
+}
+
+// Validate applies defaults and checks the database config
+func (c *DatabaseConfig) Validate() error {
+	if c.ReplicaMaxLag < 0 {
+		return errors.New("replica max lag must not be negative")
+	}
+	if c.ReplicaMaxLag == 0 {
+		c.ReplicaMaxLag = defaultReplicaMaxLag
+	}
+	if c.ConnectTimeout < 0 {
+		return errors.New("connect timeout must not be negative")
+	}
+	if c.ConnectTimeout == 0 {
+		c.ConnectTimeout = defaultConnectTimeout
+	}
+	if err := c.CircuitBreaker.Validate(); err != nil {
+		return fmt.Errorf("circuit_breaker: %w", err)
+	}
+	return nil
+}
+
+// ShutdownConfig configures graceful shutdown
+type ShutdownConfig struct {
+	// Timeout is how long in-flight RPCs, CRL publications in progress and
+	// due publish retries may take to finish before they are cancelled
+	// (default 30s)
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Validate applies defaults and checks the shutdown config
+func (c *ShutdownConfig) Validate() error {
+	if c.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultShutdownTimeout
+	}
+	return nil
+}
+
+// IssuerConfigs returns the configured issuers, falling back to the
+// top-level sections as the default issuer
+func (c *Config) IssuerConfigs() []IssuerConfig {
+	if len(c.Issuers) == 0 {
+		return []IssuerConfig{{
+			ID:         DefaultIssuerID,
+			Signer:     c.Signer,
+			CRL:        c.CRL,
+			Publishers: c.Publishers,
+			CDN:        c.CDN,
+
+			OCSPResponders: c.OCSPResponders,
+		}}
+	}
+	return c.Issuers
+}
+
+// DefaultIssuer returns the ID of the issuer used when a request names none
+func (c *Config) DefaultIssuer() string {
+	return c.IssuerConfigs()[0].ID
+}
+
+// validateListeners checks that every enabled listener has a valid port of
+// its own
+func (c *Config) validateListeners() error {
+	listeners := []struct {
+		name    string
+		port    int
+		enabled bool
+	}{
+		{"server.http_port", c.Server.HTTPPort, true},
+		{"server.grpc_port", c.Server.GRPCPort, true},
+		{"distribution.port", c.Distribution.Port, c.Distribution.Enabled},
+		{"gateway.port", c.Gateway.Port, c.Gateway.Enabled},
+		{"ocsp.port", c.OCSP.Port, c.OCSP.Enabled},
+		{"diagnostics.port", c.Diagnostics.Port, c.Diagnostics.Enabled},
+	}
+	used := make(map[int]string)
+	for _, l := range listeners {
+		if !l.enabled {
+			continue
+		}
+		if l.port < 1 || l.port > 65535 {
+			return fmt.Errorf("%s must be between 1 and 65535, got %d", l.name, l.port)
+		}
+		if other, ok := used[l.port]; ok {
+			return fmt.Errorf("%s %d is already used by %s", l.name, l.port, other)
+		}
+		used[l.port] = l.name
+	}
+	return nil
+}
+
+// Load loads the shared configuration and the crl-specific sections from
+// the same YAML file, then applies the CRL_* environment overrides
+func Load(path string) (*Config, error) {
+	base, err := shared.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg := Config{Config: base}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if err := applyEnv(EnvPrefix, cfg.Config); err != nil {
+		return nil, err
+	}
+	if err := applyEnv(EnvPrefix, &cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.DB.DSN == "" && cfg.Database.Host == "" {
+		return nil, errors.New("invalid database config: host or dsn is required")
+	}
+	if err := cfg.DB.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid database config: %w", err)
+	}
+
+	if cfg.Distribution.Enabled && cfg.Distribution.Port == 0 {
+		return nil, errors.New("invalid distribution config: port is required")
+	}
+	if err := cfg.Gateway.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid gateway config: %w", err)
+	}
+	if cfg.Security.MTLSEnabled && !cfg.Security.TLSEnabled {
+		return nil, errors.New("invalid security config: mtls_enabled requires tls_enabled")
+	}
+	if err := cfg.Auth.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid auth config: %w", err)
+	}
+	if err := cfg.Tracing.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tracing config: %w", err)
+	}
+	if err := cfg.RateLimit.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rate_limit config: %w", err)
+	}
+	if err := cfg.Admin.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid admin config: %w", err)
+	}
+	if cfg.Auth.APIKeys.Enabled && cfg.Admin.Token == "" {
+		return nil, errors.New("invalid auth config: api_keys requires admin.token to manage keys")
+	}
+	if err := cfg.Approval.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid approval config: %w", err)
+	}
+	if err := cfg.Health.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid health config: %w", err)
+	}
+	if err := cfg.Archive.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid archive config: %w", err)
+	}
+	if err := cfg.Notify.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid notify config: %w", err)
+	}
+	if err := cfg.Events.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid events config: %w", err)
+	}
+	if err := cfg.StatusCache.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid status_cache config: %w", err)
+	}
+	if err := cfg.StatusFilter.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid status_filter config: %w", err)
+	}
+	if err := cfg.Export.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid export config: %w", err)
+	}
+	if cfg.Export.CRLite.Enabled && cfg.CA.Address == "" {
+		return nil, errors.New("invalid export config: crlite needs ca.address to list valid certificates")
+	}
+	if err := cfg.CA.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ca config: %w", err)
+	}
+	if err := cfg.Diagnostics.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid diagnostics config: %w", err)
+	}
+	if err := cfg.Shutdown.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid shutdown config: %w", err)
+	}
+	if err := cfg.Scheduler.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scheduler config: %w", err)
+	}
+	if err := cfg.OCSP.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid ocsp config: %w", err)
+	}
+	if err := cfg.validateListeners(); err != nil {
+		return nil, fmt.Errorf("invalid listener config: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, ic := range cfg.IssuerConfigs() {
+		if ic.ID == "" {
+			return nil, errors.New("invalid issuers config: id is required")
+		}
+		if seen[ic.ID] {
+			return nil, fmt.Errorf("invalid issuers config: duplicate id %q", ic.ID)
+		}
+		seen[ic.ID] = true
+		if err := ic.CRL.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid crl config for issuer %q: %w", ic.ID, err)
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnvPrefix starts the name of every environment override
+const EnvPrefix = "CRL"
+
+// applyEnv overrides the fields of the struct v points to from the
+// environment. A field's variable is named after its YAML path, such as
+// CRL_SERVER_GRPC_PORT for server.grpc_port or CRL_CRL_VALIDITY for
+// crl.validity. String fields take the value as is; other fields parse it
+// as YAML, so lists and maps use flow syntax, e.g. [a, b]. The elements
+// of lists and maps have no variables of their own.
+func applyEnv(prefix string, v interface{}) error {
+	return applyEnvValue(prefix, reflect.ValueOf(v).Elem())
+}
+
+func applyEnvValue(prefix string, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		opts := strings.Split(field.Tag.Get("yaml"), ",")
+		tag := opts[0]
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if len(opts) > 1 && opts[1] == "inline" && fv.Kind() == reflect.Struct {
+			if err := applyEnvValue(prefix, fv); err != nil {
+				return err
+			}
+			continue
+		}
+		if tag == "" {
+			tag = strings.ToLower(field.Name)
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+
+		if fv.Kind() == reflect.Struct {
+			if _, custom := fv.Addr().Interface().(yaml.Unmarshaler); !custom {
+				if err := applyEnvValue(name, fv); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if fv.Kind() == reflect.String {
+			fv.SetString(value)
+			continue
+		}
+		if err := yaml.Unmarshal([]byte(value), fv.Addr().Interface()); err != nil {
+			return fmt.Errorf("invalid value of %s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// reloadDebounce coalesces the burst of events of one file update
+	reloadDebounce = time.Second
+
+	// kubernetesDataLink is swapped when Kubernetes updates a mounted
+	// ConfigMap, instead of writing the file itself
+	kubernetesDataLink = "..data"
+)
+
+// ReloadConfig configures hot reloading of the configuration
+type ReloadConfig struct {
+	// Watch also reloads when the configuration file changes; SIGHUP
+	// always reloads
+	Watch bool `yaml:"watch"`
+}
+
+// Reloader loads the configuration file again on SIGHUP and, with watch
+// enabled, when the file changes, and hands it to apply. A file that fails
+// to load is logged and leaves the running configuration in place.
+type Reloader struct {
+	path   string
+	cfg    ReloadConfig
+	apply  func(*Config) error
+	logger *logger.Logger
+}
+
+// NewReloader creates a reloader of the configuration file at path
+func NewReloader(path string, cfg ReloadConfig, apply func(*Config) error) *Reloader {
+	return &Reloader{
+		path:   path,
+		cfg:    cfg,
+		apply:  apply,
+		logger: logger.Global(),
+	}
+}
+
+// Run reloads on every trigger until ctx is done
+func (r *Reloader) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var events <-chan fsnotify.Event
+	var errs <-chan error
+	if r.cfg.Watch {
+		// Watch the directory: editors and Kubernetes replace the file
+		// rather than writing it, which ends a watch on the file itself
+		w, err := fsnotify.NewWatcher()
+		if err == nil {
+			err = w.Add(filepath.Dir(r.path))
+		}
+		if err != nil {
+			r.logger.Error("Failed to watch configuration file; reloading on SIGHUP only",
+				zap.String("path", r.path), zap.Error(err))
+		} else {
+			defer w.Close()
+			events, errs = w.Events, w.Errors
+		}
+	}
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.reload("signal")
+		case ev := <-events:
+			if r.affects(ev) {
+				debounce = time.After(reloadDebounce)
+			}
+		case err := <-errs:
+			r.logger.Warn("Configuration file watch error", zap.Error(err))
+		case <-debounce:
+			debounce = nil
+			r.reload("file change")
+		}
+	}
+}
+
+// affects reports whether ev may have changed the configuration file
+func (r *Reloader) affects(ev fsnotify.Event) bool {
+	if !ev.Has(fsnotify.Write) && !ev.Has(fsnotify.Create) && !ev.Has(fsnotify.Rename) {
+		return false
+	}
+	name := filepath.Base(ev.Name)
+	return name == filepath.Base(r.path) || name == kubernetesDataLink
+}
+
+func (r *Reloader) reload(trigger string) {
+	cfg, err := Load(r.path)
+	if err == nil {
+		err = r.apply(cfg)
+	}
+	metrics.ConfigReloads.WithLabelValues(metrics.Result(err)).Inc()
+	if err != nil {
+		r.logger.Error("Configuration reload failed",
+			zap.String("trigger", trigger), zap.String("path", r.path), zap.Error(err))
+		return
+	}
+	r.logger.Info("Configuration reloaded", zap.String("trigger", trigger), zap.String("path", r.path))
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gigvault/crl/internal/store"
+)
+
+// Schema identifies the JSON encoding of Event, described by
+// api/events/event.schema.json. It changes only on incompatible changes.
+const Schema = "gigvault.crl.event.v1"
+
+// Event types
+const (
+	// TypeRevocationAdded is a serial revoked, or its revocation changed
+	TypeRevocationAdded = "revocation.added"
+
+	// TypeRevocationHeld is a certificate put on hold
+	TypeRevocationHeld = "revocation.held"
+
+	// TypeRevocationRemoved is a hold released or a revocation removed or
+	// deleted: the serial is no longer revoked
+	TypeRevocationRemoved = "revocation.removed"
+
+	// TypeCRLPublished is an issuer's CRLs signed and distributed
+	TypeCRLPublished = "crl.published"
+
+	// TypeCRLPublishFailed is a publication that failed: no CRL was
+	// signed, or the signed CRLs failed to upload or verify
+	TypeCRLPublishFailed = "crl.publish_failed"
+)
+
+// eventTypes lists the event types, which sinks may be limited to
+var eventTypes = []string{
+	TypeRevocationAdded,
+	TypeRevocationHeld,
+	TypeRevocationRemoved,
+	TypeCRLPublished,
+	TypeCRLPublishFailed,
+}
+
+// Event is a change to the revocation set or a CRL publication, as sent
+// to the sinks
+type Event struct {
+	// ID is unique per event, so consumers can drop the duplicates of
+	// at-least-once delivery
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Schema   string    `json:"schema"`
+	Time     time.Time `json:"time"`
+	IssuerID string    `json:"issuer_id"`
+
+	// Revocation events
+	Sequence          int64      `json:"sequence,omitempty"`
+	Serial            string     `json:"serial_number,omitempty"`
+	Status            string     `json:"status,omitempty"`
+	Reason            string     `json:"reason,omitempty"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	InvalidityDate    *time.Time `json:"invalidity_date,omitempty"`
+	CertificateIssuer []byte     `json:"certificate_issuer,omitempty"`
+	EffectiveAt       *time.Time `json:"effective_at,omitempty"`
+
+	// CRL events
+	CRLNumber     int64      `json:"crl_number,omitempty"`
+	Partitions    int        `json:"partitions,omitempty"`
+	RevokedCount  int        `json:"revoked_count,omitempty"`
+	NextUpdate    *time.Time `json:"next_update,omitempty"`
+	FailedUploads int        `json:"failed_uploads,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// Key returns the partitioning key of the event: the serial of revocation
+// events, so each serial's changes stay in order, and the issuer of CRL
+// events
+func (e *Event) Key() string {
+	if e.Serial != "" {
+		return e.IssuerID + "/" + e.Serial
+	}
+	return e.IssuerID
+}
+
+// Marshal encodes the event as JSON
+func (e *Event) Marshal() ([]byte, error) {
+	return json.Marshal(e)
+}
+
+// revocationType returns the event type of a revocation event status, or
+// "" for changes that are not sent, such as archiving
+func revocationType(status string) string {
+	switch status {
+	case store.StatusRevoked:
+		return TypeRevocationAdded
+	case store.StatusOnHold:
+		return TypeRevocationHeld
+	case store.StatusReleased, store.StatusRemoved, store.StatusDeleted:
+		return TypeRevocationRemoved
+	}
+	return ""
+}
+
+// fromStore converts a recorded revocation event, reporting false for
+// changes that are not sent
+func fromStore(e store.Event) (Event, bool) {
+	typ := revocationType(e.Status)
+	if typ == "" {
+		return Event{}, false
+	}
+	revokedAt := e.RevokedAt
+	return Event{
+		ID:                fmt.Sprintf("revocation-%d", e.Sequence),
+		Type:              typ,
+		Schema:            Schema,
+		Time:              e.CreatedAt,
+		IssuerID:          e.IssuerID,
+		Sequence:          e.Sequence,
+		Serial:            e.Serial,
+		Status:            e.Status,
+		Reason:            e.Reason,
+		RevokedAt:         &revokedAt,
+		InvalidityDate:    e.InvalidityDate,
+		CertificateIssuer: e.CertificateIssuer,
+		EffectiveAt:       e.EffectiveAt,
+	}, true
+}
@@ -0,0 +1,217 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// sendTimeout bounds the delivery of one batch to one sink
+const sendTimeout = 30 * time.Second
+
+// cursorPrefix prefixes the export_cursors names of the sinks
+const cursorPrefix = "events:"
+
+// Relay sends the revocation events recorded by every replica, and the
+// CRL publications of this one, to the sinks. Each sink has a cursor in
+// export_cursors, so revocation events are delivered at least once and in
+// order, by one replica at a time, across restarts.
+type Relay struct {
+	store  *store.Store
+	cfg    Config
+	sinks  []*relaySink
+	logger *logger.Logger
+}
+
+// relaySink is a sink with the event types it is limited to
+type relaySink struct {
+	Sink
+	types map[string]bool // nil for all
+}
+
+func (s *relaySink) accepts(typ string) bool {
+	return s.types == nil || s.types[typ]
+}
+
+// timeoutSink is implemented by sinks that may need longer than
+// sendTimeout to deliver a batch
+type timeoutSink interface {
+	SendTimeout(events int) time.Duration
+}
+
+// NewRelay creates the sinks of cfg. A sink without a cursor yet starts at
+// the newest revocation event rather than replaying the history.
+func NewRelay(ctx context.Context, st *store.Store, cfg Config) (*Relay, error) {
+	latest, err := st.LatestEventSequence(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r := &Relay{store: st, cfg: cfg, logger: logger.Global()}
+	for _, sc := range cfg.Sinks {
+		sink, err := NewSink(sc)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("sink %q: %w", sc.Name, err)
+		}
+		rs := &relaySink{Sink: sink}
+		if len(sc.Types) > 0 {
+			rs.types = make(map[string]bool, len(sc.Types))
+			for _, typ := range sc.Types {
+				rs.types[typ] = true
+			}
+		}
+		r.sinks = append(r.sinks, rs)
+		if err := st.CreateExportCursor(ctx, cursorPrefix+sink.Name(), latest); err != nil {
+			r.Close()
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Run delivers new revocation events to every sink until ctx is done
+func (r *Relay) Run(ctx context.Context) {
+	for _, sink := range r.sinks {
+		go r.run(ctx, sink)
+	}
+	<-ctx.Done()
+}
+
+func (r *Relay) run(ctx context.Context, sink *relaySink) {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := r.store.AdvanceExportCursor(ctx, cursorPrefix+sink.Name(), func(after int64) (int64, error) {
+			return r.deliver(ctx, sink, after)
+		})
+		if err != nil && !errors.Is(err, store.ErrCursorBusy) && ctx.Err() == nil {
+			r.logger.Error("Failed to send revocation events", zap.String("sink", sink.Name()), zap.Error(err))
+		}
+	}
+}
+
+// deliver sends the settled events after sequence after to sink and
+// returns the sequence of the last one delivered
+func (r *Relay) deliver(ctx context.Context, sink *relaySink, after int64) (int64, error) {
+	until, err := r.store.EventSequenceBefore(ctx, time.Now().Add(-r.cfg.Settle))
+	if err != nil {
+		return after, err
+	}
+	for after < until {
+		recorded, err := r.store.ListEvents(ctx, after, r.cfg.BatchSize)
+		if err != nil {
+			return after, err
+		}
+		last := after
+		var batch []Event
+		for _, e := range recorded {
+			if e.Sequence > until {
+				break
+			}
+			last = e.Sequence
+			if ev, ok := fromStore(e); ok && sink.accepts(ev.Type) {
+				batch = append(batch, ev)
+			}
+		}
+		if last == after {
+			return after, nil
+		}
+		if len(batch) > 0 {
+			if err := r.send(ctx, sink, batch); err != nil {
+				return after, err
+			}
+		}
+		after = last
+	}
+	return after, nil
+}
+
+func (r *Relay) send(ctx context.Context, sink *relaySink, batch []Event) error {
+	timeout := sendTimeout
+	if ts, ok := sink.Sink.(timeoutSink); ok {
+		timeout = max(timeout, ts.SendTimeout(len(batch)))
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	err := sink.Send(ctx, batch)
+	metrics.EventsSent.WithLabelValues(sink.Name(), metrics.Result(err)).Add(float64(len(batch)))
+	return err
+}
+
+// CRLPublished is a generator.PublishHook sending a crl.published event,
+// or crl.publish_failed if err is set, to every sink. Publication events
+// are not recorded, so one that cannot be sent is dropped.
+func (r *Relay) CRLPublished(ctx context.Context, g *generator.Generator, pub *generator.Publication, err error) {
+	if errors.Is(err, context.Canceled) {
+		// Shutting down
+		return
+	}
+	ev := Event{
+		Type:     TypeCRLPublished,
+		Schema:   Schema,
+		Time:     time.Now(),
+		IssuerID: g.ID(),
+	}
+	if pub != nil {
+		last := pub.CRLs[len(pub.CRLs)-1]
+		nextUpdate := pub.NextUpdate()
+		ev.ID = fmt.Sprintf("crl-%s-%s", g.ID(), last.Number)
+		ev.Time = pub.PublishedAt
+		ev.CRLNumber = last.Number.Int64()
+		ev.Partitions = len(pub.CRLs)
+		ev.RevokedCount = pub.RevokedCount()
+		ev.NextUpdate = &nextUpdate
+		ev.FailedUploads = pub.Failed()
+	}
+	if err != nil {
+		ev.Type = TypeCRLPublishFailed
+		ev.Error = err.Error()
+		if pub == nil {
+			ev.ID = fmt.Sprintf("crl-%s-failed-%d", g.ID(), ev.Time.UnixNano())
+		} else {
+			ev.ID += "-failed"
+		}
+	}
+	r.broadcast(ev)
+}
+
+// broadcast sends an unrecorded event to every sink in the background
+func (r *Relay) broadcast(ev Event) {
+	for _, sink := range r.sinks {
+		if !sink.accepts(ev.Type) {
+			continue
+		}
+		go func() {
+			if err := r.send(context.Background(), sink, []Event{ev}); err != nil {
+				r.logger.Error("Failed to send event",
+					zap.String("sink", sink.Name()),
+					zap.String("type", ev.Type),
+					zap.String("issuer_id", ev.IssuerID),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+}
+
+// Close closes the sinks
+func (r *Relay) Close() {
+	for _, sink := range r.sinks {
+		if err := sink.Close(); err != nil {
+			r.logger.Warn("Failed to close event sink", zap.String("sink", sink.Name()), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Supported sink types
+const (
+	TypeKafka   = "kafka"
+	TypeNATS    = "nats"
+	TypeWebhook = "webhook"
+)
+
+const (
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 500
+	defaultSettle       = 5 * time.Second
+)
+
+// Config configures the sinks revocation and CRL events are sent to
+type Config struct {
+	// PollInterval is how often new revocation events are read (default 1s)
+	PollInterval time.Duration `yaml:"poll_interval"`
+
+	// BatchSize bounds the events sent to a sink at once (default 500)
+	BatchSize int `yaml:"batch_size"`
+
+	// Settle is how old revocation events must be to be sent (default 5s).
+	// Events are numbered when their transaction starts but may commit out
+	// of order; waiting keeps a late commit from being skipped.
+	Settle time.Duration `yaml:"settle"`
+
+	Sinks []SinkConfig `yaml:"sinks"`
+}
+
+// SinkConfig selects an event sink
+type SinkConfig struct {
+	// Name identifies the sink in logs and metrics and keys its delivery
+	// cursor; it defaults to the type. Renaming a sink starts it over at
+	// the newest event.
+	Name string `yaml:"name"`
+	Type string `yaml:"type"` // kafka, nats, webhook
+
+	// Types limits the event types sent to the sink; empty sends all
+	Types []string `yaml:"types"`
+
+	Kafka   KafkaConfig   `yaml:"kafka"`
+	NATS    NATSConfig    `yaml:"nats"`
+	Webhook WebhookConfig `yaml:"webhook"`
+}
+
+// Validate applies defaults and checks the events config
+func (c *Config) Validate() error {
+	if c.PollInterval < 0 || c.BatchSize < 0 || c.Settle < 0 {
+		return errors.New("poll_interval, batch_size and settle must not be negative")
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = defaultBatchSize
+	}
+	if c.Settle == 0 {
+		c.Settle = defaultSettle
+	}
+	names := make(map[string]bool, len(c.Sinks))
+	for i := range c.Sinks {
+		s := &c.Sinks[i]
+		if s.Name == "" {
+			s.Name = s.Type
+		}
+		if _, ok := factories[s.Type]; !ok {
+			return fmt.Errorf("sink %q: unknown type %q (registered: %s)", s.Name, s.Type, strings.Join(Types(), ", "))
+		}
+		for _, typ := range s.Types {
+			if !slices.Contains(eventTypes, typ) {
+				return fmt.Errorf("sink %q: unknown event type %q (known: %s)", s.Name, typ, strings.Join(eventTypes, ", "))
+			}
+		}
+		if names[s.Name] {
+			return fmt.Errorf("duplicate sink name %q", s.Name)
+		}
+		names[s.Name] = true
+	}
+	return nil
+}
+
+// Enabled reports whether any sink is configured
+func (c Config) Enabled() bool {
+	return len(c.Sinks) > 0
+}
+
+// Sink delivers events to a downstream system. Backends register a
+// Factory for their type.
+type Sink interface {
+	// Name identifies the sink in logs
+	Name() string
+
+	// Send delivers events in order; on error none of them may be
+	// considered delivered
+	Send(ctx context.Context, events []Event) error
+
+	Close() error
+}
+
+// Factory creates a sink from its configuration
+type Factory func(cfg SinkConfig) (Sink, error)
+
+// factories maps sink types to their constructors
+var factories = make(map[string]Factory)
+
+// Register makes a sink type available to NewSink. It is meant to be
+// called from init and panics if the type is registered twice.
+func Register(typ string, f Factory) {
+	if _, dup := factories[typ]; dup {
+		panic("events: Register called twice for type " + typ)
+	}
+	factories[typ] = f
+}
+
+// Types returns the registered sink types
+func Types() []string {
+	types := make([]string, 0, len(factories))
+	for typ := range factories {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// NewSink creates the sink selected by cfg.Type
+func NewSink(cfg SinkConfig) (Sink, error) {
+	f, ok := factories[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown sink type: %q (registered: %s)", cfg.Type, strings.Join(Types(), ", "))
+	}
+	return f(cfg)
+}
@@ -0,0 +1,204 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultWebhookTimeout     = 10 * time.Second
+	defaultWebhookMaxAttempts = 5
+
+	webhookBackoffInitial = time.Second
+	webhookBackoffMax     = 30 * time.Second
+)
+
+// Headers of webhook requests
+const (
+	HeaderEvent     = "X-Gigvault-Event"
+	HeaderDelivery  = "X-Gigvault-Delivery"
+	HeaderTimestamp = "X-Gigvault-Timestamp"
+	HeaderSignature = "X-Gigvault-Signature"
+)
+
+// WebhookConfig is a URL each event is POSTed to as JSON
+type WebhookConfig struct {
+	URL string `yaml:"url"`
+
+	// Secret, or the contents of SecretFile, signs requests: the
+	// signature header is sha256= and the hex HMAC-SHA256 of the
+	// timestamp header, a dot and the body
+	Secret     string `yaml:"secret"`
+	SecretFile string `yaml:"secret_file"`
+
+	// Timeout bounds each attempt (default 10s)
+	Timeout time.Duration `yaml:"timeout"`
+
+	// MaxAttempts bounds the attempts at an event before the batch is
+	// left for the next poll (default 5)
+	MaxAttempts int `yaml:"max_attempts"`
+}
+
+// webhookSink POSTs events to a URL, one request per event
+type webhookSink struct {
+	name        string
+	url         string
+	secret      []byte
+	maxAttempts int
+	timeout     time.Duration
+	client      *http.Client
+	logger      *logger.Logger
+}
+
+func init() {
+	Register(TypeWebhook, newWebhookSink)
+}
+
+func newWebhookSink(cfg SinkConfig) (Sink, error) {
+	c := cfg.Webhook
+	if !strings.HasPrefix(c.URL, "https://") && !strings.HasPrefix(c.URL, "http://") {
+		return nil, errors.New("webhook sink requires an http or https url")
+	}
+	if c.Timeout < 0 || c.MaxAttempts < 0 {
+		return nil, errors.New("webhook timeout and max_attempts must not be negative")
+	}
+	secret := c.Secret
+	if c.SecretFile != "" {
+		data, err := os.ReadFile(c.SecretFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook secret: %w", err)
+		}
+		secret = strings.TrimSpace(string(data))
+	}
+	if secret == "" {
+		return nil, errors.New("webhook sink requires secret or secret_file")
+	}
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = defaultWebhookTimeout
+	}
+	maxAttempts := c.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+
+	return &webhookSink{
+		name:        cfg.Name,
+		url:         c.URL,
+		secret:      []byte(secret),
+		maxAttempts: maxAttempts,
+		timeout:     timeout,
+		client:      &http.Client{Timeout: timeout},
+		logger:      logger.Global(),
+	}, nil
+}
+
+func (s *webhookSink) Name() string {
+	return s.name
+}
+
+// SendTimeout allows one attempt at each event and every retry of one
+func (s *webhookSink) SendTimeout(events int) time.Duration {
+	return time.Duration(events)*s.timeout + time.Duration(s.maxAttempts)*(s.timeout+webhookBackoffMax)
+}
+
+// Send delivers the events in order. An event the receiver rejects with a
+// client error other than 408 or 429 is dropped rather than retried
+// forever.
+func (s *webhookSink) Send(ctx context.Context, events []Event) error {
+	for i := range events {
+		body, err := events[i].Marshal()
+		if err != nil {
+			return err
+		}
+		if err := s.deliver(ctx, &events[i], body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deliver posts one event, retrying with backoff. Every attempt carries
+// the timestamp and signature of the first, so a retry is the same request.
+func (s *webhookSink) deliver(ctx context.Context, ev *Event, body []byte) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := "sha256=" + Sign(s.secret, timestamp, body)
+	backoff := webhookBackoffInitial
+	var err error
+	for attempt := 1; ; attempt++ {
+		var status int
+		status, err = s.post(ctx, ev, body, timestamp, signature)
+		switch {
+		case err == nil && status < 300:
+			return nil
+		case err == nil && status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests:
+			s.logger.Warn("Webhook rejected event; dropping it",
+				zap.String("sink", s.name),
+				zap.String("event_id", ev.ID),
+				zap.Int("status", status),
+			)
+			return nil
+		case err == nil:
+			err = fmt.Errorf("webhook returned status %d", status)
+		}
+		if attempt == s.maxAttempts {
+			return fmt.Errorf("failed to deliver event %s after %d attempts: %w", ev.ID, attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, webhookBackoffMax)
+	}
+}
+
+func (s *webhookSink) post(ctx context.Context, ev *Event, body []byte, timestamp, signature string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "gigvault-crl")
+	req.Header.Set(HeaderEvent, ev.Type)
+	req.Header.Set(HeaderDelivery, ev.ID)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, signature)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
+
+// Sign returns the hex HMAC-SHA256 with secret of the timestamp, a dot and
+// the body, the signature receivers recompute to authenticate a webhook
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *webhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 keyed with whsec_test of 1700000000.{"id":"evt-1"}
+	const want = "5056f09710e0bebdbcd623bb1a7714db4eac94f18745b31b96dd55a69f444e14"
+	if got := Sign([]byte("whsec_test"), "1700000000", []byte(`{"id":"evt-1"}`)); got != want {
+		t.Fatalf("Sign = %s, want %s", got, want)
+	}
+}
+
+// received is a webhook request as the receiver saw it
+type received struct {
+	header http.Header
+	body   []byte
+}
+
+// webhookReceiver answers requests with statuses in turn, repeating the
+// last one, and records them
+func webhookReceiver(t *testing.T, statuses ...int) (*httptest.Server, func() []received) {
+	t.Helper()
+	var mu sync.Mutex
+	var requests []received
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, received{header: r.Header.Clone(), body: body})
+		status := statuses[min(len(requests), len(statuses))-1]
+		mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []received {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]received(nil), requests...)
+	}
+}
+
+func newTestWebhook(t *testing.T, url string, maxAttempts int) Sink {
+	t.Helper()
+	sink, err := newWebhookSink(SinkConfig{Name: "hook", Webhook: WebhookConfig{URL: url, Secret: "whsec_test", MaxAttempts: maxAttempts}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sink.Close() })
+	return sink
+}
+
+// verify checks a request the way a receiver does
+func verify(t *testing.T, r received, secret string) {
+	t.Helper()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(r.header.Get(HeaderTimestamp) + "."))
+	mac.Write(r.body)
+	signature, ok := strings.CutPrefix(r.header.Get(HeaderSignature), "sha256=")
+	if !ok {
+		t.Fatalf("signature %q without sha256=", r.header.Get(HeaderSignature))
+	}
+	got, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(got, mac.Sum(nil)) {
+		t.Fatalf("signature %q does not verify", signature)
+	}
+}
+
+func TestWebhookSignsRequests(t *testing.T) {
+	srv, requests := webhookReceiver(t, http.StatusNoContent)
+	sink := newTestWebhook(t, srv.URL, 1)
+
+	before := time.Now().Unix()
+	events := []Event{
+		{ID: "evt-1", Type: TypeRevocationAdded, IssuerID: "root", Serial: "a1b"},
+		{ID: "evt-2", Type: TypeRevocationHeld, IssuerID: "root", Serial: "a1c"},
+	}
+	if err := sink.Send(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	got := requests()
+	if len(got) != len(events) {
+		t.Fatalf("%d requests, want %d", len(got), len(events))
+	}
+	for i, r := range got {
+		verify(t, r, "whsec_test")
+		body, _ := events[i].Marshal()
+		if string(r.body) != string(body) {
+			t.Fatalf("body %s, want %s", r.body, body)
+		}
+		if r.header.Get(HeaderEvent) != events[i].Type || r.header.Get(HeaderDelivery) != events[i].ID {
+			t.Fatalf("headers %v for event %s", r.header, events[i].ID)
+		}
+		if ts, err := strconv.ParseInt(r.header.Get(HeaderTimestamp), 10, 64); err != nil || ts < before || ts > time.Now().Unix() {
+			t.Fatalf("timestamp %q", r.header.Get(HeaderTimestamp))
+		}
+	}
+}
+
+func TestWebhookRetryKeepsSignature(t *testing.T) {
+	srv, requests := webhookReceiver(t, http.StatusServiceUnavailable, http.StatusOK)
+	sink := newTestWebhook(t, srv.URL, 2)
+
+	// The backoff spans a second boundary, so a retry signed again would
+	// carry a later timestamp
+	if err := sink.Send(context.Background(), []Event{{ID: "evt-1", Type: TypeRevocationAdded}}); err != nil {
+		t.Fatal(err)
+	}
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("%d attempts, want 2", len(got))
+	}
+	for _, r := range got[1:] {
+		verify(t, r, "whsec_test")
+		for _, h := range []string{HeaderTimestamp, HeaderSignature, HeaderDelivery} {
+			if r.header.Get(h) != got[0].header.Get(h) {
+				t.Fatalf("retry sent %s %q, first attempt %q", h, r.header.Get(h), got[0].header.Get(h))
+			}
+		}
+	}
+}
+
+func TestWebhookDropsRejectedEvent(t *testing.T) {
+	srv, requests := webhookReceiver(t, http.StatusBadRequest)
+	sink := newTestWebhook(t, srv.URL, 3)
+	if err := sink.Send(context.Background(), []Event{{ID: "evt-1"}, {ID: "evt-2"}}); err != nil {
+		t.Fatalf("rejected events: %v", err)
+	}
+	if got := requests(); len(got) != 2 {
+		t.Fatalf("%d requests, want one per event", len(got))
+	}
+}
+
+func TestWebhookGivesUp(t *testing.T) {
+	srv, requests := webhookReceiver(t, http.StatusBadGateway)
+	sink := newTestWebhook(t, srv.URL, 2)
+	if err := sink.Send(context.Background(), []Event{{ID: "evt-1"}, {ID: "evt-2"}}); err == nil {
+		t.Fatal("delivered to a failing receiver")
+	}
+	// Later events wait behind the undelivered one
+	if got := requests(); len(got) != 2 || got[1].header.Get(HeaderDelivery) != "evt-1" {
+		t.Fatalf("%d requests", len(got))
+	}
+}
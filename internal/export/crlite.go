@@ -0,0 +1,114 @@
+package export
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/gigvault/crl/internal/bloom"
+)
+
+// crliteMagic starts every encoded filter cascade
+const crliteMagic = "GVCRLITE"
+
+// crliteVersion is the version of the cascade encoding
+const crliteVersion = 1
+
+// maxLevels bounds the depth of a cascade; construction converges long
+// before unless the revoked and valid sets overlap
+const maxLevels = 64
+
+// ErrCascadeDiverged is returned when a cascade does not converge, which
+// happens when a serial is in both sets
+var ErrCascadeDiverged = errors.New("filter cascade did not converge")
+
+// Cascade is a CRLite filter cascade over the revoked serials of one
+// issuer. Level 1 holds the revoked serials, level 2 the valid serials
+// level 1 falsely matches, level 3 the revoked serials level 2 falsely
+// matches and so on, so that every serial of either set is classified
+// exactly. Serials of neither set give an arbitrary answer; relying parties
+// only query certificates they know the issuer issued and are unexpired.
+type Cascade struct {
+	levels []*bloom.Filter
+}
+
+// levelKey salts a serial with its level, so the levels hash it
+// independently
+func levelKey(level int, serial []byte) []byte {
+	return append([]byte{byte(level)}, serial...)
+}
+
+// BuildCascade builds the cascade separating revoked from valid serials,
+// given as canonical big-endian bytes. The sets must be disjoint.
+func BuildCascade(revoked, valid [][]byte) (*Cascade, error) {
+	// CRLite sizes the first level so that about as many valid serials as
+	// revoked ones fall through; deeper levels use 1/2
+	rate := 0.5
+	if len(valid) > 0 {
+		rate = math.Max(1e-6, math.Min(0.5, float64(len(revoked))*math.Sqrt2/float64(len(valid))))
+	}
+
+	c := &Cascade{}
+	include, exclude := revoked, valid
+	for level := 1; len(include) > 0; level++ {
+		if level > maxLevels {
+			return nil, ErrCascadeDiverged
+		}
+		f := bloom.NewFilter(len(include), rate)
+		for _, s := range include {
+			f.Add(levelKey(level, s))
+		}
+		c.levels = append(c.levels, f)
+
+		var falsePositives [][]byte
+		for _, s := range exclude {
+			if f.Test(levelKey(level, s)) {
+				falsePositives = append(falsePositives, s)
+			}
+		}
+		include, exclude = falsePositives, include
+		rate = 0.5
+	}
+	return c, nil
+}
+
+// Revoked reports whether the cascade classifies a serial as revoked: it
+// is matched by an odd number of consecutive levels
+func (c *Cascade) Revoked(serial []byte) bool {
+	for i, f := range c.levels {
+		if !f.Test(levelKey(i+1, serial)) {
+			return i%2 == 1
+		}
+	}
+	return len(c.levels)%2 == 1
+}
+
+// Levels returns the depth of the cascade
+func (c *Cascade) Levels() int {
+	return len(c.levels)
+}
+
+// MarshalBinary encodes the cascade as the magic GVCRLITE, a version
+// octet, the length-prefixed (one octet) key identifier of the issuer,
+// the coverage time in Unix seconds (eight octets, big-endian) and the
+// number of levels (one octet), followed by each level as encoded by
+// bloom.Filter.MarshalBinary
+func (c *Cascade) MarshalBinary(keyID []byte, coverage time.Time) ([]byte, error) {
+	if len(keyID) > math.MaxUint8 {
+		return nil, errors.New("issuer key identifier is too long")
+	}
+	out := []byte(crliteMagic)
+	out = append(out, crliteVersion, byte(len(keyID)))
+	out = append(out, keyID...)
+	out = binary.BigEndian.AppendUint64(out, uint64(coverage.Unix()))
+	out = append(out, byte(len(c.levels)))
+	for _, f := range c.levels {
+		level, err := f.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, level...)
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// crlSetHeader is the JSON header of a Chrome CRLSet
+type crlSetHeader struct {
+	Version      int      `json:"Version"`
+	ContentType  string   `json:"ContentType"`
+	Sequence     uint64   `json:"Sequence"`
+	DeltaFrom    int      `json:"DeltaFrom"`
+	NumParents   int      `json:"NumParents"`
+	BlockedSPKIs []string `json:"BlockedSPKIs"`
+}
+
+// MarshalCRLSet encodes serials, as canonical big-endian bytes, as a
+// Chrome CRLSet with a single parent, the issuer with the SHA-256 digest
+// spkiHash of its SubjectPublicKeyInfo. Chrome only applies a CRLSet with
+// a higher sequence than the one it has.
+func MarshalCRLSet(spkiHash [sha256.Size]byte, serials [][]byte, sequence uint64) ([]byte, error) {
+	header, err := json.Marshal(crlSetHeader{
+		ContentType:  "CRLSet",
+		Sequence:     sequence,
+		NumParents:   1,
+		BlockedSPKIs: []string{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(header) > math.MaxUint16 {
+		return nil, errors.New("CRLSet header is too long")
+	}
+
+	out := binary.LittleEndian.AppendUint16(nil, uint16(len(header)))
+	out = append(out, header...)
+	out = append(out, spkiHash[:]...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(serials)))
+	for _, s := range serials {
+		if len(s) > math.MaxUint8 {
+			return nil, errors.New("serial is too long for a CRLSet")
+		}
+		out = append(out, byte(len(s)))
+		out = append(out, s...)
+	}
+	return out, nil
+}
@@ -0,0 +1,174 @@
+package export
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gigvault/crl/internal/store"
+)
+
+// Formats of WriteEntries
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// entriesPageSize is how many entries WriteEntries reads per query
+const entriesPageSize = 1000
+
+// entryColumns are the CSV columns, and JSON names, of an exported entry
+var entryColumns = []string{
+	"issuer_id", "serial_number", "reason", "status", "revoked_at", "invalidity_date",
+	"certificate_issuer", "updated_at", "removed_by", "removal_reason", "removed_at",
+	"effective_at", "not_after", "archived_at",
+}
+
+// ContentType returns the media type of a format of WriteEntries
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv"
+	}
+	return "application/json"
+}
+
+// WriteEntries writes every entry matching filter, newest revocation first,
+// to w as a JSON array of objects or as CSV with a header row. Times are
+// RFC 3339 in UTC and the certificate issuer is base64 DER; unset fields
+// are omitted from JSON and empty in CSV. It returns how many entries it
+// wrote.
+func WriteEntries(ctx context.Context, st *store.Store, filter store.EntryFilter, format string, w io.Writer) (int, error) {
+	var enc entryEncoder
+	switch format {
+	case FormatJSON:
+		enc = &jsonEntries{w: w}
+	case FormatCSV:
+		enc = &csvEntries{w: csv.NewWriter(w)}
+	default:
+		return 0, fmt.Errorf("unknown export format %q", format)
+	}
+
+	if err := enc.begin(); err != nil {
+		return 0, err
+	}
+	var cursor *store.EntryCursor
+	n := 0
+	for {
+		entries, err := st.ListEntriesPage(ctx, filter, cursor, entriesPageSize)
+		if err != nil {
+			return n, err
+		}
+		for i := range entries {
+			if err := enc.write(entryFields(&entries[i])); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(entries) < entriesPageSize {
+			break
+		}
+		last := entries[len(entries)-1]
+		cursor = &store.EntryCursor{RevokedAt: last.RevokedAt, Serial: last.Serial}
+	}
+	return n, enc.end()
+}
+
+// entryFields returns the values of entryColumns for e, empty when unset
+func entryFields(e *store.Entry) []string {
+	var certIssuer string
+	if e.CertificateIssuer != nil {
+		certIssuer = base64.StdEncoding.EncodeToString(e.CertificateIssuer)
+	}
+	return []string{
+		e.IssuerID, e.Serial, e.Reason, e.Status, formatTime(&e.RevokedAt), formatTime(e.InvalidityDate),
+		certIssuer, formatTime(&e.UpdatedAt), e.RemovedBy, e.RemovalReason, formatTime(e.RemovedAt),
+		formatTime(e.EffectiveAt), formatTime(e.NotAfter), formatTime(e.ArchivedAt),
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+type entryEncoder interface {
+	begin() error
+	write(fields []string) error
+	end() error
+}
+
+type jsonEntries struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonEntries) begin() error {
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+// write writes one object, its members in the order of entryColumns
+func (j *jsonEntries) write(fields []string) error {
+	data := []byte(",\n{")
+	if j.count == 0 {
+		data = data[1:]
+	}
+	j.count++
+	first := true
+	for i, v := range fields {
+		if v == "" {
+			continue
+		}
+		if !first {
+			data = append(data, ',')
+		}
+		first = false
+		value, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		data = append(data, '"')
+		data = append(data, entryColumns[i]...)
+		data = append(data, '"', ':')
+		data = append(data, value...)
+	}
+	data = append(data, '}')
+	_, err := j.w.Write(data)
+	return err
+}
+
+func (j *jsonEntries) end() error {
+	_, err := io.WriteString(j.w, "\n]\n")
+	return err
+}
+
+type csvEntries struct {
+	w *csv.Writer
+}
+
+func (c *csvEntries) begin() error {
+	return c.w.Write(entryColumns)
+}
+
+// write writes one row. Values a spreadsheet would evaluate as a formula,
+// such as a removal reason starting with =, are prefixed with a quote.
+func (c *csvEntries) write(fields []string) error {
+	for i, v := range fields {
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			fields[i] = "'" + v
+		}
+	}
+	return c.w.Write(fields)
+}
+
+func (c *csvEntries) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
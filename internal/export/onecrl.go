@@ -0,0 +1,86 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// oneCRLRecord is a record of Mozilla's OneCRL collection, in the
+// issuer/serial form
+type oneCRLRecord struct {
+	ID           string        `json:"id"`
+	LastModified int64         `json:"last_modified"`
+	Enabled      bool          `json:"enabled"`
+	IssuerName   string        `json:"issuerName"`
+	SerialNumber string        `json:"serialNumber"`
+	Details      oneCRLDetails `json:"details"`
+}
+
+type oneCRLDetails struct {
+	Who     string `json:"who"`
+	Why     string `json:"why"`
+	Name    string `json:"name"`
+	Bug     string `json:"bug"`
+	Created string `json:"created"`
+}
+
+// OneCRLEntry is one revoked certificate of a OneCRL export
+type OneCRLEntry struct {
+	// Issuer is the DER issuer Name of the certificate
+	Issuer []byte
+	Serial *big.Int
+	Reason string
+
+	RevokedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MarshalOneCRL encodes entries as the records of a OneCRL collection,
+// {"data": [...]} as the Remote Settings API lists them
+func MarshalOneCRL(entries []OneCRLEntry) ([]byte, error) {
+	records := make([]oneCRLRecord, 0, len(entries))
+	for _, e := range entries {
+		serialDER := integerContent(e.Serial)
+		records = append(records, oneCRLRecord{
+			ID:           recordID(e.Issuer, serialDER),
+			LastModified: e.UpdatedAt.UnixMilli(),
+			Enabled:      true,
+			IssuerName:   base64.StdEncoding.EncodeToString(e.Issuer),
+			SerialNumber: base64.StdEncoding.EncodeToString(serialDER),
+			Details: oneCRLDetails{
+				Why:     e.Reason,
+				Created: e.RevokedAt.UTC().Format(time.RFC3339),
+			},
+		})
+	}
+	return json.Marshal(struct {
+		Data []oneCRLRecord `json:"data"`
+	}{records})
+}
+
+// integerContent returns the content octets of the DER INTEGER n, which
+// OneCRL matches serials by: the magnitude with a leading zero octet when
+// its high bit is set
+func integerContent(n *big.Int) []byte {
+	b := n.Bytes()
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		b = append([]byte{0}, b...)
+	}
+	return b
+}
+
+// recordID derives a stable UUID for the record of a certificate, so
+// consumers can diff successive exports by ID
+func recordID(issuer, serialDER []byte) string {
+	h := sha256.New()
+	h.Write(issuer)
+	h.Write(serialDER)
+	u := h.Sum(nil)[:16]
+	u[6] = u[6]&0x0f | 0x80 // version 8, custom
+	u[8] = u[8]&0x3f | 0x80 // RFC 9562 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
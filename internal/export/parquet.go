@@ -0,0 +1,245 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"github.com/parquet-go/parquet-go"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultParquetInterval = time.Hour
+	defaultRowsPerFile     = 100000
+
+	// parquetCursor names the cursor of the Parquet export in
+	// export_cursors
+	parquetCursor = "parquet"
+
+	// eventSettle is how old events must be to be exported. Events are
+	// numbered when they are created but may commit out of order, so the
+	// newest ones are left for the next run.
+	eventSettle = time.Minute
+
+	parquetUploadTimeout = 5 * time.Minute
+)
+
+// ParquetConfig configures the scheduled export of the revocation events
+// to Parquet files in an S3 bucket, for analytics outside the database
+type ParquetConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Interval is how often new events are exported (default 1h)
+	Interval time.Duration `yaml:"interval"`
+
+	// RowsPerFile bounds the events of one file (default 100000)
+	RowsPerFile int `yaml:"rows_per_file"`
+
+	S3 ParquetS3Config `yaml:"s3"`
+}
+
+// ParquetS3Config is the bucket the Parquet files are written to.
+// Credentials come from the default AWS chain.
+type ParquetS3Config struct {
+	Region string `yaml:"region"`
+	Bucket string `yaml:"bucket"`
+	Prefix string `yaml:"prefix"` // e.g. warehouse/crl/
+
+	// Endpoint and PathStyle support S3-compatible stores (MinIO, Ceph)
+	Endpoint  string `yaml:"endpoint"`
+	PathStyle bool   `yaml:"path_style"`
+}
+
+// Validate applies defaults and checks the Parquet export config
+func (c *ParquetConfig) Validate() error {
+	if c.Interval < 0 || c.RowsPerFile < 0 {
+		return errors.New("interval and rows_per_file must not be negative")
+	}
+	if c.Enabled && c.S3.Bucket == "" {
+		return errors.New("s3.bucket is required")
+	}
+	if c.Interval == 0 {
+		c.Interval = defaultParquetInterval
+	}
+	if c.RowsPerFile == 0 {
+		c.RowsPerFile = defaultRowsPerFile
+	}
+	return nil
+}
+
+// eventRow is the Parquet schema of a revocation event
+type eventRow struct {
+	Sequence          int64      `parquet:"sequence"`
+	IssuerID          string     `parquet:"issuer_id"`
+	Serial            string     `parquet:"serial"`
+	Status            string     `parquet:"status"`
+	Reason            string     `parquet:"reason"`
+	RevokedAt         time.Time  `parquet:"revoked_at,timestamp(microsecond)"`
+	InvalidityDate    *time.Time `parquet:"invalidity_date,timestamp(microsecond)"`
+	CertificateIssuer []byte     `parquet:"certificate_issuer,optional"`
+	CreatedAt         time.Time  `parquet:"created_at,timestamp(microsecond)"`
+	EffectiveAt       *time.Time `parquet:"effective_at,timestamp(microsecond)"`
+}
+
+// ParquetExporter appends the revocation events created since its last
+// run to the bucket every interval
+type ParquetExporter struct {
+	store  *store.Store
+	cfg    ParquetConfig
+	client *s3.Client
+	logger *logger.Logger
+}
+
+// NewParquetExporter creates the exporter of the revocation events
+func NewParquetExporter(ctx context.Context, st *store.Store, cfg ParquetConfig) (*ParquetExporter, error) {
+	var opts []func(*awsconfig.LoadOptions) error
+	if cfg.S3.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(cfg.S3.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.S3.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.S3.Endpoint)
+		}
+		o.UsePathStyle = cfg.S3.PathStyle
+	})
+
+	return &ParquetExporter{
+		store:  st,
+		cfg:    cfg,
+		client: client,
+		logger: logger.Global(),
+	}, nil
+}
+
+// Run exports new events every interval until ctx is done
+func (p *ParquetExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.export(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// export writes the settled events after the cursor, unless another
+// replica is exporting them
+func (p *ParquetExporter) export(ctx context.Context) {
+	until, err := p.store.EventSequenceBefore(ctx, time.Now().Add(-eventSettle))
+	if err != nil {
+		p.logger.Error("Failed to read revocation events for export", zap.Error(err))
+		return
+	}
+
+	err = p.store.AdvanceExportCursor(ctx, parquetCursor, func(after int64) (int64, error) {
+		return p.exportRange(ctx, after, until)
+	})
+	switch {
+	case errors.Is(err, store.ErrCursorBusy):
+		p.logger.Debug("Parquet export is running on another replica")
+	case err != nil:
+		p.logger.Error("Failed to export revocation events to Parquet", zap.Error(err))
+	}
+}
+
+// exportRange writes the events after sequence after up to until, one file
+// per day of creation and at most RowsPerFile events, and returns the
+// sequence of the last event written
+func (p *ParquetExporter) exportRange(ctx context.Context, after, until int64) (int64, error) {
+	for after < until {
+		events, err := p.store.ListEvents(ctx, after, p.cfg.RowsPerFile)
+		if err != nil {
+			return after, err
+		}
+		for len(events) > 0 && events[len(events)-1].Sequence > until {
+			events = events[:len(events)-1]
+		}
+		if len(events) == 0 {
+			return after, nil
+		}
+
+		for len(events) > 0 {
+			day := events[0].CreatedAt.UTC().Format(time.DateOnly)
+			n := 1
+			for n < len(events) && events[n].CreatedAt.UTC().Format(time.DateOnly) == day {
+				n++
+			}
+			if err := p.upload(ctx, day, events[:n]); err != nil {
+				return after, err
+			}
+			after = events[n-1].Sequence
+			events = events[n:]
+		}
+	}
+	return after, nil
+}
+
+// upload writes events as one Parquet file named after the day they were
+// created and their sequence range, so a warehouse can partition by date
+func (p *ParquetExporter) upload(ctx context.Context, day string, events []store.Event) error {
+	rows := make([]eventRow, len(events))
+	for i, e := range events {
+		rows[i] = eventRow{
+			Sequence:          e.Sequence,
+			IssuerID:          e.IssuerID,
+			Serial:            e.Serial,
+			Status:            e.Status,
+			Reason:            e.Reason,
+			RevokedAt:         e.RevokedAt,
+			InvalidityDate:    e.InvalidityDate,
+			CertificateIssuer: e.CertificateIssuer,
+			CreatedAt:         e.CreatedAt,
+			EffectiveAt:       e.EffectiveAt,
+		}
+	}
+
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[eventRow](&buf, parquet.Compression(&parquet.Zstd))
+	if _, err := w.Write(rows); err != nil {
+		return fmt.Errorf("failed to encode Parquet: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to encode Parquet: %w", err)
+	}
+
+	key := fmt.Sprintf("%srevocation_events/date=%s/%020d-%020d.parquet",
+		p.cfg.S3.Prefix, day, events[0].Sequence, events[len(events)-1].Sequence)
+	uploadCtx, cancel := context.WithTimeout(ctx, parquetUploadTimeout)
+	defer cancel()
+	_, err := p.client.PutObject(uploadCtx, &s3.PutObjectInput{
+		Bucket:        aws.String(p.cfg.S3.Bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(buf.Bytes()),
+		ContentLength: aws.Int64(int64(buf.Len())),
+		ContentType:   aws.String("application/vnd.apache.parquet"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload s3://%s/%s: %w", p.cfg.S3.Bucket, key, err)
+	}
+	metrics.EventsExported.Add(float64(len(events)))
+
+	p.logger.Info("Exported revocation events to Parquet",
+		zap.String("object", key),
+		zap.Int64("first_sequence", events[0].Sequence),
+		zap.Int64("last_sequence", events[len(events)-1].Sequence),
+		zap.Int("bytes", buf.Len()),
+	)
+	return nil
+}
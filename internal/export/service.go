@@ -0,0 +1,307 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/internal/ca"
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/serial"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const defaultInterval = time.Hour
+
+// Names of the exports, as served by the distribution point
+const (
+	NameCRLite = "crlite"
+	NameCRLSet = "crlset"
+	NameOneCRL = "onecrl"
+)
+
+// Content types of the exports
+const (
+	contentTypeCRLite = "application/octet-stream"
+	contentTypeCRLSet = "application/octet-stream"
+	contentTypeOneCRL = "application/json"
+)
+
+// Config selects the alternative encodings of the revocation set served
+// next to the CRLs
+type Config struct {
+	// Interval is how often the exports are rebuilt (default 1h)
+	Interval time.Duration `yaml:"interval"`
+
+	// CRLite builds a filter cascade of each issuer's revocation set
+	CRLite CRLiteConfig `yaml:"crlite"`
+
+	// CRLSet encodes each issuer's revoked serials as a Chrome CRLSet
+	CRLSet CRLSetConfig `yaml:"crlset"`
+
+	// OneCRL lists each issuer's revoked certificates as Mozilla OneCRL
+	// records
+	OneCRL OneCRLConfig `yaml:"onecrl"`
+
+	// Parquet writes the revocation events to an object store on a
+	// schedule of its own
+	Parquet ParquetConfig `yaml:"parquet"`
+}
+
+// CRLiteConfig configures the CRLite filter cascade export. It needs the
+// CA service (ca.address) to list the valid certificates.
+type CRLiteConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// CRLSetConfig configures the Chrome CRLSet export
+type CRLSetConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// OneCRLConfig configures the OneCRL JSON export
+type OneCRLConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Validate applies defaults and checks the export config
+func (c *Config) Validate() error {
+	if c.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	if c.Interval == 0 {
+		c.Interval = defaultInterval
+	}
+	if err := c.Parquet.Validate(); err != nil {
+		return fmt.Errorf("parquet: %w", err)
+	}
+	return nil
+}
+
+// Enabled reports whether any export served by the distribution point is
+// built
+func (c Config) Enabled() bool {
+	return c.CRLite.Enabled || c.CRLSet.Enabled || c.OneCRL.Enabled
+}
+
+// Artifact is one built export
+type Artifact struct {
+	Data        []byte
+	ContentType string
+	SHA256      [sha256.Size]byte
+	BuiltAt     time.Time
+}
+
+// Service rebuilds the exports every interval and keeps the latest in
+// memory for the distribution point
+type Service struct {
+	store   *store.Store
+	issuers *generator.Registry
+	ca      *ca.Client
+	cfg     Config
+	logger  *logger.Logger
+
+	mu        sync.RWMutex
+	artifacts map[string]*Artifact // by issuer ID and export name
+}
+
+// NewService creates the export service. caClient may be nil unless the
+// CRLite export is enabled.
+func NewService(st *store.Store, issuers *generator.Registry, caClient *ca.Client, cfg Config) *Service {
+	return &Service{
+		store:     st,
+		issuers:   issuers,
+		ca:        caClient,
+		cfg:       cfg,
+		logger:    logger.Global(),
+		artifacts: make(map[string]*Artifact),
+	}
+}
+
+func artifactKey(issuerID, name string) string {
+	return issuerID + "/" + name
+}
+
+// Interval returns how often the exports are rebuilt
+func (s *Service) Interval() time.Duration {
+	return s.cfg.Interval
+}
+
+// Get returns the latest export of an issuer, or false if it is disabled
+// or not built yet
+func (s *Service) Get(issuerID, name string) (*Artifact, bool) {
+	if s == nil {
+		return nil, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	a, ok := s.artifacts[artifactKey(issuerID, name)]
+	return a, ok
+}
+
+func (s *Service) put(issuerID, name, contentType string, data []byte) {
+	a := &Artifact{
+		Data:        data,
+		ContentType: contentType,
+		SHA256:      sha256.Sum256(data),
+		BuiltAt:     time.Now(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.artifacts[artifactKey(issuerID, name)] = a
+}
+
+// Run builds the exports every interval until ctx is done
+func (s *Service) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.build(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// build rebuilds every enabled export of every issuer. An export that
+// fails keeps its previous version.
+func (s *Service) build(ctx context.Context) {
+	var valid []string
+	if s.cfg.CRLite.Enabled {
+		var err error
+		valid, err = s.ca.ValidSerials(ctx)
+		if err != nil {
+			s.logger.Error("Failed to list valid certificates for CRLite", zap.Error(err))
+		}
+	}
+
+	for _, gen := range s.issuers.All() {
+		entries, err := s.store.ListEntries(ctx, gen.ID())
+		if err != nil {
+			s.logger.Error("Failed to read revocations for export", zap.String("issuer_id", gen.ID()), zap.Error(err))
+			continue
+		}
+		if s.cfg.CRLite.Enabled && valid != nil {
+			if err := s.buildCRLite(gen, entries, valid); err != nil {
+				s.logger.Error("Failed to build CRLite filter cascade", zap.String("issuer_id", gen.ID()), zap.Error(err))
+			}
+		}
+		if s.cfg.CRLSet.Enabled {
+			if err := s.buildCRLSet(gen, entries); err != nil {
+				s.logger.Error("Failed to build CRLSet", zap.String("issuer_id", gen.ID()), zap.Error(err))
+			}
+		}
+		if s.cfg.OneCRL.Enabled {
+			if err := s.buildOneCRL(gen, entries); err != nil {
+				s.logger.Error("Failed to build OneCRL records", zap.String("issuer_id", gen.ID()), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (s *Service) buildCRLite(gen *generator.Generator, entries []store.Entry, valid []string) error {
+	coverage := time.Now()
+	revokedSet := make(map[string]bool, len(entries))
+	revoked := make([][]byte, 0, len(entries))
+	for _, e := range entries {
+		if e.Number == nil {
+			continue
+		}
+		revokedSet[e.Serial] = true
+		revoked = append(revoked, serial.Bytes(e.Number))
+	}
+	validKeys := make([][]byte, 0, len(valid))
+	for _, v := range valid {
+		// The CA may not have caught up with a revocation yet
+		if revokedSet[v] {
+			continue
+		}
+		validKeys = append(validKeys, serial.Key(v))
+	}
+
+	cascade, err := BuildCascade(revoked, validKeys)
+	if err != nil {
+		return err
+	}
+	data, err := cascade.MarshalBinary(gen.Issuer().SubjectKeyId, coverage)
+	if err != nil {
+		return err
+	}
+	s.put(gen.ID(), NameCRLite, contentTypeCRLite, data)
+
+	s.logger.Info("Built CRLite filter cascade",
+		zap.String("issuer_id", gen.ID()),
+		zap.Int("revoked", len(revoked)),
+		zap.Int("valid", len(validKeys)),
+		zap.Int("levels", cascade.Levels()),
+		zap.Int("bytes", len(data)),
+	)
+	return nil
+}
+
+func (s *Service) buildCRLSet(gen *generator.Generator, entries []store.Entry) error {
+	serials := make([][]byte, 0, len(entries))
+	for _, e := range entries {
+		// Entries of indirect CRLs belong to another parent, whose public
+		// key is not known
+		if e.Number == nil || e.CertificateIssuer != nil {
+			continue
+		}
+		serials = append(serials, serial.Bytes(e.Number))
+	}
+
+	spkiHash := sha256.Sum256(gen.Issuer().RawSubjectPublicKeyInfo)
+	data, err := MarshalCRLSet(spkiHash, serials, uint64(time.Now().Unix()))
+	if err != nil {
+		return err
+	}
+	s.put(gen.ID(), NameCRLSet, contentTypeCRLSet, data)
+
+	s.logger.Info("Built CRLSet",
+		zap.String("issuer_id", gen.ID()),
+		zap.Int("serials", len(serials)),
+		zap.Int("bytes", len(data)),
+	)
+	return nil
+}
+
+func (s *Service) buildOneCRL(gen *generator.Generator, entries []store.Entry) error {
+	records := make([]OneCRLEntry, 0, len(entries))
+	for _, e := range entries {
+		if e.Number == nil {
+			continue
+		}
+		issuer := e.CertificateIssuer
+		if issuer == nil {
+			issuer = gen.Issuer().RawSubject
+		}
+		records = append(records, OneCRLEntry{
+			Issuer:    issuer,
+			Serial:    e.Number,
+			Reason:    e.Reason,
+			RevokedAt: e.RevokedAt,
+			UpdatedAt: e.UpdatedAt,
+		})
+	}
+
+	data, err := MarshalOneCRL(records)
+	if err != nil {
+		return err
+	}
+	s.put(gen.ID(), NameOneCRL, contentTypeOneCRL, data)
+
+	s.logger.Info("Built OneCRL records",
+		zap.String("issuer_id", gen.ID()),
+		zap.Int("records", len(records)),
+		zap.Int("bytes", len(data)),
+	)
+	return nil
+}
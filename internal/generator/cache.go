@@ -0,0 +1,99 @@
+package generator
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// cacheKey identifies a cached CRL: a full CRL partition or the delta CRL
+type cacheKey struct {
+	partition int
+	delta     bool
+}
+
+// cachedCRL is a signed CRL served until refreshAt or until the entries
+// change
+type cachedCRL struct {
+	list      *CRL
+	refreshAt time.Time
+	version   uint64
+}
+
+// Latest returns a full CRL of a partition reflecting every stored
+// revocation. It is signed once and served from memory until an entry
+// changes (see Invalidate), a scheduled revocation takes effect, the
+// schedule's lead time before nextUpdate is reached or crl.cache_ttl
+// passes.
+func (g *Generator) Latest(ctx context.Context, partition int) (*CRL, error) {
+	return g.cached(ctx, cacheKey{partition: partition}, func() (*CRL, error) {
+		return g.Generate(ctx, partition)
+	})
+}
+
+// LatestDelta is Latest for the delta CRL against the last published full
+// CRL
+func (g *Generator) LatestDelta(ctx context.Context) (*CRL, error) {
+	return g.cached(ctx, cacheKey{delta: true}, func() (*CRL, error) {
+		return g.GenerateDelta(ctx)
+	})
+}
+
+// Invalidate drops the CRLs cached by Latest and LatestDelta, so the next
+// call signs a new one. It is called whenever an entry of the issuer
+// changes and does not wait for CRLs being signed.
+func (g *Generator) Invalidate() {
+	g.cacheVersion.Add(1)
+}
+
+func (g *Generator) cached(ctx context.Context, key cacheKey, generate func() (*CRL, error)) (*CRL, error) {
+	g.cacheMu.Lock()
+	defer g.cacheMu.Unlock()
+
+	version := g.cacheVersion.Load()
+	if c, ok := g.cache[key]; ok && c.version == version && time.Now().Before(c.refreshAt) {
+		return c.list, nil
+	}
+
+	list, err := generate()
+	if err != nil {
+		return nil, err
+	}
+
+	refreshAt, err := g.refreshAt(ctx, list)
+	if err != nil {
+		g.logger.Warn("Not caching CRL", zap.String("issuer_id", g.id), zap.Error(err))
+		delete(g.cache, key)
+		return list, nil
+	}
+	// An entry changed while signing may be missing from list; it is
+	// served once and replaced on the next call
+	g.cache[key] = &cachedCRL{list: list, refreshAt: refreshAt, version: version}
+
+	return list, nil
+}
+
+// refreshAt returns when a cached CRL must be signed again regardless of
+// entry changes
+func (g *Generator) refreshAt(ctx context.Context, list *CRL) (time.Time, error) {
+	cfg := g.config()
+	now := time.Now()
+
+	refresh := list.NextUpdate
+	if lead := cfg.Schedule.LeadTime; lead > 0 && list.NextUpdate.Add(-lead).After(now) {
+		refresh = list.NextUpdate.Add(-lead)
+	}
+	if cfg.CacheTTL > 0 && now.Add(cfg.CacheTTL).Before(refresh) {
+		refresh = now.Add(cfg.CacheTTL)
+	}
+
+	next, err := g.store.NextEffectiveAt(ctx, g.id)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !next.IsZero() && next.Before(refresh) {
+		refresh = next
+	}
+	return refresh, nil
+}
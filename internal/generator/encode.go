@@ -0,0 +1,449 @@
+package generator
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// OIDs the encoder writes itself, as x509.CreateRevocationList does
+var (
+	oidAuthorityKeyID = asn1.ObjectIdentifier{2, 5, 29, 35}
+	oidCRLNumber      = asn1.ObjectIdentifier{2, 5, 29, 20}
+	oidReasonCode     = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+	oidEd25519         = asn1.ObjectIdentifier{1, 3, 101, 112}
+)
+
+// maxHeaderLen is the longest DER tag and length this encoder writes
+const maxHeaderLen = 6
+
+// crlEncoder encodes the revoked certificates of a CRL into temporary
+// files as they arrive, so that signing a CRL over millions of entries
+// holds no more than the finished CRL in memory. The encoding is the one
+// x509.CreateRevocationList produces.
+type crlEncoder struct {
+	// groups holds the entries by certificate issuer, the CRL issuer's
+	// own under "". Without indirect CRLs there is only that group.
+	groups map[string]*entryGroup
+	count  int
+
+	// dir holds the temporary files, the system's temporary directory
+	// when empty
+	dir string
+}
+
+// entryGroup is the encoded entries of one certificate issuer. Its first
+// entry is kept apart until the groups are ordered: only then is it known
+// whether it needs the CertificateIssuer extension.
+type entryGroup struct {
+	issuer []byte
+	first  *x509.RevocationListEntry
+	file   *os.File
+	w      *bufio.Writer
+	size   int
+}
+
+func newCRLEncoder(dir string) *crlEncoder {
+	return &crlEncoder{groups: make(map[string]*entryGroup), dir: dir}
+}
+
+// add appends an entry of a certificate issued by certIssuer, a DER Name,
+// or by the CRL issuer when nil
+func (enc *crlEncoder) add(entry x509.RevocationListEntry, certIssuer []byte) error {
+	if entry.SerialNumber == nil {
+		return errors.New("CRL entry has no serial number")
+	}
+	if entry.RevocationTime.IsZero() {
+		return errors.New("CRL entry has no revocation time")
+	}
+	enc.count++
+
+	group, ok := enc.groups[string(certIssuer)]
+	if !ok {
+		enc.groups[string(certIssuer)] = &entryGroup{issuer: certIssuer, first: &entry}
+		return nil
+	}
+	if group.file == nil {
+		f, err := os.CreateTemp(enc.dir, "crl-entries-*")
+		if err != nil {
+			return fmt.Errorf("failed to create CRL entry file: %w", err)
+		}
+		group.file, group.w = f, bufio.NewWriterSize(f, 1<<16)
+	}
+	der, err := encodeEntry(&entry)
+	if err != nil {
+		return err
+	}
+	if _, err := group.w.Write(der); err != nil {
+		return fmt.Errorf("failed to write CRL entry: %w", err)
+	}
+	group.size += len(der)
+	return nil
+}
+
+// close removes the temporary files
+func (enc *crlEncoder) close() {
+	for _, group := range enc.groups {
+		if group.file != nil {
+			group.file.Close()
+			os.Remove(group.file.Name())
+		}
+	}
+}
+
+// sign encodes and signs the CRL like x509.CreateRevocationList does with
+// the template's other fields and the entries added. The issuer's entries
+// come first, then those of other certificate issuers grouped by issuer,
+// each group after a CertificateIssuer extension.
+func (enc *crlEncoder) sign(template *x509.RevocationList, issuer *x509.Certificate, priv crypto.Signer) ([]byte, error) {
+	if issuer.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		return nil, errors.New("issuer must have the crlSign key usage bit set")
+	}
+	if len(issuer.SubjectKeyId) == 0 {
+		return nil, errors.New("issuer certificate doesn't contain a subject key identifier")
+	}
+	if template.NextUpdate.Before(template.ThisUpdate) {
+		return nil, errors.New("thisUpdate is after nextUpdate")
+	}
+	if b := template.Number.Bytes(); len(b) > 20 || (len(b) == 20 && b[0]&0x80 != 0) {
+		return nil, errors.New("CRL number exceeds 20 octets")
+	}
+	alg, err := signatureAlgorithm(priv.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	// The first entries, and with them the extensions, follow the order
+	// of the groups
+	keys := make([]string, 0, len(enc.groups))
+	for key := range enc.groups {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	firsts := make([][]byte, len(keys))
+	current := issuer.RawSubject
+	revokedLen := 0
+	for i, key := range keys {
+		group := enc.groups[key]
+		entryIssuer := group.issuer
+		if len(entryIssuer) == 0 {
+			entryIssuer = issuer.RawSubject
+		}
+		first := *group.first
+		if !bytes.Equal(entryIssuer, current) {
+			ext, err := certificateIssuerExtension(entryIssuer)
+			if err != nil {
+				return nil, err
+			}
+			first.ExtraExtensions = append(slices.Clip(first.ExtraExtensions), ext)
+			current = entryIssuer
+		}
+		if firsts[i], err = encodeEntry(&first); err != nil {
+			return nil, err
+		}
+		revokedLen += len(firsts[i]) + group.size
+		if group.w != nil {
+			if err := group.w.Flush(); err != nil {
+				return nil, fmt.Errorf("failed to write CRL entry: %w", err)
+			}
+		}
+	}
+
+	head, err := encodeTBSHead(template, issuer, alg)
+	if err != nil {
+		return nil, err
+	}
+	tail, err := encodeTBSExtensions(template, issuer)
+	if err != nil {
+		return nil, err
+	}
+	tbsLen := len(head) + len(tail)
+	if enc.count > 0 {
+		tbsLen += headerLen(revokedLen) + revokedLen
+	}
+
+	// The CRL is written once, into a buffer leaving room in front for the
+	// outer header whose length is known only after signing
+	buf := make([]byte, maxHeaderLen, maxHeaderLen+headerLen(tbsLen)+tbsLen+len(alg.id)+1024)
+	buf = appendHeader(buf, cbasn1.SEQUENCE, tbsLen)
+	buf = append(buf, head...)
+	if enc.count > 0 {
+		buf = appendHeader(buf, cbasn1.SEQUENCE, revokedLen)
+		for i, key := range keys {
+			buf = append(buf, firsts[i]...)
+			if buf, err = enc.groups[key].appendTo(buf); err != nil {
+				return nil, err
+			}
+		}
+	}
+	buf = append(buf, tail...)
+	tbs := buf[maxHeaderLen:]
+
+	signature, err := alg.sign(priv, tbs)
+	if err != nil {
+		return nil, err
+	}
+	var b cryptobyte.Builder
+	b.AddBytes(alg.id)
+	b.AddASN1BitString(signature)
+	trailer, err := b.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CRL signature: %w", err)
+	}
+
+	outerLen := len(tbs) + len(trailer)
+	start := maxHeaderLen - headerLen(outerLen)
+	appendHeader(buf[start:start], cbasn1.SEQUENCE, outerLen)
+	return append(buf, trailer...)[start:], nil
+}
+
+// appendTo appends the entries written to the group's file
+func (group *entryGroup) appendTo(buf []byte) ([]byte, error) {
+	if group.file == nil {
+		return buf, nil
+	}
+	if _, err := group.file.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read CRL entries: %w", err)
+	}
+	n := len(buf)
+	buf = buf[:n+group.size]
+	if _, err := io.ReadFull(group.file, buf[n:]); err != nil {
+		return nil, fmt.Errorf("failed to read CRL entries: %w", err)
+	}
+	return buf, nil
+}
+
+// encodeEntry encodes a revokedCertificates element, with the reason code
+// extension after the others
+func encodeEntry(entry *x509.RevocationListEntry) ([]byte, error) {
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(entry.SerialNumber)
+		addTime(b, entry.RevocationTime)
+		if len(entry.ExtraExtensions) == 0 && entry.ReasonCode == 0 {
+			return
+		}
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			for _, ext := range entry.ExtraExtensions {
+				if ext.Id.Equal(oidReasonCode) {
+					b.SetError(errors.New("CRL entry has a reason code extension"))
+					return
+				}
+				addExtension(b, ext)
+			}
+			if entry.ReasonCode != 0 {
+				var value cryptobyte.Builder
+				value.AddASN1Enum(int64(entry.ReasonCode))
+				addExtension(b, pkix.Extension{Id: oidReasonCode, Value: value.BytesOrPanic()})
+			}
+		})
+	})
+	der, err := b.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CRL entry: %w", err)
+	}
+	return der, nil
+}
+
+// encodeTBSHead encodes the tbsCertList fields before revokedCertificates
+func encodeTBSHead(template *x509.RevocationList, issuer *x509.Certificate, alg signingAlgorithm) ([]byte, error) {
+	var b cryptobyte.Builder
+	b.AddASN1Int64(1) // v2
+	b.AddBytes(alg.id)
+	b.AddBytes(issuer.RawSubject)
+	addTime(&b, template.ThisUpdate)
+	addTime(&b, template.NextUpdate)
+	der, err := b.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CRL: %w", err)
+	}
+	return der, nil
+}
+
+// encodeTBSExtensions encodes the crlExtensions: authority key identifier,
+// CRL number, then the template's extra extensions
+func encodeTBSExtensions(template *x509.RevocationList, issuer *x509.Certificate) ([]byte, error) {
+	var aki cryptobyte.Builder
+	aki.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		// keyIdentifier [0] IMPLICIT OCTET STRING
+		b.AddASN1(cbasn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddBytes(issuer.SubjectKeyId)
+		})
+	})
+	akiDER, err := aki.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode authority key identifier: %w", err)
+	}
+
+	var number cryptobyte.Builder
+	number.AddASN1BigInt(template.Number)
+	numberDER, err := number.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CRL number: %w", err)
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.Tag(0).ContextSpecific().Constructed(), func(b *cryptobyte.Builder) {
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			addExtension(b, pkix.Extension{Id: oidAuthorityKeyID, Value: akiDER})
+			addExtension(b, pkix.Extension{Id: oidCRLNumber, Value: numberDER})
+			for _, ext := range template.ExtraExtensions {
+				addExtension(b, ext)
+			}
+		})
+	})
+	der, err := b.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CRL extensions: %w", err)
+	}
+	return der, nil
+}
+
+func addExtension(b *cryptobyte.Builder, ext pkix.Extension) {
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(ext.Id)
+		if ext.Critical {
+			b.AddASN1Boolean(true)
+		}
+		b.AddASN1OctetString(ext.Value)
+	})
+}
+
+// addTime encodes t in UTC as UTCTime through 2049 and GeneralizedTime
+// from 2050, as RFC 5280 requires
+func addTime(b *cryptobyte.Builder, t time.Time) {
+	t = t.UTC()
+	if t.Year() >= 1950 && t.Year() < 2050 {
+		b.AddASN1UTCTime(t)
+		return
+	}
+	b.AddASN1GeneralizedTime(t)
+}
+
+// headerLen is the length of the DER tag and length of n content octets
+func headerLen(n int) int {
+	switch {
+	case n < 0x80:
+		return 2
+	case n <= 0xff:
+		return 3
+	case n <= 0xffff:
+		return 4
+	case n <= 0xffffff:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// appendHeader appends a DER tag and the length of n content octets
+func appendHeader(buf []byte, tag cbasn1.Tag, n int) []byte {
+	buf = append(buf, byte(tag))
+	if n < 0x80 {
+		return append(buf, byte(n))
+	}
+	octets := headerLen(n) - 2
+	buf = append(buf, 0x80|byte(octets))
+	for i := octets - 1; i >= 0; i-- {
+		buf = append(buf, byte(n>>(8*i)))
+	}
+	return buf
+}
+
+// signingAlgorithm is the signature algorithm x509.CreateRevocationList
+// picks for a key by default
+type signingAlgorithm struct {
+	id   []byte // DER AlgorithmIdentifier
+	hash crypto.Hash
+	pub  crypto.PublicKey
+}
+
+func signatureAlgorithm(pub crypto.PublicKey) (signingAlgorithm, error) {
+	var oid asn1.ObjectIdentifier
+	var hash crypto.Hash
+	nullParams := false
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		oid, hash, nullParams = oidSHA256WithRSA, crypto.SHA256, true
+	case *ecdsa.PublicKey:
+		switch pub.Curve {
+		case elliptic.P224(), elliptic.P256():
+			oid, hash = oidECDSAWithSHA256, crypto.SHA256
+		case elliptic.P384():
+			oid, hash = oidECDSAWithSHA384, crypto.SHA384
+		case elliptic.P521():
+			oid, hash = oidECDSAWithSHA512, crypto.SHA512
+		default:
+			return signingAlgorithm{}, errors.New("unsupported elliptic curve")
+		}
+	case ed25519.PublicKey:
+		oid = oidEd25519
+	default:
+		return signingAlgorithm{}, fmt.Errorf("unsupported signing key type %T", pub)
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(oid)
+		if nullParams {
+			b.AddASN1NULL()
+		}
+	})
+	id, err := b.Bytes()
+	if err != nil {
+		return signingAlgorithm{}, fmt.Errorf("failed to encode signature algorithm: %w", err)
+	}
+	return signingAlgorithm{id: id, hash: hash, pub: pub}, nil
+}
+
+// sign signs tbs and checks the signature, so that a misbehaving signer
+// backend cannot produce a CRL that does not verify
+func (alg signingAlgorithm) sign(priv crypto.Signer, tbs []byte) ([]byte, error) {
+	// Ed25519 signs the message itself
+	message := tbs
+	if alg.hash != 0 {
+		h := alg.hash.New()
+		h.Write(tbs)
+		message = h.Sum(nil)
+	}
+	signature, err := priv.Sign(rand.Reader, message, alg.hash)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	switch pub := alg.pub.(type) {
+	case *rsa.PublicKey:
+		ok = rsa.VerifyPKCS1v15(pub, alg.hash, message, signature) == nil
+	case *ecdsa.PublicKey:
+		ok = ecdsa.VerifyASN1(pub, message, signature)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(pub, message, signature)
+	}
+	if !ok {
+		return nil, errors.New("signature returned by signer is invalid")
+	}
+	return signature, nil
+}
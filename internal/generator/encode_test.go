@@ -0,0 +1,432 @@
+package generator
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gigvault/crl/internal/testutil"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// testEntry is an entry added to the encoder, certIssuer nil for the CRL
+// issuer's own certificates
+type testEntry struct {
+	entry      x509.RevocationListEntry
+	certIssuer []byte
+}
+
+type testKey struct {
+	name string
+	priv crypto.Signer
+	// deterministic signatures make the whole CRL comparable, not only
+	// the TBS
+	deterministic bool
+}
+
+var testKeys = sync.OnceValue(func() []testKey {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	ecKey := func(curve elliptic.Curve) crypto.Signer {
+		k, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			panic(err)
+		}
+		return k
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	return []testKey{
+		{name: "RSA", priv: rsaKey, deterministic: true},
+		{name: "P-256", priv: ecKey(elliptic.P256())},
+		{name: "P-384", priv: ecKey(elliptic.P384())},
+		{name: "P-521", priv: ecKey(elliptic.P521())},
+		{name: "Ed25519", priv: edKey, deterministic: true},
+	}
+})
+
+func testName(t *testing.T, cn string) []byte {
+	t.Helper()
+	der, err := asn1.Marshal(pkix.Name{CommonName: cn}.ToRDNSequence())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// referenceEntries orders the entries as the encoder does, the issuer's
+// own first and then by certificate issuer, and adds the CertificateIssuer
+// extensions for x509.CreateRevocationList
+func referenceEntries(t *testing.T, issuer *x509.Certificate, entries []testEntry) []x509.RevocationListEntry {
+	t.Helper()
+	sorted := slices.Clone(entries)
+	slices.SortStableFunc(sorted, func(a, b testEntry) int {
+		return bytes.Compare(a.certIssuer, b.certIssuer)
+	})
+	var out []x509.RevocationListEntry
+	current := issuer.RawSubject
+	for _, e := range sorted {
+		entry := e.entry
+		entryIssuer := e.certIssuer
+		if len(entryIssuer) == 0 {
+			entryIssuer = issuer.RawSubject
+		}
+		if !bytes.Equal(entryIssuer, current) {
+			ext, err := certificateIssuerExtension(entryIssuer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entry.ExtraExtensions = append(slices.Clip(entry.ExtraExtensions), ext)
+			current = entryIssuer
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func revoked(serial int64, at time.Time, reason int, exts ...pkix.Extension) x509.RevocationListEntry {
+	return x509.RevocationListEntry{
+		SerialNumber:    big.NewInt(serial),
+		RevocationTime:  at,
+		ReasonCode:      reason,
+		ExtraExtensions: exts,
+	}
+}
+
+// padding is an opaque entry extension of n value octets
+func padding(n int) pkix.Extension {
+	return pkix.Extension{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 1}, Value: make([]byte, n)}
+}
+
+// boundaryEntries returns entries whose encodings have content lengths just
+// below, at and above the points where DER lengths take another octet
+func boundaryEntries(t *testing.T, at time.Time) []testEntry {
+	t.Helper()
+	var entries []testEntry
+	serial := int64(1000)
+	for _, limit := range []int{0x7f, 0xff, 0xffff} {
+		for pad := max(0, limit-64); pad <= limit+8; pad++ {
+			entry := revoked(serial, at, 1, padding(pad))
+			der, err := encodeEntry(&entry)
+			if err != nil {
+				t.Fatal(err)
+			}
+			input, content := cryptobyte.String(der), cryptobyte.String(nil)
+			if !input.ReadASN1(&content, cbasn1.SEQUENCE) {
+				t.Fatalf("cannot parse entry %x", der)
+			}
+			if n := len(content); n >= limit-1 && n <= limit+2 {
+				entries = append(entries, testEntry{entry: entry})
+				serial++
+			}
+		}
+	}
+	return entries
+}
+
+func TestCRLEncoderMatchesCreateRevocationList(t *testing.T) {
+	thisUpdate := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	nextUpdate := thisUpdate.Add(7 * 24 * time.Hour)
+	revokedAt := time.Date(2025, 2, 1, 8, 30, 15, 0, time.UTC)
+	other := testName(t, "Other CA")
+	another := testName(t, "Another CA")
+
+	ext := func(e pkix.Extension, err error) pkix.Extension {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return e
+	}
+	invalidity := ext(invalidityDateExtension(revokedAt.Add(-time.Hour)))
+
+	highBit := new(big.Int).SetBytes(bytes.Repeat([]byte{0xff}, 20))
+	highBit.Rsh(highBit, 1) // largest 20-octet serial
+
+	many := make([]testEntry, 3000)
+	for i := range many {
+		many[i] = testEntry{entry: revoked(int64(i+1), revokedAt.Add(time.Duration(i)*time.Second), i%3)}
+		if i%2 == 1 {
+			many[i].certIssuer = other
+		}
+	}
+
+	tests := []struct {
+		name       string
+		thisUpdate time.Time
+		nextUpdate time.Time
+		entries    []testEntry
+		extensions []pkix.Extension
+	}{
+		{name: "empty"},
+		{
+			name:    "single entry",
+			entries: []testEntry{{entry: revoked(1, revokedAt, 0)}},
+		},
+		{
+			name: "serials",
+			entries: []testEntry{
+				{entry: revoked(0, revokedAt, 0)},
+				{entry: revoked(0x80, revokedAt, 0)},
+				{entry: x509.RevocationListEntry{SerialNumber: highBit, RevocationTime: revokedAt}},
+				{entry: x509.RevocationListEntry{SerialNumber: new(big.Int).Lsh(big.NewInt(1), 159), RevocationTime: revokedAt}},
+			},
+		},
+		{
+			name: "reasons and invalidity dates",
+			entries: []testEntry{
+				{entry: revoked(1, revokedAt, 1, invalidity)},
+				{entry: revoked(2, revokedAt, 2)},
+				{entry: revoked(3, revokedAt, 3, invalidity)},
+				{entry: revoked(4, revokedAt, 4)},
+				{entry: revoked(5, revokedAt, 5)},
+				{entry: revoked(6, revokedAt, 6)},
+				{entry: revoked(8, revokedAt, 8)},
+				{entry: revoked(9, revokedAt, 9)},
+				{entry: revoked(10, revokedAt, 10)},
+				{entry: revoked(11, revokedAt, 0, invalidity)},
+			},
+		},
+		{
+			name:       "generalized time",
+			thisUpdate: time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC),
+			nextUpdate: time.Date(2051, 6, 1, 0, 0, 0, 0, time.UTC),
+			entries: []testEntry{
+				{entry: revoked(1, time.Date(2049, 12, 31, 23, 59, 59, 0, time.UTC), 1)},
+				{entry: revoked(2, time.Date(2050, 1, 1, 0, 0, 0, 0, time.UTC), 1)},
+				{entry: revoked(3, time.Date(2072, 5, 4, 3, 2, 1, 0, time.UTC), 0, ext(invalidityDateExtension(time.Date(2071, 1, 1, 0, 0, 0, 0, time.UTC))))},
+			},
+		},
+		{
+			name:       "utc time until 2049",
+			thisUpdate: time.Date(2049, 12, 1, 0, 0, 0, 0, time.UTC),
+			nextUpdate: time.Date(2050, 1, 2, 0, 0, 0, 0, time.UTC),
+			entries:    []testEntry{{entry: revoked(1, time.Date(1950, 1, 1, 0, 0, 0, 0, time.UTC), 0)}},
+		},
+		{
+			name:    "delta CRL",
+			entries: []testEntry{{entry: revoked(1, revokedAt, 8)}},
+			extensions: []pkix.Extension{
+				ext(deltaCRLIndicatorExtension(big.NewInt(41))),
+				ext(issuingDistributionPointExtension(IDPConfig{URL: "http://crl.example.com/ca.crl"})),
+			},
+		},
+		{
+			name:    "freshest CRL",
+			entries: []testEntry{{entry: revoked(1, revokedAt, 1)}},
+			extensions: []pkix.Extension{
+				ext(freshestCRLExtension([]string{"http://crl.example.com/delta.crl", "ldap://ldap.example.com/cn=CA"})),
+				ext(issuingDistributionPointExtension(IDPConfig{URL: "http://crl.example.com/ca.crl", OnlyContainsUserCerts: true})),
+			},
+		},
+		{
+			name: "indirect CRL",
+			entries: []testEntry{
+				{entry: revoked(1, revokedAt, 1), certIssuer: other},
+				{entry: revoked(2, revokedAt, 0)},
+				{entry: revoked(3, revokedAt, 0, invalidity), certIssuer: another},
+				{entry: revoked(4, revokedAt, 2), certIssuer: other},
+				{entry: revoked(5, revokedAt, 1)},
+				{entry: revoked(6, revokedAt, 0), certIssuer: another},
+			},
+			extensions: []pkix.Extension{ext(issuingDistributionPointExtension(IDPConfig{IndirectCRL: true}))},
+		},
+		{
+			name: "indirect CRL without own entries",
+			entries: []testEntry{
+				{entry: revoked(1, revokedAt, 1), certIssuer: other},
+				{entry: revoked(2, revokedAt, 0), certIssuer: other},
+			},
+			extensions: []pkix.Extension{ext(issuingDistributionPointExtension(IDPConfig{IndirectCRL: true}))},
+		},
+		{
+			name:    "entry length boundaries",
+			entries: boundaryEntries(t, revokedAt),
+		},
+		{
+			name:    "many entries in two groups",
+			entries: many,
+		},
+	}
+
+	for _, key := range testKeys() {
+		issuer := testutil.Issuer(t, key.priv, "Test CA")
+		for _, tt := range tests {
+			t.Run(key.name+"/"+tt.name, func(t *testing.T) {
+				thisUpdate, nextUpdate := thisUpdate, nextUpdate
+				if !tt.thisUpdate.IsZero() {
+					thisUpdate, nextUpdate = tt.thisUpdate, tt.nextUpdate
+				}
+				template := &x509.RevocationList{
+					Number:          big.NewInt(42),
+					ThisUpdate:      thisUpdate,
+					NextUpdate:      nextUpdate,
+					ExtraExtensions: tt.extensions,
+				}
+
+				enc := newCRLEncoder(t.TempDir())
+				defer enc.close()
+				for _, e := range tt.entries {
+					if err := enc.add(e.entry, e.certIssuer); err != nil {
+						t.Fatal(err)
+					}
+				}
+				got, err := enc.sign(template, issuer, key.priv)
+				if err != nil {
+					t.Fatalf("sign: %v", err)
+				}
+
+				reference := *template
+				reference.RevokedCertificateEntries = referenceEntries(t, issuer, tt.entries)
+				want, err := x509.CreateRevocationList(rand.Reader, &reference, issuer, key.priv)
+				if err != nil {
+					t.Fatalf("CreateRevocationList: %v", err)
+				}
+
+				gotCRL := parseAndVerify(t, got, issuer)
+				wantCRL := parseAndVerify(t, want, issuer)
+				if !bytes.Equal(gotCRL.RawTBSRevocationList, wantCRL.RawTBSRevocationList) {
+					t.Fatalf("TBS differs from x509.CreateRevocationList:\n got %x\nwant %x",
+						gotCRL.RawTBSRevocationList, wantCRL.RawTBSRevocationList)
+				}
+				if key.deterministic && !bytes.Equal(got, want) {
+					t.Fatal("CRL differs from x509.CreateRevocationList")
+				}
+
+				if n := len(gotCRL.RevokedCertificateEntries); n != len(tt.entries) || enc.count != n {
+					t.Fatalf("got %d entries (count %d), want %d", n, enc.count, len(tt.entries))
+				}
+				for i, entry := range gotCRL.RevokedCertificateEntries {
+					want := reference.RevokedCertificateEntries[i]
+					if entry.SerialNumber.Cmp(want.SerialNumber) != 0 ||
+						!entry.RevocationTime.Equal(want.RevocationTime) ||
+						entry.ReasonCode != want.ReasonCode {
+						t.Fatalf("entry %d: got %v %v %d, want %v %v %d", i,
+							entry.SerialNumber, entry.RevocationTime, entry.ReasonCode,
+							want.SerialNumber, want.RevocationTime, want.ReasonCode)
+					}
+				}
+				if gotCRL.Number.Cmp(template.Number) != 0 ||
+					!gotCRL.ThisUpdate.Equal(thisUpdate) || !gotCRL.NextUpdate.Equal(nextUpdate) {
+					t.Fatalf("got number %v, %v to %v", gotCRL.Number, gotCRL.ThisUpdate, gotCRL.NextUpdate)
+				}
+			})
+		}
+	}
+}
+
+func parseAndVerify(t *testing.T, der []byte, issuer *x509.Certificate) *x509.RevocationList {
+	t.Helper()
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		t.Fatalf("ParseRevocationList: %v", err)
+	}
+	if err := crl.CheckSignatureFrom(issuer); err != nil {
+		t.Fatalf("CheckSignatureFrom: %v", err)
+	}
+	return crl
+}
+
+func TestCRLEncoderRejects(t *testing.T) {
+	key := testKeys()[4]
+	issuer := testutil.Issuer(t, key.priv, "Test CA")
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	enc := newCRLEncoder(t.TempDir())
+	defer enc.close()
+	if err := enc.add(x509.RevocationListEntry{RevocationTime: now}, nil); err == nil {
+		t.Error("entry without serial accepted")
+	}
+	if err := enc.add(x509.RevocationListEntry{SerialNumber: big.NewInt(1)}, nil); err == nil {
+		t.Error("entry without revocation time accepted")
+	}
+	reason := pkix.Extension{Id: oidReasonCode, Value: []byte{0x0a, 0x01, 0x01}}
+	if _, err := encodeEntry(&x509.RevocationListEntry{SerialNumber: big.NewInt(1), RevocationTime: now, ExtraExtensions: []pkix.Extension{reason}}); err == nil {
+		t.Error("reason code extension accepted")
+	}
+
+	tests := []struct {
+		name     string
+		template x509.RevocationList
+	}{
+		{"nextUpdate before thisUpdate", x509.RevocationList{Number: big.NewInt(1), ThisUpdate: now, NextUpdate: now.Add(-time.Second)}},
+		{"number over 20 octets", x509.RevocationList{Number: new(big.Int).Lsh(big.NewInt(1), 160), ThisUpdate: now, NextUpdate: now}},
+		{"number with 20 octets and high bit", x509.RevocationList{Number: new(big.Int).Lsh(big.NewInt(1), 159), ThisUpdate: now, NextUpdate: now}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newCRLEncoder("").sign(&tt.template, issuer, key.priv); err == nil {
+				t.Fatal("accepted")
+			}
+		})
+	}
+}
+
+func TestCRLEncoderTempDir(t *testing.T) {
+	dir := t.TempDir()
+	enc := newCRLEncoder(dir)
+	for i := range 3 {
+		entry := revoked(int64(i+1), time.Now(), 0)
+		if err := enc.add(entry, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 {
+		t.Fatalf("got %d files in temp_dir, want 1", len(files))
+	}
+	enc.close()
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Fatalf("close left %d files", len(files))
+	}
+
+	// The first entry of a group is kept in memory
+	enc = newCRLEncoder(dir + "/missing")
+	if err := enc.add(revoked(1, time.Now(), 0), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := enc.add(revoked(2, time.Now(), 0), nil); err == nil {
+		t.Fatal("missing temp_dir accepted")
+	}
+}
+
+func TestHeaderLen(t *testing.T) {
+	for _, n := range []int{0, 1, 0x7f, 0x80, 0xff, 0x100, 0xffff, 0x10000, 0xffffff, 0x1000000} {
+		var b cryptobyte.Builder
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddBytes(make([]byte, n))
+		})
+		want := b.BytesOrPanic()[:len(b.BytesOrPanic())-n]
+
+		if got := headerLen(n); got != len(want) {
+			t.Errorf("headerLen(%d) = %d, want %d", n, got, len(want))
+		}
+		if got := appendHeader(nil, cbasn1.SEQUENCE, n); !bytes.Equal(got, want) {
+			t.Errorf("appendHeader(%d) = %x, want %x", n, got, want)
+		}
+		if headerLen(n) > maxHeaderLen {
+			t.Errorf("headerLen(%d) exceeds maxHeaderLen", n)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package generator
+
+import (
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CRL extension OIDs from RFC 5280 section 5.2
+var (
+	oidDeltaCRLIndicator = asn1.ObjectIdentifier{2, 5, 29, 27}
+	oidFreshestCRL       = asn1.ObjectIdentifier{2, 5, 29, 46}
+	oidIssuingDistPoint  = asn1.ObjectIdentifier{2, 5, 29, 28}
+	oidInvalidityDate    = asn1.ObjectIdentifier{2, 5, 29, 24}
+	oidCertificateIssuer = asn1.ObjectIdentifier{2, 5, 29, 29}
+)
+
+// distributionPointName and distributionPoint mirror the ASN.1 structures
+// of RFC 5280 section 4.2.1.13
+type distributionPointName struct {
+	FullName []asn1.RawValue `asn1:"optional,tag:0"`
+}
+
+type distributionPoint struct {
+	DistributionPoint distributionPointName `asn1:"optional,tag:0"`
+}
+
+// issuingDistributionPoint mirrors RFC 5280 section 5.2.5. BOOLEAN fields
+// default to FALSE and are omitted from the encoding when false.
+type issuingDistributionPoint struct {
+	DistributionPoint     distributionPointName `asn1:"optional,tag:0"`
+	OnlyContainsUserCerts bool                  `asn1:"optional,tag:1"`
+	OnlyContainsCACerts   bool                  `asn1:"optional,tag:2"`
+	IndirectCRL           bool                  `asn1:"optional,tag:4"`
+}
+
+// IDPConfig configures the Issuing Distribution Point extension
+type IDPConfig struct {
+	URL                   string `yaml:"url"`
+	OnlyContainsUserCerts bool   `yaml:"only_contains_user_certs"`
+	OnlyContainsCACerts   bool   `yaml:"only_contains_ca_certs"`
+
+	// IndirectCRL allows entries for certificates issued by other CAs,
+	// identified by the CertificateIssuer entry extension
+	IndirectCRL bool `yaml:"indirect_crl"`
+}
+
+// Enabled reports whether the extension should be emitted
+func (c IDPConfig) Enabled() bool {
+	return c.URL != "" || c.OnlyContainsUserCerts || c.OnlyContainsCACerts || c.IndirectCRL
+}
+
+// Validate rejects combinations RFC 5280 forbids
+func (c IDPConfig) Validate() error {
+	if c.OnlyContainsUserCerts && c.OnlyContainsCACerts {
+		return errors.New("issuing distribution point cannot set both only_contains_user_certs and only_contains_ca_certs")
+	}
+	return nil
+}
+
+// uriGeneralNames encodes URIs as GeneralName uniformResourceIdentifier values
+func uriGeneralNames(uris []string) []asn1.RawValue {
+	names := make([]asn1.RawValue, 0, len(uris))
+	for _, uri := range uris {
+		names = append(names, asn1.RawValue{
+			Tag:   6,
+			Class: asn1.ClassContextSpecific,
+			Bytes: []byte(uri),
+		})
+	}
+	return names
+}
+
+// deltaCRLIndicatorExtension marks a CRL as a delta of baseNumber
+func deltaCRLIndicatorExtension(baseNumber interface{}) (pkix.Extension, error) {
+	value, err := asn1.Marshal(baseNumber)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("failed to encode delta CRL indicator: %w", err)
+	}
+	return pkix.Extension{Id: oidDeltaCRLIndicator, Critical: true, Value: value}, nil
+}
+
+// freshestCRLExtension points relying parties at the delta CRL for a full
+// CRL (RFC 5280 section 5.2.6)
+func freshestCRLExtension(uris []string) (pkix.Extension, error) {
+	points := []distributionPoint{{
+		DistributionPoint: distributionPointName{FullName: uriGeneralNames(uris)},
+	}}
+	value, err := asn1.Marshal(points)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("failed to encode freshest CRL extension: %w", err)
+	}
+	return pkix.Extension{Id: oidFreshestCRL, Value: value}, nil
+}
+
+// issuingDistributionPointExtension scopes the CRL to the configured
+// distribution point and certificate types. It is always critical.
+func issuingDistributionPointExtension(c IDPConfig) (pkix.Extension, error) {
+	idp := issuingDistributionPoint{
+		OnlyContainsUserCerts: c.OnlyContainsUserCerts,
+		OnlyContainsCACerts:   c.OnlyContainsCACerts,
+		IndirectCRL:           c.IndirectCRL,
+	}
+	if c.URL != "" {
+		idp.DistributionPoint = distributionPointName{FullName: uriGeneralNames([]string{c.URL})}
+	}
+
+	value, err := asn1.Marshal(idp)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("failed to encode issuing distribution point: %w", err)
+	}
+	return pkix.Extension{Id: oidIssuingDistPoint, Critical: true, Value: value}, nil
+}
+
+// invalidityDateExtension records when the key was believed compromised
+// (RFC 5280 section 5.3.2)
+func invalidityDateExtension(t time.Time) (pkix.Extension, error) {
+	value, err := asn1.MarshalWithParams(t.UTC(), "generalized")
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("failed to encode invalidity date: %w", err)
+	}
+	return pkix.Extension{Id: oidInvalidityDate, Value: value}, nil
+}
+
+// certificateIssuerExtension names the issuer of this and all following
+// entries of an indirect CRL (RFC 5280 section 5.3.3). issuerName is a DER
+// encoded Name, wrapped as a directoryName GeneralName.
+func certificateIssuerExtension(issuerName []byte) (pkix.Extension, error) {
+	names := []asn1.RawValue{{
+		Class:      asn1.ClassContextSpecific,
+		Tag:        4,
+		IsCompound: true,
+		Bytes:      issuerName,
+	}}
+	value, err := asn1.Marshal(names)
+	if err != nil {
+		return pkix.Extension{}, fmt.Errorf("failed to encode certificate issuer: %w", err)
+	}
+	return pkix.Extension{Id: oidCertificateIssuer, Critical: true, Value: value}, nil
+}
+
+// ParseIssuerName validates a DER encoded X.501 Name and returns its
+// string form
+func ParseIssuerName(der []byte) (string, error) {
+	var rdn pkix.RDNSequence
+	rest, err := asn1.Unmarshal(der, &rdn)
+	if err != nil {
+		return "", fmt.Errorf("invalid certificate issuer name: %w", err)
+	}
+	if len(rest) > 0 {
+		return "", errors.New("invalid certificate issuer name: trailing data")
+	}
+	return rdn.String(), nil
+}
+
+// issuerWithKeyID returns the issuer certificate used to build CRLs.
+// The CRL encoder, like x509.CreateRevocationList, copies the issuer's Subject
+// Key Identifier into the Authority Key Identifier extension and refuses
+// issuers without one, so
+// legacy CA certificates lacking an SKI get one derived from their public key
+// using method (1) of RFC 5280 section 4.2.1.2.
+func issuerWithKeyID(cert *x509.Certificate) (*x509.Certificate, bool, error) {
+	if len(cert.SubjectKeyId) > 0 {
+		return cert, false, nil
+	}
+
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(cert.RawSubjectPublicKeyInfo, &spki); err != nil {
+		return nil, false, fmt.Errorf("failed to parse issuer public key: %w", err)
+	}
+	ski := sha1.Sum(spki.PublicKey.Bytes)
+
+	c := *cert
+	c.SubjectKeyId = ski[:]
+	return &c, true, nil
+}
@@ -0,0 +1,679 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gigvault/crl/internal/cdn"
+	"github.com/gigvault/crl/internal/publisher"
+	"github.com/gigvault/crl/internal/revocation"
+	"github.com/gigvault/crl/internal/signer"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/crl/internal/tracing"
+	"github.com/gigvault/shared/pkg/logger"
+	"github.com/gigvault/shared/pkg/models"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// ErrDeltaDisabled is returned when a delta CRL is requested but not enabled
+var ErrDeltaDisabled = errors.New("delta CRLs are disabled")
+
+// Config controls CRL generation
+type Config struct {
+	// DeltaEnabled allows delta CRLs relative to the last published full CRL
+	DeltaEnabled bool `yaml:"delta_enabled"`
+
+	// FreshestCRLURLs are advertised in full CRLs as the location of the
+	// delta CRL when delta CRLs are enabled
+	FreshestCRLURLs []string `yaml:"freshest_crl_urls"`
+
+	// IssuingDistributionPoint scopes generated CRLs (RFC 5280 section 5.2.5)
+	IssuingDistributionPoint IDPConfig `yaml:"issuing_distribution_point"`
+
+	// Partitions splits the revocation set over several CRLs
+	Partitions PartitionConfig `yaml:"partitions"`
+
+	// Window sets the validity period (validity, overlap) of signed CRLs
+	Window Window `yaml:",inline"`
+
+	// Schedule republishes the CRL automatically
+	Schedule ScheduleConfig `yaml:"schedule"`
+
+	// EmergencyPublish publishes immediately, outside the schedule, when a
+	// certificate is revoked for keyCompromise or cACompromise
+	EmergencyPublish bool `yaml:"emergency_publish"`
+
+	// Verify downloads published CRLs back from their public URLs
+	Verify VerifyConfig `yaml:"verify"`
+
+	// ExpiredRetention keeps the entries of expired certificates on CRLs
+	// for this long after their notAfter, then leaves them out (RFC 5280
+	// section 3.3). Zero keeps them for good.
+	ExpiredRetention time.Duration `yaml:"expired_retention"`
+
+	// LastKnownGoodDir keeps the last full CRL of each partition on disk,
+	// so it can still be served after a restart while the database is
+	// down. Without it the last CRL is only kept in memory.
+	LastKnownGoodDir string `yaml:"last_known_good_dir"`
+
+	// TempDir holds the temporary files the revoked entries are encoded
+	// into while a CRL is signed. Empty uses the system's temporary
+	// directory (TMPDIR).
+	TempDir string `yaml:"temp_dir"`
+
+	// CacheTTL bounds how long GetCRL serves a cached CRL, which picks up
+	// entries changed by other replicas. Zero serves it until an entry
+	// changes on this replica or the validity window requires a new one.
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+// ScheduleConfig controls automatic publication. A CRL is published every
+// Interval (if set) and at the latest LeadTime before nextUpdate.
+type ScheduleConfig struct {
+	Enabled  bool          `yaml:"enabled"`
+	Interval time.Duration `yaml:"interval"`
+	LeadTime time.Duration `yaml:"lead_time"`
+}
+
+// Validate checks the schedule against the CRL validity period
+func (c ScheduleConfig) Validate(validity time.Duration) error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Interval < 0 || c.LeadTime < 0 {
+		return errors.New("schedule: interval and lead_time must not be negative")
+	}
+	if c.LeadTime >= validity {
+		return fmt.Errorf("schedule: lead_time must be shorter than the CRL validity (%s)", validity)
+	}
+	if c.Interval >= validity {
+		return fmt.Errorf("schedule: interval must be shorter than the CRL validity (%s)", validity)
+	}
+	return nil
+}
+
+// Validate checks the generation settings
+func (c Config) Validate() error {
+	if err := c.IssuingDistributionPoint.Validate(); err != nil {
+		return err
+	}
+	if err := c.Partitions.Validate(); err != nil {
+		return err
+	}
+	if err := c.Window.Validate(); err != nil {
+		return err
+	}
+	validity := c.Window.withDefaults().Validity
+	if err := c.Schedule.Validate(validity); err != nil {
+		return err
+	}
+	// An entry must appear on at least one CRL issued after the
+	// certificate expired
+	if c.ExpiredRetention < 0 {
+		return errors.New("expired_retention must not be negative")
+	}
+	if c.ExpiredRetention > 0 && c.ExpiredRetention < validity {
+		return fmt.Errorf("expired_retention must be at least the CRL validity (%s)", validity)
+	}
+	if c.CacheTTL < 0 {
+		return errors.New("cache_ttl must not be negative")
+	}
+	if c.TempDir != "" {
+		if info, err := os.Stat(c.TempDir); err != nil {
+			return fmt.Errorf("temp_dir: %w", err)
+		} else if !info.IsDir() {
+			return fmt.Errorf("temp_dir: %s is not a directory", c.TempDir)
+		}
+	}
+	idpURL := c.IssuingDistributionPoint.URL
+	if c.Partitions.Enabled() {
+		idpURL = c.Partitions.URLTemplate
+	}
+	if err := c.Verify.Validate(idpURL); err != nil {
+		return err
+	}
+	// Delta bases are tracked per issuer, not per partition
+	if c.DeltaEnabled && c.Partitions.Enabled() {
+		return errors.New("delta CRLs cannot be combined with partitions")
+	}
+	return nil
+}
+
+// CRL is a signed certificate revocation list
+type CRL struct {
+	DER          []byte
+	ThisUpdate   time.Time
+	NextUpdate   time.Time
+	Number       *big.Int
+	RevokedCount int
+
+	// Partition is the partition number of a partitioned CRL
+	Partition int
+
+	// BaseNumber is the base CRL number of a delta CRL, nil for full CRLs
+	BaseNumber *big.Int
+
+	// SnapshotAt is the database time, from Store.SnapshotTime, before
+	// which every change to the revocation set is included
+	SnapshotAt time.Time
+
+	// SHA256 is the digest of DER, which keys the CRL in the history
+	SHA256 []byte
+
+	// Stale marks a last known good CRL served because a new one could not
+	// be signed, e.g. while the database is down
+	Stale bool
+}
+
+// IsDelta reports whether the CRL is a delta CRL
+func (c *CRL) IsDelta() bool {
+	return c.BaseNumber != nil
+}
+
+// PEM returns the CRL encoded as PEM
+func (c *CRL) PEM() string {
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "X509 CRL",
+		Bytes: c.DER,
+	}))
+}
+
+// Generator builds and signs the CRLs of one issuer from the revocations
+// in the store
+type Generator struct {
+	id     string
+	store  *store.Store
+	signer signer.Signer
+	issuer *x509.Certificate
+	logger *logger.Logger
+
+	cfgMu sync.RWMutex
+	cfg   Config
+
+	// targetsMu guards the distribution targets, which a reload replaces
+	targetsMu  sync.RWMutex
+	publishers []publisher.Publisher
+	purgers    []cdn.Purger
+
+	partitions *partitioner
+
+	mu      sync.Mutex
+	current map[int]*CRL // last published full CRL per partition
+
+	// genMu serializes signing on this replica, the store's generation
+	// lock across replicas
+	genMu sync.Mutex
+
+	cacheMu      sync.Mutex
+	cache        map[cacheKey]*cachedCRL // CRLs served by Latest and LatestDelta
+	cacheVersion atomic.Uint64           // bumped by Invalidate
+
+	hooksMu      sync.RWMutex
+	publishHooks []PublishHook
+}
+
+// New creates a new CRL generator for the issuer id. Published CRLs are
+// uploaded to every publisher, then purged from every CDN.
+func New(id string, st *store.Store, s signer.Signer, cfg Config, publishers []publisher.Publisher, purgers []cdn.Purger) (*Generator, error) {
+	issuer, derived, err := issuerWithKeyID(s.Certificate())
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := newPartitioner(cfg.Partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Generator{
+		id:     id,
+		store:  st,
+		signer: s,
+		issuer: issuer,
+		cfg:    cfg,
+		logger: logger.Global(),
+
+		publishers: publishers,
+		purgers:    purgers,
+		partitions: partitions,
+		current:    make(map[int]*CRL),
+		cache:      make(map[cacheKey]*cachedCRL),
+	}
+	if derived {
+		g.logger.Warn("Issuer certificate has no subject key identifier; deriving one for the CRL authority key identifier",
+			zap.String("issuer_id", id),
+			zap.String("issuer", issuer.Subject.String()),
+			zap.String("key_id", fmt.Sprintf("%X", issuer.SubjectKeyId)),
+		)
+	}
+	if cfg.LastKnownGoodDir != "" {
+		if err := g.loadLastKnownGood(cfg.LastKnownGoodDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// ID returns the issuer identifier
+func (g *Generator) ID() string {
+	return g.id
+}
+
+// Issuer returns the CRL issuer certificate
+func (g *Generator) Issuer() *x509.Certificate {
+	return g.issuer
+}
+
+// Signer returns the issuer's signing key, which also signs OCSP responses
+func (g *Generator) Signer() signer.Signer {
+	return g.signer
+}
+
+// config returns the current generation settings
+func (g *Generator) config() Config {
+	g.cfgMu.RLock()
+	defer g.cfgMu.RUnlock()
+	return g.cfg
+}
+
+// Reload applies cfg to the CRLs generated from now on, such as a new
+// validity window, schedule or distribution point. Partitioning cannot
+// change at runtime.
+func (g *Generator) Reload(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	g.cfgMu.Lock()
+	defer g.cfgMu.Unlock()
+	if !reflect.DeepEqual(cfg.Partitions, g.cfg.Partitions) {
+		return errors.New("partitions cannot change without a restart")
+	}
+	g.cfg = cfg
+	g.Invalidate()
+	return nil
+}
+
+// targets returns the publishers and CDN purgers
+func (g *Generator) targets() ([]publisher.Publisher, []cdn.Purger) {
+	g.targetsMu.RLock()
+	defer g.targetsMu.RUnlock()
+	return g.publishers, g.purgers
+}
+
+// SetTargets replaces the publishers and CDN purgers, as on a reload.
+// Uploads in progress on this replica finish on the previous publishers,
+// which are closed once they are done.
+func (g *Generator) SetTargets(publishers []publisher.Publisher, purgers []cdn.Purger) {
+	// Uploads run under genMu
+	g.genMu.Lock()
+	g.targetsMu.Lock()
+	previous := g.publishers
+	g.publishers, g.purgers = publishers, purgers
+	g.targetsMu.Unlock()
+	g.genMu.Unlock()
+	publisher.CloseAll(previous)
+}
+
+// Close closes the publishers holding resources, such as clients
+func (g *Generator) Close() {
+	publishers, _ := g.targets()
+	publisher.CloseAll(publishers)
+}
+
+// Schedule returns the automatic publication settings
+func (g *Generator) Schedule() ScheduleConfig {
+	return g.config().Schedule
+}
+
+// Window returns the configured validity period
+func (g *Generator) Window() Window {
+	return g.config().Window.withDefaults()
+}
+
+// ExpiredRetention returns how long entries of expired certificates stay
+// on CRLs; zero keeps them for good
+func (g *Generator) ExpiredRetention() time.Duration {
+	return g.config().ExpiredRetention
+}
+
+// EmergencyPublish reports whether compromise revocations trigger an
+// immediate publication
+func (g *Generator) EmergencyPublish() bool {
+	return g.config().EmergencyPublish
+}
+
+// Partitions returns the number of CRLs the issuer publishes (1 when
+// partitioning is disabled)
+func (g *Generator) Partitions() int {
+	return g.partitions.count()
+}
+
+// Indirect reports whether generated CRLs may carry entries for
+// certificates issued by other CAs
+func (g *Generator) Indirect() bool {
+	return g.config().IssuingDistributionPoint.IndirectCRL
+}
+
+// IsCRLIssuer reports whether the DER encoded Name is the CRL issuer's subject
+func (g *Generator) IsCRLIssuer(name []byte) bool {
+	return bytes.Equal(name, g.issuer.RawSubject)
+}
+
+// CheckHealth reports whether the signer can currently sign a CRL
+func (g *Generator) CheckHealth(ctx context.Context) error {
+	return signer.CheckHealth(ctx, g.signer)
+}
+
+// Generate builds a new full CRL containing every stored revocation of the
+// given partition, signs it and records it in the history. Unpartitioned
+// issuers have only partition 0.
+func (g *Generator) Generate(ctx context.Context, partition int) (*CRL, error) {
+	return g.generate(ctx, partition, g.config().Window)
+}
+
+func (g *Generator) generate(ctx context.Context, partition int, w Window) (*CRL, error) {
+	if partition < 0 || partition >= g.partitions.count() {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownPartition, partition)
+	}
+
+	unlock, err := g.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	return g.generateLocked(ctx, partition, w)
+}
+
+// generateLocked is generate for callers holding the generation lock
+func (g *Generator) generateLocked(ctx context.Context, partition int, w Window) (_ *CRL, err error) {
+	ctx, span := tracing.Start(ctx, "crl.generate", trace.WithAttributes(
+		attribute.String("crl.issuer_id", g.id),
+		attribute.Int("crl.partition", partition),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	cfg := g.config()
+	idp := cfg.IssuingDistributionPoint
+	if cfg.Partitions.Enabled() {
+		idp.URL = g.partitions.url(partition)
+	}
+
+	var snapshot time.Time
+	entries := func(add func(store.Entry) error) (err error) {
+		snapshot, err = g.store.EachEntry(ctx, g.id, func(e store.Entry) error {
+			// Malformed serials are kept so sign reports them
+			if cfg.Partitions.Enabled() && e.Number != nil && g.partitions.of(e.Number) != partition {
+				return nil
+			}
+			return add(e)
+		})
+		return err
+	}
+
+	list, err := g.sign(ctx, entries, nil, idp, w)
+	if err != nil {
+		return nil, err
+	}
+	list.Partition = partition
+	list.SnapshotAt = snapshot
+	if err := g.record(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// lock takes the issuer's generation lock, so that from the snapshot to
+// recording the signed CRL no other replica or goroutine signs one. CRL
+// numbers then follow the order of the snapshots they cover. Publish and
+// RetryUpload also hold it while uploading, so CRLs reach each target in
+// number order. It returns the function releasing the lock.
+func (g *Generator) lock(ctx context.Context) (func(), error) {
+	_, span := tracing.Start(ctx, "crl.lock", trace.WithAttributes(
+		attribute.String("crl.issuer_id", g.id),
+	))
+	g.genMu.Lock()
+	l, err := g.store.LockIssuer(ctx, g.id)
+	tracing.End(span, err)
+	if err != nil {
+		g.genMu.Unlock()
+		return nil, err
+	}
+	return func() {
+		l.Release(context.WithoutCancel(ctx))
+		g.genMu.Unlock()
+	}, nil
+}
+
+// SetCurrent records a published full CRL as the one served for its
+// partition
+func (g *Generator) SetCurrent(list *CRL) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.keepCurrent(list)
+}
+
+// Current returns the last published full CRL of a partition. If none has
+// been published since startup, or its nextUpdate has passed, a new one is
+// signed and kept as current. When that fails, e.g. because the database
+// is down, the previous CRL is returned marked Stale.
+func (g *Generator) Current(ctx context.Context, partition int) (*CRL, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if list, ok := g.current[partition]; ok && time.Now().Before(list.NextUpdate) {
+		return list, nil
+	}
+
+	list, err := g.Generate(ctx, partition)
+	if errors.Is(err, ErrUnknownPartition) {
+		return nil, err
+	}
+	if err != nil {
+		if stale := g.lastKnownGood(partition, err); stale != nil {
+			return stale, nil
+		}
+		return nil, err
+	}
+	g.keepCurrent(list)
+
+	return list, nil
+}
+
+// GenerateDelta builds and records a delta CRL containing the revocations
+// added or changed since the last published full CRL
+func (g *Generator) GenerateDelta(ctx context.Context) (_ *CRL, err error) {
+	if !g.config().DeltaEnabled {
+		return nil, ErrDeltaDisabled
+	}
+	ctx, span := tracing.Start(ctx, "crl.generate_delta", trace.WithAttributes(
+		attribute.String("crl.issuer_id", g.id),
+	))
+	defer func() { tracing.End(span, err) }()
+
+	unlock, err := g.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	baseNumber, baseAt, err := g.store.BaseCRL(ctx, g.id)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := g.store.SnapshotTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := g.store.ListEntriesSince(ctx, g.id, baseAt)
+	if err != nil {
+		return nil, err
+	}
+	entries := func(add func(store.Entry) error) error {
+		for _, e := range changed {
+			if err := add(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	cfg := g.config()
+	list, err := g.sign(ctx, entries, big.NewInt(baseNumber), cfg.IssuingDistributionPoint, cfg.Window)
+	if err != nil {
+		return nil, err
+	}
+	list.SnapshotAt = snapshot
+	if err := g.record(ctx, list); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// sign allocates a CRL number and signs a CRL over the entries passed to
+// add by entries, which are encoded as they come. A non-nil base marks the
+// result as a delta CRL.
+func (g *Generator) sign(ctx context.Context, entries func(add func(store.Entry) error) error, base *big.Int, idp IDPConfig, w Window) (*CRL, error) {
+	return g.signNumbered(ctx, entries, base, idp, w, func() (int64, error) {
+		return g.store.NextCRLNumber(ctx, g.id)
+	})
+}
+
+// signNumbered is sign with the CRL number taken from nextNumber once the
+// entries are encoded
+func (g *Generator) signNumbered(ctx context.Context, entries func(add func(store.Entry) error) error, base *big.Int, idp IDPConfig, w Window, nextNumber func() (int64, error)) (*CRL, error) {
+	cfg := g.config()
+	thisUpdate, nextUpdate := w.bounds(time.Now())
+
+	enc := newCRLEncoder(cfg.TempDir)
+	defer enc.close()
+	expired := 0
+	err := entries(func(e store.Entry) error {
+		if cfg.ExpiredRetention > 0 && e.NotAfter != nil && thisUpdate.After(e.NotAfter.Add(cfg.ExpiredRetention)) {
+			expired++
+			return nil
+		}
+		serial := e.Number
+		if serial == nil {
+			g.logger.Warn("Skipping CRL entry with invalid serial",
+				zap.String("issuer_id", g.id),
+				zap.String("serial", e.Serial),
+			)
+			return nil
+		}
+		reason, err := revocation.ParseReason(e.Reason)
+		if err != nil {
+			g.logger.Warn("Treating unknown stored reason as unspecified",
+				zap.String("serial", e.Serial),
+				zap.String("reason", e.Reason),
+			)
+		}
+		// Released holds and removed revocations only reach delta CRLs,
+		// where they tell relying parties to drop the earlier entry
+		if e.Status == store.StatusReleased || e.Status == store.StatusRemoved {
+			reason = models.ReasonRemoveFromCRL
+		}
+		// ReasonCode 0 (unspecified) is omitted, as RFC 5280 recommends
+		entry := x509.RevocationListEntry{
+			SerialNumber:   serial,
+			RevocationTime: e.RevokedAt.UTC(),
+			ReasonCode:     reason,
+		}
+		if e.InvalidityDate != nil {
+			ext, err := invalidityDateExtension(*e.InvalidityDate)
+			if err != nil {
+				return err
+			}
+			entry.ExtraExtensions = append(entry.ExtraExtensions, ext)
+		}
+		// The encoder adds the CertificateIssuer extensions, as it orders
+		// the entries by issuer
+		var certIssuer []byte
+		if cfg.IssuingDistributionPoint.IndirectCRL {
+			certIssuer = e.CertificateIssuer
+		}
+		return enc.add(entry, certIssuer)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if expired > 0 {
+		g.logger.Debug("Left expired certificates out of CRL",
+			zap.String("issuer_id", g.id),
+			zap.Int("entries", expired),
+		)
+	}
+
+	n, err := nextNumber()
+	if err != nil {
+		return nil, err
+	}
+	number := big.NewInt(n)
+
+	template := &x509.RevocationList{
+		Number:     number,
+		ThisUpdate: thisUpdate,
+		NextUpdate: nextUpdate,
+	}
+
+	if base != nil {
+		ext, err := deltaCRLIndicatorExtension(base)
+		if err != nil {
+			return nil, err
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, ext)
+	} else if cfg.DeltaEnabled && len(cfg.FreshestCRLURLs) > 0 {
+		ext, err := freshestCRLExtension(cfg.FreshestCRLURLs)
+		if err != nil {
+			return nil, err
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, ext)
+	}
+
+	if idp.Enabled() {
+		ext, err := issuingDistributionPointExtension(idp)
+		if err != nil {
+			return nil, err
+		}
+		template.ExtraExtensions = append(template.ExtraExtensions, ext)
+	}
+
+	// The Authority Key Identifier is taken from the issuer's SKI. The
+	// span covers the signer backend, e.g. a KMS round trip.
+	_, span := tracing.Start(ctx, "crl.sign", trace.WithAttributes(
+		attribute.String("crl.issuer_id", g.id),
+		attribute.String("crl.number", number.String()),
+		attribute.Int("crl.revoked_count", enc.count),
+	))
+	der, err := enc.sign(template, g.issuer, g.signer)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign CRL: %w", err)
+	}
+
+	sum := sha256.Sum256(der)
+	return &CRL{
+		DER:          der,
+		SHA256:       sum[:],
+		ThisUpdate:   thisUpdate,
+		NextUpdate:   nextUpdate,
+		Number:       number,
+		RevokedCount: enc.count,
+		BaseNumber:   base,
+	}, nil
+}
@@ -0,0 +1,139 @@
+package generator
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/crl/internal/testutil"
+)
+
+var (
+	benchEntries = flag.Int("crl.entries", 5000000, "revocations BenchmarkGenerate and BenchmarkSign sign a CRL over")
+	benchBudget  = flag.Duration("crl.budget", 2*time.Minute, "longest one generation in BenchmarkGenerate may take")
+)
+
+// benchEntry returns the i-th of the benchmark's revocations, with a
+// serial of 16 random-looking octets as CAs issue them
+func benchEntry(issuerID string, i int, revokedAt, notAfter time.Time) store.Entry {
+	number := new(big.Int).SetUint64(uint64(i)*0x9e3779b97f4a7c15 | 1<<63)
+	number.Lsh(number, 64).Or(number, big.NewInt(int64(i)))
+	return store.Entry{
+		IssuerID:  issuerID,
+		Serial:    fmt.Sprintf("%x", number),
+		Number:    number,
+		RevokedAt: revokedAt.Add(time.Duration(i) * time.Millisecond),
+		Reason:    "keyCompromise",
+		Status:    store.StatusRevoked,
+		NotAfter:  &notAfter,
+	}
+}
+
+// seedBenchmark stores the -crl.entries revocations of issuer id once;
+// later runs find the last one and reuse them
+func seedBenchmark(b *testing.B, st *store.Store, g *Generator) {
+	b.Helper()
+	ctx := context.Background()
+	cert := g.Issuer()
+	if err := st.UpsertIssuer(ctx, store.Issuer{ID: g.id, Subject: cert.Subject.String(), RawSubject: cert.RawSubject, SubjectKeyID: cert.SubjectKeyId}); err != nil {
+		b.Fatal(err)
+	}
+
+	revokedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	notAfter := time.Date(2060, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := benchEntry(g.id, *benchEntries-1, revokedAt, notAfter)
+	if _, err := st.GetEntry(ctx, g.id, last.Serial); err == nil {
+		return
+	}
+
+	const chunk = 10000
+	batch := make([]store.Entry, 0, chunk)
+	for i := range *benchEntries {
+		batch = append(batch, benchEntry(g.id, i, revokedAt, notAfter))
+		if len(batch) == chunk || i == *benchEntries-1 {
+			if err := st.AddEntries(ctx, batch); err != nil {
+				b.Fatal(err)
+			}
+			batch = batch[:0]
+		}
+	}
+}
+
+// BenchmarkGenerate runs Generate over -crl.entries revocations stored in
+// the database named by CRL_TEST_DATABASE_URL, so it measures the page
+// reads over idx_crl_entries_in_effect along with encoding, signing and
+// recording the CRL. It fails when one generation takes longer than
+// -crl.budget. The revocations are stored by the first run and kept:
+//
+//	CRL_TEST_DATABASE_URL=postgres://... go test ./internal/generator \
+//		-run '^$' -bench Generate -benchtime 1x
+func BenchmarkGenerate(b *testing.B) {
+	st := testutil.Store(b)
+	id := fmt.Sprintf("benchmark-%d", *benchEntries)
+	g, err := New(id, st, testutil.NewSigner(b, "Test CA"), Config{TempDir: b.TempDir()}, nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	seedBenchmark(b, st, g)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		start := time.Now()
+		list, err := g.Generate(context.Background(), 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if list.RevokedCount != *benchEntries {
+			b.Fatalf("signed %d entries, want %d", list.RevokedCount, *benchEntries)
+		}
+		if elapsed := time.Since(start); elapsed > *benchBudget {
+			b.Fatalf("generating a CRL over %d entries took %s, over the budget of %s", *benchEntries, elapsed, *benchBudget)
+		}
+		b.ReportMetric(float64(len(list.DER))/float64(*benchEntries), "bytes/entry")
+	}
+}
+
+// BenchmarkSign encodes and signs a CRL over -crl.entries revocations fed
+// from memory, without a database, to tell the cost of encoding and
+// signing apart from the page reads BenchmarkGenerate also measures
+func BenchmarkSign(b *testing.B) {
+	g, err := New("benchmark", nil, testutil.NewSigner(b, "Test CA"), Config{TempDir: b.TempDir()}, nil, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	revokedAt := time.Now().Add(-24 * time.Hour)
+	notAfter := time.Now().Add(365 * 24 * time.Hour)
+	entries := func(add func(store.Entry) error) error {
+		for i := range *benchEntries {
+			if err := add(benchEntry(g.id, i, revokedAt, notAfter)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var number int64
+	nextNumber := func() (int64, error) {
+		number++
+		return number, nil
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		list, err := g.signNumbered(context.Background(), entries, nil, IDPConfig{}, Window{}, nextNumber)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if list.RevokedCount != *benchEntries {
+			b.Fatalf("signed %d entries, want %d", list.RevokedCount, *benchEntries)
+		}
+		b.ReportMetric(float64(len(list.DER))/float64(*benchEntries), "bytes/entry")
+	}
+}
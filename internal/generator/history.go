@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/gigvault/crl/internal/store"
+)
+
+// record keeps a signed CRL in the store's history
+func (g *Generator) record(ctx context.Context, list *CRL) error {
+	r := store.CRLRecord{
+		IssuerID:     g.id,
+		Number:       list.Number.Int64(),
+		Partition:    list.Partition,
+		ThisUpdate:   list.ThisUpdate,
+		NextUpdate:   list.NextUpdate,
+		RevokedCount: list.RevokedCount,
+		DER:          list.DER,
+		SHA256:       list.SHA256,
+		SignerKeyID:  g.issuer.SubjectKeyId,
+	}
+	if list.IsDelta() {
+		base := list.BaseNumber.Int64()
+		r.BaseNumber = &base
+	}
+	return g.store.RecordCRL(ctx, r)
+}
+
+// Historical returns the CRL the issuer signed with a number, or
+// store.ErrCRLNotFound. CRLs signed before migration 022 are not kept.
+func (g *Generator) Historical(ctx context.Context, number int64) (*CRL, error) {
+	r, err := g.store.Reader().GetCRL(ctx, g.id, number)
+	// A CRL signed moments ago may not have reached the replica yet
+	if errors.Is(err, store.ErrCRLNotFound) && g.store.Reader() != g.store {
+		r, err = g.store.GetCRL(ctx, g.id, number)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return historicalCRL(r), nil
+}
+
+// HistoricalByDigest returns the CRL the issuer signed whose DER has a
+// SHA-256 digest, or store.ErrCRLNotFound
+func (g *Generator) HistoricalByDigest(ctx context.Context, digest []byte) (*CRL, error) {
+	r, err := g.store.Reader().GetCRLByDigest(ctx, g.id, digest)
+	if errors.Is(err, store.ErrCRLNotFound) && g.store.Reader() != g.store {
+		r, err = g.store.GetCRLByDigest(ctx, g.id, digest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return historicalCRL(r), nil
+}
+
+func historicalCRL(r *store.CRLRecord) *CRL {
+	list := &CRL{
+		DER:          r.DER,
+		SHA256:       r.SHA256,
+		ThisUpdate:   r.ThisUpdate,
+		NextUpdate:   r.NextUpdate,
+		Number:       big.NewInt(r.Number),
+		RevokedCount: r.RevokedCount,
+		Partition:    r.Partition,
+	}
+	if r.BaseNumber != nil {
+		list.BaseNumber = big.NewInt(*r.BaseNumber)
+	}
+	return list
+}
@@ -0,0 +1,141 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/gigvault/crl/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// lastKnownGoodPath is the file keeping the last full CRL of a partition
+// in dir
+func (g *Generator) lastKnownGoodPath(dir string, partition int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%d.der", url.PathEscape(g.id), partition))
+}
+
+// keepCurrent makes list the CRL served for its partition and, with
+// crl.last_known_good_dir set, writes it to disk so it survives a restart.
+// The caller holds g.mu.
+func (g *Generator) keepCurrent(list *CRL) {
+	g.current[list.Partition] = list
+
+	dir := g.config().LastKnownGoodDir
+	if dir == "" {
+		return
+	}
+	path := g.lastKnownGoodPath(dir, list.Partition)
+	if err := writeFileAtomic(path, list.DER); err != nil {
+		g.logger.Warn("Failed to save last known good CRL",
+			zap.String("issuer_id", g.id),
+			zap.String("path", path),
+			zap.Error(err),
+		)
+	}
+}
+
+// writeFileAtomic replaces path with data, so readers never see a partly
+// written CRL
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// loadLastKnownGood reads the CRLs saved in dir by an earlier run, keeping
+// those signed by this issuer as current until a new CRL is signed
+func (g *Generator) loadLastKnownGood(dir string) error {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create last known good CRL directory: %w", err)
+	}
+	for p := 0; p < g.partitions.count(); p++ {
+		path := g.lastKnownGoodPath(dir, p)
+		der, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read last known good CRL: %w", err)
+		}
+		list, err := g.parseLastKnownGood(der)
+		if err != nil {
+			g.logger.Warn("Ignoring saved last known good CRL",
+				zap.String("issuer_id", g.id),
+				zap.String("path", path),
+				zap.Error(err),
+			)
+			continue
+		}
+		list.Partition = p
+		g.current[p] = list
+	}
+	return nil
+}
+
+// parseLastKnownGood decodes a saved CRL and checks that the issuer
+// signed it
+func (g *Generator) parseLastKnownGood(der []byte) (*CRL, error) {
+	rl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return nil, err
+	}
+	if err := rl.CheckSignatureFrom(g.issuer); err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(der)
+	return &CRL{
+		DER:          der,
+		SHA256:       sum[:],
+		ThisUpdate:   rl.ThisUpdate,
+		NextUpdate:   rl.NextUpdate,
+		Number:       rl.Number,
+		RevokedCount: len(rl.RevokedCertificateEntries),
+	}, nil
+}
+
+// lastKnownGood returns the current CRL of a partition marked stale, to
+// serve when a new one cannot be signed, or nil. The caller holds g.mu.
+func (g *Generator) lastKnownGood(partition int, cause error) *CRL {
+	list, ok := g.current[partition]
+	if !ok {
+		return nil
+	}
+	g.logger.Warn("Serving last known good CRL",
+		zap.String("issuer_id", g.id),
+		zap.Int("partition", partition),
+		zap.String("crl_number", list.Number.String()),
+		zap.Time("next_update", list.NextUpdate),
+		zap.Error(cause),
+	)
+	metrics.StaleCRLsServed.WithLabelValues(g.id).Inc()
+	stale := *list
+	stale.Stale = true
+	return &stale
+}
+
+// LastKnownGood returns the last full CRL of a partition that was
+// published or served, marked stale, or nil if there is none. cause is
+// why a new CRL could not be signed.
+func (g *Generator) LastKnownGood(partition int, cause error) *CRL {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.lastKnownGood(partition, cause)
+}
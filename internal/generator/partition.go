@@ -0,0 +1,127 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Partition schemes
+const (
+	PartitionRange = "range"
+	PartitionHash  = "hash"
+)
+
+// partitionPlaceholder is replaced by the partition number in URLTemplate
+const partitionPlaceholder = "{partition}"
+
+// ErrUnknownPartition is returned for a partition number outside the
+// configured partitions
+var ErrUnknownPartition = errors.New("unknown CRL partition")
+
+// PartitionConfig splits an issuer's revocations over several CRLs, each
+// scoped by its own Issuing Distribution Point URL
+type PartitionConfig struct {
+	Scheme string `yaml:"scheme"` // range or hash; empty disables partitioning
+
+	// Count is the number of hash buckets (hash scheme)
+	Count int `yaml:"count"`
+
+	// Boundaries are ascending hex serial numbers at which partitions 1..N-1
+	// start (range scheme); partition 0 holds every lower serial
+	Boundaries []string `yaml:"boundaries"`
+
+	// URLTemplate is the IDP URL of each partition, with {partition}
+	// replaced by the partition number
+	URLTemplate string `yaml:"url_template"`
+}
+
+// Enabled reports whether revocations are split over several CRLs
+func (c PartitionConfig) Enabled() bool {
+	return c.Scheme != ""
+}
+
+// Validate checks the partition scheme and its parameters
+func (c PartitionConfig) Validate() error {
+	_, err := newPartitioner(c)
+	return err
+}
+
+// partitioner assigns serial numbers to partitions
+type partitioner struct {
+	cfg        PartitionConfig
+	boundaries []*big.Int
+}
+
+func newPartitioner(c PartitionConfig) (*partitioner, error) {
+	p := &partitioner{cfg: c}
+	if !c.Enabled() {
+		return p, nil
+	}
+	if !strings.Contains(c.URLTemplate, partitionPlaceholder) {
+		return nil, fmt.Errorf("partitions: url_template must contain %s", partitionPlaceholder)
+	}
+
+	switch c.Scheme {
+	case PartitionHash:
+		if c.Count < 2 {
+			return nil, errors.New("partitions: hash scheme requires count of at least 2")
+		}
+	case PartitionRange:
+		if len(c.Boundaries) == 0 {
+			return nil, errors.New("partitions: range scheme requires boundaries")
+		}
+		for i, b := range c.Boundaries {
+			n, ok := new(big.Int).SetString(b, 16)
+			if !ok {
+				return nil, fmt.Errorf("partitions: invalid boundary %q", b)
+			}
+			if i > 0 && n.Cmp(p.boundaries[i-1]) <= 0 {
+				return nil, errors.New("partitions: boundaries must be ascending")
+			}
+			p.boundaries = append(p.boundaries, n)
+		}
+	default:
+		return nil, fmt.Errorf("partitions: unknown scheme %q", c.Scheme)
+	}
+
+	return p, nil
+}
+
+// count returns the number of CRLs the issuer publishes
+func (p *partitioner) count() int {
+	switch p.cfg.Scheme {
+	case PartitionHash:
+		return p.cfg.Count
+	case PartitionRange:
+		return len(p.boundaries) + 1
+	default:
+		return 1
+	}
+}
+
+// of returns the partition holding serial. Hash buckets are the FNV-1a
+// hash of the big-endian serial bytes modulo the bucket count.
+func (p *partitioner) of(serial *big.Int) int {
+	switch p.cfg.Scheme {
+	case PartitionHash:
+		h := fnv.New64a()
+		h.Write(serial.Bytes())
+		return int(h.Sum64() % uint64(p.cfg.Count))
+	case PartitionRange:
+		return sort.Search(len(p.boundaries), func(i int) bool {
+			return p.boundaries[i].Cmp(serial) > 0
+		})
+	default:
+		return 0
+	}
+}
+
+// url returns the distribution point URL of a partition
+func (p *partitioner) url(partition int) string {
+	return strings.ReplaceAll(p.cfg.URLTemplate, partitionPlaceholder, strconv.Itoa(partition))
+}
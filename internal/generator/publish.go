@@ -0,0 +1,384 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/publisher"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/crl/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// ErrSignerUnavailable is returned by Publish when the signing key cannot
+// be reached
+var ErrSignerUnavailable = errors.New("CRL signer unavailable")
+
+// ErrInvalidWindow is returned by Publish for an invalid validity window
+var ErrInvalidWindow = errors.New("invalid CRL validity window")
+
+// ErrDistributionFailed is returned by Publish, together with the
+// publication, when uploads to some distribution targets failed
+var ErrDistributionFailed = errors.New("CRL distribution failed")
+
+// ErrUnknownPublisher is returned by RetryUpload for a publisher that is no
+// longer configured
+var ErrUnknownPublisher = errors.New("unknown publisher")
+
+// ErrRetrySuperseded is returned by RetryUpload for a queued CRL that a
+// newer one has replaced on its target
+var ErrRetrySuperseded = errors.New("publish retry superseded")
+
+// Backoff bounds of queued upload retries
+const (
+	retryBackoffInitial = 30 * time.Second
+	retryBackoffMax     = time.Hour
+)
+
+// TargetResult is the outcome of uploading one CRL to one publisher
+type TargetResult struct {
+	Publisher string
+	Partition int
+	Err       error
+	Duration  time.Duration
+}
+
+// Publication is the outcome of publishing an issuer's CRLs
+type Publication struct {
+	// CRLs holds one full CRL per partition
+	CRLs        []*CRL
+	PublishedAt time.Time
+
+	// Targets holds one result per publisher and partition
+	Targets []TargetResult
+}
+
+// Failed returns the number of failed uploads
+func (p *Publication) Failed() int {
+	n := 0
+	for _, t := range p.Targets {
+		if t.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// RevokedCount returns the number of entries over all partitions
+func (p *Publication) RevokedCount() int {
+	n := 0
+	for _, list := range p.CRLs {
+		n += list.RevokedCount
+	}
+	return n
+}
+
+// Size returns the total DER size of the published CRLs
+func (p *Publication) Size() int {
+	n := 0
+	for _, list := range p.CRLs {
+		n += len(list.DER)
+	}
+	return n
+}
+
+// NextUpdate returns the earliest nextUpdate of the published CRLs
+func (p *Publication) NextUpdate() time.Time {
+	next := p.CRLs[0].NextUpdate
+	for _, list := range p.CRLs[1:] {
+		if list.NextUpdate.Before(next) {
+			next = list.NextUpdate
+		}
+	}
+	return next
+}
+
+// Publish signs a new full CRL for every partition with validity window w,
+// uploads them to the distribution targets, makes them current and records
+// the publication, which becomes the base for delta CRLs. The CRLs are
+// recorded even if some uploads fail or the served CRLs fail verification;
+// the publication is then returned with an error wrapping
+// ErrDistributionFailed or ErrVerificationFailed.
+func (g *Generator) Publish(ctx context.Context, w Window) (*Publication, error) {
+	ctx, span := tracing.Start(ctx, "crl.publish", trace.WithAttributes(
+		attribute.String("crl.issuer_id", g.id),
+		attribute.Int("crl.partitions", g.Partitions()),
+	))
+	pub, err := g.publish(ctx, w)
+	tracing.End(span, err)
+
+	g.hooksMu.RLock()
+	hooks := g.publishHooks
+	g.hooksMu.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx, g, pub, err)
+	}
+	return pub, err
+}
+
+// PublishHook is called after every Publish with its result. pub is nil if
+// no CRL was signed. Hooks run on the publishing goroutine and must not
+// block.
+type PublishHook func(ctx context.Context, g *Generator, pub *Publication, err error)
+
+// OnPublish adds a hook called after every Publish
+func (g *Generator) OnPublish(hook PublishHook) {
+	g.hooksMu.Lock()
+	defer g.hooksMu.Unlock()
+	g.publishHooks = append(g.publishHooks, hook)
+}
+
+func (g *Generator) publish(ctx context.Context, w Window) (*Publication, error) {
+	if err := w.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWindow, err)
+	}
+
+	// Make sure the signing key is reachable before generating
+	if err := g.CheckHealth(ctx); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSignerUnavailable, err)
+	}
+
+	pub, err := g.publishLocked(ctx, w)
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range pub.CRLs {
+		g.SetCurrent(list)
+	}
+	// The cached delta CRL refers to the previous base
+	g.Invalidate()
+	pub.PublishedAt = time.Now()
+
+	if n := pub.Failed(); n > 0 {
+		return pub, fmt.Errorf("%w: %d of %d uploads failed", ErrDistributionFailed, n, len(pub.Targets))
+	}
+	// Verification must see the new CRL rather than a cached copy
+	g.purge(ctx, pub.CRLs)
+	if g.config().Verify.Enabled {
+		if err := g.verify(ctx, pub.CRLs); err != nil {
+			return pub, err
+		}
+	}
+	return pub, nil
+}
+
+// publishLocked signs, uploads and records the CRLs under the generation
+// lock. A replica signing a newer CRL waits until these uploads are done,
+// so no target receives an older CRL after a newer one.
+func (g *Generator) publishLocked(ctx context.Context, w Window) (*Publication, error) {
+	unlock, err := g.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	pub := &Publication{}
+	for p := 0; p < g.Partitions(); p++ {
+		list, err := g.generateLocked(ctx, p, w)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate partition %d: %w", p, err)
+		}
+		pub.CRLs = append(pub.CRLs, list)
+	}
+
+	pub.Targets = g.distribute(ctx, pub.CRLs)
+
+	last := pub.CRLs[len(pub.CRLs)-1]
+	err = g.store.MarkPublished(ctx, g.id, last.Number.Int64(), last.SnapshotAt, pub.NextUpdate())
+	if err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// distribute uploads the CRLs to all publishers concurrently. Each
+// publisher receives the partitions in order.
+func (g *Generator) distribute(ctx context.Context, lists []*CRL) []TargetResult {
+	publishers, _ := g.targets()
+	results := make([]TargetResult, len(publishers)*len(lists))
+
+	var wg sync.WaitGroup
+	for i, p := range publishers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j, list := range lists {
+				results[i*len(lists)+j] = g.upload(ctx, p, list)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// upload sends one CRL to one publisher
+func (g *Generator) upload(ctx context.Context, p publisher.Publisher, list *CRL) TargetResult {
+	artifact := publisher.Artifact{
+		IssuerID:   g.id,
+		Partition:  list.Partition,
+		Number:     list.Number,
+		DER:        list.DER,
+		ThisUpdate: list.ThisUpdate,
+		NextUpdate: list.NextUpdate,
+	}
+
+	ctx, span := tracing.Start(ctx, "crl.upload", trace.WithAttributes(
+		attribute.String("crl.issuer_id", g.id),
+		attribute.String("crl.publisher", p.Name()),
+		attribute.Int("crl.partition", list.Partition),
+		attribute.String("crl.number", list.Number.String()),
+		attribute.Int("crl.size", len(list.DER)),
+	))
+	start := time.Now()
+	err := p.Publish(ctx, artifact)
+	tracing.End(span, err)
+	result := TargetResult{
+		Publisher: p.Name(),
+		Partition: list.Partition,
+		Err:       err,
+		Duration:  time.Since(start),
+	}
+
+	fields := []zap.Field{
+		zap.String("issuer_id", g.id),
+		zap.String("publisher", p.Name()),
+		zap.Int("partition", list.Partition),
+		zap.String("crl_number", list.Number.String()),
+		zap.Duration("duration", result.Duration),
+	}
+	if err != nil {
+		g.logger.Error("CRL upload failed", append(fields, zap.Error(err))...)
+		g.queueRetry(ctx, p.Name(), list, err)
+	} else {
+		g.logger.Info("CRL uploaded", fields...)
+		// A pending retry of an older CRL is superseded
+		if err := g.store.DeleteRetry(ctx, g.id, p.Name(), list.Partition, list.Number.Int64()); err != nil {
+			g.logger.Warn("Failed to clear publish retry", append(fields, zap.Error(err))...)
+		}
+	}
+	return result
+}
+
+// queueRetry persists a failed upload for the retry worker
+func (g *Generator) queueRetry(ctx context.Context, name string, list *CRL, uploadErr error) {
+	err := g.store.EnqueueRetry(ctx, store.PublishRetry{
+		IssuerID:      g.id,
+		Publisher:     name,
+		Partition:     list.Partition,
+		CRLNumber:     list.Number.Int64(),
+		DER:           list.DER,
+		ThisUpdate:    list.ThisUpdate,
+		NextUpdate:    list.NextUpdate,
+		Attempts:      1,
+		NextAttemptAt: time.Now().Add(RetryBackoff(1)),
+		LastError:     uploadErr.Error(),
+	})
+	if err != nil {
+		g.logger.Error("Failed to queue publish retry",
+			zap.String("issuer_id", g.id),
+			zap.String("publisher", name),
+			zap.Int("partition", list.Partition),
+			zap.Error(err),
+		)
+	}
+}
+
+// RetryBackoff returns the wait after the given number of failed attempts:
+// 30s doubling up to an hour
+func RetryBackoff(attempts int) time.Duration {
+	d := retryBackoffInitial
+	for i := 1; i < attempts && d < retryBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, retryBackoffMax)
+}
+
+// RetryUpload uploads a queued CRL again to its publisher. The retry is
+// removed from the queue on success. It returns ErrRetrySuperseded without
+// uploading once a newer CRL has reached the target.
+func (g *Generator) RetryUpload(ctx context.Context, r store.PublishRetry) error {
+	// A publication uploading a newer CRL holds the lock and clears the
+	// retry when done
+	unlock, err := g.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var target publisher.Publisher
+	publishers, _ := g.targets()
+	for _, p := range publishers {
+		if p.Name() == r.Publisher {
+			target = p
+			break
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("%w: %s", ErrUnknownPublisher, r.Publisher)
+	}
+	pending, err := g.store.RetryPending(ctx, r)
+	if err != nil {
+		return err
+	}
+	if !pending {
+		return ErrRetrySuperseded
+	}
+
+	uploadCtx, span := tracing.Start(ctx, "crl.upload", trace.WithAttributes(
+		attribute.String("crl.issuer_id", g.id),
+		attribute.String("crl.publisher", r.Publisher),
+		attribute.Int("crl.partition", r.Partition),
+		attribute.Int64("crl.number", r.CRLNumber),
+		attribute.Int("crl.size", len(r.DER)),
+		attribute.Int("crl.upload_attempt", r.Attempts+1),
+	))
+	err = target.Publish(uploadCtx, publisher.Artifact{
+		IssuerID:   g.id,
+		Partition:  r.Partition,
+		Number:     big.NewInt(r.CRLNumber),
+		DER:        r.DER,
+		ThisUpdate: r.ThisUpdate,
+		NextUpdate: r.NextUpdate,
+	})
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+	return g.store.DeleteRetry(ctx, g.id, r.Publisher, r.Partition, r.CRLNumber)
+}
+
+// purge invalidates the CDN caches of the published CRLs. Failures are
+// logged only: caches still expire at the CRL's max-age.
+func (g *Generator) purge(ctx context.Context, lists []*CRL) {
+	partitions := make([]int, 0, len(lists))
+	for _, list := range lists {
+		partitions = append(partitions, list.Partition)
+	}
+	_, purgers := g.targets()
+	for _, p := range purgers {
+		purgeCtx, span := tracing.Start(ctx, "cdn.purge", trace.WithAttributes(
+			attribute.String("crl.issuer_id", g.id),
+			attribute.String("cdn.name", p.Name()),
+		))
+		err := p.Purge(purgeCtx, g.id, partitions)
+		tracing.End(span, err)
+		if err != nil {
+			metrics.CDNPurgeFailures.WithLabelValues(g.id, p.Name()).Inc()
+			g.logger.Warn("CDN purge failed",
+				zap.String("issuer_id", g.id),
+				zap.String("cdn", p.Name()),
+				zap.Error(err),
+			)
+			continue
+		}
+		g.logger.Info("CDN cache purged", zap.String("issuer_id", g.id), zap.String("cdn", p.Name()))
+	}
+}
@@ -0,0 +1,74 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownIssuer is returned when no generator is registered for an issuer
+var ErrUnknownIssuer = errors.New("unknown issuer")
+
+// Registry holds the generator of every configured issuer
+type Registry struct {
+	byID      map[string]*Generator
+	defaultID string
+}
+
+// NewRegistry creates an empty registry. defaultID names the issuer used
+// when a request does not specify one.
+func NewRegistry(defaultID string) *Registry {
+	return &Registry{
+		byID:      make(map[string]*Generator),
+		defaultID: defaultID,
+	}
+}
+
+// Add registers a generator under its issuer ID
+func (r *Registry) Add(g *Generator) error {
+	if _, ok := r.byID[g.ID()]; ok {
+		return fmt.Errorf("duplicate issuer %q", g.ID())
+	}
+	r.byID[g.ID()] = g
+	return nil
+}
+
+// Get returns the generator for the issuer id, or the default issuer's
+// generator when id is empty
+func (r *Registry) Get(id string) (*Generator, error) {
+	if id == "" {
+		id = r.defaultID
+	}
+	g, ok := r.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIssuer, id)
+	}
+	return g, nil
+}
+
+// Lookup resolves an issuer by ID or, failing that, by the common name of
+// its certificate
+func (r *Registry) Lookup(name string) (*Generator, error) {
+	g, err := r.Get(name)
+	if err == nil {
+		return g, nil
+	}
+	for _, g := range r.All() {
+		if g.Issuer().Subject.CommonName == name {
+			return g, nil
+		}
+	}
+	return nil, err
+}
+
+// All returns every registered generator ordered by issuer ID
+func (r *Registry) All() []*Generator {
+	all := make([]*Generator, 0, len(r.byID))
+	for _, g := range r.byID {
+		all = append(all, g)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].ID() < all[j].ID()
+	})
+	return all
+}
@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gigvault/crl/internal/metrics"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultVerifyAttempts = 3
+	defaultVerifyDelay    = 5 * time.Second
+	defaultVerifyTimeout  = 10 * time.Second
+
+	// maxServedCRLSize bounds the download of a published CRL
+	maxServedCRLSize = 64 << 20
+)
+
+// ErrVerificationFailed is returned by Publish, together with the
+// publication, when a distribution URL serves a stale or corrupted CRL
+var ErrVerificationFailed = errors.New("published CRL verification failed")
+
+// VerifyConfig downloads each CRL back from its public URLs after a
+// publication and checks that the served CRL is the one just signed
+type VerifyConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// URLs are fetched for every partition and may use {partition}. They
+	// default to the issuing distribution point (or partition) URL.
+	URLs []string `yaml:"urls"`
+
+	// Attempts and Delay allow for propagation through caches and replicas
+	Attempts int           `yaml:"attempts"`
+	Delay    time.Duration `yaml:"delay"`
+	Timeout  time.Duration `yaml:"timeout"` // per download
+}
+
+// withDefaults fills in the default attempts, delay and timeout
+func (c VerifyConfig) withDefaults() VerifyConfig {
+	if c.Attempts == 0 {
+		c.Attempts = defaultVerifyAttempts
+	}
+	if c.Delay == 0 {
+		c.Delay = defaultVerifyDelay
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultVerifyTimeout
+	}
+	return c
+}
+
+// Validate checks the verification settings; idpURL is the configured
+// issuing distribution point or partition URL template
+func (c VerifyConfig) Validate(idpURL string) error {
+	if !c.Enabled {
+		return nil
+	}
+	if len(c.URLs) == 0 && idpURL == "" {
+		return errors.New("verify: urls are required without an issuing distribution point url")
+	}
+	if c.Attempts < 0 || c.Delay < 0 || c.Timeout < 0 {
+		return errors.New("verify: attempts, delay and timeout must not be negative")
+	}
+	return nil
+}
+
+// verifyURLs returns the public URLs of a partition's CRL
+func (g *Generator) verifyURLs(partition int) []string {
+	cfg := g.config()
+	if len(cfg.Verify.URLs) == 0 {
+		if cfg.Partitions.Enabled() {
+			return []string{g.partitions.url(partition)}
+		}
+		return []string{cfg.IssuingDistributionPoint.URL}
+	}
+	urls := make([]string, 0, len(cfg.Verify.URLs))
+	for _, u := range cfg.Verify.URLs {
+		urls = append(urls, strings.ReplaceAll(u, partitionPlaceholder, strconv.Itoa(partition)))
+	}
+	return urls
+}
+
+// verify checks every public URL of the published CRLs
+func (g *Generator) verify(ctx context.Context, lists []*CRL) error {
+	cfg := g.config().Verify.withDefaults()
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	var errs []error
+	for _, list := range lists {
+		for _, url := range g.verifyURLs(list.Partition) {
+			var err error
+			for attempt := 1; attempt <= cfg.Attempts; attempt++ {
+				if err = g.verifyURL(ctx, client, url, list); err == nil {
+					break
+				}
+				if attempt == cfg.Attempts {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(cfg.Delay):
+				}
+			}
+			if err != nil {
+				metrics.VerificationFailures.WithLabelValues(g.id).Inc()
+				g.logger.Error("Published CRL failed verification",
+					zap.String("issuer_id", g.id),
+					zap.Int("partition", list.Partition),
+					zap.String("url", url),
+					zap.String("crl_number", list.Number.String()),
+					zap.Error(err),
+				)
+				errs = append(errs, fmt.Errorf("%s: %w", url, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrVerificationFailed, errors.Join(errs...))
+	}
+	return nil
+}
+
+// verifyURL downloads the CRL served at url and compares it with list
+func (g *Generator) verifyURL(ctx context.Context, client *http.Client, url string, list *CRL) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	// Bypass caches that still hold the previous CRL
+	req.Header.Set("Cache-Control", "no-cache")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxServedCRLSize))
+	if err != nil {
+		return err
+	}
+
+	der := body
+	if block, _ := pem.Decode(body); block != nil {
+		der = block.Bytes
+	}
+	served, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return fmt.Errorf("served CRL is corrupted: %w", err)
+	}
+	if err := served.CheckSignatureFrom(g.issuer); err != nil {
+		return fmt.Errorf("served CRL has an invalid signature: %w", err)
+	}
+	if served.Number == nil || served.Number.Cmp(list.Number) != 0 {
+		return fmt.Errorf("served CRL number %v, expected %s", served.Number, list.Number)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// defaultValidity is the interval from signing to nextUpdate
+	defaultValidity = 24 * time.Hour
+
+	// maxValidity bounds configured and per-request validity periods
+	maxValidity = 31 * 24 * time.Hour
+)
+
+// Window is the validity period of signed CRLs. nextUpdate is Validity
+// after signing; thisUpdate is backdated by Overlap so relying parties with
+// slow clocks accept a new CRL at once.
+type Window struct {
+	Validity time.Duration `yaml:"validity"`
+	Overlap  time.Duration `yaml:"overlap"`
+}
+
+// withDefaults fills in the default validity
+func (w Window) withDefaults() Window {
+	if w.Validity == 0 {
+		w.Validity = defaultValidity
+	}
+	return w
+}
+
+// Validate checks the validity and overlap periods
+func (w Window) Validate() error {
+	w = w.withDefaults()
+	if w.Validity < 0 || w.Validity > maxValidity {
+		return fmt.Errorf("validity must be between 0 and %s", maxValidity)
+	}
+	if w.Overlap < 0 {
+		return errors.New("overlap must not be negative")
+	}
+	if w.Overlap >= w.Validity {
+		return errors.New("overlap must be shorter than validity")
+	}
+	return nil
+}
+
+// bounds returns thisUpdate and nextUpdate for a CRL signed at now
+func (w Window) bounds(now time.Time) (time.Time, time.Time) {
+	w = w.withDefaults()
+	now = now.UTC().Truncate(time.Second)
+	return now.Add(-w.Overlap), now.Add(w.Validity)
+}
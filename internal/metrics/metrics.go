@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "gigvault_crl"
+
+// SigningDuration observes the latency of CRL signing operations
+var SigningDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "signing_duration_seconds",
+	Help:      "Time spent signing a CRL, by signer backend and result.",
+	Buckets:   prometheus.ExponentialBuckets(0.005, 2, 12),
+}, []string{"backend", "result"})
+
+// SigningRetries counts signing attempts retried after throttling or
+// transient backend errors
+var SigningRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "signing_retries_total",
+	Help:      "Signing calls retried after a transient backend error.",
+}, []string{"backend"})
+
+// EmergencyPublishDuration observes the time from receiving a key or CA
+// compromise revocation to publishing the CRL that lists it
+var EmergencyPublishDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Name:      "emergency_publish_duration_seconds",
+	Help:      "Time from a compromise revocation to the emergency CRL publication, by issuer and result.",
+	Buckets:   prometheus.ExponentialBuckets(0.05, 2, 12),
+}, []string{"issuer_id", "result"})
+
+// VerificationFailures counts distribution URLs that served a stale or
+// corrupted CRL after a publication
+var VerificationFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "publish_verification_failures_total",
+	Help:      "Distribution URLs serving a stale or corrupted CRL after publication, by issuer.",
+}, []string{"issuer_id"})
+
+// CDNPurgeFailures counts failed CDN cache purges after a publication
+var CDNPurgeFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "cdn_purge_failures_total",
+	Help:      "CDN cache purges that failed after a publication, by issuer and CDN.",
+}, []string{"issuer_id", "cdn"})
+
+// PublishRetries counts queued upload retries to distribution targets
+var PublishRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "publish_retries_total",
+	Help:      "Queued CRL uploads retried, by issuer, publisher and result.",
+}, []string{"issuer_id", "publisher", "result"})
+
+// OCSPCacheLookups counts lookups of pre-signed OCSP responses
+var OCSPCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "ocsp_cache_lookups_total",
+	Help:      "OCSP requests answered from the response cache (hit) or signed on demand (miss).",
+}, []string{"result"})
+
+// RateLimited counts gRPC calls rejected by the per-client rate limiter
+var RateLimited = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "rate_limited_total",
+	Help:      "gRPC calls rejected for exceeding the caller's rate limit, by method.",
+}, []string{"method"})
+
+// ConfigReloads counts configuration reloads
+var ConfigReloads = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "config_reloads_total",
+	Help:      "Configuration reloads triggered by SIGHUP or a change of the file, by result.",
+}, []string{"result"})
+
+// CAVerifications counts lookups of revoked serials in the CA service
+var CAVerifications = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "ca_verifications_total",
+	Help:      "Revoked serials looked up in the CA service, by result: issued, unknown, mismatch or error.",
+}, []string{"result"})
+
+// EntriesArchived counts entries of expired certificates moved to the
+// archive
+var EntriesArchived = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "entries_archived_total",
+	Help:      "Entries of expired certificates moved from crl_entries to the archive, by issuer.",
+}, []string{"issuer_id"})
+
+// Result returns the result label for err
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// CacheResult returns the result label of a cache lookup
+func CacheResult(hit bool) string {
+	if hit {
+		return "hit"
+	}
+	return "miss"
+}
+
+// StaleCRLsServed counts last known good CRLs served because a new CRL
+// could not be signed
+var StaleCRLsServed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "stale_crls_served_total",
+	Help:      "Last known good CRLs served because a new CRL could not be signed, by issuer.",
+}, []string{"issuer_id"})
+
+// StatusCacheLookups counts revocation status lookups in the Redis cache
+var StatusCacheLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "status_cache_lookups_total",
+	Help:      "Revocation status lookups in the Redis status cache, by result: hit, miss or error.",
+}, []string{"result"})
+
+// StatusFilterLookups counts status checks answered by the Bloom filter
+var StatusFilterLookups = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "status_filter_lookups_total",
+	Help:      "Status checks tested against the revoked serial Bloom filter, by result: negative (answered) or maybe (looked up).",
+}, []string{"result"})
+
+// EventsExported counts revocation events written to Parquet files in the
+// object store
+var EventsExported = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "events_exported_total",
+	Help:      "Revocation events exported to Parquet files in the object store.",
+})
+
+// EventsSent counts events sent to the event sinks
+var EventsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: namespace,
+	Name:      "events_sent_total",
+	Help:      "Revocation and CRL events sent to the event sinks, by sink and result.",
+}, []string{"sink", "result"})
+
+// SchedulerLeader is 1 while this replica runs the scheduled publications
+// under leader election
+var SchedulerLeader = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "scheduler_leader",
+	Help:      "Whether this replica holds the scheduler lock and runs the scheduled CRL publications (1) or not (0).",
+})
+
+// ReplicaLag is the last measured lag of the database read replica
+var ReplicaLag = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "database_replica_lag_seconds",
+	Help:      "How far the database read replica trails the primary; reads go to the primary while it exceeds database.replica_max_lag.",
+})
+
+// DatabaseCircuitOpen is 1 while the database circuit breaker fails calls
+// fast
+var DatabaseCircuitOpen = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: namespace,
+	Name:      "database_circuit_open",
+	Help:      "Whether the database circuit breaker is open (1), failing database calls without sending them, or closed (0).",
+})
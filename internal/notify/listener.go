@@ -0,0 +1,175 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/ocsp"
+	"github.com/gigvault/crl/internal/statuscache"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultDebounce = 500 * time.Millisecond
+
+	// minBackoff and maxBackoff bound the delay before listening again
+	// after the connection failed
+	minBackoff = time.Second
+	maxBackoff = 30 * time.Second
+)
+
+// Config configures regeneration driven by Postgres notifications
+type Config struct {
+	// Enabled listens for the revocation events of every replica and
+	// refreshes the cached CRLs and OCSP responses of this one
+	Enabled bool `yaml:"enabled"`
+
+	// Debounce coalesces the events arriving within this long into one
+	// regeneration per issuer (default 500ms)
+	Debounce time.Duration `yaml:"debounce"`
+}
+
+// Validate applies defaults and checks the notify config
+func (c *Config) Validate() error {
+	if c.Debounce < 0 {
+		return errors.New("debounce must not be negative")
+	}
+	if c.Debounce == 0 {
+		c.Debounce = defaultDebounce
+	}
+	return nil
+}
+
+// Listener regenerates the CRLs of issuers whose entries changed on any
+// replica, as announced by LISTEN/NOTIFY
+type Listener struct {
+	store     *store.Store
+	issuers   *generator.Registry
+	responder *ocsp.Responder
+	status    *statuscache.Cache
+	cfg       Config
+	logger    *logger.Logger
+
+	mu      sync.Mutex
+	pending map[string]bool
+	wake    chan struct{}
+}
+
+// NewListener creates a listener refreshing the CRLs of issuers and, if
+// not nil, the cached OCSP responses of responder and statuses of status
+func NewListener(st *store.Store, issuers *generator.Registry, responder *ocsp.Responder, status *statuscache.Cache, cfg Config) *Listener {
+	return &Listener{
+		store:     st,
+		issuers:   issuers,
+		responder: responder,
+		status:    status,
+		cfg:       cfg,
+		logger:    logger.Global(),
+		pending:   make(map[string]bool),
+		wake:      make(chan struct{}, 1),
+	}
+}
+
+// Run listens until ctx is done, listening again with backoff whenever the
+// connection fails
+func (l *Listener) Run(ctx context.Context) {
+	go l.regenerate(ctx)
+
+	backoff := minBackoff
+	for {
+		started := time.Now()
+		err := l.store.ListenRevocations(ctx, func(n store.RevocationNotification) {
+			l.changed(ctx, n)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		// A connection that listened for a while starts over
+		if time.Since(started) > maxBackoff {
+			backoff = minBackoff
+		}
+		l.logger.Warn("Lost revocation notifications; listening again",
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+		// Events missed meanwhile are not announced again
+		l.invalidateAll()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+// changed invalidates the issuer's cached CRLs and the certificate's OCSP
+// response and status, and schedules a regeneration
+func (l *Listener) changed(ctx context.Context, n store.RevocationNotification) {
+	gen, err := l.issuers.Get(n.IssuerID)
+	if err != nil {
+		return
+	}
+	gen.Invalidate()
+	l.responder.StatusChanged(ctx, n.IssuerID, n.Serial)
+	l.status.Delete(ctx, n.IssuerID, n.Serial)
+
+	l.mu.Lock()
+	l.pending[n.IssuerID] = true
+	l.mu.Unlock()
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (l *Listener) invalidateAll() {
+	for _, gen := range l.issuers.All() {
+		gen.Invalidate()
+	}
+}
+
+// regenerate signs the CRLs of the issuers changed within each debounce
+// window, so the next GetCRL is served from memory
+func (l *Listener) regenerate(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.wake:
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.cfg.Debounce):
+		}
+
+		l.mu.Lock()
+		pending := l.pending
+		l.pending = make(map[string]bool)
+		l.mu.Unlock()
+
+		for id := range pending {
+			gen, err := l.issuers.Get(id)
+			if err != nil {
+				continue
+			}
+			for p := 0; p < gen.Partitions(); p++ {
+				if _, err := gen.Latest(ctx, p); err != nil {
+					l.logger.Warn("Failed to regenerate CRL after revocation notification",
+						zap.String("issuer_id", id),
+						zap.Int("partition", p),
+						zap.Error(err),
+					)
+				}
+			}
+			l.logger.Debug("Regenerated CRL after revocation notifications", zap.String("issuer_id", id))
+		}
+	}
+}
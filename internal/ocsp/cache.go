@@ -0,0 +1,212 @@
+package ocsp
+
+import (
+	"context"
+	"crypto"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/serial"
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+)
+
+// defaultMaxCached bounds the responses signed on demand for serials
+// without a revocation
+const defaultMaxCached = 100000
+
+// CacheConfig pre-signs OCSP responses so requests are answered without
+// touching the store or the signing key
+type CacheConfig struct {
+	Enabled bool `yaml:"enabled"`
+
+	// RefreshInterval re-signs every cached response; it defaults to half
+	// the response validity
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+
+	// MaxEntries bounds the cache of on-demand responses for good serials
+	MaxEntries int `yaml:"max_entries"`
+}
+
+// cacheKey identifies a response: the CertID hash algorithm is part of the
+// signed response
+type cacheKey struct {
+	issuerID string
+	hash     crypto.Hash
+	serial   string
+}
+
+// cachedResponse is a signed response and when to re-sign it
+type cachedResponse struct {
+	resp      response
+	refreshAt time.Time
+}
+
+// responseCache holds signed responses by issuer, hash and serial
+type responseCache struct {
+	mu         sync.RWMutex
+	entries    map[cacheKey]cachedResponse
+	refresh    time.Duration
+	maxEntries int
+}
+
+func newResponseCache(cfg CacheConfig, validity time.Duration) *responseCache {
+	c := &responseCache{
+		entries:    make(map[cacheKey]cachedResponse),
+		refresh:    cfg.RefreshInterval,
+		maxEntries: cfg.MaxEntries,
+	}
+	if c.refresh == 0 {
+		c.refresh = validity / 2
+	}
+	if c.maxEntries == 0 {
+		c.maxEntries = defaultMaxCached
+	}
+	return c
+}
+
+// get returns a fresh cached response
+func (c *responseCache) get(key cacheKey) (response, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.refreshAt) {
+		return response{}, false
+	}
+	return entry.resp, true
+}
+
+// put caches a response. On-demand responses are dropped once the cache
+// is full; pre-signed responses of revoked serials always fit.
+func (c *responseCache) put(key cacheKey, resp response, presigned bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.entries[key]; !exists && !presigned && len(c.entries) >= c.maxEntries {
+		return
+	}
+	refreshAt := time.Now().Add(c.refresh)
+	if !resp.nextUpdate.IsZero() && resp.nextUpdate.Before(refreshAt) {
+		refreshAt = resp.nextUpdate
+	}
+	c.entries[key] = cachedResponse{resp: resp, refreshAt: refreshAt}
+}
+
+// drop removes every cached response for a serial
+func (c *responseCache) drop(issuerID, serial string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.issuerID == issuerID && key.serial == serial {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// prune removes responses due for re-signing
+func (c *responseCache) prune() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.refreshAt) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Run pre-signs the responses of every revoked serial and refreshes them
+// until ctx is cancelled. It returns at once when caching is disabled.
+func (r *Responder) Run(ctx context.Context) {
+	if r.cache == nil {
+		return
+	}
+	ticker := time.NewTicker(r.cache.refresh)
+	defer ticker.Stop()
+
+	for {
+		r.presign(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// presign signs fresh responses for the revoked serials of every issuer
+// and evicts stale on-demand responses
+func (r *Responder) presign(ctx context.Context) {
+	start := time.Now()
+	r.cache.prune()
+
+	for _, gen := range r.issuers.All() {
+		entries, err := r.store.Reader().ListEntries(ctx, gen.ID())
+		if err != nil {
+			r.logger.Warn("Failed to list revocations for OCSP pre-signing", zap.String("issuer_id", gen.ID()), zap.Error(err))
+			continue
+		}
+		signed := 0
+		for i := range entries {
+			if ctx.Err() != nil {
+				return
+			}
+			if err := r.presignEntry(gen, &entries[i]); err != nil {
+				r.logger.Warn("Failed to pre-sign OCSP response",
+					zap.String("issuer_id", gen.ID()),
+					zap.String("serial", entries[i].Serial),
+					zap.Error(err),
+				)
+				continue
+			}
+			signed++
+		}
+		r.logger.Info("OCSP responses pre-signed",
+			zap.String("issuer_id", gen.ID()),
+			zap.Int("responses", signed),
+			zap.Duration("duration", time.Since(start)),
+		)
+	}
+}
+
+// presignEntry signs and caches the SHA-1 CertID response for an entry,
+// the hash RFC 5019 clients use
+func (r *Responder) presignEntry(gen *generator.Generator, entry *store.Entry) error {
+	if entry.Number == nil {
+		return serial.ErrInvalid
+	}
+	template := r.template(gen, entry.Number, entry)
+	template.IssuerHash = crypto.SHA1
+	resp, err := r.sign(gen, template)
+	if err != nil {
+		return err
+	}
+	r.cache.put(cacheKey{gen.ID(), crypto.SHA1, serial.Hex(entry.Number)}, resp, true)
+	return nil
+}
+
+// StatusChanged refreshes the cached responses of a serial after it was
+// revoked, held or released. It is a no-op without a cache.
+func (r *Responder) StatusChanged(ctx context.Context, issuerID, serialHex string) {
+	if r == nil || r.cache == nil {
+		return
+	}
+	number, err := serial.ParseHex(serialHex)
+	if err != nil {
+		return
+	}
+	r.cache.drop(issuerID, serial.Hex(number))
+
+	gen, err := r.issuers.Get(issuerID)
+	if err != nil {
+		return
+	}
+	entry, err := r.store.GetEntry(ctx, issuerID, serialHex)
+	if err != nil {
+		// Signed on demand by the next request
+		return
+	}
+	if err := r.presignEntry(gen, entry); err != nil {
+		r.logger.Warn("Failed to pre-sign OCSP response", zap.String("issuer_id", issuerID), zap.String("serial", serialHex), zap.Error(err))
+	}
+}
@@ -0,0 +1,91 @@
+package ocsp
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gigvault/crl/internal/signer"
+	"go.uber.org/zap"
+)
+
+// oidOCSPNoCheck marks responder certificates that relying parties need
+// not check for revocation (RFC 6960 section 4.2.2.2.1)
+var oidOCSPNoCheck = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 5}
+
+// AddDelegate registers a delegated responder certificate and key for the
+// issuer. Several delegates may be registered to rotate them: each response
+// is signed by the valid delegate issued most recently.
+func (r *Responder) AddDelegate(issuerID string, s signer.Signer) error {
+	gen, err := r.issuers.Get(issuerID)
+	if err != nil {
+		return err
+	}
+	cert := s.Certificate()
+
+	hasEKU := false
+	for _, eku := range cert.ExtKeyUsage {
+		if eku == x509.ExtKeyUsageOCSPSigning {
+			hasEKU = true
+		}
+	}
+	if !hasEKU {
+		return errors.New("responder certificate lacks the id-kp-OCSPSigning extended key usage")
+	}
+	if err := cert.CheckSignatureFrom(gen.Issuer()); err != nil {
+		return fmt.Errorf("responder certificate is not issued by %s: %w", gen.Issuer().Subject, err)
+	}
+
+	noCheck := false
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidOCSPNoCheck) {
+			noCheck = true
+		}
+	}
+	if !noCheck {
+		r.logger.Warn("OCSP responder certificate has no id-pkix-ocsp-nocheck extension",
+			zap.String("issuer_id", issuerID),
+			zap.String("responder", cert.Subject.String()),
+		)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delegates := append(r.delegates[issuerID], s)
+	// Newest first, so delegateFor picks the most recent valid certificate
+	sort.SliceStable(delegates, func(i, j int) bool {
+		return delegates[i].Certificate().NotBefore.After(delegates[j].Certificate().NotBefore)
+	})
+	r.delegates[issuerID] = delegates
+	return nil
+}
+
+// delegateFor returns the delegate to sign a response valid until
+// nextUpdate, or nil to sign with the issuer key. A delegate whose
+// certificate expires before nextUpdate is used only if no other is valid.
+func (r *Responder) delegateFor(issuerID string, nextUpdate time.Time) signer.Signer {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	now := time.Now()
+	var fallback signer.Signer
+	for _, d := range r.delegates[issuerID] {
+		cert := d.Certificate()
+		if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+			continue
+		}
+		if !nextUpdate.After(cert.NotAfter) {
+			return d
+		}
+		if fallback == nil {
+			fallback = d
+		}
+	}
+	if fallback == nil && len(r.delegates[issuerID]) > 0 {
+		r.logger.Warn("No valid OCSP responder certificate; signing with the issuer key", zap.String("issuer_id", issuerID))
+	}
+	return fallback
+}
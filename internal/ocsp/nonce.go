@@ -0,0 +1,52 @@
+package ocsp
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+)
+
+// maxNonceLen is the longest nonce RFC 8954 allows, in octets
+const maxNonceLen = 32
+
+// oidNonce identifies the OCSP nonce extension (RFC 6960 section 4.4.1)
+var oidNonce = asn1.ObjectIdentifier{1, 3, 6, 1, 5, 5, 7, 48, 1, 2}
+
+// errInvalidNonce is returned for nonces outside 1 to 32 octets
+var errInvalidNonce = errors.New("invalid OCSP nonce")
+
+// ocspRequest and tbsRequest decode just enough of an OCSPRequest to
+// reach the request extensions, which ocsp.ParseRequest discards
+type ocspRequest struct {
+	TBSRequest tbsRequest
+}
+
+type tbsRequest struct {
+	Version           int           `asn1:"explicit,tag:0,default:0,optional"`
+	RequestorName     asn1.RawValue `asn1:"explicit,tag:1,optional"`
+	RequestList       []asn1.RawValue
+	RequestExtensions []pkix.Extension `asn1:"explicit,tag:2,optional"`
+}
+
+// requestNonce returns the nonce extension of a DER OCSP request, if any
+func requestNonce(der []byte) (*pkix.Extension, error) {
+	var req ocspRequest
+	if _, err := asn1.Unmarshal(der, &req); err != nil {
+		return nil, err
+	}
+	for _, ext := range req.TBSRequest.RequestExtensions {
+		if !ext.Id.Equal(oidNonce) {
+			continue
+		}
+		var nonce []byte
+		if _, err := asn1.Unmarshal(ext.Value, &nonce); err != nil {
+			return nil, errInvalidNonce
+		}
+		if len(nonce) < 1 || len(nonce) > maxNonceLen {
+			return nil, errInvalidNonce
+		}
+		// Echoed unchanged, so the response matches byte for byte
+		return &pkix.Extension{Id: oidNonce, Value: ext.Value}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,379 @@
+package ocsp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha1"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/revocation"
+	"github.com/gigvault/crl/internal/serial"
+	"github.com/gigvault/crl/internal/signer"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"github.com/gigvault/shared/pkg/models"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	contentTypeRequest  = "application/ocsp-request"
+	contentTypeResponse = "application/ocsp-response"
+
+	// maxRequestSize bounds OCSP request bodies
+	maxRequestSize = 64 << 10
+
+	// defaultValidity is the interval from thisUpdate to nextUpdate
+	defaultValidity = time.Hour
+)
+
+// Config configures the OCSP responder listener
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+
+	// Validity is how long responses may be cached (nextUpdate - thisUpdate)
+	Validity time.Duration `yaml:"validity"`
+
+	// Cache pre-signs responses instead of signing per request
+	Cache CacheConfig `yaml:"cache"`
+
+	// Nonce echoes the request nonce in responses. Responses with a nonce
+	// are signed per request and never cached.
+	Nonce bool `yaml:"nonce"`
+}
+
+// Validate checks the listener and cache settings
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Port == 0 {
+		return errors.New("port is required")
+	}
+	if c.Validity < 0 || c.Cache.RefreshInterval < 0 || c.Cache.MaxEntries < 0 {
+		return errors.New("validity, cache.refresh_interval and cache.max_entries must not be negative")
+	}
+	validity := c.Validity
+	if validity == 0 {
+		validity = defaultValidity
+	}
+	if c.Cache.RefreshInterval >= validity {
+		return errors.New("cache.refresh_interval must be shorter than validity")
+	}
+	return nil
+}
+
+// Responder answers OCSP requests (RFC 6960) from the revocations in the
+// store, for every issuer this service signs CRLs for
+type Responder struct {
+	store    *store.Store
+	issuers  *generator.Registry
+	validity time.Duration
+	nonce    bool
+	cache    *responseCache // nil when caching is disabled
+	logger   *logger.Logger
+
+	mu        sync.RWMutex
+	delegates map[string][]signer.Signer // responder keys by issuer, newest first
+}
+
+// NewResponder creates a new OCSP responder
+func NewResponder(st *store.Store, issuers *generator.Registry, cfg Config) *Responder {
+	validity := cfg.Validity
+	if validity == 0 {
+		validity = defaultValidity
+	}
+	r := &Responder{
+		store:    st,
+		issuers:  issuers,
+		validity: validity,
+		nonce:    cfg.Nonce,
+		logger:   logger.Global(),
+
+		delegates: make(map[string][]signer.Signer),
+	}
+	if cfg.Cache.Enabled {
+		r.cache = newResponseCache(cfg.Cache, validity)
+	}
+	return r
+}
+
+// response is a DER OCSP response. Signed responses carry their validity
+// for HTTP caching; error responses and responses with a nonce are not
+// cacheable.
+type response struct {
+	der        []byte
+	thisUpdate time.Time
+	nextUpdate time.Time
+	cacheable  bool
+}
+
+// errorResponse wraps an unsigned OCSP error response
+func errorResponse(der []byte) response {
+	return response{der: der}
+}
+
+// Routes serves OCSP requests POSTed to / and the GET form /{base64 request}
+func (r *Responder) Routes() http.Handler {
+	// Base64 requests may contain "/" and "//", which must not be cleaned
+	router := mux.NewRouter().SkipClean(true).UseEncodedPath()
+	router.HandleFunc("/", r.ServeOCSP).Methods("POST")
+	router.PathPrefix("/").HandlerFunc(r.ServeOCSPGet).Methods("GET")
+	return router
+}
+
+// ServeOCSP answers a DER OCSP request. Errors are reported as OCSP error
+// responses with status 200, as RFC 6960 appendix A requires.
+func (r *Responder) ServeOCSP(w http.ResponseWriter, req *http.Request) {
+	if ct := req.Header.Get("Content-Type"); ct != contentTypeRequest {
+		http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestSize+1))
+	if err != nil || len(body) > maxRequestSize {
+		writeResponse(w, errorResponse(ocsp.MalformedRequestErrorResponse))
+		return
+	}
+
+	writeResponse(w, r.respond(req.Context(), body))
+}
+
+// ServeOCSPGet answers a request encoded in the URL path (RFC 6960
+// appendix A.1). Successful responses are cacheable until nextUpdate, so a
+// CDN can serve them (RFC 5019 section 6).
+func (r *Responder) ServeOCSPGet(w http.ResponseWriter, req *http.Request) {
+	der, err := decodeGetRequest(req.URL.EscapedPath())
+	if err != nil {
+		writeResponse(w, errorResponse(ocsp.MalformedRequestErrorResponse))
+		return
+	}
+
+	resp := r.respond(req.Context(), der)
+	if resp.cacheable {
+		maxAge := int(time.Until(resp.nextUpdate).Seconds())
+		if maxAge < 0 {
+			maxAge = 0
+		}
+		sum := sha1.Sum(resp.der)
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d, no-transform, must-revalidate", maxAge))
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("Last-Modified", resp.thisUpdate.UTC().Format(http.TimeFormat))
+		w.Header().Set("Expires", resp.nextUpdate.UTC().Format(http.TimeFormat))
+	} else {
+		w.Header().Set("Cache-Control", "no-store")
+	}
+	writeResponse(w, resp)
+}
+
+// decodeGetRequest recovers the DER request from a GET path. Clients
+// differ in how they encode it: percent-encoded or raw base64, "+" turned
+// into a space by form decoding, missing padding, or the URL-safe alphabet.
+func decodeGetRequest(escapedPath string) ([]byte, error) {
+	encoded, err := url.PathUnescape(strings.TrimLeft(escapedPath, "/"))
+	if err != nil {
+		return nil, err
+	}
+	encoded = strings.ReplaceAll(encoded, " ", "+")
+	if encoded == "" || len(encoded) > base64.StdEncoding.EncodedLen(maxRequestSize) {
+		return nil, errors.New("invalid OCSP GET request")
+	}
+
+	trimmed := strings.TrimRight(encoded, "=")
+	if strings.ContainsAny(trimmed, "-_") {
+		return base64.RawURLEncoding.DecodeString(trimmed)
+	}
+	return base64.RawStdEncoding.DecodeString(trimmed)
+}
+
+// respond builds the response to a DER request
+func (r *Responder) respond(ctx context.Context, der []byte) response {
+	request, err := ocsp.ParseRequest(der)
+	if err != nil {
+		return errorResponse(ocsp.MalformedRequestErrorResponse)
+	}
+
+	gen := r.issuerOf(request)
+	if gen == nil {
+		// Not a CA this responder is authoritative for
+		return errorResponse(ocsp.UnauthorizedErrorResponse)
+	}
+
+	var nonce *pkix.Extension
+	if r.nonce {
+		if nonce, err = requestNonce(der); err != nil {
+			return errorResponse(ocsp.MalformedRequestErrorResponse)
+		}
+	}
+	cache := r.cache
+	if nonce != nil {
+		cache = nil
+	}
+
+	key := cacheKey{gen.ID(), request.HashAlgorithm, serial.Hex(request.SerialNumber)}
+	if cache != nil {
+		resp, hit := cache.get(key)
+		metrics.OCSPCacheLookups.WithLabelValues(metrics.CacheResult(hit)).Inc()
+		if hit {
+			return resp
+		}
+	}
+
+	template, err := r.status(ctx, gen, request.SerialNumber)
+	if err != nil {
+		r.logger.Error("Failed to look up certificate status",
+			zap.String("issuer_id", gen.ID()),
+			zap.String("serial", request.SerialNumber.Text(16)),
+			zap.Error(err),
+		)
+		if errors.Is(err, store.ErrUnavailable) {
+			return errorResponse(ocsp.TryLaterErrorResponse)
+		}
+		return errorResponse(ocsp.InternalErrorErrorResponse)
+	}
+	template.IssuerHash = request.HashAlgorithm
+	if nonce != nil {
+		template.ExtraExtensions = []pkix.Extension{*nonce}
+	}
+
+	resp, err := r.sign(gen, template)
+	if err != nil {
+		r.logger.Error("Failed to sign OCSP response", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		return errorResponse(ocsp.TryLaterErrorResponse)
+	}
+	if nonce != nil {
+		// Specific to one request
+		resp.cacheable = false
+	}
+	if cache != nil {
+		cache.put(key, resp, false)
+	}
+	return resp
+}
+
+// sign signs a response with the issuer's delegated responder key, which
+// is included in the response, or with the issuer key itself
+func (r *Responder) sign(gen *generator.Generator, template ocsp.Response) (response, error) {
+	responderCert, key := gen.Issuer(), crypto.Signer(gen.Signer())
+	if d := r.delegateFor(gen.ID(), template.NextUpdate); d != nil {
+		responderCert, key = d.Certificate(), d
+		template.Certificate = responderCert
+	}
+
+	der, err := ocsp.CreateResponse(gen.Issuer(), responderCert, template, key)
+	if err != nil {
+		return response{}, err
+	}
+	return response{
+		der:        der,
+		thisUpdate: template.ThisUpdate,
+		nextUpdate: template.NextUpdate,
+		cacheable:  true,
+	}, nil
+}
+
+// status looks up the serial number and returns its response template
+func (r *Responder) status(ctx context.Context, gen *generator.Generator, number *big.Int) (ocsp.Response, error) {
+	if serial.Validate(number) != nil {
+		return r.template(gen, number, nil), nil
+	}
+	entry, err := r.store.Reader().GetEntry(ctx, gen.ID(), serial.Hex(number))
+	switch {
+	case errors.Is(err, store.ErrEntryNotFound):
+		return r.template(gen, number, nil), nil
+	case err != nil:
+		return ocsp.Response{}, err
+	}
+	return r.template(gen, number, entry), nil
+}
+
+// template returns the response for the serial number given its entry,
+// nil when it has none. Certificates without a revocation are good; serials
+// no conforming CA can have issued are unknown.
+func (r *Responder) template(gen *generator.Generator, number *big.Int, entry *store.Entry) ocsp.Response {
+	now := time.Now()
+	template := ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: number,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(r.validity),
+	}
+
+	switch {
+	case serial.Validate(number) != nil:
+		template.Status = ocsp.Unknown
+		return template
+	case entry == nil || entry.Status == store.StatusReleased || entry.Status == store.StatusRemoved:
+		return template
+	case entry.EffectiveAt != nil && entry.EffectiveAt.After(now):
+		// Good until a scheduled revocation takes effect
+		if entry.EffectiveAt.Before(template.NextUpdate) {
+			template.NextUpdate = *entry.EffectiveAt
+		}
+		return template
+	case entry.CertificateIssuer != nil && !gen.IsCRLIssuer(entry.CertificateIssuer):
+		// Entries of other CAs on an indirect CRL say nothing about this one
+		return template
+	}
+
+	template.Status = ocsp.Revoked
+	template.RevokedAt = entry.RevokedAt
+	reason, err := revocation.ParseReason(entry.Reason)
+	if err != nil {
+		reason = ocsp.Unspecified
+	}
+	template.RevocationReason = reason
+	if entry.Status == store.StatusOnHold {
+		template.RevocationReason = models.ReasonCertificateHold
+	}
+	return template
+}
+
+// issuerOf returns the issuer whose name and key hashes match the request
+func (r *Responder) issuerOf(request *ocsp.Request) *generator.Generator {
+	if !request.HashAlgorithm.Available() {
+		return nil
+	}
+	for _, gen := range r.issuers.All() {
+		cert := gen.Issuer()
+		var spki struct {
+			Algorithm pkix.AlgorithmIdentifier
+			PublicKey asn1.BitString
+		}
+		if _, err := asn1.Unmarshal(cert.RawSubjectPublicKeyInfo, &spki); err != nil {
+			continue
+		}
+		if bytes.Equal(hash(request.HashAlgorithm, cert.RawSubject), request.IssuerNameHash) &&
+			bytes.Equal(hash(request.HashAlgorithm, spki.PublicKey.RightAlign()), request.IssuerKeyHash) {
+			return gen
+		}
+	}
+	return nil
+}
+
+func hash(h crypto.Hash, data []byte) []byte {
+	hh := h.New()
+	hh.Write(data)
+	return hh.Sum(nil)
+}
+
+func writeResponse(w http.ResponseWriter, resp response) {
+	w.Header().Set("Content-Type", contentTypeResponse)
+	w.Write(resp.der)
+}
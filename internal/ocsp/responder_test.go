@@ -0,0 +1,314 @@
+package ocsp
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/crl/internal/testutil"
+	"github.com/gigvault/shared/pkg/models"
+	"golang.org/x/crypto/ocsp"
+)
+
+// newTestResponder answers for one issuer, id, from st, which may be nil
+// for requests answered without the store
+func newTestResponder(t *testing.T, id string, st *store.Store, cfg Config) (*Responder, *generator.Generator) {
+	t.Helper()
+	gen, err := generator.New(id, st, testutil.NewSigner(t, "Test CA "+id), generator.Config{}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuers := generator.NewRegistry(id)
+	if err := issuers.Add(gen); err != nil {
+		t.Fatal(err)
+	}
+	return NewResponder(st, issuers, cfg), gen
+}
+
+// request returns a DER request for the serial number of a certificate of
+// issuer
+func request(t *testing.T, issuer *x509.Certificate, number *big.Int, h crypto.Hash) []byte {
+	t.Helper()
+	der, err := ocsp.CreateRequest(&x509.Certificate{SerialNumber: number}, issuer, &ocsp.RequestOptions{Hash: h})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// post sends a DER request to the responder and returns the response body
+func post(t *testing.T, r *Responder, contentType string, body []byte) []byte {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	r.Routes().ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", w.Code, w.Body)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != contentTypeResponse {
+		t.Fatalf("content type %q", ct)
+	}
+	return w.Body.Bytes()
+}
+
+// parse parses and verifies a signed response of issuer
+func parse(t *testing.T, der []byte, issuer *x509.Certificate) *ocsp.Response {
+	t.Helper()
+	resp, err := ocsp.ParseResponse(der, issuer)
+	if err != nil {
+		t.Fatalf("parse response: %v", err)
+	}
+	return resp
+}
+
+func TestTemplateStatus(t *testing.T) {
+	r, gen := newTestResponder(t, "root", nil, Config{})
+	number := big.NewInt(0x1234)
+	revokedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	future := time.Now().Add(10 * time.Minute)
+	entry := func(status, reason string) *store.Entry {
+		return &store.Entry{Serial: "1234", Number: number, RevokedAt: revokedAt, Reason: reason, Status: status}
+	}
+
+	tests := []struct {
+		name   string
+		number *big.Int
+		entry  *store.Entry
+		status int
+		reason int
+	}{
+		{"no entry", number, nil, ocsp.Good, 0},
+		{"revoked", number, entry(store.StatusRevoked, "keyCompromise"), ocsp.Revoked, ocsp.KeyCompromise},
+		{"unknown reason", number, entry(store.StatusRevoked, "bogus"), ocsp.Revoked, ocsp.Unspecified},
+		{"on hold", number, entry(store.StatusOnHold, "certificateHold"), ocsp.Revoked, models.ReasonCertificateHold},
+		{"hold released", number, entry(store.StatusReleased, "certificateHold"), ocsp.Good, 0},
+		{"revocation removed", number, entry(store.StatusRemoved, "keyCompromise"), ocsp.Good, 0},
+		{"scheduled", number, func() *store.Entry {
+			e := entry(store.StatusRevoked, "superseded")
+			e.EffectiveAt = &future
+			return e
+		}(), ocsp.Good, 0},
+		{"other issuer on an indirect CRL", number, func() *store.Entry {
+			e := entry(store.StatusRevoked, "keyCompromise")
+			e.CertificateIssuer = []byte{0x30, 0}
+			return e
+		}(), ocsp.Good, 0},
+		{"own issuer on an indirect CRL", number, func() *store.Entry {
+			e := entry(store.StatusRevoked, "keyCompromise")
+			e.CertificateIssuer = gen.Issuer().RawSubject
+			return e
+		}(), ocsp.Revoked, ocsp.KeyCompromise},
+		{"zero serial", big.NewInt(0), nil, ocsp.Unknown, 0},
+		{"negative serial", big.NewInt(-5), nil, ocsp.Unknown, 0},
+		{"21 octets", new(big.Int).Lsh(big.NewInt(1), 160), nil, ocsp.Unknown, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := r.template(gen, tt.number, tt.entry)
+			if template.Status != tt.status {
+				t.Fatalf("status %d, want %d", template.Status, tt.status)
+			}
+			if tt.status == ocsp.Revoked {
+				if template.RevocationReason != tt.reason || !template.RevokedAt.Equal(revokedAt) {
+					t.Fatalf("revoked at %s for %d, want %s for %d", template.RevokedAt, template.RevocationReason, revokedAt, tt.reason)
+				}
+			}
+			if !template.NextUpdate.After(template.ThisUpdate) {
+				t.Fatalf("nextUpdate %s not after thisUpdate %s", template.NextUpdate, template.ThisUpdate)
+			}
+		})
+	}
+
+	// A scheduled revocation ends the validity of the good response
+	e := entry(store.StatusRevoked, "superseded")
+	e.EffectiveAt = &future
+	if template := r.template(gen, number, e); !template.NextUpdate.Equal(future) {
+		t.Fatalf("nextUpdate %s, want the effective time %s", template.NextUpdate, future)
+	}
+}
+
+func TestRespondUnknownSerials(t *testing.T) {
+	// Serials no conforming CA issues are answered without the store
+	r, gen := newTestResponder(t, "root", nil, Config{})
+	for _, number := range []*big.Int{
+		new(big.Int).Lsh(big.NewInt(1), 160),
+		new(big.Int).Lsh(big.NewInt(1), 300),
+		big.NewInt(0),
+		big.NewInt(-1),
+	} {
+		t.Run(number.Text(16), func(t *testing.T) {
+			resp := parse(t, post(t, r, contentTypeRequest, request(t, gen.Issuer(), number, crypto.SHA1)), gen.Issuer())
+			if resp.Status != ocsp.Unknown {
+				t.Fatalf("status %d, want unknown", resp.Status)
+			}
+			if resp.SerialNumber.Cmp(number) != 0 {
+				t.Fatalf("response for %s, want %s", resp.SerialNumber, number)
+			}
+		})
+	}
+}
+
+func TestRespondMalformed(t *testing.T) {
+	r, gen := newTestResponder(t, "root", nil, Config{})
+	other := testutil.NewSigner(t, "Other CA")
+	valid := request(t, gen.Issuer(), big.NewInt(1), crypto.SHA1)
+
+	tests := map[string]struct {
+		body []byte
+		want []byte
+	}{
+		"empty":          {nil, ocsp.MalformedRequestErrorResponse},
+		"not DER":        {[]byte("GET / HTTP/1.1"), ocsp.MalformedRequestErrorResponse},
+		"truncated":      {valid[:len(valid)-4], ocsp.MalformedRequestErrorResponse},
+		"trailing data":  {append(bytes.Clone(valid), 0), ocsp.MalformedRequestErrorResponse},
+		"too large":      {bytes.Repeat([]byte{0x30}, maxRequestSize+1), ocsp.MalformedRequestErrorResponse},
+		"unknown issuer": {request(t, other.Cert, big.NewInt(1), crypto.SHA1), ocsp.UnauthorizedErrorResponse},
+		"unknown hash":   {bytes.Replace(valid, []byte{0x2b, 0x0e, 0x03, 0x02, 0x1a}, []byte{0x2b, 0x0e, 0x03, 0x02, 0x1b}, 1), ocsp.MalformedRequestErrorResponse},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := post(t, r, contentTypeRequest, tt.body); !bytes.Equal(got, tt.want) {
+				t.Fatalf("got %x, want %x", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("content type", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(valid))
+		req.Header.Set("Content-Type", "application/octet-stream")
+		w := httptest.NewRecorder()
+		r.Routes().ServeHTTP(w, req)
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Fatalf("status %d, want %d", w.Code, http.StatusUnsupportedMediaType)
+		}
+	})
+
+	t.Run("GET", func(t *testing.T) {
+		for _, path := range []string{"/", "/!!!!", "/MA%3D%3D%3D%3D", "/" + strings.Repeat("A", base64.StdEncoding.EncodedLen(maxRequestSize)+4)} {
+			w := httptest.NewRecorder()
+			r.Routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			if !bytes.Equal(w.Body.Bytes(), ocsp.MalformedRequestErrorResponse) {
+				t.Fatalf("GET %.20s: got %x", path, w.Body.Bytes())
+			}
+		}
+	})
+}
+
+func TestDecodeGetRequest(t *testing.T) {
+	der := []byte{0x30, 0xfb, 0xff, 0xfe, 0x3e, 0x01}
+	std := base64.StdEncoding.EncodeToString(der)
+	for _, path := range []string{
+		"/" + std,
+		"/" + strings.ReplaceAll(std, "/", "%2F"),
+		"/" + strings.TrimRight(std, "="),
+		"/" + base64.URLEncoding.EncodeToString(der),
+		"/" + base64.RawURLEncoding.EncodeToString(der),
+		"//" + std,
+	} {
+		got, err := decodeGetRequest(path)
+		if err != nil || !bytes.Equal(got, der) {
+			t.Fatalf("decodeGetRequest(%q) = %x, %v; want %x", path, got, err, der)
+		}
+	}
+
+	plus := []byte{0xfb, 0xef}
+	spaced := strings.ReplaceAll(base64.StdEncoding.EncodeToString(plus), "+", " ")
+	if got, err := decodeGetRequest("/" + spaced); err != nil || !bytes.Equal(got, plus) {
+		t.Fatalf("form-decoded + : %x, %v", got, err)
+	}
+}
+
+func TestRespondFromCache(t *testing.T) {
+	r, gen := newTestResponder(t, "root", nil, Config{Cache: CacheConfig{Enabled: true}})
+	revokedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	number := big.NewInt(0xa1b)
+	// A serial stored before serials were canonical is cached under the
+	// canonical form requests are looked up by
+	entry := store.Entry{Serial: "000A1B", Number: number, RevokedAt: revokedAt, Reason: "keyCompromise", Status: store.StatusRevoked}
+	if err := r.presignEntry(gen, &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	der := request(t, gen.Issuer(), number, crypto.SHA1)
+	w := httptest.NewRecorder()
+	r.Routes().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/"+base64.StdEncoding.EncodeToString(der), nil))
+	resp := parse(t, w.Body.Bytes(), gen.Issuer())
+	if resp.Status != ocsp.Revoked || resp.RevocationReason != ocsp.KeyCompromise || !resp.RevokedAt.Equal(revokedAt) {
+		t.Fatalf("status %d reason %d at %s, want revoked for keyCompromise at %s", resp.Status, resp.RevocationReason, resp.RevokedAt, revokedAt)
+	}
+	if !strings.HasPrefix(w.Header().Get("Cache-Control"), "public, max-age=") || w.Header().Get("ETag") == "" {
+		t.Fatalf("cacheable response with headers %v", w.Header())
+	}
+
+	// Served from the cache: the same signed response again
+	if again := post(t, r, contentTypeRequest, der); !bytes.Equal(again, w.Body.Bytes()) {
+		t.Fatal("second response was signed again")
+	}
+
+	_, hit := r.cache.get(cacheKey{gen.ID(), crypto.SHA1, "a1b"})
+	if !hit {
+		t.Fatal("no cached response under the canonical serial")
+	}
+	r.cache.drop(gen.ID(), "a1b")
+	if _, hit := r.cache.get(cacheKey{gen.ID(), crypto.SHA1, "a1b"}); hit {
+		t.Fatal("response still cached after drop")
+	}
+}
+
+func TestPresignRejectsInvalidSerial(t *testing.T) {
+	r, gen := newTestResponder(t, "root", nil, Config{Cache: CacheConfig{Enabled: true}})
+	err := r.presignEntry(gen, &store.Entry{Serial: "not hex", Status: store.StatusRevoked})
+	if err == nil {
+		t.Fatal("pre-signed an entry without a serial number")
+	}
+}
+
+func TestRespondFromStore(t *testing.T) {
+	st := testutil.Store(t)
+	ctx := context.Background()
+	id := fmt.Sprintf("ocsp-%d", time.Now().UnixNano())
+	r, gen := newTestResponder(t, id, st, Config{Cache: CacheConfig{Enabled: true}})
+	cert := gen.Issuer()
+	if err := st.UpsertIssuer(ctx, store.Issuer{ID: id, Subject: cert.Subject.String(), RawSubject: cert.RawSubject, SubjectKeyID: cert.SubjectKeyId}); err != nil {
+		t.Fatal(err)
+	}
+	revokedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := st.AddEntries(ctx, []store.Entry{{IssuerID: id, Serial: "a1b", RevokedAt: revokedAt, Reason: "keyCompromise"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	status := func(number int64, h crypto.Hash) int {
+		t.Helper()
+		return parse(t, post(t, r, contentTypeRequest, request(t, cert, big.NewInt(number), h)), cert).Status
+	}
+	for _, h := range []crypto.Hash{crypto.SHA1, crypto.SHA256} {
+		if got := status(0xa1b, h); got != ocsp.Revoked {
+			t.Fatalf("revoked serial with %v: status %d", h, got)
+		}
+		if got := status(0xa1c, h); got != ocsp.Good {
+			t.Fatalf("serial without a revocation with %v: status %d", h, got)
+		}
+	}
+
+	// A changed status replaces the cached responses, whatever the
+	// spelling of the serial it is reported with
+	if _, err := st.RemoveEntry(ctx, id, "a1b", "test", "erroneous"); err != nil {
+		t.Fatal(err)
+	}
+	r.StatusChanged(ctx, id, "000A1B")
+	if got := status(0xa1b, crypto.SHA256); got != ocsp.Good {
+		t.Fatalf("removed revocation: status %d", got)
+	}
+}
@@ -0,0 +1,94 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+)
+
+// AzureBlobConfig is an Azure Storage container and blob name for the CRL.
+// Requests are authorized with a SAS token when one is configured, and with
+// the pod's managed identity otherwise.
+type AzureBlobConfig struct {
+	AccountURL string `yaml:"account_url"` // https://<account>.blob.core.windows.net/
+	Container  string `yaml:"container"`
+	Blob       string `yaml:"blob"` // e.g. {issuer}/crl.der
+
+	// SASToken may also be supplied through AZURE_STORAGE_SAS_TOKEN
+	SASToken string `yaml:"sas_token"`
+
+	// ClientID selects a user-assigned managed identity
+	ClientID string `yaml:"client_id"`
+}
+
+// azureBlobPublisher uploads CRLs to Azure Blob Storage
+type azureBlobPublisher struct {
+	cfg    Config
+	client *azblob.Client
+}
+
+func init() {
+	Register(TypeAzureBlob, newAzureBlobPublisher)
+}
+
+func newAzureBlobPublisher(_ context.Context, cfg Config) (Publisher, error) {
+	c := cfg.AzureBlob
+	if c.AccountURL == "" || c.Container == "" || c.Blob == "" {
+		return nil, errors.New("azblob publisher requires account_url, container and blob")
+	}
+	sas := c.SASToken
+	if sas == "" {
+		sas = os.Getenv("AZURE_STORAGE_SAS_TOKEN")
+	}
+
+	var client *azblob.Client
+	var err error
+	if sas != "" {
+		serviceURL := strings.TrimSuffix(c.AccountURL, "/") + "/?" + strings.TrimPrefix(sas, "?")
+		client, err = azblob.NewClientWithNoCredential(serviceURL, nil)
+	} else {
+		opts := &azidentity.ManagedIdentityCredentialOptions{}
+		if c.ClientID != "" {
+			opts.ID = azidentity.ClientID(c.ClientID)
+		}
+		cred, credErr := azidentity.NewManagedIdentityCredential(opts)
+		if credErr != nil {
+			return nil, fmt.Errorf("failed to create managed identity credential: %w", credErr)
+		}
+		client, err = azblob.NewClient(c.AccountURL, cred, nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create blob storage client: %w", err)
+	}
+
+	return &azureBlobPublisher{cfg: cfg, client: client}, nil
+}
+
+func (p *azureBlobPublisher) Name() string {
+	return p.cfg.Name
+}
+
+func (p *azureBlobPublisher) Publish(ctx context.Context, a Artifact) error {
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	name := expand(p.cfg.AzureBlob.Blob, a)
+	contentType := contentTypeCRL
+	cacheCtl := cacheControl(p.cfg, a)
+	_, err := p.client.UploadBuffer(ctx, p.cfg.AzureBlob.Container, name, a.DER, &azblob.UploadBufferOptions{
+		HTTPHeaders: &blob.HTTPHeaders{
+			BlobContentType:  &contentType,
+			BlobCacheControl: &cacheCtl,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload CRL to blob %s/%s: %w", p.cfg.AzureBlob.Container, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloud.google.com/go/storage"
+)
+
+// GCSConfig is a Cloud Storage bucket and object name for the CRL.
+// Credentials come from the application default credentials.
+type GCSConfig struct {
+	Bucket string `yaml:"bucket"`
+	Object string `yaml:"object"` // e.g. {issuer}/crl.der or {issuer}/{number}.crl
+
+	// PublicRead applies the publicRead predefined ACL. Buckets with
+	// uniform bucket-level access must grant public access via IAM instead.
+	PublicRead bool `yaml:"public_read"`
+}
+
+// gcsPublisher uploads CRLs to a Cloud Storage bucket
+type gcsPublisher struct {
+	cfg    Config
+	client *storage.Client
+}
+
+func init() {
+	Register(TypeGCS, newGCSPublisher)
+}
+
+func newGCSPublisher(ctx context.Context, cfg Config) (Publisher, error) {
+	c := cfg.GCS
+	if c.Bucket == "" || c.Object == "" {
+		return nil, errors.New("gcs publisher requires bucket and object")
+	}
+
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cloud storage client: %w", err)
+	}
+
+	return &gcsPublisher{cfg: cfg, client: client}, nil
+}
+
+func (p *gcsPublisher) Name() string {
+	return p.cfg.Name
+}
+
+func (p *gcsPublisher) Publish(ctx context.Context, a Artifact) error {
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	name := expand(p.cfg.GCS.Object, a)
+	w := p.client.Bucket(p.cfg.GCS.Bucket).Object(name).NewWriter(ctx)
+	w.ContentType = contentTypeCRL
+	w.CacheControl = cacheControl(p.cfg, a)
+	if p.cfg.GCS.PublicRead {
+		w.PredefinedACL = "publicRead"
+	}
+
+	if _, err := w.Write(a.DER); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to upload CRL to gs://%s/%s: %w", p.cfg.GCS.Bucket, name, err)
+	}
+	// The object is only committed on Close
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to upload CRL to gs://%s/%s: %w", p.cfg.GCS.Bucket, name, err)
+	}
+	return nil
+}
+
+// Close releases the storage client
+func (p *gcsPublisher) Close() error {
+	return p.client.Close()
+}
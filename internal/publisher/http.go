@@ -0,0 +1,96 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// HTTPConfig is a URL the CRL is uploaded to with an HTTP PUT, covering
+// WebDAV servers and object stores without a dedicated publisher
+type HTTPConfig struct {
+	URL    string `yaml:"url"`    // e.g. https://dav.example.com/crl/{issuer}.crl
+	Method string `yaml:"method"` // defaults to PUT
+
+	// Headers are sent with every upload. Values are expanded from the
+	// environment, e.g. "Bearer ${CRL_UPLOAD_TOKEN}".
+	Headers map[string]string `yaml:"headers"`
+
+	// Username enables basic auth; the password may also be supplied through
+	// HTTP_PUBLISHER_PASSWORD
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// httpPublisher uploads CRLs with plain HTTP requests
+type httpPublisher struct {
+	cfg      Config
+	headers  http.Header
+	password string
+	client   *http.Client
+}
+
+func init() {
+	Register(TypeHTTP, newHTTPPublisher)
+}
+
+func newHTTPPublisher(_ context.Context, cfg Config) (Publisher, error) {
+	c := cfg.HTTP
+	if c.URL == "" {
+		return nil, errors.New("http publisher requires url")
+	}
+	if cfg.HTTP.Method == "" {
+		cfg.HTTP.Method = http.MethodPut
+	}
+
+	headers := make(http.Header)
+	for k, v := range c.Headers {
+		headers.Set(k, os.ExpandEnv(v))
+	}
+	password := c.Password
+	if password == "" {
+		password = os.Getenv("HTTP_PUBLISHER_PASSWORD")
+	}
+
+	return &httpPublisher{
+		cfg:      cfg,
+		headers:  headers,
+		password: password,
+		client:   &http.Client{Timeout: uploadTimeout},
+	}, nil
+}
+
+func (p *httpPublisher) Name() string {
+	return p.cfg.Name
+}
+
+func (p *httpPublisher) Publish(ctx context.Context, a Artifact) error {
+	url := expand(p.cfg.HTTP.URL, a)
+	req, err := http.NewRequestWithContext(ctx, p.cfg.HTTP.Method, url, bytes.NewReader(a.DER))
+	if err != nil {
+		return fmt.Errorf("invalid upload request: %w", err)
+	}
+	req.Header = p.headers.Clone()
+	req.Header.Set("Content-Type", contentTypeCRL)
+	req.Header.Set("Cache-Control", cacheControl(p.cfg, a))
+	if p.cfg.HTTP.Username != "" {
+		req.SetBasicAuth(p.cfg.HTTP.Username, p.password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload CRL to %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("upload to %s returned %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package publisher
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// defaultLDAPAttribute holds DER CRLs on cRLDistributionPoint and
+// certificationAuthority entries (RFC 4523)
+const defaultLDAPAttribute = "certificateRevocationList;binary"
+
+// LDAPConfig is a directory entry whose CRL attribute is replaced on
+// publish. The bind password may be supplied through LDAP_BIND_PASSWORD.
+type LDAPConfig struct {
+	URL          string `yaml:"url"` // ldap:// or ldaps://
+	StartTLS     bool   `yaml:"start_tls"`
+	BindDN       string `yaml:"bind_dn"`
+	BindPassword string `yaml:"bind_password"`
+
+	// DN of the entry, e.g. CN={issuer},CN=CDP,CN=Public Key Services,CN=Services,CN=Configuration,DC=example,DC=com
+	DN        string `yaml:"dn"`
+	Attribute string `yaml:"attribute"` // defaults to certificateRevocationList;binary
+}
+
+// ldapPublisher writes CRLs to an LDAP directory such as Active Directory
+type ldapPublisher struct {
+	cfg      Config
+	password string
+}
+
+func init() {
+	Register(TypeLDAP, newLDAPPublisher)
+}
+
+func newLDAPPublisher(_ context.Context, cfg Config) (Publisher, error) {
+	c := cfg.LDAP
+	if c.URL == "" || c.DN == "" {
+		return nil, errors.New("ldap publisher requires url and dn")
+	}
+	password := c.BindPassword
+	if password == "" {
+		password = os.Getenv("LDAP_BIND_PASSWORD")
+	}
+	if c.BindDN != "" && password == "" {
+		return nil, errors.New("ldap publisher requires bind_password or LDAP_BIND_PASSWORD with bind_dn")
+	}
+	if cfg.LDAP.Attribute == "" {
+		cfg.LDAP.Attribute = defaultLDAPAttribute
+	}
+
+	return &ldapPublisher{cfg: cfg, password: password}, nil
+}
+
+func (p *ldapPublisher) Name() string {
+	return p.cfg.Name
+}
+
+func (p *ldapPublisher) Publish(ctx context.Context, a Artifact) error {
+	c := p.cfg.LDAP
+
+	conn, err := ldap.DialURL(c.URL, ldap.DialWithDialer(&net.Dialer{Timeout: uploadTimeout}))
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", c.URL, err)
+	}
+	defer conn.Close()
+	conn.SetTimeout(uploadTimeout)
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if c.StartTLS {
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return err
+		}
+		if err := conn.StartTLS(&tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("ldap StartTLS failed: %w", err)
+		}
+	}
+	if c.BindDN != "" {
+		if err := conn.Bind(c.BindDN, p.password); err != nil {
+			return fmt.Errorf("ldap bind as %s failed: %w", c.BindDN, err)
+		}
+	}
+
+	dn := expand(c.DN, a)
+	req := ldap.NewModifyRequest(dn, nil)
+	req.Replace(c.Attribute, []string{string(a.DER)})
+	if err := conn.Modify(req); err != nil {
+		return fmt.Errorf("failed to write %s of %s: %w", c.Attribute, dn, err)
+	}
+	return nil
+}
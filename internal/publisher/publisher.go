@@ -0,0 +1,131 @@
+package publisher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported publisher types
+const (
+	TypeS3        = "s3"
+	TypeGCS       = "gcs"
+	TypeAzureBlob = "azblob"
+	TypeSFTP      = "sftp"
+	TypeLDAP      = "ldap"
+	TypeHTTP      = "http"
+)
+
+// contentTypeCRL is the media type of DER CRLs (RFC 2585 section 4.2)
+const contentTypeCRL = "application/pkix-crl"
+
+// uploadTimeout bounds a single upload to a distribution target
+const uploadTimeout = 30 * time.Second
+
+// Artifact is a signed CRL to distribute
+type Artifact struct {
+	IssuerID   string
+	Partition  int
+	Number     *big.Int
+	DER        []byte
+	ThisUpdate time.Time
+	NextUpdate time.Time
+}
+
+// Publisher uploads signed CRLs to a distribution target. Backends register
+// a Factory for their type; the generator only sees this interface.
+type Publisher interface {
+	// Name identifies the target in logs
+	Name() string
+
+	Publish(ctx context.Context, a Artifact) error
+}
+
+// Config selects a distribution target. Object names and paths may use the
+// {issuer}, {partition} and {number} placeholders.
+type Config struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"` // s3, gcs, azblob, sftp, ldap, http
+
+	// CacheControl overrides the default "public, max-age=<seconds until nextUpdate>"
+	CacheControl string `yaml:"cache_control"`
+
+	S3        S3Config        `yaml:"s3"`
+	GCS       GCSConfig       `yaml:"gcs"`
+	AzureBlob AzureBlobConfig `yaml:"azblob"`
+	SFTP      SFTPConfig      `yaml:"sftp"`
+	LDAP      LDAPConfig      `yaml:"ldap"`
+	HTTP      HTTPConfig      `yaml:"http"`
+}
+
+// Factory creates a publisher from its configuration
+type Factory func(ctx context.Context, cfg Config) (Publisher, error)
+
+// factories maps publisher types to their constructors
+var factories = make(map[string]Factory)
+
+// Register makes a publisher type available to New. It is meant to be
+// called from init and panics if the type is registered twice.
+func Register(typ string, f Factory) {
+	if _, dup := factories[typ]; dup {
+		panic("publisher: Register called twice for type " + typ)
+	}
+	factories[typ] = f
+}
+
+// Types returns the registered publisher types
+func Types() []string {
+	types := make([]string, 0, len(factories))
+	for typ := range factories {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New creates the publisher selected by cfg.Type
+func New(ctx context.Context, cfg Config) (Publisher, error) {
+	if cfg.Name == "" {
+		cfg.Name = cfg.Type
+	}
+	f, ok := factories[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown publisher type: %q (registered: %s)", cfg.Type, strings.Join(Types(), ", "))
+	}
+	return f(ctx, cfg)
+}
+
+// CloseAll closes the publishers holding resources, such as clients
+func CloseAll(publishers []Publisher) {
+	for _, p := range publishers {
+		if c, ok := p.(io.Closer); ok {
+			c.Close()
+		}
+	}
+}
+
+// expand replaces the artifact placeholders in a name template
+func expand(template string, a Artifact) string {
+	return strings.NewReplacer(
+		"{issuer}", a.IssuerID,
+		"{partition}", strconv.Itoa(a.Partition),
+		"{number}", a.Number.String(),
+	).Replace(template)
+}
+
+// cacheControl lets caches keep the CRL until its nextUpdate
+func cacheControl(cfg Config, a Artifact) string {
+	if cfg.CacheControl != "" {
+		return cfg.CacheControl
+	}
+	maxAge := int(time.Until(a.NextUpdate).Seconds())
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	return fmt.Sprintf("public, max-age=%d", maxAge)
+}
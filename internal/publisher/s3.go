@@ -0,0 +1,81 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Config is an S3 bucket and object key for the CRL. Credentials come
+// from the default AWS chain.
+type S3Config struct {
+	Region string `yaml:"region"`
+	Bucket string `yaml:"bucket"`
+	Key    string `yaml:"key"` // e.g. crl/{issuer}.crl
+
+	// Endpoint and PathStyle support S3-compatible stores (MinIO, Ceph)
+	Endpoint  string `yaml:"endpoint"`
+	PathStyle bool   `yaml:"path_style"`
+}
+
+// s3Publisher uploads CRLs to an S3 bucket
+type s3Publisher struct {
+	cfg    Config
+	client *s3.Client
+}
+
+func init() {
+	Register(TypeS3, newS3Publisher)
+}
+
+func newS3Publisher(ctx context.Context, cfg Config) (Publisher, error) {
+	c := cfg.S3
+	if c.Bucket == "" || c.Key == "" {
+		return nil, errors.New("s3 publisher requires bucket and key")
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if c.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(c.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if c.Endpoint != "" {
+			o.BaseEndpoint = aws.String(c.Endpoint)
+		}
+		o.UsePathStyle = c.PathStyle
+	})
+
+	return &s3Publisher{cfg: cfg, client: client}, nil
+}
+
+func (p *s3Publisher) Name() string {
+	return p.cfg.Name
+}
+
+func (p *s3Publisher) Publish(ctx context.Context, a Artifact) error {
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	key := expand(p.cfg.S3.Key, a)
+	_, err := p.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(p.cfg.S3.Bucket),
+		Key:           aws.String(key),
+		Body:          bytes.NewReader(a.DER),
+		ContentLength: aws.Int64(int64(len(a.DER))),
+		ContentType:   aws.String(contentTypeCRL),
+		CacheControl:  aws.String(cacheControl(p.cfg, a)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload CRL to s3://%s/%s: %w", p.cfg.S3.Bucket, key, err)
+	}
+	return nil
+}
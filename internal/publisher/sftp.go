@@ -0,0 +1,164 @@
+package publisher
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// SFTPConfig is a host and remote path for the CRL. The host key must be
+// listed in known_hosts; the password may be supplied through SFTP_PASSWORD.
+type SFTPConfig struct {
+	Address        string `yaml:"address"` // host:port
+	User           string `yaml:"user"`
+	Password       string `yaml:"password"`
+	PrivateKeyPath string `yaml:"private_key_path"`
+	KnownHostsPath string `yaml:"known_hosts_path"`
+	Path           string `yaml:"path"` // e.g. /var/www/crl/{issuer}.crl
+}
+
+// sftpPublisher uploads CRLs over SFTP, writing a temporary file that is
+// renamed over the target so readers never see a partial CRL
+type sftpPublisher struct {
+	cfg       Config
+	sshConfig *ssh.ClientConfig
+}
+
+func init() {
+	Register(TypeSFTP, newSFTPPublisher)
+}
+
+func newSFTPPublisher(_ context.Context, cfg Config) (Publisher, error) {
+	c := cfg.SFTP
+	if c.Address == "" || c.User == "" || c.Path == "" {
+		return nil, errors.New("sftp publisher requires address, user and path")
+	}
+	if c.KnownHostsPath == "" {
+		return nil, errors.New("sftp publisher requires known_hosts_path for host key verification")
+	}
+
+	hostKeys, err := knownhosts.New(c.KnownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known hosts: %w", err)
+	}
+
+	var auth []ssh.AuthMethod
+	if c.PrivateKeyPath != "" {
+		keyPEM, err := os.ReadFile(c.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sftp private key: %w", err)
+		}
+		key, err := ssh.ParsePrivateKey(keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse sftp private key: %w", err)
+		}
+		auth = append(auth, ssh.PublicKeys(key))
+	}
+	password := c.Password
+	if password == "" {
+		password = os.Getenv("SFTP_PASSWORD")
+	}
+	if password != "" {
+		auth = append(auth, ssh.Password(password))
+	}
+	if len(auth) == 0 {
+		return nil, errors.New("sftp publisher requires private_key_path or password")
+	}
+
+	return &sftpPublisher{
+		cfg: cfg,
+		sshConfig: &ssh.ClientConfig{
+			User:            c.User,
+			Auth:            auth,
+			HostKeyCallback: hostKeys,
+			Timeout:         uploadTimeout,
+		},
+	}, nil
+}
+
+func (p *sftpPublisher) Name() string {
+	return p.cfg.Name
+}
+
+func (p *sftpPublisher) Publish(ctx context.Context, a Artifact) error {
+	ctx, cancel := context.WithTimeout(ctx, uploadTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", p.cfg.SFTP.Address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", p.cfg.SFTP.Address, err)
+	}
+	// Abort the transfer if ctx expires mid-upload
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, p.cfg.SFTP.Address, p.sshConfig)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("ssh handshake with %s failed: %w", p.cfg.SFTP.Address, err)
+	}
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	defer sshClient.Close()
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return fmt.Errorf("failed to start sftp session: %w", err)
+	}
+	defer client.Close()
+
+	target := expand(p.cfg.SFTP.Path, a)
+	if err := client.MkdirAll(path.Dir(target)); err != nil {
+		return fmt.Errorf("failed to create %s: %w", path.Dir(target), err)
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	tmp := fmt.Sprintf("%s.tmp-%s", target, hex.EncodeToString(suffix))
+
+	if err := writeRemote(client, tmp, a.DER); err != nil {
+		client.Remove(tmp)
+		return fmt.Errorf("failed to upload %s: %w", tmp, err)
+	}
+	if err := rename(client, tmp, target); err != nil {
+		client.Remove(tmp)
+		return fmt.Errorf("failed to rename %s to %s: %w", tmp, target, err)
+	}
+	return nil
+}
+
+func writeRemote(client *sftp.Client, name string, data []byte) error {
+	f, err := client.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// rename replaces target atomically where the server supports the
+// posix-rename extension; plain SFTP rename fails if target exists, so
+// the fallback removes it first
+func rename(client *sftp.Client, from, to string) error {
+	if _, ok := client.HasExtension("posix-rename@openssh.com"); ok {
+		return client.PosixRename(from, to)
+	}
+	if err := client.Remove(to); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return client.Rename(from, to)
+}
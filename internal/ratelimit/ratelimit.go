@@ -0,0 +1,201 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/internal/auth"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// defaultIdleTimeout is how long an unused bucket is kept
+const defaultIdleTimeout = 10 * time.Minute
+
+// Limit is a token bucket: Rate calls per second on average, in bursts of
+// up to Burst
+type Limit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+func (l Limit) unlimited() bool {
+	return l.Rate == 0
+}
+
+func (l Limit) validate() error {
+	if l.Rate < 0 {
+		return errors.New("rate must not be negative")
+	}
+	if l.Rate > 0 && l.Burst < 1 {
+		return errors.New("burst must be at least 1")
+	}
+	return nil
+}
+
+// Config configures per-client rate limiting of gRPC calls
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Default limits RPCs without their own limit; unlimited when unset
+	Default Limit `yaml:"default"`
+
+	// Methods are the limits of individual RPCs, by name
+	Methods map[string]Limit `yaml:"methods"`
+
+	// IdleTimeout is how long the bucket of an idle client is kept
+	// (default 10m)
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+}
+
+// Validate applies defaults and checks the limits
+func (c *Config) Validate() error {
+	if err := c.Default.validate(); err != nil {
+		return fmt.Errorf("default: %w", err)
+	}
+	for method, l := range c.Methods {
+		if err := l.validate(); err != nil {
+			return fmt.Errorf("methods[%s]: %w", method, err)
+		}
+	}
+	if c.IdleTimeout < 0 {
+		return errors.New("idle_timeout must not be negative")
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = defaultIdleTimeout
+	}
+	return nil
+}
+
+// bucketKey identifies the bucket of one client and RPC
+type bucketKey struct {
+	client string
+	method string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter rate-limits gRPC calls per caller identity and RPC. Callers
+// without an identity are keyed by source IP.
+type Limiter struct {
+	cfg    Config
+	logger *logger.Logger
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+// New creates a limiter
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:       cfg,
+		logger:    logger.Global(),
+		buckets:   make(map[bucketKey]*bucket),
+		lastSweep: time.Now(),
+	}
+}
+
+// limit returns the limit of method
+func (l *Limiter) limit(method string) Limit {
+	if lim, ok := l.cfg.Methods[method]; ok {
+		return lim
+	}
+	return l.cfg.Default
+}
+
+// client returns the key of the caller of ctx
+func client(ctx context.Context) string {
+	if id, ok := auth.FromContext(ctx); ok {
+		return id.Method + ":" + id.Subject
+	}
+	return "ip:" + auth.SourceIP(ctx)
+}
+
+// allow takes a token for a call to fullMethod, returning a
+// ResourceExhausted error with the suggested delay when none is left
+func (l *Limiter) allow(ctx context.Context, fullMethod string) error {
+	if !l.cfg.Enabled {
+		return nil
+	}
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	lim := l.limit(method)
+	if lim.unlimited() {
+		return nil
+	}
+
+	key := bucketKey{client: client(ctx), method: method}
+	now := time.Now()
+
+	l.mu.Lock()
+	if now.Sub(l.lastSweep) >= l.cfg.IdleTimeout {
+		for k, b := range l.buckets {
+			if now.Sub(b.lastSeen) >= l.cfg.IdleTimeout {
+				delete(l.buckets, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(rate.Limit(lim.Rate), lim.Burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	l.mu.Unlock()
+
+	r := b.limiter.ReserveN(now, 1)
+	delay := r.DelayFrom(now)
+	if delay == 0 {
+		return nil
+	}
+	// Give the token back; the call is rejected rather than delayed
+	r.CancelAt(now)
+
+	metrics.RateLimited.WithLabelValues(method).Inc()
+	l.logger.Debug("Rate limit exceeded",
+		zap.String("client", key.client),
+		zap.String("method", method),
+		zap.Duration("retry_after", delay),
+	)
+
+	st := status.New(codes.ResourceExhausted, fmt.Sprintf("rate limit of %s exceeded", method))
+	if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// UnaryInterceptor rate-limits unary calls
+func (l *Limiter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := l.allow(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor rate-limits the start of streaming calls
+func (l *Limiter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := l.allow(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,199 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gigvault/crl/api/proto/crl"
+	"github.com/gigvault/crl/internal/auth"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// newLimiter limits AddRevocation to bursts of two, refilled every 50ms
+func newLimiter(t *testing.T) *Limiter {
+	t.Helper()
+	cfg := Config{
+		Enabled: true,
+		Methods: map[string]Limit{"AddRevocation": {Rate: 20, Burst: 2}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	return New(cfg)
+}
+
+func caller(subject string) context.Context {
+	return auth.NewContext(context.Background(), auth.Identity{Subject: subject, Method: auth.MethodAPIKey})
+}
+
+// call makes a unary call through the interceptor and reports whether the
+// handler ran
+func call(ctx context.Context, l *Limiter, method string) (bool, error) {
+	called := false
+	_, err := l.UnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, interface{}) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+	return called, err
+}
+
+func TestLimiterBurstAndRefill(t *testing.T) {
+	l := newLimiter(t)
+	ctx := caller("ra")
+	for i := 0; i < 2; i++ {
+		if called, err := call(ctx, l, crl.CRLService_AddRevocation_FullMethodName); err != nil || !called {
+			t.Fatalf("call %d within the burst: %v", i, err)
+		}
+	}
+
+	called, err := call(ctx, l, crl.CRLService_AddRevocation_FullMethodName)
+	if called {
+		t.Fatal("handler ran after the burst")
+	}
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted", err)
+	}
+	var delay time.Duration
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.RetryInfo); ok {
+			delay = info.RetryDelay.AsDuration()
+		}
+	}
+	if delay <= 0 || delay > 50*time.Millisecond {
+		t.Fatalf("retry delay %s, want up to one refill interval", delay)
+	}
+
+	// Rejected calls take no token, so one is back after the delay
+	time.Sleep(delay + 10*time.Millisecond)
+	if _, err := call(ctx, l, crl.CRLService_AddRevocation_FullMethodName); err != nil {
+		t.Fatalf("call after refill: %v", err)
+	}
+	if _, err := call(ctx, l, crl.CRLService_AddRevocation_FullMethodName); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("second call after refill: got %v, want ResourceExhausted", err)
+	}
+}
+
+func TestLimiterPerClientAndRPC(t *testing.T) {
+	l := newLimiter(t)
+	exhaust := func(ctx context.Context) {
+		t.Helper()
+		for i := 0; i < 2; i++ {
+			if _, err := call(ctx, l, crl.CRLService_AddRevocation_FullMethodName); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := call(ctx, l, crl.CRLService_AddRevocation_FullMethodName); status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("got %v, want ResourceExhausted", err)
+		}
+	}
+
+	ra := caller("ra")
+	exhaust(ra)
+
+	// Other clients have their own buckets
+	exhaust(caller("ra-2"))
+	mtls := auth.NewContext(context.Background(), auth.Identity{Subject: "ra", Method: auth.MethodMTLS})
+	exhaust(mtls)
+
+	// Callers without an identity are keyed by source IP
+	fromIP := func(ip string) context.Context {
+		return peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000}})
+	}
+	exhaust(fromIP("192.0.2.1"))
+	exhaust(fromIP("192.0.2.2"))
+	if _, err := call(fromIP("192.0.2.1"), l, crl.CRLService_AddRevocation_FullMethodName); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("another port of the same IP: got %v, want ResourceExhausted", err)
+	}
+
+	// Other RPCs of the same client are limited separately; without a
+	// default they are not limited at all
+	for i := 0; i < 10; i++ {
+		if _, err := call(ra, l, crl.CRLService_GetCRL_FullMethodName); err != nil {
+			t.Fatalf("unlimited RPC: %v", err)
+		}
+	}
+}
+
+func TestLimiterDefault(t *testing.T) {
+	cfg := Config{
+		Enabled: true,
+		Default: Limit{Rate: 1, Burst: 1},
+		Methods: map[string]Limit{"GetCRL": {}},
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	l := New(cfg)
+	ctx := caller("ra")
+
+	for _, method := range []string{crl.CRLService_AddRevocation_FullMethodName, crl.CRLService_HoldCertificate_FullMethodName} {
+		if _, err := call(ctx, l, method); err != nil {
+			t.Fatalf("%s: %v", method, err)
+		}
+		if _, err := call(ctx, l, method); status.Code(err) != codes.ResourceExhausted {
+			t.Fatalf("%s: got %v, want ResourceExhausted", method, err)
+		}
+	}
+	// A zero rate exempts an RPC from the default
+	for i := 0; i < 3; i++ {
+		if _, err := call(ctx, l, crl.CRLService_GetCRL_FullMethodName); err != nil {
+			t.Fatalf("exempt RPC: %v", err)
+		}
+	}
+}
+
+func TestLimiterStream(t *testing.T) {
+	l := newLimiter(t)
+	ctx := caller("ra")
+	info := &grpc.StreamServerInfo{FullMethod: crl.CRLService_AddRevocation_FullMethodName}
+	handler := func(interface{}, grpc.ServerStream) error { return nil }
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = l.StreamInterceptor()(nil, &stream{ctx: ctx}, info, handler)
+	}
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("got %v, want ResourceExhausted", err)
+	}
+}
+
+func TestLimiterDisabled(t *testing.T) {
+	l := New(Config{Methods: map[string]Limit{"AddRevocation": {Rate: 1, Burst: 1}}})
+	for i := 0; i < 3; i++ {
+		if _, err := call(caller("ra"), l, crl.CRLService_AddRevocation_FullMethodName); err != nil {
+			t.Fatalf("disabled limiter: %v", err)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	for name, cfg := range map[string]Config{
+		"negative rate":         {Default: Limit{Rate: -1, Burst: 1}},
+		"no burst":              {Methods: map[string]Limit{"GetCRL": {Rate: 1}}},
+		"negative idle timeout": {IdleTimeout: -time.Second},
+	} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%s: accepted", name)
+		}
+	}
+	cfg := Config{}
+	if err := cfg.Validate(); err != nil || cfg.IdleTimeout != defaultIdleTimeout {
+		t.Fatalf("defaults: %v, idle timeout %s", err, cfg.IdleTimeout)
+	}
+}
+
+// stream is a server stream with a context
+type stream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,51 @@
+package revocation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gigvault/shared/pkg/models"
+)
+
+// Reason names as written in RFC 5280 section 5.3.1
+var reasonNames = map[int]string{
+	models.ReasonUnspecified:          "unspecified",
+	models.ReasonKeyCompromise:        "keyCompromise",
+	models.ReasonCACompromise:         "cACompromise",
+	models.ReasonAffiliationChanged:   "affiliationChanged",
+	models.ReasonSuperseded:           "superseded",
+	models.ReasonCessationOfOperation: "cessationOfOperation",
+	models.ReasonCertificateHold:      "certificateHold",
+	models.ReasonRemoveFromCRL:        "removeFromCRL",
+	models.ReasonPrivilegeWithdrawn:   "privilegeWithdrawn",
+	models.ReasonAACompromise:         "aACompromise",
+}
+
+var reasonCodes = func() map[string]int {
+	m := make(map[string]int, len(reasonNames))
+	for code, name := range reasonNames {
+		m[strings.ToLower(name)] = code
+	}
+	return m
+}()
+
+// ParseReason maps a reason name to its RFC 5280 CRLReason code. Matching is
+// case-insensitive and an empty reason means unspecified.
+func ParseReason(name string) (int, error) {
+	if name == "" {
+		return models.ReasonUnspecified, nil
+	}
+	code, ok := reasonCodes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return 0, fmt.Errorf("unknown revocation reason %q", name)
+	}
+	return code, nil
+}
+
+// ReasonName returns the canonical RFC 5280 name of a reason code
+func ReasonName(code int) string {
+	if name, ok := reasonNames[code]; ok {
+		return name
+	}
+	return reasonNames[models.ReasonUnspecified]
+}
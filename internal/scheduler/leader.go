@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+)
+
+const defaultLeaderCheckInterval = 5 * time.Second
+
+// Config configures the scheduler
+type Config struct {
+	// LeaderElection runs the scheduled publications on one replica at a
+	// time, the holder of a Postgres advisory lock. Emergency publications
+	// and publish retries still run on every replica.
+	LeaderElection bool `yaml:"leader_election"`
+
+	// LeaderCheckInterval is how often the leader checks that it still
+	// holds the lock and the other replicas try to take it (default 5s)
+	LeaderCheckInterval time.Duration `yaml:"leader_check_interval"`
+}
+
+// Validate applies defaults and checks the scheduler config
+func (c *Config) Validate() error {
+	if c.LeaderCheckInterval < 0 {
+		return errors.New("leader check interval must not be negative")
+	}
+	if c.LeaderCheckInterval == 0 {
+		c.LeaderCheckInterval = defaultLeaderCheckInterval
+	}
+	return nil
+}
+
+// IsLeader reports whether this replica runs the scheduled publications
+func (s *Scheduler) IsLeader() bool {
+	return !s.cfg.LeaderElection || s.leader.Load()
+}
+
+// runElection holds or tries to take the scheduler lock every check
+// interval until ctx is cancelled or resign is closed, then releases it so
+// another replica takes over
+func (s *Scheduler) runElection(ctx context.Context, resign <-chan struct{}) {
+	var lock *store.Lock
+	defer func() {
+		if lock != nil {
+			s.stepDown(ctx, lock)
+		}
+	}()
+
+	ticker := time.NewTicker(s.cfg.LeaderCheckInterval)
+	defer ticker.Stop()
+	for {
+		if lock != nil {
+			if err := lock.Check(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.logger.Warn("Lost scheduler leadership", zap.Error(err))
+				s.stepDown(ctx, lock)
+				lock = nil
+			}
+		}
+		if lock == nil {
+			var err error
+			if lock, err = s.store.TryLock(ctx, store.SchedulerLock); err != nil && ctx.Err() == nil {
+				s.logger.Warn("Failed to take scheduler lock", zap.Error(err))
+			}
+			if lock != nil {
+				s.leader.Store(true)
+				metrics.SchedulerLeader.Set(1)
+				s.logger.Info("Became scheduler leader")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-resign:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) stepDown(ctx context.Context, lock *store.Lock) {
+	s.leader.Store(false)
+	metrics.SchedulerLeader.Set(0)
+	lock.Release(context.WithoutCancel(ctx))
+	s.logger.Info("Stepped down as scheduler leader")
+}
+
+// followAt returns when a replica that is not the leader looks at an
+// issuer's publications again: at the leader's next due time, or every
+// check interval while the leader owes a publication, so that a replica
+// taking over from a failed leader publishes soon
+func (s *Scheduler) followAt(ctx context.Context, gen *generator.Generator, cfg generator.ScheduleConfig) time.Time {
+	check := time.Now().Add(s.cfg.LeaderCheckInterval)
+	lastPublished, nextUpdate, err := s.store.PublicationState(ctx, gen.ID())
+	if err != nil {
+		s.logger.Warn("Failed to read CRL publication state", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		return check
+	}
+	if nextUpdate.IsZero() {
+		return check
+	}
+	owed, err := s.store.EffectiveSince(ctx, gen.ID(), lastPublished)
+	if err != nil {
+		s.logger.Warn("Failed to read scheduled revocations", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		return check
+	}
+	if due := dueAt(cfg, lastPublished, nextUpdate); !owed && due.After(check) {
+		return due
+	}
+	return check
+}
@@ -0,0 +1,114 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/store"
+	"go.uber.org/zap"
+)
+
+const (
+	// retryPollInterval is how often the queue is checked for due uploads
+	retryPollInterval = 15 * time.Second
+
+	// retryLease hides a claimed upload from other replicas while it runs
+	retryLease = 5 * time.Minute
+
+	// retryBatch bounds the uploads claimed per poll
+	retryBatch = 50
+)
+
+// runRetries drains the publish retry queue until ctx is cancelled, or
+// until Shutdown after a last pass over the due retries
+func (s *Scheduler) runRetries(ctx context.Context) {
+	ticker := time.NewTicker(retryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopping:
+			s.flushRetries(ctx)
+			return
+		case <-ticker.C:
+			s.retryDue(ctx)
+		}
+	}
+}
+
+// flushRetries retries due uploads batch by batch until none are left, so
+// that CRLs queued by the last publications reach their targets before
+// exit. Failed uploads stay queued for other replicas or the next start.
+func (s *Scheduler) flushRetries(ctx context.Context) {
+	for ctx.Err() == nil && s.retryDue(ctx) == retryBatch {
+	}
+}
+
+// retryDue uploads the queued CRLs whose backoff has elapsed and returns
+// how many were claimed
+func (s *Scheduler) retryDue(ctx context.Context) int {
+	retries, err := s.store.ClaimRetries(ctx, retryLease, retryBatch)
+	if err != nil {
+		s.logger.Warn("Failed to read publish retry queue", zap.Error(err))
+		return 0
+	}
+
+	for _, r := range retries {
+		fields := []zap.Field{
+			zap.String("issuer_id", r.IssuerID),
+			zap.String("publisher", r.Publisher),
+			zap.Int("partition", r.Partition),
+			zap.Int64("crl_number", r.CRLNumber),
+			zap.Int("attempts", r.Attempts),
+		}
+		run, ok := s.runs[r.IssuerID]
+		if !ok {
+			// Served by another deployment; the lease expires for it
+			continue
+		}
+		if time.Now().After(r.NextUpdate) {
+			s.logger.Warn("Dropping publish retry of an expired CRL", fields...)
+			s.dropRetry(ctx, r)
+			continue
+		}
+
+		err := run.gen.RetryUpload(ctx, r)
+		if errors.Is(err, generator.ErrRetrySuperseded) {
+			s.logger.Debug("Skipping superseded publish retry", fields...)
+			continue
+		}
+		metrics.PublishRetries.WithLabelValues(r.IssuerID, r.Publisher, metrics.Result(err)).Inc()
+		switch {
+		case errors.Is(err, generator.ErrUnknownPublisher):
+			s.logger.Warn("Dropping publish retry for a removed publisher", fields...)
+			s.dropRetry(ctx, r)
+		case err != nil:
+			if ctx.Err() != nil {
+				return len(retries)
+			}
+			r.Attempts++
+			r.NextAttemptAt = time.Now().Add(generator.RetryBackoff(r.Attempts))
+			r.LastError = err.Error()
+			s.logger.Error("CRL upload retry failed",
+				append(fields, zap.Time("next_attempt_at", r.NextAttemptAt), zap.Error(err))...)
+			if err := s.store.RescheduleRetry(ctx, r); err != nil {
+				s.logger.Warn("Failed to reschedule publish retry", append(fields, zap.Error(err))...)
+			}
+		default:
+			s.logger.Info("CRL upload retry succeeded", fields...)
+		}
+	}
+	return len(retries)
+}
+
+// dropRetry removes a retry that can no longer succeed
+func (s *Scheduler) dropRetry(ctx context.Context, r store.PublishRetry) {
+	if err := s.store.DeleteRetry(ctx, r.IssuerID, r.Publisher, r.Partition, r.CRLNumber); err != nil {
+		s.logger.Warn("Failed to delete publish retry", zap.String("issuer_id", r.IssuerID), zap.Error(err))
+	}
+}
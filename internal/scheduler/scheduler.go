@@ -0,0 +1,316 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gigvault/crl/internal/generator"
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/crl/internal/store"
+	"github.com/gigvault/shared/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// retryDelay is the wait before retrying a failed publication
+const retryDelay = time.Minute
+
+// Scheduler republishes the CRL of every issuer with an enabled schedule
+// before its nextUpdate elapses and when scheduled revocations take effect,
+// publishes out of band on emergency triggers, and retries queued uploads
+// to distribution targets
+type Scheduler struct {
+	store  *store.Store
+	runs   map[string]*issuerRun
+	cfg    Config
+	logger *logger.Logger
+
+	// leader is set while this replica holds the scheduler lock
+	leader atomic.Bool
+
+	// stopping is closed by Shutdown; done is closed when Run returns
+	stopping chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// issuerRun is the publication state of one issuer
+type issuerRun struct {
+	gen *generator.Generator
+
+	// emergency wakes the issuer loop; pendingSince is the receipt time of
+	// the oldest revocation waiting for an emergency publication
+	emergency    chan struct{}
+	mu           sync.Mutex
+	pendingSince time.Time
+
+	// reschedule wakes the issuer loop to pick up a new scheduled revocation
+	reschedule chan struct{}
+}
+
+// New creates a new scheduler
+func New(st *store.Store, issuers *generator.Registry, cfg Config) *Scheduler {
+	s := &Scheduler{
+		store:    st,
+		runs:     make(map[string]*issuerRun),
+		cfg:      cfg,
+		logger:   logger.Global(),
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, gen := range issuers.All() {
+		s.runs[gen.ID()] = &issuerRun{
+			gen:        gen,
+			emergency:  make(chan struct{}, 1),
+			reschedule: make(chan struct{}, 1),
+		}
+	}
+	return s
+}
+
+// Run publishes CRLs on schedule and on emergency triggers, and retries
+// failed uploads, until Shutdown is called or ctx is cancelled. Cancelling
+// ctx also aborts the publications in progress.
+func (s *Scheduler) Run(ctx context.Context) {
+	defer close(s.done)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.runRetries(ctx)
+	}()
+	// The lock is held until the publications in progress are done
+	elected := make(chan struct{})
+	resign := make(chan struct{})
+	if s.cfg.LeaderElection {
+		go func() {
+			defer close(elected)
+			s.runElection(ctx, resign)
+		}()
+	} else {
+		close(elected)
+	}
+	// Issuers without a schedule or emergency publication idle until a
+	// configuration reload enables one
+	for _, run := range s.runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runIssuer(ctx, run)
+		}()
+	}
+	wg.Wait()
+	close(resign)
+	<-elected
+}
+
+// Shutdown stops scheduling publications and waits for Run to finish the
+// ones in progress and a last pass over the due publish retries. If ctx is
+// done first it returns ctx's error; cancelling the context of Run then
+// aborts them.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stopping) })
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TriggerEmergency requests an immediate publication of the issuer's CRL
+// for a revocation received at receivedAt. Triggers arriving while a
+// publication is pending are coalesced. It reports whether emergency
+// publication is enabled for the issuer.
+func (s *Scheduler) TriggerEmergency(issuerID string, receivedAt time.Time) bool {
+	run, ok := s.runs[issuerID]
+	if !ok || !run.gen.EmergencyPublish() {
+		return false
+	}
+
+	run.mu.Lock()
+	if run.pendingSince.IsZero() || receivedAt.Before(run.pendingSince) {
+		run.pendingSince = receivedAt
+	}
+	run.mu.Unlock()
+
+	select {
+	case run.emergency <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// Reschedule makes the issuer's schedule account for a scheduled
+// revocation added or a schedule reloaded since it was last computed
+func (s *Scheduler) Reschedule(issuerID string) {
+	run, ok := s.runs[issuerID]
+	if !ok {
+		return
+	}
+	select {
+	case run.reschedule <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Scheduler) runIssuer(ctx context.Context, run *issuerRun) {
+	gen := run.gen
+	cfg := gen.Schedule()
+	s.logger.Info("CRL schedule started",
+		zap.String("issuer_id", gen.ID()),
+		zap.Bool("scheduled", cfg.Enabled),
+		zap.Duration("interval", cfg.Interval),
+		zap.Duration("lead_time", cfg.LeadTime),
+		zap.Bool("emergency_publish", gen.EmergencyPublish()),
+	)
+
+	due := s.resumeAt(ctx, gen, cfg)
+
+	for {
+		var tick <-chan time.Time
+		var timer *time.Timer
+		if cfg.Enabled {
+			timer = time.NewTimer(time.Until(s.wakeAt(ctx, gen, due)))
+			tick = timer.C
+		}
+
+		emergency := false
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-s.stopping:
+			if timer != nil {
+				timer.Stop()
+			}
+			return
+		case <-run.reschedule:
+			if timer != nil {
+				timer.Stop()
+			}
+			if next := gen.Schedule(); next != cfg {
+				s.logger.Info("CRL schedule changed",
+					zap.String("issuer_id", gen.ID()),
+					zap.Bool("scheduled", next.Enabled),
+					zap.Duration("interval", next.Interval),
+					zap.Duration("lead_time", next.LeadTime),
+				)
+				cfg = next
+				due = s.resumeAt(ctx, gen, cfg)
+			}
+			continue
+		case <-tick:
+			if !s.IsLeader() {
+				due = s.followAt(ctx, gen, cfg)
+				continue
+			}
+		case <-run.emergency:
+			if timer != nil {
+				timer.Stop()
+			}
+			emergency = true
+		}
+
+		// Claim pending triggers before signing; revocations arriving
+		// during the publication trigger another one
+		run.mu.Lock()
+		pendingSince := run.pendingSince
+		run.pendingSince = time.Time{}
+		run.mu.Unlock()
+
+		pub, err := gen.Publish(ctx, gen.Window())
+		if errors.Is(err, generator.ErrDistributionFailed) {
+			// The CRL is current; the retry queue catches up the failed targets
+			s.logger.Warn("CRL published with failed uploads", zap.String("issuer_id", gen.ID()), zap.Error(err))
+			err = nil
+		}
+		if !pendingSince.IsZero() {
+			metrics.EmergencyPublishDuration.
+				WithLabelValues(gen.ID(), metrics.Result(err)).
+				Observe(time.Since(pendingSince).Seconds())
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			s.logger.Error("CRL publication failed",
+				zap.String("issuer_id", gen.ID()),
+				zap.Bool("emergency", emergency),
+				zap.Duration("retry_in", retryDelay),
+				zap.Error(err),
+			)
+			due = time.Now().Add(retryDelay)
+			if !pendingSince.IsZero() && !cfg.Enabled {
+				// Without a schedule nothing else would retry
+				s.retryEmergency(ctx, run, pendingSince)
+			}
+			continue
+		}
+
+		s.logger.Info("CRL published",
+			zap.String("issuer_id", gen.ID()),
+			zap.Bool("emergency", emergency),
+			zap.Int("entries", pub.RevokedCount()),
+			zap.Time("next_update", pub.NextUpdate()),
+		)
+		due = dueAt(cfg, pub.PublishedAt, pub.NextUpdate())
+	}
+}
+
+// resumeAt returns when the next scheduled publication of an issuer is
+// due, resuming from the last publication, which may have been made by
+// another replica or before a restart. Unknown state publishes now.
+func (s *Scheduler) resumeAt(ctx context.Context, gen *generator.Generator, cfg generator.ScheduleConfig) time.Time {
+	if !cfg.Enabled {
+		return time.Time{}
+	}
+	lastPublished, nextUpdate, err := s.store.PublicationState(ctx, gen.ID())
+	if err != nil {
+		s.logger.Warn("Failed to read CRL publication state", zap.String("issuer_id", gen.ID()), zap.Error(err))
+	}
+	if nextUpdate.IsZero() {
+		return time.Now()
+	}
+	return dueAt(cfg, lastPublished, nextUpdate)
+}
+
+// wakeAt returns the earlier of due and when the issuer's next scheduled
+// revocation takes effect
+func (s *Scheduler) wakeAt(ctx context.Context, gen *generator.Generator, due time.Time) time.Time {
+	next, err := s.store.NextEffectiveAt(ctx, gen.ID())
+	if err != nil {
+		s.logger.Warn("Failed to read scheduled revocations", zap.String("issuer_id", gen.ID()), zap.Error(err))
+		return due
+	}
+	if !next.IsZero() && next.Before(due) {
+		return next
+	}
+	return due
+}
+
+// retryEmergency re-arms a failed emergency publication after retryDelay
+func (s *Scheduler) retryEmergency(ctx context.Context, run *issuerRun, pendingSince time.Time) {
+	time.AfterFunc(retryDelay, func() {
+		if ctx.Err() == nil {
+			s.TriggerEmergency(run.gen.ID(), pendingSince)
+		}
+	})
+}
+
+// dueAt returns when the next publication is due: LeadTime before
+// nextUpdate, or Interval after the last publication if that is earlier
+func dueAt(cfg generator.ScheduleConfig, lastPublished, nextUpdate time.Time) time.Time {
+	due := nextUpdate.Add(-cfg.LeadTime)
+	if cfg.Interval > 0 && !lastPublished.IsZero() {
+		if next := lastPublished.Add(cfg.Interval); next.Before(due) {
+			due = next
+		}
+	}
+	return due
+}
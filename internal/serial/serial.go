@@ -0,0 +1,128 @@
+package serial
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// MaxLen is the longest serial number RFC 5280 requires CAs to use and
+// relying parties to handle, in octets
+const MaxLen = 20
+
+// ErrInvalid is returned when a serial number is not a positive hex integer
+var ErrInvalid = errors.New("invalid serial number")
+
+// DecimalPrefix marks a serial number written in decimal, such as
+// dec:1234; without it serials are hexadecimal
+const DecimalPrefix = "dec:"
+
+// Parse parses a serial number as clients write it: hexadecimal in either
+// case, optionally prefixed with 0x or separated into octets by colons
+// (0a:1b:2c), or decimal after DecimalPrefix. Surrounding spaces are
+// ignored. The serial must be positive and at most MaxLen octets.
+func Parse(s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	var n *big.Int
+	var err error
+	switch {
+	case strings.HasPrefix(s, DecimalPrefix):
+		n, err = parseDecimal(strings.TrimPrefix(s, DecimalPrefix))
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0X"):
+		n, err = ParseHex(s[2:])
+	case strings.Contains(s, ":"):
+		n, err = parseOctets(s)
+	default:
+		n, err = ParseHex(s)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// Normalize parses s and returns it in canonical form, see Hex
+func Normalize(s string) (string, error) {
+	n, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return Hex(n), nil
+}
+
+func parseDecimal(s string) (*big.Int, error) {
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return nil, fmt.Errorf("%w: %q is not decimal", ErrInvalid, s)
+	}
+	n, _ := new(big.Int).SetString(s, 10)
+	if n.Sign() == 0 {
+		return nil, fmt.Errorf("%w: serial must be positive", ErrInvalid)
+	}
+	return n, nil
+}
+
+// parseOctets parses colon-separated hex octets
+func parseOctets(s string) (*big.Int, error) {
+	octets := strings.Split(s, ":")
+	for _, o := range octets {
+		if len(o) == 0 || len(o) > 2 {
+			return nil, fmt.Errorf("%w: %q is not colon-separated hex octets", ErrInvalid, s)
+		}
+	}
+	return ParseHex(strings.Join(octets, ""))
+}
+
+// ParseHex parses a bare hexadecimal serial number, as stored. Case and
+// leading zeros do not matter; signs, prefixes and zero are rejected.
+func ParseHex(s string) (*big.Int, error) {
+	if s == "" || strings.Trim(s, "0123456789abcdefABCDEF") != "" {
+		return nil, fmt.Errorf("%w: %q is not hexadecimal", ErrInvalid, s)
+	}
+	n, _ := new(big.Int).SetString(s, 16)
+	if n.Sign() == 0 {
+		return nil, fmt.Errorf("%w: serial must be positive", ErrInvalid)
+	}
+	return n, nil
+}
+
+// Validate checks that n is positive and at most MaxLen octets long
+func Validate(n *big.Int) error {
+	if n.Sign() <= 0 {
+		return fmt.Errorf("%w: serial must be positive", ErrInvalid)
+	}
+	if len(n.Bytes()) > MaxLen {
+		return fmt.Errorf("%w: serial is longer than %d octets", ErrInvalid, MaxLen)
+	}
+	return nil
+}
+
+// Bytes returns the canonical encoding of n: its magnitude, big-endian,
+// without leading zero octets
+func Bytes(n *big.Int) []byte {
+	return n.Bytes()
+}
+
+// FromBytes decodes the canonical encoding returned by Bytes
+func FromBytes(b []byte) *big.Int {
+	return new(big.Int).SetBytes(b)
+}
+
+// Hex returns n in lowercase hex without leading zeros, the canonical
+// spelling of a serial
+func Hex(n *big.Int) string {
+	return n.Text(16)
+}
+
+// Key returns the canonical encoding of a hex serial, or nil if it is not
+// valid, which matches no stored serial_number
+func Key(s string) []byte {
+	n, err := ParseHex(s)
+	if err != nil {
+		return nil
+	}
+	return Bytes(n)
+}
@@ -0,0 +1,231 @@
+package serial
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	octets20 = strings.Repeat("ff", MaxLen)
+	octets21 = "01" + octets20
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // canonical hex; empty for ErrInvalid
+	}{
+		{"1", "1"},
+		{"0a1b2c", "a1b2c"},
+		{"ABCDEF", "abcdef"},
+		{"AbCdEf09", "abcdef09"},
+		{"abc", "abc"},
+		{"000000000000000000000001", "1"},
+		{"  7f  ", "7f"},
+		{"0x1f", "1f"},
+		{"0X1F", "1f"},
+		{"0x000a", "a"},
+		{"0a:1b:2c", "a1b2c"},
+		{"0A:1B:2C", "a1b2c"},
+		{"a:bc", "abc"},
+		{"00:00:01", "1"},
+		{"dec:1234", "4d2"},
+		{"dec:0001234", "4d2"},
+		{"dec:1461501637330902918203684832716283019655932542975", octets20}, // 2^160-1
+		{octets20, octets20},
+		{"0x" + octets20, octets20},
+		{"00" + octets20, octets20},
+		{strings.ToUpper(octets20), octets20},
+
+		{"", ""},
+		{"   ", ""},
+		{"0", ""},
+		{"0000", ""},
+		{"0x0", ""},
+		{"00:00", ""},
+		{"dec:0", ""},
+		{"-1", ""},
+		{"-0x1", ""},
+		{"0x-1", ""},
+		{"dec:-5", ""},
+		{"+1", ""},
+		{"dec:", ""},
+		{"dec:12a", ""},
+		{"dec:1461501637330902918203684832716283019655932542976", ""}, // 2^160
+		{"0x", ""},
+		{"0xg", ""},
+		{"xyz", ""},
+		{"12 34", ""},
+		{"0a::1b", ""},
+		{":0a", ""},
+		{"0a:", ""},
+		{"abc:01", ""},
+		{"0x0a:1b", ""},
+		{octets21, ""},
+		{"0x" + octets21, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			n, err := Parse(tt.in)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Parse(%q) = %v, %v; want ErrInvalid", tt.in, n, err)
+				}
+				if _, err := Normalize(tt.in); !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Normalize(%q) = %v; want ErrInvalid", tt.in, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.in, err)
+			}
+			if got := Hex(n); got != tt.want {
+				t.Fatalf("Parse(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+			if got, err := Normalize(tt.in); err != nil || got != tt.want {
+				t.Fatalf("Normalize(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+			}
+			// The canonical form parses to itself
+			if again, err := Parse(tt.want); err != nil || again.Cmp(n) != 0 {
+				t.Fatalf("Parse(%q) = %v, %v; want %v", tt.want, again, err, n)
+			}
+		})
+	}
+}
+
+func TestParseHex(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // empty for ErrInvalid
+	}{
+		{"1", "1"},
+		{"01", "1"},
+		{"abc", "abc"},
+		{"ABC", "abc"},
+		{"aBc", "abc"},
+		{"00ff", "ff"},
+		{octets20, octets20},
+		// Length is checked by Validate, not by ParseHex, like the stored
+		// serial_number
+		{octets21, octets21[1:]},
+
+		{"", ""},
+		{"0", ""},
+		{"000", ""},
+		{"0x1", ""},
+		{"0a:1b", ""},
+		{"-1", ""},
+		{"+1", ""},
+		{" 1", ""},
+		{"dec:1", ""},
+		{"g", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			n, err := ParseHex(tt.in)
+			if tt.want == "" {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("ParseHex(%q) = %v, %v; want ErrInvalid", tt.in, n, err)
+				}
+				if key := Key(tt.in); key != nil {
+					t.Fatalf("Key(%q) = %x, want nil", tt.in, key)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseHex(%q): %v", tt.in, err)
+			}
+			if got := Hex(n); got != tt.want {
+				t.Fatalf("ParseHex(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+			if got := FromBytes(Key(tt.in)); got.Cmp(n) != 0 {
+				t.Fatalf("FromBytes(Key(%q)) = %v, want %v", tt.in, got, n)
+			}
+		})
+	}
+}
+
+func TestValidateBoundary(t *testing.T) {
+	n, err := ParseHex(octets20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(n); err != nil {
+		t.Fatalf("20 octets: %v", err)
+	}
+	if len(Bytes(n)) != MaxLen {
+		t.Fatalf("got %d octets, want %d", len(Bytes(n)), MaxLen)
+	}
+	n, err = ParseHex(octets21)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(n); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("21 octets: %v, want ErrInvalid", err)
+	}
+	if err := Validate(n.Neg(n)); !errors.Is(err, ErrInvalid) {
+		t.Fatalf("negative: %v, want ErrInvalid", err)
+	}
+}
+
+// sqlHex matches the serials migration 018 converts
+var sqlHex = regexp.MustCompile(`(?i)^[0-9a-f]+$`)
+
+// sqlSerialNumber computes serial_number as migration 018 does:
+// decode(lpad(digits, length(digits) + length(digits) % 2, '0'), 'hex')
+// with digits = ltrim(lower(serial), '0'), for hex serials whose digits are
+// not empty; nil where it leaves serial_number NULL
+func sqlSerialNumber(t *testing.T, s string) []byte {
+	t.Helper()
+	if !sqlHex.MatchString(s) {
+		return nil
+	}
+	digits := strings.TrimLeft(strings.ToLower(s), "0")
+	if digits == "" {
+		return nil
+	}
+	padded := strings.Repeat("0", len(digits)%2) + digits
+	b, err := hex.DecodeString(padded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// sqlSerial computes the canonical serial as migration 019 does:
+// ltrim(encode(serial_number, 'hex'), '0')
+func sqlSerial(serialNumber []byte) string {
+	return strings.TrimLeft(hex.EncodeToString(serialNumber), "0")
+}
+
+func TestKeyMatchesMigrations(t *testing.T) {
+	for _, s := range []string{
+		"1", "01", "001", "a", "0a", "abc", "0abc", "ABC", "AbC", "fF00",
+		"100", "1000", "0000100", "7f", "80", "ff", "0100",
+		octets20, "00" + octets20, octets21, strings.ToUpper(octets21),
+		"0", "00", "", "0x1", "a:b", "-1", "dec:1", "g1",
+	} {
+		want := sqlSerialNumber(t, s)
+		got := Key(s)
+		if !bytes.Equal(got, want) {
+			t.Errorf("Key(%q) = %x, migration 018 computes %x", s, got, want)
+		}
+		if want == nil {
+			continue
+		}
+		n, err := ParseHex(s)
+		if err != nil {
+			t.Fatalf("ParseHex(%q): %v", s, err)
+		}
+		if got, want := Hex(n), sqlSerial(want); got != want {
+			t.Errorf("Hex(ParseHex(%q)) = %s, migration 019 computes %s", s, got, want)
+		}
+		if b := Bytes(n); len(b) > 0 && b[0] == 0 {
+			t.Errorf("Bytes(%q) = %x has a leading zero octet", s, b)
+		}
+	}
+}
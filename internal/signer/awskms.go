@@ -0,0 +1,162 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/aws/aws-sdk-go-v2/service/kms/types"
+)
+
+// kmsTimeout bounds a single remote signing call, since crypto.Signer
+// carries no context
+const kmsTimeout = 10 * time.Second
+
+// AWSKMSConfig identifies an asymmetric AWS KMS signing key
+type AWSKMSConfig struct {
+	Region string `yaml:"region"`
+	KeyARN string `yaml:"key_arn"`
+}
+
+// awsKMSSigner signs with an asymmetric AWS KMS key
+type awsKMSSigner struct {
+	client  *kms.Client
+	keyID   string
+	keySpec types.KeySpec
+	algs    []types.SigningAlgorithmSpec
+	pub     crypto.PublicKey
+	cert    *x509.Certificate
+}
+
+func newAWSKMSSigner(ctx context.Context, cfg Config) (Signer, error) {
+	a := cfg.AWSKMS
+	if a.KeyARN == "" {
+		return nil, errors.New("awskms signer requires key_arn")
+	}
+
+	cert, err := loadCertificate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []func(*awsconfig.LoadOptions) error
+	if a.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(a.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	client := kms.NewFromConfig(awsCfg)
+
+	out, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{KeyId: &a.KeyARN})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch KMS public key: %w", err)
+	}
+	if out.KeyUsage != types.KeyUsageTypeSignVerify {
+		return nil, fmt.Errorf("KMS key %s is not a signing key (usage %s)", a.KeyARN, out.KeyUsage)
+	}
+	pub, err := x509.ParsePKIXPublicKey(out.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse KMS public key: %w", err)
+	}
+	if err := checkKeyMatchesCert(pub, cert); err != nil {
+		return nil, err
+	}
+
+	return &awsKMSSigner{
+		client:  client,
+		keyID:   a.KeyARN,
+		keySpec: out.KeySpec,
+		algs:    out.SigningAlgorithms,
+		pub:     pub,
+		cert:    cert,
+	}, nil
+}
+
+func (s *awsKMSSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+func (s *awsKMSSigner) Certificate() *x509.Certificate {
+	return s.cert
+}
+
+func (s *awsKMSSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	alg, err := s.signingAlgorithm(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	out, err := s.client.Sign(ctx, &kms.SignInput{
+		KeyId:            &s.keyID,
+		Message:          digest,
+		MessageType:      types.MessageTypeDigest,
+		SigningAlgorithm: alg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("KMS sign failed: %w", err)
+	}
+
+	return out.Signature, nil
+}
+
+// signingAlgorithm picks the KMS algorithm matching the key spec and the
+// digest requested by the caller
+func (s *awsKMSSigner) signingAlgorithm(opts crypto.SignerOpts) (types.SigningAlgorithmSpec, error) {
+	var bits string
+	switch opts.HashFunc() {
+	case crypto.SHA256:
+		bits = "256"
+	case crypto.SHA384:
+		bits = "384"
+	case crypto.SHA512:
+		bits = "512"
+	default:
+		return "", fmt.Errorf("unsupported digest %v for KMS signing", opts.HashFunc())
+	}
+
+	var alg types.SigningAlgorithmSpec
+	switch {
+	case strings.HasPrefix(string(s.keySpec), "RSA_"):
+		if _, pss := opts.(*rsa.PSSOptions); pss {
+			alg = types.SigningAlgorithmSpec("RSASSA_PSS_SHA_" + bits)
+		} else {
+			alg = types.SigningAlgorithmSpec("RSASSA_PKCS1_V1_5_SHA_" + bits)
+		}
+	case strings.HasPrefix(string(s.keySpec), "ECC_NIST_P"):
+		alg = types.SigningAlgorithmSpec("ECDSA_SHA_" + bits)
+	default:
+		return "", fmt.Errorf("unsupported KMS key spec %s", s.keySpec)
+	}
+
+	for _, a := range s.algs {
+		if a == alg {
+			return alg, nil
+		}
+	}
+	return "", fmt.Errorf("KMS key %s does not support %s", s.keyID, alg)
+}
+
+// CheckHealth confirms the key is still enabled
+func (s *awsKMSSigner) CheckHealth(ctx context.Context) error {
+	out, err := s.client.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: &s.keyID})
+	if err != nil {
+		return fmt.Errorf("KMS unreachable: %w", err)
+	}
+	if out.KeyMetadata == nil || out.KeyMetadata.KeyState != types.KeyStateEnabled {
+		return fmt.Errorf("KMS key %s is not enabled", s.keyID)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/security/keyvault/azkeys"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// AzureKeyVaultConfig identifies a Key Vault key. Authentication uses the
+// pod's managed identity; set ClientID to select a user-assigned identity.
+type AzureKeyVaultConfig struct {
+	VaultURL   string `yaml:"vault_url"`
+	KeyName    string `yaml:"key_name"`
+	KeyVersion string `yaml:"key_version"` // empty selects the current version
+	ClientID   string `yaml:"client_id"`
+}
+
+// azureKVSigner signs with a Key Vault (or Managed HSM) key
+type azureKVSigner struct {
+	client  *azkeys.Client
+	name    string
+	version string
+	pub     crypto.PublicKey
+	cert    *x509.Certificate
+}
+
+func newAzureKVSigner(ctx context.Context, cfg Config) (Signer, error) {
+	a := cfg.AzureKeyVault
+	if a.VaultURL == "" || a.KeyName == "" {
+		return nil, errors.New("azurekv signer requires vault_url and key_name")
+	}
+
+	cert, err := loadCertificate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &azidentity.ManagedIdentityCredentialOptions{}
+	if a.ClientID != "" {
+		opts.ID = azidentity.ClientID(a.ClientID)
+	}
+	cred, err := azidentity.NewManagedIdentityCredential(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create managed identity credential: %w", err)
+	}
+	client, err := azkeys.NewClient(a.VaultURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Key Vault client: %w", err)
+	}
+
+	resp, err := client.GetKey(ctx, a.KeyName, a.KeyVersion, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Key Vault key: %w", err)
+	}
+	pub, err := jwkPublicKey(resp.Key)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKeyMatchesCert(pub, cert); err != nil {
+		return nil, err
+	}
+
+	// Pin the version so a key rotation never silently changes the signer
+	version := a.KeyVersion
+	if version == "" && resp.Key.KID != nil {
+		version = resp.Key.KID.Version()
+	}
+
+	return &azureKVSigner{
+		client:  client,
+		name:    a.KeyName,
+		version: version,
+		pub:     pub,
+		cert:    cert,
+	}, nil
+}
+
+// jwkPublicKey converts a Key Vault JSON web key to a Go public key
+func jwkPublicKey(k *azkeys.JSONWebKey) (crypto.PublicKey, error) {
+	if k == nil || k.Kty == nil {
+		return nil, errors.New("key vault returned no key material")
+	}
+
+	switch *k.Kty {
+	case azkeys.KeyTypeRSA, azkeys.KeyTypeRSAHSM:
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(k.N),
+			E: int(new(big.Int).SetBytes(k.E).Int64()),
+		}, nil
+	case azkeys.KeyTypeEC, azkeys.KeyTypeECHSM:
+		if k.Crv == nil {
+			return nil, errors.New("key vault EC key has no curve")
+		}
+		var curve elliptic.Curve
+		switch *k.Crv {
+		case azkeys.CurveNameP256:
+			curve = elliptic.P256()
+		case azkeys.CurveNameP384:
+			curve = elliptic.P384()
+		case azkeys.CurveNameP521:
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported Key Vault curve %s", *k.Crv)
+		}
+		return &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(k.X),
+			Y:     new(big.Int).SetBytes(k.Y),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported Key Vault key type %s", *k.Kty)
+	}
+}
+
+func (s *azureKVSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+func (s *azureKVSigner) Certificate() *x509.Certificate {
+	return s.cert
+}
+
+func (s *azureKVSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	alg, err := s.signatureAlgorithm(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	resp, err := s.client.Sign(ctx, s.name, s.version, azkeys.SignParameters{
+		Algorithm: &alg,
+		Value:     digest,
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("key vault sign failed: %w", err)
+	}
+
+	// Key Vault returns ECDSA signatures as raw r||s; X.509 needs ASN.1
+	if _, ok := s.pub.(*ecdsa.PublicKey); ok {
+		return ecdsaRawToASN1(resp.Result)
+	}
+	return resp.Result, nil
+}
+
+func (s *azureKVSigner) signatureAlgorithm(opts crypto.SignerOpts) (azkeys.SignatureAlgorithm, error) {
+	_, pss := opts.(*rsa.PSSOptions)
+	_, isEC := s.pub.(*ecdsa.PublicKey)
+
+	switch opts.HashFunc() {
+	case crypto.SHA256:
+		switch {
+		case isEC:
+			return azkeys.SignatureAlgorithmES256, nil
+		case pss:
+			return azkeys.SignatureAlgorithmPS256, nil
+		default:
+			return azkeys.SignatureAlgorithmRS256, nil
+		}
+	case crypto.SHA384:
+		switch {
+		case isEC:
+			return azkeys.SignatureAlgorithmES384, nil
+		case pss:
+			return azkeys.SignatureAlgorithmPS384, nil
+		default:
+			return azkeys.SignatureAlgorithmRS384, nil
+		}
+	case crypto.SHA512:
+		switch {
+		case isEC:
+			return azkeys.SignatureAlgorithmES512, nil
+		case pss:
+			return azkeys.SignatureAlgorithmPS512, nil
+		default:
+			return azkeys.SignatureAlgorithmRS512, nil
+		}
+	default:
+		return "", fmt.Errorf("unsupported digest %v for Key Vault signing", opts.HashFunc())
+	}
+}
+
+// CheckHealth confirms the key is reachable and enabled
+func (s *azureKVSigner) CheckHealth(ctx context.Context) error {
+	resp, err := s.client.GetKey(ctx, s.name, s.version, nil)
+	if err != nil {
+		return fmt.Errorf("key vault unreachable: %w", err)
+	}
+	if resp.Attributes != nil && resp.Attributes.Enabled != nil && !*resp.Attributes.Enabled {
+		return fmt.Errorf("key vault key %s is disabled", s.name)
+	}
+	return nil
+}
+
+// ecdsaRawToASN1 converts a fixed-width r||s signature to ASN.1 DER
+func ecdsaRawToASN1(raw []byte) ([]byte, error) {
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, fmt.Errorf("invalid raw ECDSA signature length %d", len(raw))
+	}
+	half := len(raw) / 2
+	r := new(big.Int).SetBytes(raw[:half])
+	sv := new(big.Int).SetBytes(raw[half:])
+
+	var b cryptobyte.Builder
+	b.AddASN1(asn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1BigInt(r)
+		b.AddASN1BigInt(sv)
+	})
+	return b.Bytes()
+}
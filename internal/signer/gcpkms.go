@@ -0,0 +1,208 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	"github.com/gigvault/crl/internal/metrics"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// GCPKMSConfig identifies a Cloud KMS asymmetric signing key version
+type GCPKMSConfig struct {
+	// Full resource name:
+	// projects/P/locations/L/keyRings/R/cryptoKeys/K/cryptoKeyVersions/V
+	KeyVersion string `yaml:"key_version"`
+
+	// Retries on throttling and transient errors
+	MaxRetries     int           `yaml:"max_retries"`
+	InitialBackoff time.Duration `yaml:"initial_backoff"`
+}
+
+// gcpKMSSigner signs with a Cloud KMS asymmetric key version
+type gcpKMSSigner struct {
+	client  *kms.KeyManagementClient
+	name    string
+	hash    crypto.Hash
+	pss     bool
+	pub     crypto.PublicKey
+	cert    *x509.Certificate
+	retries int
+	backoff time.Duration
+}
+
+func newGCPKMSSigner(ctx context.Context, cfg Config) (Signer, error) {
+	g := cfg.GCPKMS
+	if g.KeyVersion == "" {
+		return nil, errors.New("gcpkms signer requires key_version")
+	}
+
+	cert, err := loadCertificate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := kms.NewKeyManagementClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud KMS client: %w", err)
+	}
+
+	pk, err := client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: g.KeyVersion})
+	if err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to fetch Cloud KMS public key: %w", err)
+	}
+	hash, pss, err := gcpAlgorithm(pk.Algorithm)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	pub, err := ParsePublicKey([]byte(pk.Pem))
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	if err := checkKeyMatchesCert(pub, cert); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	s := &gcpKMSSigner{
+		client:  client,
+		name:    g.KeyVersion,
+		hash:    hash,
+		pss:     pss,
+		pub:     pub,
+		cert:    cert,
+		retries: g.MaxRetries,
+		backoff: g.InitialBackoff,
+	}
+	if s.retries <= 0 {
+		s.retries = 5
+	}
+	if s.backoff <= 0 {
+		s.backoff = 100 * time.Millisecond
+	}
+
+	return s, nil
+}
+
+// gcpAlgorithm returns the digest fixed by a Cloud KMS signing algorithm
+func gcpAlgorithm(alg kmspb.CryptoKeyVersion_CryptoKeyVersionAlgorithm) (crypto.Hash, bool, error) {
+	switch alg {
+	case kmspb.CryptoKeyVersion_EC_SIGN_P256_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_2048_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_3072_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_4096_SHA256:
+		return crypto.SHA256, false, nil
+	case kmspb.CryptoKeyVersion_EC_SIGN_P384_SHA384:
+		return crypto.SHA384, false, nil
+	case kmspb.CryptoKeyVersion_RSA_SIGN_PKCS1_4096_SHA512:
+		return crypto.SHA512, false, nil
+	case kmspb.CryptoKeyVersion_RSA_SIGN_PSS_2048_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PSS_3072_SHA256,
+		kmspb.CryptoKeyVersion_RSA_SIGN_PSS_4096_SHA256:
+		return crypto.SHA256, true, nil
+	case kmspb.CryptoKeyVersion_RSA_SIGN_PSS_4096_SHA512:
+		return crypto.SHA512, true, nil
+	default:
+		return 0, false, fmt.Errorf("unsupported Cloud KMS algorithm %s", alg)
+	}
+}
+
+func (s *gcpKMSSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+func (s *gcpKMSSigner) Certificate() *x509.Certificate {
+	return s.cert
+}
+
+func (s *gcpKMSSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) (sig []byte, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.SigningDuration.WithLabelValues(BackendGCPKMS, metrics.Result(err)).Observe(time.Since(start).Seconds())
+	}()
+
+	if opts.HashFunc() != s.hash {
+		return nil, fmt.Errorf("cloud KMS key requires %v digest, got %v", s.hash, opts.HashFunc())
+	}
+	if _, pss := opts.(*rsa.PSSOptions); pss != s.pss {
+		return nil, errors.New("cloud KMS key padding does not match requested signature scheme")
+	}
+
+	req := &kmspb.AsymmetricSignRequest{
+		Name:         s.name,
+		DigestCrc32C: wrapperspb.Int64(int64(crc32c(digest))),
+	}
+	switch s.hash {
+	case crypto.SHA256:
+		req.Digest = &kmspb.Digest{Digest: &kmspb.Digest_Sha256{Sha256: digest}}
+	case crypto.SHA384:
+		req.Digest = &kmspb.Digest{Digest: &kmspb.Digest_Sha384{Sha384: digest}}
+	case crypto.SHA512:
+		req.Digest = &kmspb.Digest{Digest: &kmspb.Digest_Sha512{Sha512: digest}}
+	}
+
+	backoff := s.backoff
+	for attempt := 0; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+		resp, err := s.client.AsymmetricSign(ctx, req)
+		cancel()
+		if err == nil {
+			if !resp.VerifiedDigestCrc32C || resp.SignatureCrc32C == nil ||
+				int64(crc32c(resp.Signature)) != resp.SignatureCrc32C.Value {
+				return nil, errors.New("cloud KMS response failed integrity check")
+			}
+			return resp.Signature, nil
+		}
+		if attempt >= s.retries || !retryableKMSError(err) {
+			return nil, fmt.Errorf("cloud KMS sign failed: %w", err)
+		}
+
+		metrics.SigningRetries.WithLabelValues(BackendGCPKMS).Inc()
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// CheckHealth confirms the key version is still enabled
+func (s *gcpKMSSigner) CheckHealth(ctx context.Context) error {
+	v, err := s.client.GetCryptoKeyVersion(ctx, &kmspb.GetCryptoKeyVersionRequest{Name: s.name})
+	if err != nil {
+		return fmt.Errorf("cloud KMS unreachable: %w", err)
+	}
+	if v.State != kmspb.CryptoKeyVersion_ENABLED {
+		return fmt.Errorf("cloud KMS key version %s is %s", s.name, v.State)
+	}
+	return nil
+}
+
+// Close releases the Cloud KMS client connection
+func (s *gcpKMSSigner) Close() error {
+	return s.client.Close()
+}
+
+func retryableKMSError(err error) bool {
+	switch status.Code(err) {
+	case codes.ResourceExhausted, codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+func crc32c(data []byte) uint32 {
+	return crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))
+}
@@ -0,0 +1,184 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gigvault/shared/pkg/keystore"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+)
+
+func newFileSigner(cfg Config) (Signer, error) {
+	if cfg.CertPath == "" || cfg.KeyPath == "" {
+		return nil, errors.New("file signer requires cert_path and key_path")
+	}
+
+	cert, err := loadCertificate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, err := os.ReadFile(cfg.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issuer key: %w", err)
+	}
+	key, err := ParsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKeySigner(key, cert)
+}
+
+func newEnvSigner(cfg Config) (Signer, error) {
+	if cfg.CertEnv == "" || cfg.KeyEnv == "" {
+		return nil, errors.New("env signer requires cert_env and key_env")
+	}
+
+	cert, err := loadCertificate(Config{CertEnv: cfg.CertEnv})
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM := os.Getenv(cfg.KeyEnv)
+	if keyPEM == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", cfg.KeyEnv)
+	}
+	key, err := ParsePrivateKey([]byte(keyPEM))
+	if err != nil {
+		return nil, err
+	}
+
+	return newKeySigner(key, cert)
+}
+
+// newDBSigner loads an envelope-encrypted key from the shared keystore.
+// Its data encryption key is unwrapped by the KMS key in master_key, so
+// the master key itself is never held by the process.
+func newDBSigner(ctx context.Context, cfg Config, db *pgxpool.Pool) (Signer, error) {
+	if cfg.KeyID == "" {
+		return nil, errors.New("db signer requires key_id")
+	}
+	if db == nil {
+		return nil, errors.New("db signer requires a database connection")
+	}
+
+	cert, err := loadCertificate(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	hsm, err := newKeyUnwrapper(ctx, cfg.MasterKey)
+	if err != nil {
+		return nil, err
+	}
+	defer hsm.Close()
+	storage := keystore.NewKeyStorage(stdlib.OpenDBFromPool(db))
+	envelope := keystore.NewEnvelopeEncryption(hsm, storage)
+
+	encKey, err := storage.Get(ctx, cfg.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	key, err := envelope.DecryptPrivateKey(encKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return newKeySigner(key, cert)
+}
+
+// loadCertificate reads the issuer certificate from cert_path, or from
+// the cert_env variable when no path is configured
+func loadCertificate(cfg Config) (*x509.Certificate, error) {
+	var data []byte
+	switch {
+	case cfg.CertPath != "":
+		b, err := os.ReadFile(cfg.CertPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read issuer certificate: %w", err)
+		}
+		data = b
+	case cfg.CertEnv != "":
+		v := os.Getenv(cfg.CertEnv)
+		if v == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", cfg.CertEnv)
+		}
+		data = []byte(v)
+	default:
+		return nil, errors.New("issuer certificate not configured (cert_path or cert_env)")
+	}
+
+	return ParseCertificate(data)
+}
+
+// ParseCertificate parses a PEM encoded X.509 certificate
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("failed to decode issuer certificate PEM")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issuer certificate: %w", err)
+	}
+
+	return cert, nil
+}
+
+// ParsePublicKey parses a PEM encoded PKIX public key
+func ParsePublicKey(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode public key PEM")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return pub, nil
+}
+
+// ParsePrivateKey parses a PEM encoded PKCS#8, SEC 1 (EC) or PKCS#1 (RSA) private key
+func ParsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("failed to decode issuer key PEM")
+	}
+
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse EC private key: %w", err)
+		}
+		return key, nil
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+		}
+		return key, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse PKCS#8 private key: %w", err)
+		}
+		s, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key type %T", key)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+}
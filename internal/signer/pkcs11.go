@@ -0,0 +1,97 @@
+//go:build pkcs11
+
+package signer
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ThalesGroup/crypto11"
+)
+
+// pkcs11Signer signs with a private key that never leaves the HSM
+type pkcs11Signer struct {
+	crypto11.Signer
+	ctx   *crypto11.Context
+	label []byte
+	cert  *x509.Certificate
+}
+
+func newPKCS11Signer(cfg Config) (Signer, error) {
+	p := cfg.PKCS11
+	if p.ModulePath == "" || p.KeyLabel == "" {
+		return nil, errors.New("pkcs11 signer requires module_path and key_label")
+	}
+	if p.Slot == nil && p.TokenLabel == "" {
+		return nil, errors.New("pkcs11 signer requires slot or token_label")
+	}
+
+	pin := p.PIN
+	if pin == "" {
+		pin = os.Getenv("PKCS11_PIN")
+	}
+
+	ctx, err := crypto11.Configure(&crypto11.Config{
+		Path:       p.ModulePath,
+		SlotNumber: p.Slot,
+		TokenLabel: p.TokenLabel,
+		Pin:        pin,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open PKCS#11 token: %w", err)
+	}
+
+	label := []byte(p.KeyLabel)
+	key, err := ctx.FindKeyPair(nil, label)
+	if err != nil || key == nil {
+		ctx.Close()
+		return nil, fmt.Errorf("failed to find PKCS#11 key %q: %v", p.KeyLabel, err)
+	}
+
+	// Prefer a configured certificate; otherwise use the one stored on the token
+	var cert *x509.Certificate
+	if cfg.CertPath != "" || cfg.CertEnv != "" {
+		cert, err = loadCertificate(cfg)
+	} else {
+		cert, err = ctx.FindCertificate(nil, label, nil)
+		if err == nil && cert == nil {
+			err = fmt.Errorf("no certificate with label %q on token", p.KeyLabel)
+		}
+	}
+	if err != nil {
+		ctx.Close()
+		return nil, err
+	}
+
+	if err := checkKeyMatchesCert(key.Public(), cert); err != nil {
+		ctx.Close()
+		return nil, err
+	}
+
+	return &pkcs11Signer{Signer: key, ctx: ctx, label: label, cert: cert}, nil
+}
+
+func (s *pkcs11Signer) Certificate() *x509.Certificate {
+	return s.cert
+}
+
+// CheckHealth opens a session and looks the key up again, which fails if the
+// token has been removed or the HSM is unreachable
+func (s *pkcs11Signer) CheckHealth(ctx context.Context) error {
+	key, err := s.ctx.FindKeyPair(nil, s.label)
+	if err != nil {
+		return fmt.Errorf("PKCS#11 token unreachable: %w", err)
+	}
+	if key == nil {
+		return fmt.Errorf("PKCS#11 key %q no longer present", s.label)
+	}
+	return nil
+}
+
+// Close releases the PKCS#11 sessions
+func (s *pkcs11Signer) Close() error {
+	return s.ctx.Close()
+}
@@ -0,0 +1,9 @@
+//go:build !pkcs11
+
+package signer
+
+import "errors"
+
+func newPKCS11Signer(cfg Config) (Signer, error) {
+	return nil, errors.New("pkcs11 signer unavailable: binary built without the pkcs11 build tag")
+}
@@ -0,0 +1,150 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"io"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Supported signer backends
+const (
+	BackendFile   = "file"
+	BackendEnv    = "env"
+	BackendDB     = "db"
+	BackendPKCS11 = "pkcs11"
+	BackendAWSKMS = "awskms"
+	BackendGCPKMS = "gcpkms"
+	BackendAzure  = "azurekv"
+	BackendVault  = "vault"
+)
+
+// Signer signs CRLs on behalf of the issuing CA
+type Signer interface {
+	crypto.Signer
+
+	// Certificate returns the CRL issuer certificate
+	Certificate() *x509.Certificate
+}
+
+// HealthChecker is implemented by signers whose key lives outside the
+// process and can become unreachable after startup
+type HealthChecker interface {
+	CheckHealth(ctx context.Context) error
+}
+
+// CheckHealth verifies that s is able to sign. Signers holding their key
+// in memory are always healthy.
+func CheckHealth(ctx context.Context, s Signer) error {
+	if hc, ok := s.(HealthChecker); ok {
+		return hc.CheckHealth(ctx)
+	}
+	return nil
+}
+
+// Config selects the signer backend and where its key material lives
+type Config struct {
+	Backend string `yaml:"backend"` // file, env, db, pkcs11, awskms, gcpkms, azurekv, vault
+
+	// Issuer certificate and private key as PEM files (file backend)
+	CertPath string `yaml:"cert_path"`
+	KeyPath  string `yaml:"key_path"`
+
+	// Environment variables holding PEM data (env backend)
+	CertEnv string `yaml:"cert_env"`
+	KeyEnv  string `yaml:"key_env"`
+
+	// Envelope-encrypted key in the encrypted_keys table (db backend).
+	// The issuer certificate is read from cert_path or cert_env.
+	KeyID     string          `yaml:"key_id"`
+	MasterKey MasterKeyConfig `yaml:"master_key"`
+
+	PKCS11 PKCS11Config `yaml:"pkcs11"`
+	AWSKMS AWSKMSConfig `yaml:"awskms"`
+	GCPKMS GCPKMSConfig `yaml:"gcpkms"`
+
+	AzureKeyVault AzureKeyVaultConfig `yaml:"azurekv"`
+	Vault         VaultConfig         `yaml:"vault"`
+}
+
+// PKCS11Config locates the signing key on a PKCS#11 token. The PIN may be
+// supplied through PKCS11_PIN instead of the config file.
+type PKCS11Config struct {
+	ModulePath string `yaml:"module_path"`
+	Slot       *int   `yaml:"slot"`
+	TokenLabel string `yaml:"token_label"`
+	PIN        string `yaml:"pin"`
+	KeyLabel   string `yaml:"key_label"`
+}
+
+// New creates the signer selected by cfg.Backend
+func New(ctx context.Context, cfg Config, db *pgxpool.Pool) (Signer, error) {
+	switch cfg.Backend {
+	case BackendFile, "":
+		return newFileSigner(cfg)
+	case BackendEnv:
+		return newEnvSigner(cfg)
+	case BackendDB:
+		return newDBSigner(ctx, cfg, db)
+	case BackendPKCS11:
+		return newPKCS11Signer(cfg)
+	case BackendAWSKMS:
+		return newAWSKMSSigner(ctx, cfg)
+	case BackendGCPKMS:
+		return newGCPKMSSigner(ctx, cfg)
+	case BackendAzure:
+		return newAzureKVSigner(ctx, cfg)
+	case BackendVault:
+		return newVaultSigner(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown signer backend: %q", cfg.Backend)
+	}
+}
+
+// keySigner pairs an in-memory private key with its issuer certificate
+type keySigner struct {
+	key  crypto.Signer
+	cert *x509.Certificate
+}
+
+func newKeySigner(key crypto.Signer, cert *x509.Certificate) (*keySigner, error) {
+	if err := checkKeyMatchesCert(key.Public(), cert); err != nil {
+		return nil, err
+	}
+	return &keySigner{key: key, cert: cert}, nil
+}
+
+func (s *keySigner) Public() crypto.PublicKey {
+	return s.key.Public()
+}
+
+func (s *keySigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return s.key.Sign(rand, digest, opts)
+}
+
+func (s *keySigner) Certificate() *x509.Certificate {
+	return s.cert
+}
+
+// checkKeyMatchesCert ensures the signing key belongs to the issuer certificate
+func checkKeyMatchesCert(pub crypto.PublicKey, cert *x509.Certificate) error {
+	want, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal certificate public key: %w", err)
+	}
+	got, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return fmt.Errorf("failed to marshal signer public key: %w", err)
+	}
+	if !bytes.Equal(want, got) {
+		return fmt.Errorf("signing key does not match issuer certificate %q", cert.Subject.String())
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		return fmt.Errorf("issuer certificate %q is not allowed to sign CRLs", cert.Subject.String())
+	}
+	return nil
+}
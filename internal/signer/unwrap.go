@@ -0,0 +1,157 @@
+package signer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	gcpkms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/kms/apiv1/kmspb"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/gigvault/shared/pkg/keystore"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// MasterKeyConfig selects the symmetric KMS key that wraps the data
+// encryption keys of the db backend
+type MasterKeyConfig struct {
+	Backend string `yaml:"backend"` // awskms or gcpkms
+
+	// Symmetric AWS KMS key (awskms)
+	Region string `yaml:"region"`
+	KeyARN string `yaml:"key_arn"`
+
+	// Cloud KMS crypto key resource name (gcpkms):
+	// projects/P/locations/L/keyRings/R/cryptoKeys/K
+	KeyName string `yaml:"key_name"`
+}
+
+// keyUnwrapper decrypts and encrypts data encryption keys with a KMS key
+// that never leaves the KMS. It implements keystore.HSMInterface so the
+// shared envelope format is kept.
+type keyUnwrapper interface {
+	keystore.HSMInterface
+	io.Closer
+}
+
+// newKeyUnwrapper connects to the KMS selected by cfg.Backend
+func newKeyUnwrapper(ctx context.Context, cfg MasterKeyConfig) (keyUnwrapper, error) {
+	switch cfg.Backend {
+	case BackendAWSKMS:
+		if cfg.KeyARN == "" {
+			return nil, errors.New("awskms master key requires key_arn")
+		}
+		var opts []func(*awsconfig.LoadOptions) error
+		if cfg.Region != "" {
+			opts = append(opts, awsconfig.WithRegion(cfg.Region))
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %w", err)
+		}
+		return &awsKMSUnwrapper{client: kms.NewFromConfig(awsCfg), keyID: cfg.KeyARN}, nil
+	case BackendGCPKMS:
+		if cfg.KeyName == "" {
+			return nil, errors.New("gcpkms master key requires key_name")
+		}
+		client, err := gcpkms.NewKeyManagementClient(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Cloud KMS client: %w", err)
+		}
+		return &gcpKMSUnwrapper{client: client, name: cfg.KeyName}, nil
+	case "":
+		return nil, errors.New("db signer requires master_key.backend")
+	default:
+		return nil, fmt.Errorf("unknown master key backend: %q", cfg.Backend)
+	}
+}
+
+// awsKMSUnwrapper wraps keys with a symmetric AWS KMS key
+type awsKMSUnwrapper struct {
+	client *kms.Client
+	keyID  string
+}
+
+func (u *awsKMSUnwrapper) Encrypt(plaintext []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	out, err := u.client.Encrypt(ctx, &kms.EncryptInput{KeyId: &u.keyID, Plaintext: plaintext})
+	if err != nil {
+		return nil, fmt.Errorf("KMS encrypt failed: %w", err)
+	}
+	return out.CiphertextBlob, nil
+}
+
+func (u *awsKMSUnwrapper) Decrypt(ciphertext []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	// Naming the key makes KMS refuse ciphertext wrapped by any other key
+	out, err := u.client.Decrypt(ctx, &kms.DecryptInput{KeyId: &u.keyID, CiphertextBlob: ciphertext})
+	if err != nil {
+		return nil, fmt.Errorf("KMS decrypt failed: %w", err)
+	}
+	return out.Plaintext, nil
+}
+
+func (u *awsKMSUnwrapper) Sign(string, []byte) ([]byte, error) {
+	return nil, errors.New("KMS master key does not sign")
+}
+
+func (u *awsKMSUnwrapper) Close() error {
+	return nil
+}
+
+// gcpKMSUnwrapper wraps keys with a symmetric Cloud KMS crypto key
+type gcpKMSUnwrapper struct {
+	client *gcpkms.KeyManagementClient
+	name   string
+}
+
+func (u *gcpKMSUnwrapper) Encrypt(plaintext []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	resp, err := u.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:            u.name,
+		Plaintext:       plaintext,
+		PlaintextCrc32C: wrapperspb.Int64(int64(crc32c(plaintext))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloud KMS encrypt failed: %w", err)
+	}
+	if !resp.VerifiedPlaintextCrc32C || resp.CiphertextCrc32C == nil ||
+		int64(crc32c(resp.Ciphertext)) != resp.CiphertextCrc32C.Value {
+		return nil, errors.New("cloud KMS response failed integrity check")
+	}
+	return resp.Ciphertext, nil
+}
+
+func (u *gcpKMSUnwrapper) Decrypt(ciphertext []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	resp, err := u.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:             u.name,
+		Ciphertext:       ciphertext,
+		CiphertextCrc32C: wrapperspb.Int64(int64(crc32c(ciphertext))),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cloud KMS decrypt failed: %w", err)
+	}
+	if resp.PlaintextCrc32C == nil || int64(crc32c(resp.Plaintext)) != resp.PlaintextCrc32C.Value {
+		return nil, errors.New("cloud KMS response failed integrity check")
+	}
+	return resp.Plaintext, nil
+}
+
+func (u *gcpKMSUnwrapper) Sign(string, []byte) ([]byte, error) {
+	return nil, errors.New("cloud KMS master key does not sign")
+}
+
+func (u *gcpKMSUnwrapper) Close() error {
+	return u.client.Close()
+}
@@ -0,0 +1,312 @@
+package signer
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gigvault/shared/pkg/logger"
+	vault "github.com/hashicorp/vault/api"
+	"go.uber.org/zap"
+)
+
+// VaultConfig selects a Vault transit key. The issuer certificate can be
+// read from a Vault PKI mount instead of cert_path/cert_env.
+type VaultConfig struct {
+	Address      string `yaml:"address"` // defaults to VAULT_ADDR
+	TransitMount string `yaml:"transit_mount"`
+	KeyName      string `yaml:"key_name"`
+	PKIMount     string `yaml:"pki_mount"`
+	PKIIssuer    string `yaml:"pki_issuer"` // issuer ref, defaults to "default"
+
+	// Auth is "token" (VAULT_TOKEN or Token) or "kubernetes"
+	Auth           string `yaml:"auth"`
+	Token          string `yaml:"token"`
+	KubernetesRole string `yaml:"kubernetes_role"`
+	KubernetesJWT  string `yaml:"kubernetes_jwt_path"`
+}
+
+// vaultSigner signs with a Vault transit key and keeps its token alive
+type vaultSigner struct {
+	client *vault.Client
+	cfg    VaultConfig
+	pub    crypto.PublicKey
+	cert   *x509.Certificate
+	stop   context.CancelFunc
+	logger *logger.Logger
+}
+
+func newVaultSigner(ctx context.Context, cfg Config) (Signer, error) {
+	v := cfg.Vault
+	if v.KeyName == "" {
+		return nil, errors.New("vault signer requires key_name")
+	}
+	if v.TransitMount == "" {
+		v.TransitMount = "transit"
+	}
+	if v.KubernetesJWT == "" {
+		v.KubernetesJWT = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	}
+
+	vcfg := vault.DefaultConfig()
+	if v.Address != "" {
+		vcfg.Address = v.Address
+	}
+	client, err := vault.NewClient(vcfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault client: %w", err)
+	}
+
+	s := &vaultSigner{client: client, cfg: v, logger: logger.Global()}
+
+	auth, err := s.login(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.CertPath != "" || cfg.CertEnv != "" {
+		s.cert, err = loadCertificate(cfg)
+	} else {
+		s.cert, err = s.fetchPKICertificate(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.pub, err = s.fetchPublicKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKeyMatchesCert(s.pub, s.cert); err != nil {
+		return nil, err
+	}
+
+	renewCtx, cancel := context.WithCancel(context.Background())
+	s.stop = cancel
+	go s.keepTokenAlive(renewCtx, auth)
+
+	return s, nil
+}
+
+// login authenticates to Vault and returns the resulting token secret
+func (s *vaultSigner) login(ctx context.Context) (*vault.Secret, error) {
+	switch s.cfg.Auth {
+	case "kubernetes":
+		jwt, err := os.ReadFile(s.cfg.KubernetesJWT)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read service account token: %w", err)
+		}
+		secret, err := s.client.Logical().WriteWithContext(ctx, "auth/kubernetes/login", map[string]interface{}{
+			"role": s.cfg.KubernetesRole,
+			"jwt":  strings.TrimSpace(string(jwt)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("vault kubernetes login failed: %w", err)
+		}
+		if secret == nil || secret.Auth == nil {
+			return nil, errors.New("vault kubernetes login returned no token")
+		}
+		s.client.SetToken(secret.Auth.ClientToken)
+		return secret, nil
+	case "token", "":
+		if s.cfg.Token != "" {
+			s.client.SetToken(s.cfg.Token)
+		}
+		if s.client.Token() == "" {
+			return nil, errors.New("vault signer requires a token (VAULT_TOKEN or token)")
+		}
+		// Renewing once yields the lease information the watcher needs;
+		// non-renewable tokens (e.g. root) are used as they are
+		secret, err := s.client.Auth().Token().RenewSelfWithContext(ctx, 0)
+		if err != nil {
+			s.logger.Info("Vault token is not renewable", zap.Error(err))
+			return nil, nil
+		}
+		return secret, nil
+	default:
+		return nil, fmt.Errorf("unknown vault auth method %q", s.cfg.Auth)
+	}
+}
+
+// keepTokenAlive renews the token until its max TTL, then logs in again
+// (kubernetes auth) so the signer never loses access
+func (s *vaultSigner) keepTokenAlive(ctx context.Context, auth *vault.Secret) {
+	backoff := time.Second
+	for {
+		if auth != nil && auth.Auth != nil && auth.Auth.Renewable {
+			watcher, err := s.client.NewLifetimeWatcher(&vault.LifetimeWatcherInput{Secret: auth})
+			if err != nil {
+				s.logger.Error("Failed to start Vault token watcher", zap.Error(err))
+				return
+			}
+			go watcher.Start()
+			err = s.watch(ctx, watcher)
+			watcher.Stop()
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				s.logger.Warn("Vault token renewal stopped", zap.Error(err))
+			}
+		} else if s.cfg.Auth != "kubernetes" {
+			// Static token without a renewable lease: nothing to do
+			return
+		}
+
+		if s.cfg.Auth != "kubernetes" {
+			s.logger.Error("Vault token expired and cannot be re-issued; signing will fail")
+			return
+		}
+
+		next, err := s.login(ctx)
+		if err != nil {
+			s.logger.Error("Vault re-login failed", zap.Error(err))
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			if backoff < time.Minute {
+				backoff *= 2
+			}
+			continue
+		}
+		backoff = time.Second
+		auth = next
+		s.logger.Info("Vault token re-issued")
+	}
+}
+
+func (s *vaultSigner) watch(ctx context.Context, w *vault.LifetimeWatcher) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-w.DoneCh():
+			return err
+		case <-w.RenewCh():
+			s.logger.Debug("Vault token renewed")
+		}
+	}
+}
+
+func (s *vaultSigner) fetchPublicKey(ctx context.Context) (crypto.PublicKey, error) {
+	secret, err := s.client.Logical().ReadWithContext(ctx, fmt.Sprintf("%s/keys/%s", s.cfg.TransitMount, s.cfg.KeyName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transit key: %w", err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("transit key %s not found", s.cfg.KeyName)
+	}
+
+	latest := fmt.Sprint(secret.Data["latest_version"])
+	keys, _ := secret.Data["keys"].(map[string]interface{})
+	version, _ := keys[latest].(map[string]interface{})
+	pemKey, _ := version["public_key"].(string)
+	if pemKey == "" {
+		return nil, fmt.Errorf("transit key %s is not an asymmetric signing key", s.cfg.KeyName)
+	}
+
+	return ParsePublicKey([]byte(pemKey))
+}
+
+func (s *vaultSigner) fetchPKICertificate(ctx context.Context) (*x509.Certificate, error) {
+	if s.cfg.PKIMount == "" {
+		return nil, errors.New("issuer certificate not configured (cert_path, cert_env or vault pki_mount)")
+	}
+	issuer := s.cfg.PKIIssuer
+	if issuer == "" {
+		issuer = "default"
+	}
+
+	secret, err := s.client.Logical().ReadWithContext(ctx, fmt.Sprintf("%s/issuer/%s", s.cfg.PKIMount, issuer))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault PKI issuer: %w", err)
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("vault PKI issuer %s not found", issuer)
+	}
+	certPEM, _ := secret.Data["certificate"].(string)
+
+	return ParseCertificate([]byte(certPEM))
+}
+
+func (s *vaultSigner) Public() crypto.PublicKey {
+	return s.pub
+}
+
+func (s *vaultSigner) Certificate() *x509.Certificate {
+	return s.cert
+}
+
+func (s *vaultSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	var hashAlg string
+	switch opts.HashFunc() {
+	case crypto.SHA256:
+		hashAlg = "sha2-256"
+	case crypto.SHA384:
+		hashAlg = "sha2-384"
+	case crypto.SHA512:
+		hashAlg = "sha2-512"
+	default:
+		return nil, fmt.Errorf("unsupported digest %v for Vault signing", opts.HashFunc())
+	}
+
+	data := map[string]interface{}{
+		"input":     base64.StdEncoding.EncodeToString(digest),
+		"prehashed": true,
+	}
+	switch s.pub.(type) {
+	case *rsa.PublicKey:
+		if _, pss := opts.(*rsa.PSSOptions); pss {
+			data["signature_algorithm"] = "pss"
+		} else {
+			data["signature_algorithm"] = "pkcs1v15"
+		}
+	case *ecdsa.PublicKey:
+		data["marshaling_algorithm"] = "asn1"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kmsTimeout)
+	defer cancel()
+
+	path := fmt.Sprintf("%s/sign/%s/%s", s.cfg.TransitMount, s.cfg.KeyName, hashAlg)
+	secret, err := s.client.Logical().WriteWithContext(ctx, path, data)
+	if err != nil {
+		return nil, fmt.Errorf("vault sign failed: %w", err)
+	}
+	if secret == nil {
+		return nil, errors.New("vault sign returned no data")
+	}
+
+	// Signatures are formatted as vault:v<version>:<base64>
+	sig, _ := secret.Data["signature"].(string)
+	parts := strings.SplitN(sig, ":", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("unexpected Vault signature format")
+	}
+	return base64.StdEncoding.DecodeString(parts[2])
+}
+
+// CheckHealth confirms Vault is reachable with a valid token
+func (s *vaultSigner) CheckHealth(ctx context.Context) error {
+	if _, err := s.client.Auth().Token().LookupSelfWithContext(ctx); err != nil {
+		return fmt.Errorf("vault unreachable or token invalid: %w", err)
+	}
+	return nil
+}
+
+// Close stops token renewal
+func (s *vaultSigner) Close() error {
+	s.stop()
+	return nil
+}
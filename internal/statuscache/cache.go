@@ -0,0 +1,206 @@
+package statuscache
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/shared/pkg/logger"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTTL       = 5 * time.Minute
+	defaultTimeout   = 100 * time.Millisecond
+	defaultKeyPrefix = "crl:status:"
+)
+
+// Config configures the Redis cache of per-serial revocation status
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+
+	// Address is the host:port of the Redis server
+	Address  string `yaml:"address"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+
+	// TLS connects with TLS, verifying the server against CAFile or the
+	// system roots
+	TLS    bool   `yaml:"tls"`
+	CAFile string `yaml:"ca_file"`
+
+	// TTL bounds how long a status is cached (default 5m). Entries changed
+	// on any replica are dropped at once; the TTL covers races with
+	// concurrent lookups, and changes made outside the service.
+	TTL time.Duration `yaml:"ttl"`
+
+	// Timeout bounds each Redis round trip (default 100ms); slower lookups
+	// fall back to Postgres
+	Timeout time.Duration `yaml:"timeout"`
+
+	// KeyPrefix namespaces the keys (default crl:status:)
+	KeyPrefix string `yaml:"key_prefix"`
+}
+
+// Validate applies defaults and checks the status cache config
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Address == "" {
+		return errors.New("address is required")
+	}
+	if c.TTL < 0 || c.Timeout < 0 {
+		return errors.New("ttl and timeout must not be negative")
+	}
+	if c.TTL == 0 {
+		c.TTL = defaultTTL
+	}
+	if c.Timeout == 0 {
+		c.Timeout = defaultTimeout
+	}
+	if c.KeyPrefix == "" {
+		c.KeyPrefix = defaultKeyPrefix
+	}
+	return nil
+}
+
+// Cache keeps encoded revocation statuses in Redis, keyed by issuer and
+// canonical serial. Redis failures are logged and treated as misses, so
+// status checks fall back to Postgres. A nil Cache caches nothing.
+type Cache struct {
+	cfg    Config
+	client *redis.Client
+	logger *logger.Logger
+}
+
+// New creates a status cache. The connection is made lazily.
+func New(cfg Config) (*Cache, error) {
+	opts := &redis.Options{
+		Addr:         cfg.Address,
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		DialTimeout:  cfg.Timeout,
+		ReadTimeout:  cfg.Timeout,
+		WriteTimeout: cfg.Timeout,
+	}
+	if cfg.TLS {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if cfg.CAFile != "" {
+			pemData, err := os.ReadFile(cfg.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			roots := x509.NewCertPool()
+			if !roots.AppendCertsFromPEM(pemData) {
+				return nil, fmt.Errorf("no certificates in CA file %s", cfg.CAFile)
+			}
+			tlsConfig.RootCAs = roots
+		}
+		opts.TLSConfig = tlsConfig
+	}
+	return &Cache{cfg: cfg, client: redis.NewClient(opts), logger: logger.Global()}, nil
+}
+
+func (c *Cache) key(issuerID, serial string) string {
+	return c.cfg.KeyPrefix + issuerID + ":" + serial
+}
+
+// Get returns the cached status of a serial
+func (c *Cache) Get(ctx context.Context, issuerID, serial string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+	value, err := c.client.Get(ctx, c.key(issuerID, serial)).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+		metrics.StatusCacheLookups.WithLabelValues("miss").Inc()
+		return nil, false
+	case err != nil:
+		metrics.StatusCacheLookups.WithLabelValues("error").Inc()
+		c.logger.Warn("Failed to read status cache", zap.Error(err))
+		return nil, false
+	}
+	metrics.StatusCacheLookups.WithLabelValues("hit").Inc()
+	return value, true
+}
+
+// GetMany returns the cached statuses of serials, nil for misses
+func (c *Cache) GetMany(ctx context.Context, issuerID string, serials []string) [][]byte {
+	values := make([][]byte, len(serials))
+	if c == nil || len(serials) == 0 {
+		return values
+	}
+	keys := make([]string, len(serials))
+	for i, serial := range serials {
+		keys[i] = c.key(issuerID, serial)
+	}
+	cached, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		metrics.StatusCacheLookups.WithLabelValues("error").Add(float64(len(serials)))
+		c.logger.Warn("Failed to read status cache", zap.Error(err))
+		return values
+	}
+	for i, v := range cached {
+		s, ok := v.(string)
+		if !ok {
+			metrics.StatusCacheLookups.WithLabelValues("miss").Inc()
+			continue
+		}
+		metrics.StatusCacheLookups.WithLabelValues("hit").Inc()
+		values[i] = []byte(s)
+	}
+	return values
+}
+
+// Set caches the status of a serial for at most ttl, capped at the
+// configured TTL; zero uses the configured TTL
+func (c *Cache) Set(ctx context.Context, issuerID, serial string, value []byte, ttl time.Duration) {
+	c.SetMany(ctx, issuerID, map[string][]byte{serial: value}, ttl)
+}
+
+// SetMany caches the statuses of several serials, see Set
+func (c *Cache) SetMany(ctx context.Context, issuerID string, values map[string][]byte, ttl time.Duration) {
+	if c == nil || len(values) == 0 {
+		return
+	}
+	if ttl <= 0 || ttl > c.cfg.TTL {
+		ttl = c.cfg.TTL
+	}
+	_, err := c.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for serial, value := range values {
+			p.Set(ctx, c.key(issuerID, serial), value, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		c.logger.Warn("Failed to write status cache", zap.Error(err))
+	}
+}
+
+// Delete drops the cached status of a serial after its entry changed
+func (c *Cache) Delete(ctx context.Context, issuerID, serial string) {
+	if c == nil {
+		return
+	}
+	if err := c.client.Del(ctx, c.key(issuerID, serial)).Err(); err != nil {
+		c.logger.Warn("Failed to invalidate status cache",
+			zap.String("issuer_id", issuerID),
+			zap.String("serial", serial),
+			zap.Error(err),
+		)
+	}
+}
+
+// Close closes the connections to Redis
+func (c *Cache) Close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrAPIKeyNotFound is returned for an unknown API key ID
+	ErrAPIKeyNotFound = errors.New("api key not found")
+
+	// ErrAPIKeyInactive is returned when rotating or revoking a key that is
+	// revoked or expired
+	ErrAPIKeyInactive = errors.New("api key is revoked or expired")
+)
+
+// APIKey is a client API key, stored in api_keys with only the hash of its
+// secret
+type APIKey struct {
+	ID          string
+	Client      string
+	SecretHash  []byte
+	Roles       []string
+	Methods     []string
+	CreatedBy   string
+	CreatedAt   time.Time
+	ExpiresAt   *time.Time
+	RevokedAt   *time.Time
+	RotatedFrom string
+}
+
+// Active reports whether the key may authenticate calls at now
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+const apiKeyColumns = `id, client, secret_hash, roles, methods, created_by, created_at, expires_at, revoked_at, COALESCE(rotated_from, '')`
+
+func scanAPIKey(row pgx.Row) (*APIKey, error) {
+	var k APIKey
+	err := row.Scan(
+		&k.ID,
+		&k.Client,
+		&k.SecretHash,
+		&k.Roles,
+		&k.Methods,
+		&k.CreatedBy,
+		&k.CreatedAt,
+		&k.ExpiresAt,
+		&k.RevokedAt,
+		&k.RotatedFrom,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+// CreateAPIKey stores a new key and returns it as stored
+func (s *Store) CreateAPIKey(ctx context.Context, key APIKey) (*APIKey, error) {
+	query := `
+		INSERT INTO api_keys (id, client, secret_hash, roles, methods, created_by, expires_at, rotated_from)
+		VALUES ($1, $2, $3, COALESCE($4::text[], '{}'), COALESCE($5::text[], '{}'), $6, $7, NULLIF($8, ''))
+		RETURNING ` + apiKeyColumns
+
+	k, err := scanAPIKey(s.db.QueryRow(ctx, query,
+		key.ID, key.Client, key.SecretHash, key.Roles, key.Methods, key.CreatedBy, key.ExpiresAt, key.RotatedFrom,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+	return k, nil
+}
+
+// GetAPIKey returns the key with id
+func (s *Store) GetAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	k, err := scanAPIKey(s.db.QueryRow(ctx, `SELECT `+apiKeyColumns+` FROM api_keys WHERE id = $1`, id))
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get api key: %w", err)
+	}
+	return k, nil
+}
+
+// RotateAPIKey replaces the active key id with next, which inherits its
+// client, roles and methods. The old key stays valid for grace so clients
+// can switch over. It returns the old and the new key as stored.
+func (s *Store) RotateAPIKey(ctx context.Context, id string, next APIKey, grace time.Duration) (*APIKey, *APIKey, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	old, err := scanAPIKey(tx.QueryRow(ctx, `
+		UPDATE api_keys
+		SET expires_at = LEAST(COALESCE(expires_at, 'infinity'), NOW() + $2::interval)
+		WHERE id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING `+apiKeyColumns, id, grace))
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		if _, getErr := s.GetAPIKey(ctx, id); getErr != nil {
+			return nil, nil, getErr
+		}
+		return nil, nil, ErrAPIKeyInactive
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to expire api key: %w", err)
+	}
+
+	created, err := scanAPIKey(tx.QueryRow(ctx, `
+		INSERT INTO api_keys (id, client, secret_hash, roles, methods, created_by, expires_at, rotated_from)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+apiKeyColumns,
+		next.ID, old.Client, next.SecretHash, old.Roles, old.Methods, next.CreatedBy, next.ExpiresAt, old.ID,
+	))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create api key: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit api key rotation: %w", err)
+	}
+	return old, created, nil
+}
+
+// RevokeAPIKey revokes the active key id at once
+func (s *Store) RevokeAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	k, err := scanAPIKey(s.db.QueryRow(ctx, `
+		UPDATE api_keys SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING `+apiKeyColumns, id))
+	if errors.Is(err, ErrAPIKeyNotFound) {
+		if _, getErr := s.GetAPIKey(ctx, id); getErr != nil {
+			return nil, getErr
+		}
+		return nil, ErrAPIKeyInactive
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke api key: %w", err)
+	}
+	return k, nil
+}
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrApprovalNotFound is returned for an unknown approval ID
+	ErrApprovalNotFound = errors.New("revocation approval not found")
+
+	// ErrApprovalDecided is returned when approving or rejecting a request
+	// that is no longer pending
+	ErrApprovalDecided = errors.New("revocation approval is no longer pending")
+
+	// ErrSelfApproval is returned when the requester approves their own
+	// revocation
+	ErrSelfApproval = errors.New("a revocation must be approved by a different identity than its requester")
+)
+
+// Approval status values
+const (
+	ApprovalPending  = "pending"
+	ApprovalApproved = "approved"
+	ApprovalRejected = "rejected"
+)
+
+// Approval is a revocation awaiting or decided by two-person approval,
+// stored in revocation_approvals
+type Approval struct {
+	ID          int64
+	Entry       Entry
+	RequestedBy string
+	RequestedAt time.Time
+	Status      string
+	DecidedBy   string
+	DecidedAt   *time.Time
+	Note        string
+}
+
+// AddApprovals queues the revocations of approvals, with their requesters,
+// in one transaction and returns their IDs in order
+func (s *Store) AddApprovals(ctx context.Context, approvals []Approval) ([]int64, error) {
+	query := `
+		INSERT INTO revocation_approvals
+			(issuer_id, serial, revoked_at, reason, invalidity_date, certificate_issuer, effective_at, not_after, requested_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id
+	`
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, a := range approvals {
+		e := a.Entry
+		batch.Queue(query,
+			e.IssuerID, e.Serial, e.RevokedAt, e.Reason, e.InvalidityDate, e.CertificateIssuer, e.EffectiveAt, e.NotAfter,
+			a.RequestedBy,
+		)
+	}
+	results := tx.SendBatch(ctx, batch)
+	ids := make([]int64, len(approvals))
+	for i := range approvals {
+		if err := results.QueryRow().Scan(&ids[i]); err != nil {
+			results.Close()
+			return nil, fmt.Errorf("failed to queue revocation for approval: %w", err)
+		}
+	}
+	if err := results.Close(); err != nil {
+		return nil, fmt.Errorf("failed to queue revocations for approval: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit revocation approvals: %w", err)
+	}
+
+	return ids, nil
+}
+
+// GetApproval returns an approval request by ID
+func (s *Store) GetApproval(ctx context.Context, id int64) (*Approval, error) {
+	query := approvalSelect + ` WHERE id = $1`
+
+	approvals, err := s.queryApprovals(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(approvals) == 0 {
+		return nil, ErrApprovalNotFound
+	}
+	return &approvals[0], nil
+}
+
+// ListPendingApprovals returns up to limit pending requests of an issuer
+// requested after since, oldest first
+func (s *Store) ListPendingApprovals(ctx context.Context, issuerID string, since time.Time, limit int) ([]Approval, error) {
+	query := approvalSelect + `
+		WHERE issuer_id = $1 AND status = 'pending' AND requested_at > $2
+		ORDER BY requested_at
+		LIMIT $3
+	`
+
+	return s.queryApprovals(ctx, query, issuerID, since, limit)
+}
+
+// ApproveRevocation approves a pending request of another identity and
+// adds its revocation in the same transaction. Requests made before
+// requestedAfter have expired and are not approved.
+func (s *Store) ApproveRevocation(ctx context.Context, id int64, approvedBy string, requestedAfter time.Time) (*Entry, error) {
+	query := `
+		UPDATE revocation_approvals
+		SET status = 'approved', decided_by = $2, decided_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND requested_by <> $2 AND requested_at > $3
+		RETURNING issuer_id, serial, revoked_at, reason, invalidity_date, certificate_issuer, effective_at, not_after
+	`
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var e Entry
+	err = tx.QueryRow(ctx, query, id, approvedBy, requestedAfter).Scan(
+		&e.IssuerID,
+		&e.Serial,
+		&e.RevokedAt,
+		&e.Reason,
+		&e.InvalidityDate,
+		&e.CertificateIssuer,
+		&e.EffectiveAt,
+		&e.NotAfter,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrApprovalDecided
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to approve revocation: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, addEntryQuery, entryArgs(e)...); err != nil {
+		return nil, fmt.Errorf("failed to add revocation: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit revocation approval: %w", err)
+	}
+
+	return &e, nil
+}
+
+// RejectApproval rejects a pending request, recording who rejected it and
+// why
+func (s *Store) RejectApproval(ctx context.Context, id int64, rejectedBy, note string) error {
+	query := `
+		UPDATE revocation_approvals
+		SET status = 'rejected', decided_by = $2, decided_at = NOW(), decision_note = $3
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	tag, err := s.db.Exec(ctx, query, id, rejectedBy, note)
+	if err != nil {
+		return fmt.Errorf("failed to reject revocation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrApprovalDecided
+	}
+
+	return nil
+}
+
+const approvalSelect = `
+	SELECT id, issuer_id, serial, revoked_at, reason, invalidity_date, certificate_issuer, effective_at, not_after,
+		requested_by, requested_at, status, COALESCE(decided_by, ''), decided_at, decision_note
+	FROM revocation_approvals
+`
+
+func (s *Store) queryApprovals(ctx context.Context, query string, args ...interface{}) ([]Approval, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revocation approvals: %w", err)
+	}
+	defer rows.Close()
+
+	var approvals []Approval
+	for rows.Next() {
+		var a Approval
+		if err := rows.Scan(
+			&a.ID,
+			&a.Entry.IssuerID,
+			&a.Entry.Serial,
+			&a.Entry.RevokedAt,
+			&a.Entry.Reason,
+			&a.Entry.InvalidityDate,
+			&a.Entry.CertificateIssuer,
+			&a.Entry.EffectiveAt,
+			&a.Entry.NotAfter,
+			&a.RequestedBy,
+			&a.RequestedAt,
+			&a.Status,
+			&a.DecidedBy,
+			&a.DecidedAt,
+			&a.Note,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan revocation approval: %w", err)
+		}
+		a.Entry.Status = StatusRevoked
+		approvals = append(approvals, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read revocation approvals: %w", err)
+	}
+
+	return approvals, nil
+}
@@ -0,0 +1,66 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StatusArchived is the status of events recording an entry moved to
+// crl_entries_archive
+const StatusArchived = "archived"
+
+// archiveEntriesQuery moves up to $3 entries of issuer $1 whose certificate
+// expired before $2 to the archive
+const archiveEntriesQuery = `
+	WITH moved AS (
+		DELETE FROM crl_entries
+		WHERE (issuer_id, serial) IN (
+			SELECT issuer_id, serial FROM crl_entries
+			WHERE issuer_id = $1 AND not_after < $2
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING issuer_id, serial, serial_number, revoked_at, reason, status, invalidity_date,
+			certificate_issuer, updated_at, removed_by, removal_reason, removed_at, effective_at, not_after
+	)
+	INSERT INTO crl_entries_archive (issuer_id, serial, serial_number, revoked_at, reason, status, invalidity_date,
+		certificate_issuer, updated_at, removed_by, removal_reason, removed_at, effective_at, not_after)
+	SELECT * FROM moved
+`
+
+// ArchiveExpired moves up to limit entries of an issuer whose certificate
+// expired before expiredBefore from crl_entries to crl_entries_archive, and
+// returns how many it moved. Their events have status archived.
+func (s *Store) ArchiveExpired(ctx context.Context, issuerID string, expiredBefore time.Time, limit int) (int64, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT set_config('crl.archiving', 'on', true)`); err != nil {
+		return 0, fmt.Errorf("failed to archive entries: %w", err)
+	}
+	tag, err := tx.Exec(ctx, archiveEntriesQuery, issuerID, expiredBefore, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive entries: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit archived entries: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// PurgeArchive deletes the entries archived before archivedBefore and
+// returns how many it deleted
+func (s *Store) PurgeArchive(ctx context.Context, archivedBefore time.Time) (int64, error) {
+	tag, err := s.db.Exec(ctx, `DELETE FROM crl_entries_archive WHERE archived_at < $1`, archivedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge archived entries: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,113 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gigvault/crl/internal/serial"
+	"github.com/jackc/pgx/v5"
+)
+
+// AuditRecord is one state-changing API call, stored in audit_log
+type AuditRecord struct {
+	ID         int64
+	OccurredAt time.Time
+	Action     string
+	IssuerID   string
+	Serial     string
+	Actor      string
+	AuthMethod string
+	SourceIP   string
+	Code       string
+	Detail     string
+}
+
+// AuditFilter selects records for ListAuditRecords; empty fields match all
+type AuditFilter struct {
+	Action   string
+	Actor    string
+	IssuerID string
+	Serial   string
+	Since    *time.Time
+	Until    *time.Time
+}
+
+// InsertAuditRecords appends records to the audit log
+func (s *Store) InsertAuditRecords(ctx context.Context, records []AuditRecord) error {
+	query := `
+		INSERT INTO audit_log (action, issuer_id, serial, actor, auth_method, source_ip, code, detail)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	batch := &pgx.Batch{}
+	for _, r := range records {
+		batch.Queue(query, r.Action, r.IssuerID, r.Serial, r.Actor, r.AuthMethod, r.SourceIP, r.Code, r.Detail)
+	}
+	if err := s.db.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to write audit records: %w", err)
+	}
+	return nil
+}
+
+// ListAuditRecords returns up to limit records matching filter, newest
+// first, with IDs below beforeID when it is positive
+func (s *Store) ListAuditRecords(ctx context.Context, filter AuditFilter, beforeID int64, limit int) ([]AuditRecord, error) {
+	query := `
+		SELECT id, occurred_at, action, issuer_id, serial, actor, auth_method, source_ip, code, detail
+		FROM audit_log
+		WHERE ($1 = '' OR action = $1)
+			AND ($2 = '' OR actor = $2)
+			AND ($3 = '' OR issuer_id = $3)
+			AND ($4 = '' OR lower(ltrim(serial, '0')) = $4)
+			AND ($5::timestamptz IS NULL OR occurred_at >= $5)
+			AND ($6::timestamptz IS NULL OR occurred_at < $6)
+			AND ($7 <= 0 OR id < $7)
+		ORDER BY id DESC
+		LIMIT $8
+	`
+
+	serialHex := ""
+	if filter.Serial != "" {
+		canonical, err := serial.Normalize(filter.Serial)
+		if err != nil {
+			return nil, err
+		}
+		serialHex = canonical
+	}
+
+	rows, err := s.db.Query(ctx, query,
+		filter.Action, filter.Actor, filter.IssuerID, serialHex,
+		filter.Since, filter.Until, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	var records []AuditRecord
+	for rows.Next() {
+		var r AuditRecord
+		if err := rows.Scan(
+			&r.ID,
+			&r.OccurredAt,
+			&r.Action,
+			&r.IssuerID,
+			&r.Serial,
+			&r.Actor,
+			&r.AuthMethod,
+			&r.SourceIP,
+			&r.Code,
+			&r.Detail,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan audit record: %w", err)
+		}
+		records = append(records, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,265 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/shared/pkg/logger"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// ErrUnavailable is returned without calling the database while the
+// circuit breaker is open
+var ErrUnavailable = errors.New("database unavailable")
+
+const (
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 5 * time.Second
+)
+
+// BreakerConfig configures the circuit breaker around the database
+type BreakerConfig struct {
+	// Failures is how many database calls in a row must fail to reach the
+	// database to open the breaker (default 5)
+	Failures int `yaml:"failures"`
+
+	// Cooldown is how long an open breaker fails calls at once before it
+	// lets one through to probe the database (default 5s)
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+// Validate applies defaults and checks the circuit breaker config
+func (c *BreakerConfig) Validate() error {
+	if c.Failures < 0 {
+		return errors.New("failures must not be negative")
+	}
+	if c.Cooldown < 0 {
+		return errors.New("cooldown must not be negative")
+	}
+	if c.Failures == 0 {
+		c.Failures = defaultBreakerFailures
+	}
+	if c.Cooldown == 0 {
+		c.Cooldown = defaultBreakerCooldown
+	}
+	return nil
+}
+
+// breaker fails database calls fast after repeated connection failures,
+// instead of letting every request wait for a timeout
+type breaker struct {
+	cfg    BreakerConfig
+	reset  func()
+	logger *logger.Logger
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+}
+
+// allow reports whether a call may go to the database: always while the
+// breaker is closed, and one probe per cooldown while it is open
+func (b *breaker) allow() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.cfg.Failures {
+		return true
+	}
+	now := time.Now()
+	if now.Before(b.openUntil) {
+		return false
+	}
+	b.openUntil = now.Add(b.cfg.Cooldown)
+	return true
+}
+
+// done records the result of a call allow let through
+func (b *breaker) done(err error) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	wasOpen := b.failures >= b.cfg.Failures
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Says nothing about the database
+	case !unreachable(err):
+		b.failures = 0
+		if wasOpen {
+			metrics.DatabaseCircuitOpen.Set(0)
+			b.logger.Info("Database reachable again; circuit breaker closed")
+		}
+	default:
+		b.failures++
+		if b.failures < b.cfg.Failures {
+			return
+		}
+		b.openUntil = time.Now().Add(b.cfg.Cooldown)
+		if !wasOpen {
+			metrics.DatabaseCircuitOpen.Set(1)
+			b.logger.Error("Database calls failing; circuit breaker opened",
+				zap.Int("failures", b.failures),
+				zap.Duration("cooldown", b.cfg.Cooldown),
+				zap.Error(err),
+			)
+			// Connections to a restarted or failed-over server are dead;
+			// the probes dial new ones
+			b.reset()
+		}
+	}
+}
+
+// unreachable reports whether err means the database could not be reached
+// or could not serve the call, rather than that the call itself failed
+func unreachable(err error) bool {
+	if err == nil || errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Connection exception, insufficient resources, operator
+		// intervention (such as a shutdown) and system errors
+		switch pgErr.Code[:min(2, len(pgErr.Code))] {
+		case "08", "53", "57", "58":
+			return true
+		}
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		pgconn.Timeout(err) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// pool runs the store's database calls through the circuit breaker, if
+// any
+type pool struct {
+	*pgxpool.Pool
+	breaker *breaker
+}
+
+func (p *pool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if !p.breaker.allow() {
+		return pgconn.CommandTag{}, ErrUnavailable
+	}
+	tag, err := p.Pool.Exec(ctx, sql, args...)
+	p.breaker.done(err)
+	return tag, err
+}
+
+func (p *pool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if !p.breaker.allow() {
+		return nil, ErrUnavailable
+	}
+	rows, err := p.Pool.Query(ctx, sql, args...)
+	p.breaker.done(err)
+	return rows, err
+}
+
+func (p *pool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	if !p.breaker.allow() {
+		return errRow{}
+	}
+	return &breakerRow{row: p.Pool.QueryRow(ctx, sql, args...), breaker: p.breaker}
+}
+
+func (p *pool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	if !p.breaker.allow() {
+		return errBatch{}
+	}
+	results := p.Pool.SendBatch(ctx, b)
+	// Failures surface per query; the batch counts as it is closed
+	return &breakerBatch{BatchResults: results, breaker: p.breaker}
+}
+
+func (p *pool) Begin(ctx context.Context) (pgx.Tx, error) {
+	if !p.breaker.allow() {
+		return nil, ErrUnavailable
+	}
+	tx, err := p.Pool.Begin(ctx)
+	p.breaker.done(err)
+	return tx, err
+}
+
+func (p *pool) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	if !p.breaker.allow() {
+		return nil, ErrUnavailable
+	}
+	tx, err := p.Pool.BeginTx(ctx, opts)
+	p.breaker.done(err)
+	return tx, err
+}
+
+func (p *pool) Acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	if !p.breaker.allow() {
+		return nil, ErrUnavailable
+	}
+	conn, err := p.Pool.Acquire(ctx)
+	p.breaker.done(err)
+	return conn, err
+}
+
+func (p *pool) Ping(ctx context.Context) error {
+	if !p.breaker.allow() {
+		return ErrUnavailable
+	}
+	err := p.Pool.Ping(ctx)
+	p.breaker.done(err)
+	return err
+}
+
+// breakerRow records the outcome of a QueryRow when it is scanned
+type breakerRow struct {
+	row     pgx.Row
+	breaker *breaker
+}
+
+func (r *breakerRow) Scan(dest ...any) error {
+	err := r.row.Scan(dest...)
+	r.breaker.done(err)
+	return err
+}
+
+type errRow struct{}
+
+func (errRow) Scan(...any) error { return ErrUnavailable }
+
+// breakerBatch records the outcome of a batch when it is closed
+type breakerBatch struct {
+	pgx.BatchResults
+	breaker *breaker
+}
+
+func (b *breakerBatch) Close() error {
+	err := b.BatchResults.Close()
+	b.breaker.done(err)
+	return err
+}
+
+type errBatch struct{}
+
+func (errBatch) Exec() (pgconn.CommandTag, error) { return pgconn.CommandTag{}, ErrUnavailable }
+func (errBatch) Query() (pgx.Rows, error)         { return nil, ErrUnavailable }
+func (errBatch) QueryRow() pgx.Row                { return errRow{} }
+func (errBatch) Close() error                     { return ErrUnavailable }
+
+// UseBreaker puts the circuit breaker in front of the store's database
+// calls. Call it before the store is used.
+func (s *Store) UseBreaker(cfg BreakerConfig) {
+	s.db.breaker = &breaker{cfg: cfg, reset: s.db.Pool.Reset, logger: logger.Global()}
+}
@@ -0,0 +1,62 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrCursorBusy is returned by AdvanceExportCursor while another replica
+// holds the export's cursor
+var ErrCursorBusy = errors.New("export is running on another replica")
+
+// CreateExportCursor creates the cursor of the export name at sequence,
+// unless it exists
+func (s *Store) CreateExportCursor(ctx context.Context, name string, sequence int64) error {
+	_, err := s.db.Exec(ctx, `
+		INSERT INTO export_cursors (name, sequence) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING
+	`, name, sequence)
+	if err != nil {
+		return fmt.Errorf("failed to create export cursor: %w", err)
+	}
+	return nil
+}
+
+// AdvanceExportCursor locks the cursor of the export name, creating it at
+// sequence 0, and calls fn with the sequence of the last event exported.
+// The cursor is moved to the sequence fn returns, even when fn also
+// returns an error, so work it finished is not repeated. The lock is held
+// until fn returns; ErrCursorBusy is returned without calling fn if
+// another replica holds it.
+func (s *Store) AdvanceExportCursor(ctx context.Context, name string, fn func(sequence int64) (int64, error)) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `INSERT INTO export_cursors (name) VALUES ($1) ON CONFLICT (name) DO NOTHING`, name); err != nil {
+		return fmt.Errorf("failed to create export cursor: %w", err)
+	}
+	var sequence int64
+	err = tx.QueryRow(ctx, `SELECT sequence FROM export_cursors WHERE name = $1 FOR UPDATE SKIP LOCKED`, name).Scan(&sequence)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrCursorBusy
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock export cursor: %w", err)
+	}
+
+	next, fnErr := fn(sequence)
+	if next != sequence {
+		if _, err := tx.Exec(ctx, `UPDATE export_cursors SET sequence = $2, updated_at = NOW() WHERE name = $1`, name, next); err != nil {
+			return fmt.Errorf("failed to advance export cursor: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit export cursor: %w", err)
+	}
+	return fnErr
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gigvault/crl/internal/serial"
+)
+
+// StatusDeleted is the status of events recording a removed entry
+const StatusDeleted = "deleted"
+
+// Event is a change to crl_entries, recorded in revocation_events
+type Event struct {
+	Sequence          int64
+	IssuerID          string
+	Serial            string
+	Status            string
+	Reason            string
+	RevokedAt         time.Time
+	InvalidityDate    *time.Time
+	CertificateIssuer []byte
+	CreatedAt         time.Time
+	EffectiveAt       *time.Time
+}
+
+// ListEvents returns up to limit events of all issuers after sequence,
+// oldest first
+func (s *Store) ListEvents(ctx context.Context, after int64, limit int) ([]Event, error) {
+	query := `
+		SELECT sequence, issuer_id, serial, status, reason, revoked_at, invalidity_date, certificate_issuer, created_at, effective_at
+		FROM revocation_events
+		WHERE sequence > $1
+		ORDER BY sequence
+		LIMIT $2
+	`
+
+	return s.queryEvents(ctx, query, after, limit)
+}
+
+func (s *Store) queryEvents(ctx context.Context, query string, args ...interface{}) ([]Event, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revocation events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var e Event
+		if err := rows.Scan(
+			&e.Sequence,
+			&e.IssuerID,
+			&e.Serial,
+			&e.Status,
+			&e.Reason,
+			&e.RevokedAt,
+			&e.InvalidityDate,
+			&e.CertificateIssuer,
+			&e.CreatedAt,
+			&e.EffectiveAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan revocation event: %w", err)
+		}
+		events = append(events, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read revocation events: %w", err)
+	}
+
+	return events, nil
+}
+
+// ListSerialEvents returns the changes to one serial of an issuer, oldest
+// first
+func (s *Store) ListSerialEvents(ctx context.Context, issuerID, serialHex string) ([]Event, error) {
+	canonical, err := serial.Normalize(serialHex)
+	if err != nil {
+		return nil, err
+	}
+	query := `
+		SELECT sequence, issuer_id, serial, status, reason, revoked_at, invalidity_date, certificate_issuer, created_at, effective_at
+		FROM revocation_events
+		WHERE issuer_id = $1 AND lower(ltrim(serial, '0')) = $2
+		ORDER BY sequence
+	`
+
+	return s.queryEvents(ctx, query, issuerID, canonical)
+}
+
+// EventSequenceBefore returns the sequence of the newest event created
+// before t, 0 if there is none
+func (s *Store) EventSequenceBefore(ctx context.Context, t time.Time) (int64, error) {
+	var seq int64
+	err := s.db.QueryRow(ctx, `
+		SELECT COALESCE(MAX(sequence), 0) FROM revocation_events WHERE created_at < $1
+	`, t).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read revocation event sequence: %w", err)
+	}
+	return seq, nil
+}
+
+// LatestEventSequence returns the sequence of the newest event, 0 if
+// there is none
+func (s *Store) LatestEventSequence(ctx context.Context) (int64, error) {
+	var seq int64
+	err := s.db.QueryRow(ctx, `SELECT COALESCE(MAX(sequence), 0) FROM revocation_events`).Scan(&seq)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read revocation event sequence: %w", err)
+	}
+	return seq, nil
+}
@@ -0,0 +1,124 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrCRLNotFound is returned when no CRL with a number or digest was
+	// recorded
+	ErrCRLNotFound = errors.New("no such CRL was recorded")
+
+	// ErrCRLCorrupt is returned when a stored CRL no longer matches its
+	// SHA-256 digest
+	ErrCRLCorrupt = errors.New("stored CRL does not match its digest")
+)
+
+// CRLRecord is a signed CRL as kept in crl_history and crl_artifacts
+type CRLRecord struct {
+	IssuerID     string
+	Number       int64
+	Partition    int
+	BaseNumber   *int64 // nil for full CRLs
+	ThisUpdate   time.Time
+	NextUpdate   time.Time
+	RevokedCount int
+	DER          []byte
+	CreatedAt    time.Time
+
+	// SHA256 is the digest of DER, the artifact's key
+	SHA256 []byte
+
+	// SignerKeyID is the subject key identifier of the issuer certificate
+	// that signed the CRL
+	SignerKeyID []byte
+}
+
+// RecordCRL stores a signed CRL in the history. The DER is stored once per
+// SHA-256 digest; r.SHA256 is computed when unset.
+func (s *Store) RecordCRL(ctx context.Context, r CRLRecord) error {
+	if r.SHA256 == nil {
+		sum := sha256.Sum256(r.DER)
+		r.SHA256 = sum[:]
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO crl_artifacts (sha256, crl_der)
+		VALUES ($1, $2)
+		ON CONFLICT (sha256) DO NOTHING
+	`, r.SHA256, r.DER)
+	if err != nil {
+		return fmt.Errorf("failed to store CRL artifact: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO crl_history
+			(issuer_id, crl_number, partition, base_crl_number, this_update, next_update, revoked_count, sha256, signer_key_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (issuer_id, crl_number) DO NOTHING
+	`,
+		r.IssuerID, r.Number, r.Partition, r.BaseNumber,
+		r.ThisUpdate, r.NextUpdate, r.RevokedCount, r.SHA256, r.SignerKeyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record CRL: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit CRL record: %w", err)
+	}
+	return nil
+}
+
+const crlRecordSelect = `
+	SELECT h.issuer_id, h.crl_number, h.partition, h.base_crl_number, h.this_update, h.next_update,
+		h.revoked_count, a.crl_der, h.created_at, h.sha256, h.signer_key_id
+	FROM crl_history h
+	JOIN crl_artifacts a ON a.sha256 = h.sha256
+`
+
+// GetCRL returns the recorded CRL of an issuer with a number, or
+// ErrCRLNotFound
+func (s *Store) GetCRL(ctx context.Context, issuerID string, number int64) (*CRLRecord, error) {
+	return s.getCRL(ctx, crlRecordSelect+`WHERE h.issuer_id = $1 AND h.crl_number = $2`, issuerID, number)
+}
+
+// GetCRLByDigest returns the recorded CRL of an issuer whose DER has a
+// SHA-256 digest, or ErrCRLNotFound
+func (s *Store) GetCRLByDigest(ctx context.Context, issuerID string, digest []byte) (*CRLRecord, error) {
+	return s.getCRL(ctx, crlRecordSelect+`WHERE h.issuer_id = $1 AND h.sha256 = $2 LIMIT 1`, issuerID, digest)
+}
+
+// getCRL reads one CRL record and checks the artifact against its digest
+func (s *Store) getCRL(ctx context.Context, query string, args ...interface{}) (*CRLRecord, error) {
+	var r CRLRecord
+	err := s.db.QueryRow(ctx, query, args...).Scan(
+		&r.IssuerID, &r.Number, &r.Partition, &r.BaseNumber, &r.ThisUpdate, &r.NextUpdate,
+		&r.RevokedCount, &r.DER, &r.CreatedAt, &r.SHA256, &r.SignerKeyID,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCRLNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get CRL: %w", err)
+	}
+
+	sum := sha256.Sum256(r.DER)
+	if !bytes.Equal(sum[:], r.SHA256) {
+		return nil, fmt.Errorf("%w: CRL %d of issuer %s", ErrCRLCorrupt, r.Number, r.IssuerID)
+	}
+	return &r, nil
+}
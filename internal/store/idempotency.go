@@ -0,0 +1,81 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrIdempotencyConflict is returned when a key is reused for a
+	// different request
+	ErrIdempotencyConflict = errors.New("idempotency key was used for a different request")
+
+	// ErrIdempotencyInProgress is returned when the first request with a
+	// key has not completed yet
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is in progress")
+)
+
+// ClaimIdempotencyKey claims key for a request with the given hash. It
+// returns the stored response when the request already completed, and nil
+// when the caller claimed the key and must complete or release it. Keys
+// older than ttl are claimed anew.
+func (s *Store) ClaimIdempotencyKey(ctx context.Context, key string, requestHash []byte, ttl time.Duration) ([]byte, error) {
+	if _, err := s.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE created_at < NOW() - $1::interval`, ttl); err != nil {
+		return nil, fmt.Errorf("failed to prune idempotency keys: %w", err)
+	}
+
+	tag, err := s.db.Exec(ctx, `
+		INSERT INTO idempotency_keys (key, request_hash)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO NOTHING
+	`, key, requestHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim idempotency key: %w", err)
+	}
+	if tag.RowsAffected() == 1 {
+		return nil, nil
+	}
+
+	var storedHash, response []byte
+	err = s.db.QueryRow(ctx, `SELECT request_hash, response FROM idempotency_keys WHERE key = $1`, key).
+		Scan(&storedHash, &response)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Released since the insert; let the client retry
+		return nil, ErrIdempotencyInProgress
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read idempotency key: %w", err)
+	}
+
+	switch {
+	case !bytes.Equal(storedHash, requestHash):
+		return nil, ErrIdempotencyConflict
+	case response == nil:
+		return nil, ErrIdempotencyInProgress
+	}
+	return response, nil
+}
+
+// CompleteIdempotencyKey stores the response of a claimed key
+func (s *Store) CompleteIdempotencyKey(ctx context.Context, key string, response []byte) error {
+	_, err := s.db.Exec(ctx, `UPDATE idempotency_keys SET response = $2 WHERE key = $1`, key, response)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey gives up a claimed key after its request failed,
+// so a retry runs the request again
+func (s *Store) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	_, err := s.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE key = $1 AND response IS NULL`, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchedulerLock is the advisory lock key held by the replica running the
+// scheduled publications
+const SchedulerLock = 0x63726c73 // "crls"
+
+// generationLockClass is the upper half of the advisory lock keys that
+// serialize the CRL generation of each issuer
+const generationLockClass = 0x63726c67 // "crlg"
+
+// Lock is a session advisory lock, held as long as its connection lives
+type Lock struct {
+	key  int64
+	conn *pgxpool.Conn
+}
+
+// TryLock takes the advisory lock key on a connection of its own. It
+// returns nil without an error if another session holds the lock.
+func (s *Store) TryLock(ctx context.Context, key int64) (*Lock, error) {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	var locked bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, key).Scan(&locked); err != nil {
+		discard(ctx, conn)
+		return nil, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !locked {
+		conn.Release()
+		return nil, nil
+	}
+	return &Lock{key: key, conn: conn}, nil
+}
+
+// LockIssuer waits for the advisory lock serializing the CRL generation of
+// an issuer and takes it on a connection of its own
+func (s *Store) LockIssuer(ctx context.Context, issuerID string) (*Lock, error) {
+	h := fnv.New32a()
+	h.Write([]byte(issuerID))
+	key := int64(generationLockClass)<<32 | int64(h.Sum32())
+
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, key); err != nil {
+		discard(ctx, conn)
+		return nil, fmt.Errorf("failed to take generation lock: %w", err)
+	}
+	return &Lock{key: key, conn: conn}, nil
+}
+
+// Check reports an error if the lock's session has ended, which released
+// the lock
+func (l *Lock) Check(ctx context.Context) error {
+	if _, err := l.conn.Exec(ctx, `SELECT 1`); err != nil {
+		return fmt.Errorf("lost advisory lock: %w", err)
+	}
+	return nil
+}
+
+// Release unlocks and returns the connection to the pool. A connection
+// that fails to unlock is closed, which ends the session and its lock.
+func (l *Lock) Release(ctx context.Context) {
+	if _, err := l.conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, l.key); err != nil {
+		discard(ctx, l.conn)
+		return
+	}
+	l.conn.Release()
+}
+
+// discard closes a connection that may hold a lock, e.g. when the call
+// taking it was cancelled after the server granted it, and releases it to
+// the pool, which drops closed connections
+func discard(ctx context.Context, conn *pgxpool.Conn) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	conn.Conn().Close(ctx)
+	conn.Release()
+}
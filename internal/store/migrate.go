@@ -0,0 +1,223 @@
+package store
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// migrationFiles are the schema migrations, named <version>_<name>.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLock is the advisory lock key that serializes migrations run by
+// several replicas at once
+const migrationLock = 0x63726c6d // "crlm"
+
+// createSchemaMigrations matches 017_schema_migrations.sql, so versions
+// can be recorded from the first migration on
+const createSchemaMigrations = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)
+`
+
+// ErrUnversionedSchema is returned when migrating a database whose schema
+// was applied by hand without recording it in schema_migrations
+var ErrUnversionedSchema = errors.New("database has a schema but no schema_migrations table; baseline it with the migrate -baseline flag")
+
+// noTransaction is the first line of migrations that cannot run in a
+// transaction, such as CREATE INDEX CONCURRENTLY. Their statements end
+// with a semicolon at the end of a line and run one by one.
+const noTransaction = "-- crl:no-transaction"
+
+// Migration is one embedded schema migration
+type Migration struct {
+	Version int
+	Name    string
+	sql     string
+}
+
+// statements splits a no-transaction migration into its statements
+func (m Migration) statements() []string {
+	var stmts []string
+	var stmt strings.Builder
+	for line := range strings.Lines(m.sql) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		stmt.WriteString(line)
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSpace(stmt.String()))
+			stmt.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(stmt.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
+
+// Migrations returns the embedded migrations in version order
+func Migrations() ([]Migration, error) {
+	names, err := fs.Glob(migrationFiles, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for _, name := range names {
+		base := path.Base(name)
+		prefix, _, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("migration %s is not named <version>_<name>.sql", base)
+		}
+		sql, err := migrationFiles.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{Version: version, Name: base, sql: string(sql)})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// SchemaVersion returns the latest embedded migration, the schema version
+// this code expects
+func SchemaVersion() int {
+	migrations, err := Migrations()
+	if err != nil || len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// Migrate applies the embedded migrations missing from the database in
+// version order, each in its own transaction, and returns the ones it
+// applied. An advisory lock keeps replicas from migrating concurrently.
+func (s *Store) Migrate(ctx context.Context) ([]Migration, error) {
+	migrations, err := Migrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLock); err != nil {
+		return nil, fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, migrationLock)
+
+	var versioned, populated bool
+	err = conn.QueryRow(ctx, `
+		SELECT to_regclass('schema_migrations') IS NOT NULL, to_regclass('crl_entries') IS NOT NULL
+	`).Scan(&versioned, &populated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect schema: %w", err)
+	}
+	if !versioned && populated {
+		return nil, ErrUnversionedSchema
+	}
+	if _, err := conn.Exec(ctx, createSchemaMigrations); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	rows, err := conn.Query(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	applied := make(map[int]bool, len(versions))
+	for _, v := range versions {
+		applied[v] = true
+	}
+
+	var ran []Migration
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		if err := applyMigration(ctx, conn.Conn(), m); err != nil {
+			return ran, err
+		}
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// applyMigration runs one migration and records it in schema_migrations
+func applyMigration(ctx context.Context, conn *pgx.Conn, m Migration) error {
+	if strings.HasPrefix(m.sql, noTransaction) {
+		return applyMigrationWithoutTransaction(ctx, conn, m)
+	}
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Without arguments the file runs as one multi-statement query
+	if _, err := tx.Exec(ctx, m.sql); err != nil {
+		return fmt.Errorf("failed to apply migration %s: %w", m.Name, err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO schema_migrations (version) VALUES ($1) ON CONFLICT (version) DO NOTHING
+	`, m.Version); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", m.Name, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", m.Name, err)
+	}
+	return nil
+}
+
+// applyMigrationWithoutTransaction runs the statements of a no-transaction
+// migration one at a time and then records it. Such a migration must be
+// safe to run again after failing halfway.
+func applyMigrationWithoutTransaction(ctx context.Context, conn *pgx.Conn, m Migration) error {
+	for _, stmt := range m.statements() {
+		if _, err := conn.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", m.Name, err)
+		}
+	}
+	if _, err := conn.Exec(ctx, `
+		INSERT INTO schema_migrations (version) VALUES ($1) ON CONFLICT (version) DO NOTHING
+	`, m.Version); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", m.Name, err)
+	}
+	return nil
+}
+
+// BaselineSchema records the migrations up to version as applied without
+// running them, for databases whose schema was applied by hand
+func (s *Store) BaselineSchema(ctx context.Context, version int) error {
+	if _, err := s.db.Exec(ctx, createSchemaMigrations); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	if _, err := s.db.Exec(ctx, `
+		INSERT INTO schema_migrations (version)
+		SELECT generate_series(1, $1::int)
+		ON CONFLICT (version) DO NOTHING
+	`, version); err != nil {
+		return fmt.Errorf("failed to record baseline: %w", err)
+	}
+	return nil
+}
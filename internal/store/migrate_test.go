@@ -0,0 +1,59 @@
+package store
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNoTransactionMigrationStatements(t *testing.T) {
+	migrations, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, m := range migrations {
+		if !strings.HasPrefix(m.sql, noTransaction) {
+			continue
+		}
+		found = true
+		stmts := m.statements()
+		if len(stmts) == 0 {
+			t.Errorf("%s has no statements", m.Name)
+		}
+		for _, stmt := range stmts {
+			if !strings.HasSuffix(stmt, ";") || strings.Count(stmt, ";") != 1 {
+				t.Errorf("%s: statement %q is not one statement ending in a semicolon", m.Name, stmt)
+			}
+			if strings.HasPrefix(stmt, "--") {
+				t.Errorf("%s: statement %q starts with a comment", m.Name, stmt)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("no no-transaction migration embedded")
+	}
+}
+
+func TestMigrationStatements(t *testing.T) {
+	m := Migration{sql: noTransaction + `
+-- comment; with a semicolon
+CREATE INDEX CONCURRENTLY idx ON t(a)
+    WHERE b;
+
+INSERT INTO schema_migrations (version) VALUES (1);
+SELECT 1`}
+	want := []string{
+		"CREATE INDEX CONCURRENTLY idx ON t(a)\n    WHERE b;",
+		"INSERT INTO schema_migrations (version) VALUES (1);",
+		"SELECT 1",
+	}
+	got := m.statements()
+	if len(got) != len(want) {
+		t.Fatalf("got %d statements %q, want %d", len(got), got, len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("statement %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,20 @@
+-- Migration: Create CRL tables
+-- crl_entries holds one row per revoked certificate, crl_metadata tracks
+-- the state of the most recently published CRL.
+
+CREATE TABLE IF NOT EXISTS crl_entries (
+    serial VARCHAR(128) PRIMARY KEY,
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reason VARCHAR(64) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_crl_entries_revoked_at ON crl_entries(revoked_at DESC);
+
+CREATE TABLE IF NOT EXISTS crl_metadata (
+    id INTEGER PRIMARY KEY,
+    last_published TIMESTAMPTZ,
+    next_update TIMESTAMPTZ
+);
+
+COMMENT ON TABLE crl_entries IS 'Revoked certificates included in generated CRLs';
+COMMENT ON TABLE crl_metadata IS 'Publication state of the CRL (single row, id = 1)';
@@ -0,0 +1,7 @@
+-- Migration: Persist the CRLNumber extension
+-- RFC 5280 section 5.2.3 requires CRL numbers to increase monotonically for
+-- a given issuer, across restarts and concurrent publishers.
+
+ALTER TABLE crl_metadata ADD COLUMN IF NOT EXISTS crl_number BIGINT NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN crl_metadata.crl_number IS 'Number of the most recently generated CRL';
@@ -0,0 +1,13 @@
+-- Migration: Delta CRL support
+-- updated_at tracks when an entry last changed so delta CRLs can select
+-- everything modified since the base CRL was generated.
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_crl_entries_updated_at ON crl_entries(updated_at);
+
+ALTER TABLE crl_metadata ADD COLUMN IF NOT EXISTS base_crl_number BIGINT;
+ALTER TABLE crl_metadata ADD COLUMN IF NOT EXISTS base_generated_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN crl_metadata.base_crl_number IS 'CRL number of the last published full CRL (delta base)';
+COMMENT ON COLUMN crl_metadata.base_generated_at IS 'Entry snapshot time of the last published full CRL';
@@ -0,0 +1,13 @@
+-- Migration: certificateHold lifecycle
+-- status tracks whether an entry is permanently revoked, on hold, or a
+-- released hold that must be reported as removeFromCRL in delta CRLs.
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'revoked';
+
+ALTER TABLE crl_entries DROP CONSTRAINT IF EXISTS crl_entries_status_check;
+ALTER TABLE crl_entries ADD CONSTRAINT crl_entries_status_check
+    CHECK (status IN ('revoked', 'on_hold', 'released'));
+
+CREATE INDEX IF NOT EXISTS idx_crl_entries_status ON crl_entries(status);
+
+COMMENT ON COLUMN crl_entries.status IS 'revoked, on_hold, or released (hold lifted; excluded from full CRLs)';
@@ -0,0 +1,7 @@
+-- Migration: Invalidity date on revocation entries
+-- The date on which the key is known or suspected to have been compromised
+-- (RFC 5280 section 5.3.2), emitted as the InvalidityDate entry extension.
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS invalidity_date TIMESTAMPTZ;
+
+COMMENT ON COLUMN crl_entries.invalidity_date IS 'When the key was believed compromised (optional)';
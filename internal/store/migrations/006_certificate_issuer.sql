@@ -0,0 +1,7 @@
+-- Migration: Indirect CRL support
+-- certificate_issuer holds the DER-encoded issuer Name of the revoked
+-- certificate when it differs from the CRL issuer (NULL otherwise).
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS certificate_issuer BYTEA;
+
+COMMENT ON COLUMN crl_entries.certificate_issuer IS 'DER issuer Name for indirect CRL entries; NULL means the CRL issuer';
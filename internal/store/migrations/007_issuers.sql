@@ -0,0 +1,30 @@
+-- Migration: Multiple issuers
+-- issuers lists the CAs this deployment signs CRLs for. Entries and CRL
+-- metadata are keyed by issuer, so every issuer has its own serial space,
+-- CRL number sequence and delta base. Existing rows move to 'default'.
+
+CREATE TABLE IF NOT EXISTS issuers (
+    id VARCHAR(64) PRIMARY KEY,
+    subject TEXT NOT NULL DEFAULT '',
+    raw_subject BYTEA,
+    subject_key_id BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO issuers (id) VALUES ('default') ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS issuer_id VARCHAR(64) NOT NULL DEFAULT 'default'
+    REFERENCES issuers(id);
+ALTER TABLE crl_entries DROP CONSTRAINT IF EXISTS crl_entries_pkey;
+ALTER TABLE crl_entries ADD PRIMARY KEY (issuer_id, serial);
+
+ALTER TABLE crl_metadata ADD COLUMN IF NOT EXISTS issuer_id VARCHAR(64) NOT NULL DEFAULT 'default'
+    REFERENCES issuers(id);
+ALTER TABLE crl_metadata DROP CONSTRAINT IF EXISTS crl_metadata_pkey;
+ALTER TABLE crl_metadata DROP COLUMN IF EXISTS id;
+ALTER TABLE crl_metadata ADD PRIMARY KEY (issuer_id);
+
+COMMENT ON TABLE issuers IS 'CAs whose CRLs are generated by this service';
+COMMENT ON COLUMN crl_entries.issuer_id IS 'Issuer whose CRL lists the entry';
+COMMENT ON TABLE crl_metadata IS 'Publication state of each issuer''s CRL (one row per issuer)';
@@ -0,0 +1,21 @@
+-- Migration: Publish retry queue
+-- publish_retries holds CRL uploads that failed for one distribution target.
+-- Only the newest CRL matters, so there is one row per issuer, publisher and
+-- partition; a later failure replaces it and a later success removes it.
+
+CREATE TABLE IF NOT EXISTS publish_retries (
+    issuer_id VARCHAR(64) NOT NULL REFERENCES issuers(id),
+    publisher VARCHAR(255) NOT NULL,
+    partition INTEGER NOT NULL DEFAULT 0,
+    crl_number BIGINT NOT NULL,
+    crl_der BYTEA NOT NULL,
+    this_update TIMESTAMPTZ NOT NULL,
+    next_update TIMESTAMPTZ NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issuer_id, publisher, partition)
+);
+
+CREATE INDEX IF NOT EXISTS idx_publish_retries_next_attempt ON publish_retries(next_attempt_at);
@@ -0,0 +1,36 @@
+-- Migration: Revocation events
+-- revocation_events is an append-only log of every change to crl_entries,
+-- written by a trigger so no write path can bypass it. The sequence lets
+-- WatchRevocations subscribers resume where they left off.
+
+CREATE TABLE IF NOT EXISTS revocation_events (
+    sequence BIGSERIAL PRIMARY KEY,
+    issuer_id VARCHAR(64) NOT NULL,
+    serial VARCHAR(128) NOT NULL,
+    status VARCHAR(20) NOT NULL, -- entry status after the change, or 'deleted'
+    reason VARCHAR(64) NOT NULL DEFAULT '',
+    revoked_at TIMESTAMPTZ NOT NULL,
+    invalidity_date TIMESTAMPTZ,
+    certificate_issuer BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revocation_events_issuer ON revocation_events(issuer_id, sequence);
+
+CREATE OR REPLACE FUNCTION record_revocation_event() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO revocation_events (issuer_id, serial, status, reason, revoked_at, invalidity_date, certificate_issuer)
+        VALUES (OLD.issuer_id, OLD.serial, 'deleted', OLD.reason, OLD.revoked_at, OLD.invalidity_date, OLD.certificate_issuer);
+        RETURN OLD;
+    END IF;
+    INSERT INTO revocation_events (issuer_id, serial, status, reason, revoked_at, invalidity_date, certificate_issuer)
+    VALUES (NEW.issuer_id, NEW.serial, NEW.status, NEW.reason, NEW.revoked_at, NEW.invalidity_date, NEW.certificate_issuer);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS crl_entries_events ON crl_entries;
+CREATE TRIGGER crl_entries_events
+    AFTER INSERT OR UPDATE OR DELETE ON crl_entries
+    FOR EACH ROW EXECUTE FUNCTION record_revocation_event();
@@ -0,0 +1,15 @@
+-- Migration: Revocation removal
+-- Erroneous revocations are not deleted: the entry keeps its row with status
+-- 'removed' so delta CRLs can report removeFromCRL, and records who removed
+-- it and why.
+
+ALTER TABLE crl_entries DROP CONSTRAINT IF EXISTS crl_entries_status_check;
+ALTER TABLE crl_entries ADD CONSTRAINT crl_entries_status_check
+    CHECK (status IN ('revoked', 'on_hold', 'released', 'removed'));
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS removed_by VARCHAR(255);
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS removal_reason TEXT;
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS removed_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN crl_entries.status IS 'revoked, on_hold, released (hold lifted) or removed (erroneous revocation); released and removed entries are excluded from full CRLs';
+COMMENT ON COLUMN crl_entries.removed_by IS 'Identity that removed the revocation';
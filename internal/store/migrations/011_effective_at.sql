@@ -0,0 +1,26 @@
+-- Migration: Future-dated revocations
+-- effective_at schedules a revocation for a cutover moment: the entry only
+-- appears in CRLs and OCSP responses generated at or after that time. NULL
+-- means effective immediately.
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS effective_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_crl_entries_effective_at ON crl_entries(issuer_id, effective_at)
+    WHERE effective_at IS NOT NULL;
+
+COMMENT ON COLUMN crl_entries.effective_at IS 'When a scheduled revocation takes effect; NULL for immediate revocations';
+
+ALTER TABLE revocation_events ADD COLUMN IF NOT EXISTS effective_at TIMESTAMPTZ;
+
+CREATE OR REPLACE FUNCTION record_revocation_event() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO revocation_events (issuer_id, serial, status, reason, revoked_at, invalidity_date, certificate_issuer, effective_at)
+        VALUES (OLD.issuer_id, OLD.serial, 'deleted', OLD.reason, OLD.revoked_at, OLD.invalidity_date, OLD.certificate_issuer, OLD.effective_at);
+        RETURN OLD;
+    END IF;
+    INSERT INTO revocation_events (issuer_id, serial, status, reason, revoked_at, invalidity_date, certificate_issuer, effective_at)
+    VALUES (NEW.issuer_id, NEW.serial, NEW.status, NEW.reason, NEW.revoked_at, NEW.invalidity_date, NEW.certificate_issuer, NEW.effective_at);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
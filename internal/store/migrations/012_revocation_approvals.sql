@@ -0,0 +1,28 @@
+-- Migration: Two-person revocation approval
+-- revocation_approvals holds revocations waiting for a second identity to
+-- approve them. An approved request is copied into crl_entries in the same
+-- transaction; decided rows are kept as a record of who approved or
+-- rejected what.
+
+CREATE TABLE IF NOT EXISTS revocation_approvals (
+    id BIGSERIAL PRIMARY KEY,
+    issuer_id VARCHAR(64) NOT NULL REFERENCES issuers(id),
+    serial VARCHAR(128) NOT NULL,
+    revoked_at TIMESTAMPTZ NOT NULL,
+    reason VARCHAR(64) NOT NULL DEFAULT '',
+    invalidity_date TIMESTAMPTZ,
+    certificate_issuer BYTEA,
+    effective_at TIMESTAMPTZ,
+    requested_by VARCHAR(255) NOT NULL,
+    requested_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected')),
+    decided_by VARCHAR(255),
+    decided_at TIMESTAMPTZ,
+    decision_note TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_revocation_approvals_pending ON revocation_approvals(issuer_id, requested_at)
+    WHERE status = 'pending';
+
+COMMENT ON TABLE revocation_approvals IS 'Revocations awaiting or decided by two-person approval';
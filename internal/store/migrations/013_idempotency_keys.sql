@@ -0,0 +1,14 @@
+-- Migration: Idempotency keys
+-- idempotency_keys remembers the response to each keyed AddRevocation and
+-- AddRevocations request so retries return it instead of writing again. A
+-- NULL response marks a request still in progress. Rows older than the
+-- retention period are replaced or pruned.
+
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    key VARCHAR(255) PRIMARY KEY,
+    request_hash BYTEA NOT NULL,
+    response BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_created_at ON idempotency_keys(created_at);
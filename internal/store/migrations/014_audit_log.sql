@@ -0,0 +1,22 @@
+-- Migration: Audit log
+-- audit_log records every state-changing RPC: who made it (authenticated
+-- identity and how it was established), from where, what it targeted and
+-- how it ended. Rows are only ever inserted.
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    occurred_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    action VARCHAR(64) NOT NULL, -- RPC name, e.g. AddRevocation
+    issuer_id VARCHAR(64) NOT NULL DEFAULT '',
+    serial VARCHAR(128) NOT NULL DEFAULT '',
+    actor VARCHAR(255) NOT NULL DEFAULT '', -- empty for unauthenticated callers
+    auth_method VARCHAR(16) NOT NULL DEFAULT '', -- mtls, jwt, api_key or empty
+    source_ip VARCHAR(64) NOT NULL DEFAULT '',
+    code VARCHAR(32) NOT NULL, -- gRPC status code name of the outcome
+    detail TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON audit_log(actor, id);
+CREATE INDEX IF NOT EXISTS idx_audit_log_serial ON audit_log(issuer_id, serial, id);
+
+COMMENT ON TABLE audit_log IS 'Append-only record of state-changing API calls';
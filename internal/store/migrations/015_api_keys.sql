@@ -0,0 +1,21 @@
+-- Migration: API keys
+-- api_keys holds the keys that authenticate gRPC clients without client
+-- certificates. Only the SHA-256 hash of each secret is stored. Rotating a
+-- key inserts its successor and lets the old key expire after a grace
+-- period; revoked keys are kept for the audit trail.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(32) PRIMARY KEY,
+    client VARCHAR(255) NOT NULL, -- identity of callers using the key
+    secret_hash BYTEA NOT NULL,
+    methods TEXT[] NOT NULL, -- RPC names the key may call, or '*'
+    created_by VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    rotated_from VARCHAR(32) REFERENCES api_keys(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_keys_client ON api_keys(client);
+
+COMMENT ON TABLE api_keys IS 'Hashed API keys of gRPC clients';
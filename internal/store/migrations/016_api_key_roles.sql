@@ -0,0 +1,6 @@
+-- Migration: API key roles
+-- API keys grant built-in or custom roles (reader, revoker, publisher,
+-- admin, ...) in addition to, or instead of, individual RPCs.
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE api_keys ALTER COLUMN methods SET DEFAULT '{}';
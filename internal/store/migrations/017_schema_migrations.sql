@@ -0,0 +1,13 @@
+-- Migration: Schema version
+-- schema_migrations records each applied migration, so the service can tell
+-- whether the database schema is as current as its code. Every migration
+-- from this one on inserts its own version.
+
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO schema_migrations (version)
+SELECT generate_series(1, 17)
+ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,22 @@
+-- Migration: Binary serial numbers
+-- serial_number holds each serial as its magnitude, big-endian and without
+-- leading zero octets, so that serials of up to 20 octets compare as
+-- integers however the client spelled them. serial keeps the text as
+-- submitted. Rows whose serial is not a positive hex integer keep a NULL
+-- serial_number, which no lookup matches.
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS serial_number BYTEA;
+
+UPDATE crl_entries e
+SET serial_number = decode(lpad(t.digits, length(t.digits) + length(t.digits) % 2, '0'), 'hex')
+FROM (
+    SELECT issuer_id, serial, ltrim(lower(serial), '0') AS digits
+    FROM crl_entries
+    WHERE serial ~* '^[0-9a-f]+$'
+) t
+WHERE e.issuer_id = t.issuer_id AND e.serial = t.serial
+    AND t.digits <> '' AND e.serial_number IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_crl_entries_serial_number ON crl_entries(issuer_id, serial_number);
+
+INSERT INTO schema_migrations (version) VALUES (18) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,32 @@
+-- Migration: Canonical serials
+-- Serials are stored in canonical form, lowercase hex without leading
+-- zeros, so that a certificate has one row however its serial was spelled.
+-- Where an issuer has several spellings of one serial, the row with the
+-- strongest status is kept (revoked, on_hold, released, then removed), the
+-- most recently updated first; the trigger records the others as deleted.
+
+DELETE FROM crl_entries e
+USING (
+    SELECT issuer_id, serial, row_number() OVER (
+        PARTITION BY issuer_id, serial_number
+        ORDER BY CASE status WHEN 'revoked' THEN 0 WHEN 'on_hold' THEN 1 WHEN 'released' THEN 2 ELSE 3 END,
+            updated_at DESC, serial
+    ) AS rank
+    FROM crl_entries
+    WHERE serial_number IS NOT NULL
+) d
+WHERE e.issuer_id = d.issuer_id AND e.serial = d.serial AND d.rank > 1;
+
+UPDATE crl_entries
+SET serial = ltrim(encode(serial_number, 'hex'), '0')
+WHERE serial_number IS NOT NULL AND serial <> ltrim(encode(serial_number, 'hex'), '0');
+
+DROP INDEX IF EXISTS idx_crl_entries_serial_number;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_crl_entries_serial_number ON crl_entries(issuer_id, serial_number);
+
+-- Pending approvals are copied into crl_entries as they are
+UPDATE revocation_approvals
+SET serial = lower(ltrim(serial, '0'))
+WHERE status = 'pending' AND serial ~* '^[0-9a-f]+$' AND serial <> lower(ltrim(serial, '0'));
+
+INSERT INTO schema_migrations (version) VALUES (19) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,10 @@
+-- Migration: Certificate expiry
+-- not_after is when the revoked certificate expires, as given with the
+-- revocation or looked up in the CA service. CRLs leave out entries whose
+-- certificate expired longer ago than crl.expired_retention. It is NULL
+-- when unknown; such entries are kept.
+
+ALTER TABLE crl_entries ADD COLUMN IF NOT EXISTS not_after TIMESTAMPTZ;
+ALTER TABLE revocation_approvals ADD COLUMN IF NOT EXISTS not_after TIMESTAMPTZ;
+
+INSERT INTO schema_migrations (version) VALUES (20) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,49 @@
+-- Migration: Entry archive
+-- crl_entries_archive keeps the entries of expired certificates after
+-- they have left CRL generation, for audit and compliance. Columns added to
+-- crl_entries later must be added here too. Archiving deletes the entry
+-- from crl_entries; the trigger records it as 'archived' rather than
+-- 'deleted' when crl.archiving is set for the transaction.
+
+CREATE TABLE IF NOT EXISTS crl_entries_archive (
+    issuer_id VARCHAR(64) NOT NULL,
+    serial VARCHAR(128) NOT NULL,
+    serial_number BYTEA,
+    revoked_at TIMESTAMPTZ NOT NULL,
+    reason VARCHAR(64) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL,
+    invalidity_date TIMESTAMPTZ,
+    certificate_issuer BYTEA,
+    updated_at TIMESTAMPTZ NOT NULL,
+    removed_by VARCHAR(255),
+    removal_reason TEXT,
+    removed_at TIMESTAMPTZ,
+    effective_at TIMESTAMPTZ,
+    not_after TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issuer_id, serial, archived_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_crl_entries_archive_revoked_at ON crl_entries_archive(issuer_id, revoked_at DESC);
+CREATE INDEX IF NOT EXISTS idx_crl_entries_archive_archived_at ON crl_entries_archive(archived_at);
+CREATE INDEX IF NOT EXISTS idx_crl_entries_not_after ON crl_entries(issuer_id, not_after)
+    WHERE not_after IS NOT NULL;
+
+COMMENT ON TABLE crl_entries_archive IS 'Entries of expired certificates moved out of crl_entries';
+
+CREATE OR REPLACE FUNCTION record_revocation_event() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO revocation_events (issuer_id, serial, status, reason, revoked_at, invalidity_date, certificate_issuer, effective_at)
+        VALUES (OLD.issuer_id, OLD.serial,
+            CASE WHEN current_setting('crl.archiving', true) = 'on' THEN 'archived' ELSE 'deleted' END,
+            OLD.reason, OLD.revoked_at, OLD.invalidity_date, OLD.certificate_issuer, OLD.effective_at);
+        RETURN OLD;
+    END IF;
+    INSERT INTO revocation_events (issuer_id, serial, status, reason, revoked_at, invalidity_date, certificate_issuer, effective_at)
+    VALUES (NEW.issuer_id, NEW.serial, NEW.status, NEW.reason, NEW.revoked_at, NEW.invalidity_date, NEW.certificate_issuer, NEW.effective_at);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+INSERT INTO schema_migrations (version) VALUES (21) ON CONFLICT (version) DO NOTHING;
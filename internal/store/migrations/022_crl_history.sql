@@ -0,0 +1,24 @@
+-- Migration: CRL history
+-- crl_history keeps every signed CRL, full and delta, so a CRL can be
+-- fetched again by its number. CRL numbers are allocated per issuer, so
+-- they identify a CRL across partitions and deltas.
+
+CREATE TABLE IF NOT EXISTS crl_history (
+    issuer_id TEXT NOT NULL,
+    crl_number BIGINT NOT NULL,
+    partition INTEGER NOT NULL DEFAULT 0,
+    base_crl_number BIGINT,
+    this_update TIMESTAMPTZ NOT NULL,
+    next_update TIMESTAMPTZ NOT NULL,
+    revoked_count INTEGER NOT NULL,
+    crl_der BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issuer_id, crl_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_crl_history_created_at ON crl_history(created_at);
+
+COMMENT ON TABLE crl_history IS 'Every signed CRL by issuer and CRL number';
+COMMENT ON COLUMN crl_history.base_crl_number IS 'Base CRL number of a delta CRL, NULL for full CRLs';
+
+INSERT INTO schema_migrations (version) VALUES (22) ON CONFLICT (version) DO NOTHING;
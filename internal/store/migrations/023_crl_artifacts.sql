@@ -0,0 +1,37 @@
+-- Migration: Content-addressed CRL artifacts
+-- crl_artifacts keeps each signed CRL once, keyed by the SHA-256 of its DER
+-- encoding, and crl_history keeps the generation metadata with the digest.
+-- Reading a CRL back recomputes the digest, so a corrupted artifact is
+-- reported instead of served. signer_key_id is the subject key identifier
+-- of the issuer certificate, carried by the CRL as its authority key
+-- identifier; it is NULL for CRLs recorded before this migration.
+
+CREATE TABLE IF NOT EXISTS crl_artifacts (
+    sha256 BYTEA PRIMARY KEY,
+    crl_der BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO crl_artifacts (sha256, crl_der, created_at)
+SELECT DISTINCT ON (sha256(crl_der)) sha256(crl_der), crl_der, created_at
+FROM crl_history
+ORDER BY sha256(crl_der), created_at
+ON CONFLICT (sha256) DO NOTHING;
+
+ALTER TABLE crl_history ADD COLUMN IF NOT EXISTS sha256 BYTEA;
+ALTER TABLE crl_history ADD COLUMN IF NOT EXISTS signer_key_id BYTEA;
+
+UPDATE crl_history SET sha256 = sha256(crl_der);
+
+ALTER TABLE crl_history ALTER COLUMN sha256 SET NOT NULL;
+ALTER TABLE crl_history ADD CONSTRAINT crl_history_sha256_fkey
+    FOREIGN KEY (sha256) REFERENCES crl_artifacts(sha256);
+ALTER TABLE crl_history DROP COLUMN crl_der;
+
+CREATE INDEX IF NOT EXISTS idx_crl_history_sha256 ON crl_history(sha256);
+
+COMMENT ON TABLE crl_artifacts IS 'Signed CRLs by SHA-256 of their DER encoding';
+COMMENT ON COLUMN crl_history.sha256 IS 'SHA-256 of the signed CRL in crl_artifacts';
+COMMENT ON COLUMN crl_history.signer_key_id IS 'Subject key identifier of the issuer certificate that signed the CRL';
+
+INSERT INTO schema_migrations (version) VALUES (23) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,23 @@
+-- Migration: Revocation notifications
+-- Every revocation event is announced on the crl_revocation_events channel
+-- with its issuer, serial and sequence, so replicas that LISTEN refresh
+-- their CRLs and OCSP responses without polling. Notifications are sent
+-- when the transaction commits.
+
+CREATE OR REPLACE FUNCTION notify_revocation_event() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('crl_revocation_events', json_build_object(
+        'issuer_id', NEW.issuer_id,
+        'serial', NEW.serial,
+        'sequence', NEW.sequence
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS revocation_events_notify ON revocation_events;
+CREATE TRIGGER revocation_events_notify
+    AFTER INSERT ON revocation_events
+    FOR EACH ROW EXECUTE FUNCTION notify_revocation_event();
+
+INSERT INTO schema_migrations (version) VALUES (24) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,12 @@
+-- Migration: Export cursors
+-- export_cursors records how far each incremental export of the revocation
+-- events has got. Replicas lock an export's row while they run it, so only
+-- one of them exports each event.
+
+CREATE TABLE IF NOT EXISTS export_cursors (
+    name VARCHAR(64) PRIMARY KEY,
+    sequence BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO schema_migrations (version) VALUES (25) ON CONFLICT (version) DO NOTHING;
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RevocationChannel is the channel revocation events are announced on
+const RevocationChannel = "crl_revocation_events"
+
+// RevocationNotification announces a revocation event committed by any
+// replica
+type RevocationNotification struct {
+	IssuerID string `json:"issuer_id"`
+	Serial   string `json:"serial"`
+	Sequence int64  `json:"sequence"`
+}
+
+// ListenRevocations calls fn for every revocation event announced on
+// RevocationChannel until ctx is done or the connection fails. It holds
+// one pool connection while listening.
+func (s *Store) ListenRevocations(ctx context.Context, fn func(RevocationNotification)) error {
+	conn, err := s.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer func() {
+		conn.Exec(context.WithoutCancel(ctx), `UNLISTEN *`)
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, `LISTEN `+RevocationChannel); err != nil {
+		return fmt.Errorf("failed to listen for revocations: %w", err)
+	}
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to wait for revocations: %w", err)
+		}
+		var rn RevocationNotification
+		if err := json.Unmarshal([]byte(n.Payload), &rn); err != nil {
+			return fmt.Errorf("invalid revocation notification %q: %w", n.Payload, err)
+		}
+		fn(rn)
+	}
+}
@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/gigvault/crl/internal/metrics"
+	"github.com/gigvault/shared/pkg/logger"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// replicaCheckInterval is how often the replication lag is measured
+const replicaCheckInterval = 2 * time.Second
+
+// replicaLagQuery returns how far the replica trails the primary in
+// seconds; a replica that has replayed all WAL it received is current even
+// if the primary has been idle since its last transaction
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN NOT pg_is_in_recovery() OR pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+	END
+`
+
+// SetReplica makes Reader read from a replica pool once MonitorReplica
+// has found it within the allowed lag
+func (s *Store) SetReplica(replica *pgxpool.Pool) {
+	s.replica = &Store{db: &pool{Pool: replica}}
+}
+
+// Reader returns the store to run reads on that may trail recent writes by
+// up to the replica's allowed lag: the replica while it is healthy and
+// current, otherwise s itself
+func (s *Store) Reader() *Store {
+	if s.replica != nil && s.replicaOK.Load() {
+		return s.replica
+	}
+	return s
+}
+
+// MonitorReplica measures the replication lag until ctx is done, sending
+// reads to the primary while the replica is unreachable or trails it by
+// more than maxLag
+func (s *Store) MonitorReplica(ctx context.Context, maxLag time.Duration) {
+	if s.replica == nil {
+		return
+	}
+	log := logger.Global()
+	ticker := time.NewTicker(replicaCheckInterval)
+	defer ticker.Stop()
+	for {
+		var lag float64
+		err := s.replica.db.QueryRow(ctx, replicaLagQuery).Scan(&lag)
+		if ctx.Err() != nil {
+			return
+		}
+		ok := err == nil && lag <= maxLag.Seconds()
+		if err == nil {
+			metrics.ReplicaLag.Set(lag)
+		}
+		if was := s.replicaOK.Swap(ok); was != ok {
+			switch {
+			case ok:
+				log.Info("Reading from the database replica", zap.Float64("lag_seconds", lag))
+			case err != nil:
+				log.Warn("Database replica unreachable; reading from the primary", zap.Error(err))
+			default:
+				log.Warn("Database replica lagging; reading from the primary",
+					zap.Float64("lag_seconds", lag),
+					zap.Duration("max_lag", maxLag),
+				)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PublishRetry is a failed upload of a CRL to one distribution target,
+// stored in publish_retries
+type PublishRetry struct {
+	IssuerID   string
+	Publisher  string
+	Partition  int
+	CRLNumber  int64
+	DER        []byte
+	ThisUpdate time.Time
+	NextUpdate time.Time
+
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+}
+
+// EnqueueRetry queues a failed upload. It replaces a pending retry of an
+// older CRL for the same target and never one of a newer CRL.
+func (s *Store) EnqueueRetry(ctx context.Context, r PublishRetry) error {
+	query := `
+		INSERT INTO publish_retries
+			(issuer_id, publisher, partition, crl_number, crl_der, this_update, next_update, attempts, next_attempt_at, last_error)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (issuer_id, publisher, partition) DO UPDATE SET
+			crl_number = EXCLUDED.crl_number,
+			crl_der = EXCLUDED.crl_der,
+			this_update = EXCLUDED.this_update,
+			next_update = EXCLUDED.next_update,
+			attempts = EXCLUDED.attempts,
+			next_attempt_at = EXCLUDED.next_attempt_at,
+			last_error = EXCLUDED.last_error,
+			created_at = NOW()
+		WHERE publish_retries.crl_number <= EXCLUDED.crl_number
+	`
+
+	_, err := s.db.Exec(ctx, query,
+		r.IssuerID, r.Publisher, r.Partition, r.CRLNumber, r.DER,
+		r.ThisUpdate, r.NextUpdate, r.Attempts, r.NextAttemptAt, r.LastError,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to queue publish retry: %w", err)
+	}
+	return nil
+}
+
+// ClaimRetries returns up to limit retries that are due and leases them for
+// lease, so other replicas skip them while they are being uploaded
+func (s *Store) ClaimRetries(ctx context.Context, lease time.Duration, limit int) ([]PublishRetry, error) {
+	query := `
+		UPDATE publish_retries r SET next_attempt_at = NOW() + $1::interval
+		FROM (
+			SELECT issuer_id, publisher, partition
+			FROM publish_retries
+			WHERE next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		) due
+		WHERE r.issuer_id = due.issuer_id AND r.publisher = due.publisher AND r.partition = due.partition
+		RETURNING r.issuer_id, r.publisher, r.partition, r.crl_number, r.crl_der,
+			r.this_update, r.next_update, r.attempts, r.next_attempt_at, r.last_error
+	`
+
+	rows, err := s.db.Query(ctx, query, lease, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim publish retries: %w", err)
+	}
+	defer rows.Close()
+
+	var retries []PublishRetry
+	for rows.Next() {
+		var r PublishRetry
+		if err := rows.Scan(
+			&r.IssuerID,
+			&r.Publisher,
+			&r.Partition,
+			&r.CRLNumber,
+			&r.DER,
+			&r.ThisUpdate,
+			&r.NextUpdate,
+			&r.Attempts,
+			&r.NextAttemptAt,
+			&r.LastError,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan publish retry: %w", err)
+		}
+		retries = append(retries, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read publish retries: %w", err)
+	}
+
+	return retries, nil
+}
+
+// RescheduleRetry records another failed attempt of a queued upload
+func (s *Store) RescheduleRetry(ctx context.Context, r PublishRetry) error {
+	query := `
+		UPDATE publish_retries
+		SET attempts = $5, next_attempt_at = $6, last_error = $7
+		WHERE issuer_id = $1 AND publisher = $2 AND partition = $3 AND crl_number = $4
+	`
+
+	_, err := s.db.Exec(ctx, query,
+		r.IssuerID, r.Publisher, r.Partition, r.CRLNumber,
+		r.Attempts, r.NextAttemptAt, r.LastError,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule publish retry: %w", err)
+	}
+	return nil
+}
+
+// RetryPending reports whether the retry is still queued for its CRL,
+// rather than removed or replaced by a newer CRL's
+func (s *Store) RetryPending(ctx context.Context, r PublishRetry) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM publish_retries
+			WHERE issuer_id = $1 AND publisher = $2 AND partition = $3 AND crl_number = $4
+		)
+	`
+
+	var pending bool
+	if err := s.db.QueryRow(ctx, query, r.IssuerID, r.Publisher, r.Partition, r.CRLNumber).Scan(&pending); err != nil {
+		return false, fmt.Errorf("failed to read publish retry: %w", err)
+	}
+	return pending, nil
+}
+
+// DeleteRetry removes the queued upload for a target once CRL crlNumber or
+// a newer one has reached it
+func (s *Store) DeleteRetry(ctx context.Context, issuerID, publisher string, partition int, crlNumber int64) error {
+	query := `
+		DELETE FROM publish_retries
+		WHERE issuer_id = $1 AND publisher = $2 AND partition = $3 AND crl_number <= $4
+	`
+
+	if _, err := s.db.Exec(ctx, query, issuerID, publisher, partition, crlNumber); err != nil {
+		return fmt.Errorf("failed to delete publish retry: %w", err)
+	}
+	return nil
+}
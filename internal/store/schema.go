@@ -0,0 +1,43 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// undefinedTable is the SQLSTATE of a query against a missing table
+const undefinedTable = "42P01"
+
+// AppliedSchemaVersion returns the latest migration applied to the
+// database; zero when schema_migrations does not exist yet
+func (s *Store) AppliedSchemaVersion(ctx context.Context) (int, error) {
+	var version *int
+	err := s.db.QueryRow(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == undefinedTable {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version == nil {
+		return 0, nil
+	}
+	return *version, nil
+}
+
+// CheckSchema returns an error unless every migration up to
+// SchemaVersion has been applied
+func (s *Store) CheckSchema(ctx context.Context) error {
+	applied, err := s.AppliedSchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if want := SchemaVersion(); applied < want {
+		return fmt.Errorf("database schema is at migration %d, need %d", applied, want)
+	}
+	return nil
+}
@@ -0,0 +1,688 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+	"time"
+
+	"github.com/gigvault/crl/internal/serial"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	// ErrNoBaseCRL is returned when no full CRL has been published yet
+	ErrNoBaseCRL = errors.New("no base CRL has been published")
+
+	// ErrNotOnHold is returned when releasing a certificate that is not on hold
+	ErrNotOnHold = errors.New("certificate is not on hold")
+
+	// ErrAlreadyRevoked is returned when holding a permanently revoked certificate
+	ErrAlreadyRevoked = errors.New("certificate is permanently revoked")
+
+	// ErrEntryNotFound is returned when a serial has no crl_entries row
+	ErrEntryNotFound = errors.New("certificate is not revoked")
+)
+
+// Entry status values
+const (
+	StatusRevoked  = "revoked"
+	StatusOnHold   = "on_hold"
+	StatusReleased = "released"
+	StatusRemoved  = "removed"
+)
+
+// Entry is a single revoked certificate stored in crl_entries
+type Entry struct {
+	IssuerID  string
+	Serial    string
+	RevokedAt time.Time
+	Reason    string
+	Status    string
+
+	// Number is the serial as an integer, read from serial_number; nil if
+	// the stored serial is not a positive hex integer
+	Number *big.Int
+
+	// InvalidityDate is when the key was believed compromised, if known
+	InvalidityDate *time.Time
+
+	// CertificateIssuer is the DER issuer Name of the revoked certificate
+	// for indirect CRLs; nil means the CRL issuer
+	CertificateIssuer []byte
+
+	// UpdatedAt is when the entry last changed; it is not written by AddEntry
+	UpdatedAt time.Time
+
+	// RemovedBy, RemovalReason and RemovedAt record who removed an
+	// erroneous revocation and why; set only with StatusRemoved
+	RemovedBy     string
+	RemovalReason string
+	RemovedAt     *time.Time
+
+	// EffectiveAt schedules the revocation: the entry is left out of CRLs
+	// generated before it. Nil means effective immediately.
+	EffectiveAt *time.Time
+
+	// NotAfter is when the revoked certificate expires; nil if unknown
+	NotAfter *time.Time
+
+	// ArchivedAt is when the entry moved to crl_entries_archive; set only
+	// on entries listed with EntryFilter.IncludeArchived
+	ArchivedAt *time.Time
+}
+
+// Store provides access to the CRL tables
+type Store struct {
+	db *pool
+
+	// replica reads from a read replica while replicaOK is set
+	replica   *Store
+	replicaOK atomic.Bool
+}
+
+// New creates a new Store backed by the given connection pool
+func New(db *pgxpool.Pool) *Store {
+	return &Store{db: &pool{Pool: db}}
+}
+
+// Ping checks that the database is reachable
+func (s *Store) Ping(ctx context.Context) error {
+	return s.db.Ping(ctx)
+}
+
+// Issuer is a CA registered in the issuers table
+type Issuer struct {
+	ID           string
+	Subject      string
+	RawSubject   []byte
+	SubjectKeyID []byte
+}
+
+// UpsertIssuer registers an issuer, or refreshes its subject after a
+// certificate rollover
+func (s *Store) UpsertIssuer(ctx context.Context, issuer Issuer) error {
+	query := `
+		INSERT INTO issuers (id, subject, raw_subject, subject_key_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET
+			subject = EXCLUDED.subject,
+			raw_subject = EXCLUDED.raw_subject,
+			subject_key_id = EXCLUDED.subject_key_id,
+			updated_at = NOW()
+	`
+
+	_, err := s.db.Exec(ctx, query, issuer.ID, issuer.Subject, issuer.RawSubject, issuer.SubjectKeyID)
+	if err != nil {
+		return fmt.Errorf("failed to register issuer: %w", err)
+	}
+
+	return nil
+}
+
+// addEntryQuery inserts or replaces a permanent revocation
+const addEntryQuery = `
+	INSERT INTO crl_entries (issuer_id, serial, revoked_at, reason, status, invalidity_date, certificate_issuer, effective_at, serial_number, not_after)
+	VALUES ($1, $2, $3, $4, 'revoked', $5, $6, $7, $8, $9)
+	ON CONFLICT (issuer_id, serial) DO UPDATE SET
+		serial_number = EXCLUDED.serial_number,
+		not_after = COALESCE(EXCLUDED.not_after, crl_entries.not_after),
+		revoked_at = EXCLUDED.revoked_at,
+		reason = EXCLUDED.reason,
+		status = 'revoked',
+		invalidity_date = EXCLUDED.invalidity_date,
+		certificate_issuer = EXCLUDED.certificate_issuer,
+		effective_at = EXCLUDED.effective_at,
+		removed_by = NULL,
+		removal_reason = NULL,
+		removed_at = NULL,
+		updated_at = NOW()
+`
+
+// AddEntry inserts a permanent revocation, replacing any previous entry
+// (including a hold) for the serial under the same issuer
+func (s *Store) AddEntry(ctx context.Context, entry Entry) error {
+	_, err := s.db.Exec(ctx, addEntryQuery, entryArgs(entry)...)
+	if err != nil {
+		return fmt.Errorf("failed to add revocation: %w", err)
+	}
+
+	return nil
+}
+
+// AddEntries inserts several permanent revocations in one transaction:
+// either all of them are stored or none
+func (s *Store) AddEntries(ctx context.Context, entries []Entry) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, entry := range entries {
+		batch.Queue(addEntryQuery, entryArgs(entry)...)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to add revocations: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit revocations: %w", err)
+	}
+
+	return nil
+}
+
+func entryArgs(entry Entry) []interface{} {
+	return []interface{}{
+		entry.IssuerID,
+		entry.Serial,
+		entry.RevokedAt,
+		entry.Reason,
+		entry.InvalidityDate,
+		entry.CertificateIssuer,
+		entry.EffectiveAt,
+		serial.Key(entry.Serial),
+		entry.NotAfter,
+	}
+}
+
+// HoldEntry places a certificate on hold. Permanently revoked certificates
+// cannot be put on hold. notAfter, if known, is when the certificate expires.
+func (s *Store) HoldEntry(ctx context.Context, issuerID, serialHex string, heldAt time.Time, notAfter *time.Time) error {
+	query := `
+		INSERT INTO crl_entries (issuer_id, serial, revoked_at, reason, status, serial_number, not_after)
+		VALUES ($1, $2, $3, 'certificateHold', 'on_hold', $4, $5)
+		ON CONFLICT (issuer_id, serial) DO UPDATE SET
+			serial_number = EXCLUDED.serial_number,
+			not_after = COALESCE(EXCLUDED.not_after, crl_entries.not_after),
+			revoked_at = EXCLUDED.revoked_at,
+			reason = EXCLUDED.reason,
+			status = 'on_hold',
+			effective_at = NULL,
+			removed_by = NULL,
+			removal_reason = NULL,
+			removed_at = NULL,
+			updated_at = NOW()
+		WHERE crl_entries.status <> 'revoked'
+	`
+
+	tag, err := s.db.Exec(ctx, query, issuerID, serialHex, heldAt, serial.Key(serialHex), notAfter)
+	if err != nil {
+		return fmt.Errorf("failed to hold certificate: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAlreadyRevoked
+	}
+
+	return nil
+}
+
+// ReleaseHold lifts a hold. The entry leaves full CRLs and appears as
+// removeFromCRL in delta CRLs until the next base CRL.
+func (s *Store) ReleaseHold(ctx context.Context, issuerID, serialHex string) error {
+	query := `
+		UPDATE crl_entries
+		SET status = 'released', updated_at = NOW()
+		WHERE issuer_id = $1 AND serial_number = $2 AND status = 'on_hold'
+	`
+
+	tag, err := s.db.Exec(ctx, query, issuerID, serial.Key(serialHex))
+	if err != nil {
+		return fmt.Errorf("failed to release hold: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotOnHold
+	}
+
+	return nil
+}
+
+// RemoveEntry withdraws an erroneous revocation or hold, recording who
+// removed it and why, and returns the stored serial. Like a released hold,
+// the entry leaves full CRLs and appears as removeFromCRL in delta CRLs.
+func (s *Store) RemoveEntry(ctx context.Context, issuerID, serialHex, removedBy, reason string) (string, error) {
+	query := `
+		UPDATE crl_entries
+		SET status = 'removed', removed_by = $3, removal_reason = $4, removed_at = NOW(), updated_at = NOW()
+		WHERE issuer_id = $1 AND serial_number = $2 AND status IN ('revoked', 'on_hold')
+		RETURNING serial
+	`
+
+	var stored string
+	err := s.db.QueryRow(ctx, query, issuerID, serial.Key(serialHex), removedBy, reason).Scan(&stored)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrEntryNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to remove revocation: %w", err)
+	}
+
+	return stored, nil
+}
+
+// entryPageSize is how many entries EachEntry reads per query
+const entryPageSize = 10000
+
+// eachEntryQuery reads the page of an issuer's entries in effect after a
+// serial. It selects only columns of idx_crl_entries_in_effect, so pages
+// are index-only range scans; the removal columns are empty for these
+// statuses. Being one fixed statement, pgx prepares it once per connection.
+const eachEntryQuery = `
+	SELECT issuer_id, serial, revoked_at, reason, status, invalidity_date, certificate_issuer, updated_at,
+		'', '', NULL::timestamptz, effective_at, serial_number, not_after
+	FROM crl_entries
+	WHERE issuer_id = $1 AND serial > $2 AND status NOT IN ('released', 'removed')
+		AND (effective_at IS NULL OR effective_at <= NOW())
+	ORDER BY serial
+	LIMIT $3
+`
+
+// SnapshotTime returns a database time no later than the start of any
+// transaction still in progress. Writers stamp updated_at with their
+// transaction's start, so a snapshot taken after this call misses only
+// changes with updated_at at or after the returned time, which
+// ListEntriesSince then returns. The database's clock is used throughout,
+// so the replicas' clocks do not matter. Other backends' transactions are
+// only visible in pg_stat_activity to the same role or pg_read_all_stats.
+func (s *Store) SnapshotTime(ctx context.Context) (time.Time, error) {
+	query := `
+		SELECT LEAST(clock_timestamp(), MIN(xact_start))
+		FROM pg_stat_activity
+		WHERE datname = current_database() AND pid <> pg_backend_pid()
+	`
+
+	var at time.Time
+	if err := s.db.QueryRow(ctx, query).Scan(&at); err != nil {
+		return time.Time{}, fmt.Errorf("failed to read snapshot time: %w", err)
+	}
+	return at, nil
+}
+
+// EachEntry calls fn with every revoked and held certificate of an issuer
+// that is in effect, in serial order, stopping at the first error fn
+// returns. The entries are read in pages from one snapshot, so even
+// millions of them are never held in memory at once. It returns the
+// SnapshotTime taken just before the snapshot, from which the changes it
+// missed can be listed.
+func (s *Store) EachEntry(ctx context.Context, issuerID string, fn func(Entry) error) (time.Time, error) {
+	// Read before the snapshot: a writer committing between the snapshot
+	// and this query would otherwise be missed by both
+	snapshotAt, err := s.SnapshotTime(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	after := ""
+	for {
+		rows, err := tx.Query(ctx, eachEntryQuery, issuerID, after, entryPageSize)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to query CRL entries: %w", err)
+		}
+		page, err := collectEntries(rows, false)
+		if err != nil {
+			return time.Time{}, err
+		}
+		for _, e := range page {
+			if err := fn(e); err != nil {
+				return time.Time{}, err
+			}
+		}
+		if len(page) < entryPageSize {
+			return snapshotAt, nil
+		}
+		after = page[len(page)-1].Serial
+	}
+}
+
+// ListEntries returns all revoked and held certificates of an issuer that
+// are in effect, in serial order
+func (s *Store) ListEntries(ctx context.Context, issuerID string) ([]Entry, error) {
+	var entries []Entry
+	_, err := s.EachEntry(ctx, issuerID, func(e Entry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// ListSerials returns the serial of every entry of an issuer, whatever its
+// status or effective time
+func (s *Store) ListSerials(ctx context.Context, issuerID string) ([]string, error) {
+	rows, err := s.db.Query(ctx, `SELECT serial FROM crl_entries WHERE issuer_id = $1`, issuerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serials: %w", err)
+	}
+	serials, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list serials: %w", err)
+	}
+	return serials, nil
+}
+
+// GetEntry returns the entry of an issuer's serial. Serials are matched
+// as integers, so case and leading zeros do not matter.
+func (s *Store) GetEntry(ctx context.Context, issuerID, serialHex string) (*Entry, error) {
+	query := `
+		SELECT issuer_id, serial, revoked_at, reason, status, invalidity_date, certificate_issuer, updated_at,
+			COALESCE(removed_by, ''), COALESCE(removal_reason, ''), removed_at, effective_at, serial_number, not_after
+		FROM crl_entries
+		WHERE issuer_id = $1 AND serial_number = $2
+	`
+
+	entries, err := s.queryEntries(ctx, query, issuerID, serial.Key(serialHex))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrEntryNotFound
+	}
+	return &entries[0], nil
+}
+
+// GetEntries returns the entries of several serials of an issuer in one
+// query, keyed by the serials as given. Serials without an entry are
+// absent from the map.
+func (s *Store) GetEntries(ctx context.Context, issuerID string, serials []string) (map[string]*Entry, error) {
+	keys := make([][]byte, 0, len(serials))
+	for _, hex := range serials {
+		if key := serial.Key(hex); key != nil {
+			keys = append(keys, key)
+		}
+	}
+
+	query := `
+		SELECT issuer_id, serial, revoked_at, reason, status, invalidity_date, certificate_issuer, updated_at,
+			COALESCE(removed_by, ''), COALESCE(removal_reason, ''), removed_at, effective_at, serial_number, not_after
+		FROM crl_entries
+		WHERE issuer_id = $1 AND serial_number = ANY($2)
+	`
+
+	entries, err := s.queryEntries(ctx, query, issuerID, keys)
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*Entry, len(entries))
+	for i := range entries {
+		if entries[i].Number != nil {
+			byKey[serial.Hex(entries[i].Number)] = &entries[i]
+		}
+	}
+
+	result := make(map[string]*Entry, len(entries))
+	for _, hex := range serials {
+		n, err := serial.ParseHex(hex)
+		if err != nil {
+			continue
+		}
+		if e, ok := byKey[serial.Hex(n)]; ok {
+			result[hex] = e
+		}
+	}
+	return result, nil
+}
+
+// EntryFilter selects entries for ListEntriesPage
+type EntryFilter struct {
+	IssuerID        string
+	Reasons         []string // canonical reason names; empty matches all
+	RevokedAfter    *time.Time
+	RevokedBefore   *time.Time
+	IncludeReleased bool // also match released holds and removed revocations
+	IncludeArchived bool // also match entries moved to crl_entries_archive
+}
+
+// EntryCursor is the position after the last entry of a page
+type EntryCursor struct {
+	RevokedAt time.Time
+	Serial    string
+}
+
+// ListEntriesPage returns up to limit entries matching filter, newest
+// revocation first, starting after cursor when it is non-nil
+func (s *Store) ListEntriesPage(ctx context.Context, filter EntryFilter, cursor *EntryCursor, limit int) ([]Entry, error) {
+	query := `
+		SELECT issuer_id, serial, revoked_at, reason, status, invalidity_date, certificate_issuer, updated_at,
+			COALESCE(removed_by, ''), COALESCE(removal_reason, ''), removed_at, effective_at, serial_number, not_after,
+			archived_at
+		FROM (
+			SELECT issuer_id, serial, revoked_at, reason, status, invalidity_date, certificate_issuer, updated_at,
+				removed_by, removal_reason, removed_at, effective_at, serial_number, not_after, NULL::timestamptz AS archived_at
+			FROM crl_entries
+			WHERE issuer_id = $1
+			UNION ALL
+			SELECT issuer_id, serial, revoked_at, reason, status, invalidity_date, certificate_issuer, updated_at,
+				removed_by, removal_reason, removed_at, effective_at, serial_number, not_after, archived_at
+			FROM crl_entries_archive
+			WHERE $9 AND issuer_id = $1
+		) entries
+		WHERE issuer_id = $1
+			AND (cardinality($2::text[]) = 0 OR reason = ANY($2))
+			AND ($3::timestamptz IS NULL OR revoked_at >= $3)
+			AND ($4::timestamptz IS NULL OR revoked_at < $4)
+			AND ($5 OR status NOT IN ('released', 'removed'))
+			AND ($6::timestamptz IS NULL OR (revoked_at, serial) < ($6, $7))
+		ORDER BY revoked_at DESC, serial DESC
+		LIMIT $8
+	`
+
+	var afterAt *time.Time
+	var afterSerial string
+	if cursor != nil {
+		afterAt, afterSerial = &cursor.RevokedAt, cursor.Serial
+	}
+	reasons := filter.Reasons
+	if reasons == nil {
+		reasons = []string{}
+	}
+
+	rows, err := s.db.Query(ctx, query,
+		filter.IssuerID, reasons, filter.RevokedAfter, filter.RevokedBefore,
+		filter.IncludeReleased, afterAt, afterSerial, limit, filter.IncludeArchived,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CRL entries: %w", err)
+	}
+	return collectEntries(rows, true)
+}
+
+// ListEntriesSince returns the entries of an issuer added, changed or
+// taking effect at or after since, a SnapshotTime, including released holds
+func (s *Store) ListEntriesSince(ctx context.Context, issuerID string, since time.Time) ([]Entry, error) {
+	query := `
+		SELECT issuer_id, serial, revoked_at, reason, status, invalidity_date, certificate_issuer, updated_at,
+			COALESCE(removed_by, ''), COALESCE(removal_reason, ''), removed_at, effective_at, serial_number, not_after
+		FROM crl_entries
+		WHERE issuer_id = $1 AND GREATEST(updated_at, effective_at) >= $2
+			AND (effective_at IS NULL OR effective_at <= NOW())
+		ORDER BY revoked_at DESC
+	`
+
+	return s.queryEntries(ctx, query, issuerID, since)
+}
+
+func (s *Store) queryEntries(ctx context.Context, query string, args ...interface{}) ([]Entry, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query CRL entries: %w", err)
+	}
+	return collectEntries(rows, false)
+}
+
+// collectEntries scans the entry columns of rows, followed by archived_at
+// when archived is set, and closes rows
+func collectEntries(rows pgx.Rows, archived bool) ([]Entry, error) {
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var e Entry
+		var number []byte
+		dest := []interface{}{
+			&e.IssuerID,
+			&e.Serial,
+			&e.RevokedAt,
+			&e.Reason,
+			&e.Status,
+			&e.InvalidityDate,
+			&e.CertificateIssuer,
+			&e.UpdatedAt,
+			&e.RemovedBy,
+			&e.RemovalReason,
+			&e.RemovedAt,
+			&e.EffectiveAt,
+			&number,
+			&e.NotAfter,
+		}
+		if archived {
+			dest = append(dest, &e.ArchivedAt)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to scan CRL entry: %w", err)
+		}
+		if number != nil {
+			e.Number = serial.FromBytes(number)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read CRL entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// MarkPublished records the publication of an issuer's full CRL, which
+// becomes the base for its subsequent delta CRLs. A publication finishing
+// after one with a higher CRL number is not recorded.
+func (s *Store) MarkPublished(ctx context.Context, issuerID string, crlNumber int64, generatedAt, nextUpdate time.Time) error {
+	query := `
+		INSERT INTO crl_metadata (issuer_id, last_published, next_update, base_crl_number, base_generated_at)
+		VALUES ($1, NOW(), $2, $3, $4)
+		ON CONFLICT (issuer_id) DO UPDATE SET
+			last_published = NOW(),
+			next_update = EXCLUDED.next_update,
+			base_crl_number = EXCLUDED.base_crl_number,
+			base_generated_at = EXCLUDED.base_generated_at
+		WHERE crl_metadata.base_crl_number IS NULL
+			OR crl_metadata.base_crl_number < EXCLUDED.base_crl_number
+	`
+
+	if _, err := s.db.Exec(ctx, query, issuerID, nextUpdate, crlNumber, generatedAt); err != nil {
+		return fmt.Errorf("failed to update CRL metadata: %w", err)
+	}
+
+	return nil
+}
+
+// NextEffectiveAt returns when the next scheduled revocation of an issuer
+// takes effect; zero when none is pending
+func (s *Store) NextEffectiveAt(ctx context.Context, issuerID string) (time.Time, error) {
+	query := `
+		SELECT MIN(effective_at)
+		FROM crl_entries
+		WHERE issuer_id = $1 AND status = 'revoked' AND effective_at > NOW()
+	`
+
+	var next *time.Time
+	if err := s.db.QueryRow(ctx, query, issuerID).Scan(&next); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get next effective revocation: %w", err)
+	}
+	if next == nil {
+		return time.Time{}, nil
+	}
+	return *next, nil
+}
+
+// EffectiveSince reports whether a scheduled revocation of an issuer took
+// effect after since, so a CRL published at since lacks it
+func (s *Store) EffectiveSince(ctx context.Context, issuerID string, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM crl_entries
+			WHERE issuer_id = $1 AND status = 'revoked' AND effective_at > $2 AND effective_at <= NOW()
+		)
+	`
+
+	var pending bool
+	if err := s.db.QueryRow(ctx, query, issuerID, since).Scan(&pending); err != nil {
+		return false, fmt.Errorf("failed to check scheduled revocations: %w", err)
+	}
+	return pending, nil
+}
+
+// PublicationState returns when an issuer's CRL was last published and its
+// nextUpdate. Both are zero if it has never been published.
+func (s *Store) PublicationState(ctx context.Context, issuerID string) (time.Time, time.Time, error) {
+	query := `
+		SELECT last_published, next_update
+		FROM crl_metadata
+		WHERE issuer_id = $1
+	`
+
+	var lastPublished, nextUpdate *time.Time
+	err := s.db.QueryRow(ctx, query, issuerID).Scan(&lastPublished, &nextUpdate)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && (lastPublished == nil || nextUpdate == nil)) {
+		return time.Time{}, time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to read CRL metadata: %w", err)
+	}
+
+	return *lastPublished, *nextUpdate, nil
+}
+
+// NextCRLNumber atomically allocates the next CRL number of an issuer. The
+// upsert takes a row lock, so concurrent generators on any replica never
+// share a number.
+func (s *Store) NextCRLNumber(ctx context.Context, issuerID string) (int64, error) {
+	query := `
+		INSERT INTO crl_metadata (issuer_id, crl_number)
+		VALUES ($1, 1)
+		ON CONFLICT (issuer_id) DO UPDATE SET
+			crl_number = crl_metadata.crl_number + 1
+		RETURNING crl_number
+	`
+
+	var number int64
+	if err := s.db.QueryRow(ctx, query, issuerID).Scan(&number); err != nil {
+		return 0, fmt.Errorf("failed to allocate CRL number: %w", err)
+	}
+
+	return number, nil
+}
+
+// BaseCRL returns the number and entry snapshot time of the issuer's last
+// published full CRL
+func (s *Store) BaseCRL(ctx context.Context, issuerID string) (int64, time.Time, error) {
+	query := `
+		SELECT base_crl_number, base_generated_at
+		FROM crl_metadata
+		WHERE issuer_id = $1
+	`
+
+	var number *int64
+	var generatedAt *time.Time
+	err := s.db.QueryRow(ctx, query, issuerID).Scan(&number, &generatedAt)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && (number == nil || generatedAt == nil)) {
+		return 0, time.Time{}, ErrNoBaseCRL
+	}
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to read base CRL: %w", err)
+	}
+
+	return *number, *generatedAt, nil
+}
@@ -0,0 +1,86 @@
+package testutil
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gigvault/crl/internal/store"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Issuer returns a self-signed CA certificate for priv, allowed to sign
+// certificates and CRLs
+func Issuer(t testing.TB, priv crypto.Signer, cn string) *x509.Certificate {
+	t.Helper()
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: cn, Organization: []string{"GigVault"}},
+		NotBefore:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2060, 1, 1, 0, 0, 0, 0, time.UTC),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		SubjectKeyId:          []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, priv.Public(), priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// Signer signs with an in-memory key on behalf of its CA certificate
+type Signer struct {
+	crypto.Signer
+	Cert *x509.Certificate
+}
+
+// Certificate returns the CA certificate
+func (s Signer) Certificate() *x509.Certificate {
+	return s.Cert
+}
+
+// NewSigner returns a signer with a new P-256 key and a CA certificate
+// named cn
+func NewSigner(t testing.TB, cn string) Signer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Signer{Signer: key, Cert: Issuer(t, key, cn)}
+}
+
+// Store connects to the database named by CRL_TEST_DATABASE_URL and
+// migrates it, skipping the test when it is not set
+func Store(t testing.TB) *store.Store {
+	t.Helper()
+	dsn := os.Getenv("CRL_TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("CRL_TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	st := store.New(pool)
+	if _, err := st.Migrate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return st
+}
@@ -0,0 +1,355 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	crlpb "github.com/gigvault/crl/api/proto/crl"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Config configures a Client
+type Config struct {
+	// Address is the gRPC address of the CRL service, such as
+	// crl.internal:9085
+	Address string
+
+	// TLS configures the connection; nil verifies the server against the
+	// system roots. Set a client certificate for mTLS.
+	TLS *tls.Config
+
+	// Insecure connects without TLS
+	Insecure bool
+
+	// APIKey, Token or TokenSource authenticate every call; TokenSource is
+	// called per call for short-lived OIDC tokens
+	APIKey      string
+	Token       string
+	TokenSource func(ctx context.Context) (string, error)
+
+	// Timeout bounds each attempt of a unary call whose context has no
+	// deadline (default 10s)
+	Timeout time.Duration
+
+	// MaxAttempts bounds the attempts of calls failing with Unavailable,
+	// ResourceExhausted or Aborted (default 4, 1 disables retries)
+	MaxAttempts int
+
+	// InitialBackoff and MaxBackoff bound the delay between attempts
+	// (default 100ms and 5s); a delay the service suggests is honoured
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// DialOptions are added to the options of the connection
+	DialOptions []grpc.DialOption
+}
+
+func (c *Config) validate() error {
+	if c.Address == "" {
+		return errors.New("address is required")
+	}
+	if c.Insecure && c.TLS != nil {
+		return errors.New("insecure and tls are mutually exclusive")
+	}
+	if c.Timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 10 * time.Second
+	}
+	if c.MaxAttempts < 0 {
+		return errors.New("max attempts must not be negative")
+	}
+	if c.MaxAttempts == 0 {
+		c.MaxAttempts = 4
+	}
+	if c.InitialBackoff == 0 {
+		c.InitialBackoff = 100 * time.Millisecond
+	}
+	if c.MaxBackoff == 0 {
+		c.MaxBackoff = 5 * time.Second
+	}
+	if c.InitialBackoff < 0 || c.MaxBackoff < c.InitialBackoff {
+		return errors.New("backoff must be positive and max backoff at least initial backoff")
+	}
+	return nil
+}
+
+// Client calls the CRL service. It is safe for concurrent use; share one
+// per process.
+type Client struct {
+	cfg  Config
+	conn *grpc.ClientConn
+	rpc  crlpb.CRLServiceClient
+}
+
+// New connects to the CRL service. The connection is established lazily and
+// re-established after failures.
+func New(cfg Config) (*Client, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid client config: %w", err)
+	}
+	c := &Client{cfg: cfg}
+
+	creds := insecure.NewCredentials()
+	if !cfg.Insecure {
+		tlsConfig := cfg.TLS
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithChainUnaryInterceptor(c.unaryInterceptor),
+	}
+	if cfg.APIKey != "" || cfg.Token != "" || cfg.TokenSource != nil {
+		opts = append(opts, grpc.WithPerRPCCredentials(&callCredentials{cfg: &c.cfg}))
+	}
+	conn, err := grpc.NewClient(cfg.Address, append(opts, cfg.DialOptions...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create connection: %w", err)
+	}
+	c.conn = conn
+	c.rpc = crlpb.NewCRLServiceClient(conn)
+	return c, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Raw returns the generated stub for calls the Client does not wrap. Unary
+// calls still get the timeout, retries and typed errors.
+func (c *Client) Raw() crlpb.CRLServiceClient {
+	return c.rpc
+}
+
+// callCredentials sends the configured API key or bearer token
+type callCredentials struct {
+	cfg *Config
+}
+
+func (cc *callCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	md := map[string]string{}
+	if cc.cfg.APIKey != "" {
+		md["x-api-key"] = cc.cfg.APIKey
+	}
+	token := cc.cfg.Token
+	if cc.cfg.TokenSource != nil {
+		var err error
+		if token, err = cc.cfg.TokenSource(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get token: %w", err)
+		}
+	}
+	if token != "" {
+		md["authorization"] = "Bearer " + token
+	}
+	return md, nil
+}
+
+func (cc *callCredentials) RequireTransportSecurity() bool {
+	return !cc.cfg.Insecure
+}
+
+// Revoke revokes a certificate. Without an idempotency key one is generated,
+// so retries after a lost response do not revoke twice.
+func (c *Client) Revoke(ctx context.Context, req *crlpb.AddRevocationRequest) (*crlpb.AddRevocationResponse, error) {
+	if req.GetIdempotencyKey() == "" {
+		req.IdempotencyKey = rand.Text()
+	}
+	return c.rpc.AddRevocation(ctx, req)
+}
+
+// RevokeBatch revokes up to 1000 certificates in one call, generating an
+// idempotency key as Revoke does
+func (c *Client) RevokeBatch(ctx context.Context, req *crlpb.AddRevocationsRequest) (*crlpb.AddRevocationsResponse, error) {
+	if req.GetIdempotencyKey() == "" {
+		req.IdempotencyKey = rand.Text()
+	}
+	return c.rpc.AddRevocations(ctx, req)
+}
+
+// Hold puts a certificate on hold
+func (c *Client) Hold(ctx context.Context, req *crlpb.HoldCertificateRequest) (*crlpb.HoldCertificateResponse, error) {
+	return c.rpc.HoldCertificate(ctx, req)
+}
+
+// ReleaseHold releases a certificate from hold
+func (c *Client) ReleaseHold(ctx context.Context, req *crlpb.ReleaseHoldRequest) (*crlpb.ReleaseHoldResponse, error) {
+	return c.rpc.ReleaseHold(ctx, req)
+}
+
+// Status returns the revocation status of a serial; an empty issuer is the
+// service's default issuer
+func (c *Client) Status(ctx context.Context, issuer, serial string) (*crlpb.CheckRevocationStatusResponse, error) {
+	return c.rpc.CheckRevocationStatus(ctx, &crlpb.CheckRevocationStatusRequest{SerialNumber: serial, IssuerId: issuer})
+}
+
+// StatusBatch returns the revocation status of several serials of an issuer
+func (c *Client) StatusBatch(ctx context.Context, issuer string, serials []string) ([]*crlpb.SerialStatus, error) {
+	resp, err := c.rpc.CheckStatusBatch(ctx, &crlpb.CheckStatusBatchRequest{SerialNumbers: serials, IssuerId: issuer})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetStatuses(), nil
+}
+
+// Revocation returns the entry of a serial, failing with ErrNotFound if the
+// serial was never revoked
+func (c *Client) Revocation(ctx context.Context, req *crlpb.GetRevocationRequest) (*crlpb.GetRevocationResponse, error) {
+	return c.rpc.GetRevocation(ctx, req)
+}
+
+// ListRevocations calls fn with every page of revocations matching req,
+// starting at req's page token, until the last page or fn fails
+func (c *Client) ListRevocations(ctx context.Context, req *crlpb.ListRevocationsRequest, fn func([]*crlpb.Revocation) error) error {
+	for {
+		resp, err := c.rpc.ListRevocations(ctx, req)
+		if err != nil {
+			return err
+		}
+		if err := fn(resp.GetRevocations()); err != nil {
+			return err
+		}
+		if resp.GetNextPageToken() == "" {
+			return nil
+		}
+		req.PageToken = resp.GetNextPageToken()
+	}
+}
+
+// Publish signs and distributes an issuer's CRLs
+func (c *Client) Publish(ctx context.Context, req *crlpb.PublishCRLRequest) (*crlpb.PublishCRLResponse, error) {
+	return c.rpc.PublishCRL(ctx, req)
+}
+
+// CRL is a CRL downloaded with Client.CRL
+type CRL struct {
+	Data         []byte
+	Format       crlpb.CRLFormat
+	Number       int64
+	BaseNumber   int64 // Set for delta CRLs only
+	Partition    int32
+	ThisUpdate   time.Time
+	NextUpdate   time.Time
+	RevokedCount int32
+	SHA256       []byte // Of the DER CRL
+	Stale        bool   // Served because a new CRL could not be signed
+}
+
+// CRL downloads a CRL with GetCRLStream, so CRLs too large for one message
+// work too. Failed downloads are retried as unary calls are.
+func (c *Client) CRL(ctx context.Context, req *crlpb.GetCRLRequest) (*CRL, error) {
+	for attempt := 1; ; attempt++ {
+		crl, err := c.fetchCRL(ctx, req)
+		if err == nil || !retryable(err) || attempt >= c.cfg.MaxAttempts {
+			return crl, err
+		}
+		if err := sleep(ctx, c.backoff(attempt, err)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) fetchCRL(ctx context.Context, req *crlpb.GetCRLRequest) (*CRL, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+	}
+	stream, err := c.rpc.GetCRLStream(ctx, req)
+	if err != nil {
+		return nil, wrapError(err)
+	}
+	var (
+		crl   *CRL
+		total int64
+	)
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, wrapError(err)
+		}
+		if crl == nil {
+			total = chunk.GetTotalSize()
+			crl = &CRL{
+				Data:         make([]byte, 0, chunk.GetTotalSize()),
+				Format:       chunk.GetFormat(),
+				Number:       chunk.GetCrlNumber(),
+				BaseNumber:   chunk.GetBaseCrlNumber(),
+				Partition:    chunk.GetPartition(),
+				ThisUpdate:   asTime(chunk.GetThisUpdate()),
+				NextUpdate:   asTime(chunk.GetNextUpdate()),
+				RevokedCount: chunk.GetRevokedCount(),
+				SHA256:       chunk.GetSha256(),
+				Stale:        chunk.GetStale(),
+			}
+		}
+		crl.Data = append(crl.Data, chunk.GetData()...)
+	}
+	if crl == nil {
+		return nil, errors.New("empty CRL stream")
+	}
+	if total != 0 && int64(len(crl.Data)) != total {
+		return nil, fmt.Errorf("received %d of %d bytes", len(crl.Data), total)
+	}
+	return crl, nil
+}
+
+// Watch calls fn with every revocation change matching req until ctx is
+// done or fn fails. Broken streams are resumed after the last event fn
+// returned for, with backoff.
+func (c *Client) Watch(ctx context.Context, req *crlpb.WatchRevocationsRequest, fn func(*crlpb.RevocationEvent) error) error {
+	after := req.GetAfterSequence()
+	for attempt := 1; ; attempt++ {
+		stream, err := c.rpc.WatchRevocations(ctx, &crlpb.WatchRevocationsRequest{
+			IssuerId:      req.GetIssuerId(),
+			AllIssuers:    req.GetAllIssuers(),
+			AfterSequence: after,
+		})
+		for err == nil {
+			var event *crlpb.RevocationEvent
+			if event, err = stream.Recv(); err != nil {
+				break
+			}
+			if err := fn(event); err != nil {
+				return err
+			}
+			after = event.GetSequence()
+			attempt = 1
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// The service ends the stream on shutdown; reconnect as after an
+		// Unavailable
+		if !errors.Is(err, io.EOF) {
+			if err = wrapError(err); !retryable(err) {
+				return err
+			}
+		}
+		if err := sleep(ctx, c.backoff(attempt, err)); err != nil {
+			return err
+		}
+	}
+}
+
+func asTime(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors matched with errors.Is against the errors returned by Client
+var (
+	ErrInvalidArgument    = errors.New("invalid argument")
+	ErrNotFound           = errors.New("not found")
+	ErrFailedPrecondition = errors.New("failed precondition")
+	ErrUnauthenticated    = errors.New("unauthenticated")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrRateLimited        = errors.New("rate limited")
+	ErrUnavailable        = errors.New("service unavailable")
+)
+
+// Error is a failed call, with the google.rpc details the service attached
+type Error struct {
+	Code    codes.Code
+	Message string
+
+	// Violations name the invalid request fields of InvalidArgument
+	Violations []FieldViolation
+
+	// Preconditions are the failed checks of FailedPrecondition, such as an
+	// ENTRY_STATE of a serial
+	Preconditions []PreconditionViolation
+
+	// RetryDelay is the delay the service suggested before a retry
+	RetryDelay time.Duration
+
+	status *status.Status
+}
+
+// FieldViolation is an invalid field of a request
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// PreconditionViolation is a failed precondition of a request
+type PreconditionViolation struct {
+	Type        string
+	Subject     string
+	Description string
+}
+
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("crl: %s: %s", e.Code, e.Message)
+	for _, v := range e.Violations {
+		msg += fmt.Sprintf("; %s: %s", v.Field, v.Description)
+	}
+	return msg
+}
+
+// Unwrap returns the Err variable of the code, if any
+func (e *Error) Unwrap() error {
+	switch e.Code {
+	case codes.InvalidArgument:
+		return ErrInvalidArgument
+	case codes.NotFound:
+		return ErrNotFound
+	case codes.FailedPrecondition:
+		return ErrFailedPrecondition
+	case codes.Unauthenticated:
+		return ErrUnauthenticated
+	case codes.PermissionDenied:
+		return ErrPermissionDenied
+	case codes.ResourceExhausted:
+		return ErrRateLimited
+	case codes.Unavailable:
+		return ErrUnavailable
+	}
+	return nil
+}
+
+// GRPCStatus returns the status of the call, so status.FromError and
+// status.Code keep working on errors returned by Client
+func (e *Error) GRPCStatus() *status.Status {
+	return e.status
+}
+
+// wrapError converts a gRPC status error to an *Error
+func wrapError(err error) error {
+	if err == nil {
+		return nil
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	e := &Error{Code: st.Code(), Message: st.Message(), status: st}
+	for _, d := range st.Details() {
+		switch d := d.(type) {
+		case *errdetails.BadRequest:
+			for _, v := range d.GetFieldViolations() {
+				e.Violations = append(e.Violations, FieldViolation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+		case *errdetails.PreconditionFailure:
+			for _, v := range d.GetViolations() {
+				e.Preconditions = append(e.Preconditions, PreconditionViolation{
+					Type:        v.GetType(),
+					Subject:     v.GetSubject(),
+					Description: v.GetDescription(),
+				})
+			}
+		case *errdetails.RetryInfo:
+			e.RetryDelay = d.GetRetryDelay().AsDuration()
+		}
+	}
+	return e
+}
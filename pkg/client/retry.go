@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// retryable reports whether a call failing with err may be sent again:
+// the service was unreachable or overloaded, rate limited the call, or
+// was still running an idempotent request with the same key
+func retryable(err error) bool {
+	var e *Error
+	if !errors.As(err, &e) {
+		return false
+	}
+	switch e.Code {
+	case codes.Unavailable, codes.ResourceExhausted, codes.Aborted:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before retry attempt n (from 1): the delay the
+// service suggested, or an exponential backoff with jitter
+func (c *Client) backoff(n int, err error) time.Duration {
+	var e *Error
+	if errors.As(err, &e) && e.RetryDelay > 0 {
+		return min(e.RetryDelay, c.cfg.MaxBackoff)
+	}
+	d := c.cfg.InitialBackoff << (n - 1)
+	if d <= 0 || d > c.cfg.MaxBackoff {
+		d = c.cfg.MaxBackoff
+	}
+	// Full jitter keeps clients retrying after an outage from arriving
+	// together
+	return time.Duration(rand.Int64N(int64(d)) + 1)
+}
+
+// sleep waits d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// unaryInterceptor applies the call timeout, converts errors and retries
+// retryable failures with backoff
+func (c *Client) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	for attempt := 1; ; attempt++ {
+		err := c.invoke(ctx, method, req, reply, cc, invoker, opts...)
+		if err == nil || !retryable(err) || attempt >= c.cfg.MaxAttempts {
+			return err
+		}
+		if err := sleep(ctx, c.backoff(attempt, err)); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) invoke(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if _, ok := ctx.Deadline(); !ok && c.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		defer cancel()
+	}
+	return wrapError(invoker(ctx, method, req, reply, cc, opts...))
+}
@@ -0,0 +1,180 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCertificateRevoked is returned by Verifier for revoked and held
+// certificates
+var ErrCertificateRevoked = errors.New("certificate revoked")
+
+// VerifierConfig configures a Verifier
+type VerifierConfig struct {
+	// Issuers maps the common name of a certificate's issuer to the issuer
+	// ID of the service. Certificates of other issuers are not checked.
+	// Empty checks every certificate against the default issuer.
+	Issuers map[string]string
+
+	// FailOpen accepts certificates whose status could not be checked;
+	// by default they are rejected
+	FailOpen bool
+
+	// Timeout bounds a status check (default 2s)
+	Timeout time.Duration
+
+	// CacheTTL is how long a status is reused (default 5m, negative
+	// disables caching). Good statuses are not reused past a scheduled
+	// revocation.
+	CacheTTL time.Duration
+
+	// CacheSize bounds the cached statuses (default 10000)
+	CacheSize int
+
+	// OnError is called with the status checks that failed, also when they
+	// fail open
+	OnError func(cert *x509.Certificate, err error)
+}
+
+// Verifier checks the revocation status of TLS peer certificates with the
+// service. Set its VerifyPeerCertificate, or VerifyConnection to also check
+// resumed sessions, on a tls.Config.
+type Verifier struct {
+	client *Client
+	cfg    VerifierConfig
+
+	mu    sync.Mutex
+	cache map[statusKey]cachedStatus
+}
+
+type statusKey struct {
+	issuerID string
+	serial   string
+}
+
+type cachedStatus struct {
+	err     error // ErrCertificateRevoked, or nil for a good certificate
+	expires time.Time
+}
+
+// NewVerifier returns a Verifier checking with c
+func NewVerifier(c *Client, cfg VerifierConfig) *Verifier {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+	if cfg.CacheTTL == 0 {
+		cfg.CacheTTL = 5 * time.Minute
+	}
+	if cfg.CacheSize <= 0 {
+		cfg.CacheSize = 10000
+	}
+	return &Verifier{client: c, cfg: cfg, cache: make(map[statusKey]cachedStatus)}
+}
+
+// VerifyPeerCertificate checks the leaf of the verified chains, or of the
+// raw certificates when the chain was not verified. It is not called for
+// resumed sessions.
+func (v *Verifier) VerifyPeerCertificate(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	if len(verifiedChains) > 0 && len(verifiedChains[0]) > 0 {
+		return v.Verify(verifiedChains[0][0])
+	}
+	if len(rawCerts) == 0 {
+		return nil
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return fmt.Errorf("failed to parse peer certificate: %w", err)
+	}
+	return v.Verify(cert)
+}
+
+// VerifyConnection checks the peer's leaf certificate on every handshake,
+// including resumed ones
+func (v *Verifier) VerifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return nil
+	}
+	return v.Verify(cs.PeerCertificates[0])
+}
+
+// Verify checks a certificate, returning ErrCertificateRevoked if it is
+// revoked or on hold
+func (v *Verifier) Verify(cert *x509.Certificate) error {
+	issuerID := ""
+	if len(v.cfg.Issuers) > 0 {
+		var ok bool
+		if issuerID, ok = v.cfg.Issuers[cert.Issuer.CommonName]; !ok {
+			return nil
+		}
+	}
+	key := statusKey{issuerID: issuerID, serial: cert.SerialNumber.Text(16)}
+	now := time.Now()
+	if entry, ok := v.cached(key, now); ok {
+		return entry.err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.cfg.Timeout)
+	defer cancel()
+	resp, err := v.client.Status(ctx, issuerID, key.serial)
+	if err != nil {
+		if v.cfg.OnError != nil {
+			v.cfg.OnError(cert, err)
+		}
+		if v.cfg.FailOpen {
+			return nil
+		}
+		return fmt.Errorf("failed to check revocation status of serial %s: %w", key.serial, err)
+	}
+
+	entry := cachedStatus{expires: now.Add(v.cfg.CacheTTL)}
+	switch {
+	case resp.GetRevoked():
+		entry.err = fmt.Errorf("%w: serial %s (%s)", ErrCertificateRevoked, key.serial, resp.GetReason())
+	case resp.GetEffectiveAt() != nil:
+		entry.expires = minTime(entry.expires, resp.GetEffectiveAt().AsTime())
+	}
+	v.store(key, entry, now)
+	return entry.err
+}
+
+func (v *Verifier) cached(key statusKey, now time.Time) (cachedStatus, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	entry, ok := v.cache[key]
+	if !ok || !now.Before(entry.expires) {
+		return cachedStatus{}, false
+	}
+	return entry, true
+}
+
+func (v *Verifier) store(key statusKey, entry cachedStatus, now time.Time) {
+	if v.cfg.CacheTTL < 0 || !now.Before(entry.expires) {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if len(v.cache) >= v.cfg.CacheSize {
+		for k, e := range v.cache {
+			if !now.Before(e.expires) {
+				delete(v.cache, k)
+			}
+		}
+		// Still full of live statuses: start over rather than track usage
+		if len(v.cache) >= v.cfg.CacheSize {
+			clear(v.cache)
+		}
+	}
+	v.cache[key] = entry
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}